@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,48 +12,125 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+	_ "time/tzdata" // Embed zoneinfo so ?tz= works on the alpine image
 
 	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
 	"github.com/IANDYI/care-service/internal/adapters/middleware"
 	"github.com/IANDYI/care-service/internal/adapters/repository"
 	"github.com/IANDYI/care-service/internal/config" //nolint:staticcheck // config package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/time/rate"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// JSON logs on stdout; the standard log package is routed through the same handler
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.LogLevel}))
+	slog.SetDefault(logger)
+
+	// Fail before connecting to anything, naming every setting that needs fixing
+	if err := cfg.Validate(); err != nil {
+		logger.Error("invalid configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Circuit breaker state changes are exported on /metrics
+	if err := repository.RegisterRepositoryMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register repository metrics: %v", err)
+	}
+
 	// Connect to database with retry logic
-	db, err := config.ConnectDatabase(cfg.DatabaseURL, 5, 2*time.Second)
+	db, err := config.ConnectDatabase(cfg.DatabaseURL, cfg.DBStatementTimeout, 5, 2*time.Second)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
+	// Read replica for read-only endpoints; without one, reads use the primary
+	var readDB *sql.DB
+	if cfg.DatabaseReadURL != "" {
+		readDB, err = config.ConnectDatabase(cfg.DatabaseReadURL, cfg.DBStatementTimeout, 5, 2*time.Second)
+		if err != nil {
+			log.Fatalf("Failed to connect to read replica: %v", err)
+		}
+		defer readDB.Close()
+	}
+
 	// Initialize RabbitMQ publisher
-	rabbitMQPublisher, err := repository.NewRabbitMQPublisher(cfg.RabbitMQURL, cfg.ALERTS_QUEUE_NAME)
+	rabbitMQPublisher, err := repository.NewRabbitMQPublisher(cfg.RabbitMQURL, cfg.ALERTS_QUEUE_NAME, cfg.CircuitBreakerSettings("rabbitmq"))
 	if err != nil {
 		log.Fatalf("Failed to initialize RabbitMQ publisher: %v", err)
 	}
 	defer rabbitMQPublisher.Close()
 
+	// Broker connections the readiness probe requires, alongside the database
+	brokers := map[string]ports.ConnectionChecker{"rabbitmq_publisher": rabbitMQPublisher}
+
 	// Initialize repositories
-	sqlRepo := repository.NewSQLRepository(db)
+	sqlRepo := repository.NewSQLRepositoryWithReadReplica(db, readDB, cfg.CircuitBreakerSettings("database"), repository.RetrySettings{
+		MaxAttempts: cfg.DBRetryMaxAttempts,
+		BaseDelay:   cfg.DBRetryBaseDelay,
+		MaxDelay:    cfg.DBRetryMaxDelay,
+	})
+
+	// NURSE reads are limited to assigned babies only when enforced
+	var nurseAssignments ports.AssignmentRepository
+	if cfg.EnforceNurseAssignments {
+		nurseAssignments = sqlRepo
+	}
 
 	// Initialize services
-	babyService := services.NewBabyService(sqlRepo)
-	measurementService := services.NewMeasurementService(sqlRepo, sqlRepo, rabbitMQPublisher)
+	babyService := services.NewBabyServiceWithConfig(sqlRepo, services.BabyServiceConfig{
+		Assignments:       nurseAssignments,
+		RoomNumberPattern: cfg.RoomNumberPattern,
+	})
+	assignmentService := services.NewAssignmentService(sqlRepo, sqlRepo)
+	alertMuteService := services.NewAlertMuteService(sqlRepo, sqlRepo)
+	auditService := services.NewAuditService(sqlRepo, sqlRepo, nurseAssignments)
+	measurementService := services.NewMeasurementServiceWithConfig(sqlRepo, sqlRepo, rabbitMQPublisher, services.MeasurementServiceConfig{
+		RequireNoteOnRed:              cfg.RequireNoteOnRed,
+		RequireBreastfeedingPosition:  cfg.RequireBreastfeedingPosition,
+		NoteTemplates:                 cfg.NoteTemplates,
+		TemperatureMinCelsius:         cfg.TemperatureMinCelsius,
+		TemperatureMaxCelsius:         cfg.TemperatureMaxCelsius,
+		AlertQueueSize:                cfg.AlertQueueSize,
+		AlertWorkers:                  cfg.AlertWorkers,
+		WeightMinInterval:             cfg.WeightMinInterval,
+		RejectWeightWithinMinInterval: cfg.WeightMinIntervalReject,
+		NearDuplicateWindow:           cfg.NearDuplicateWindow,
+		Visibility:                    cfg.MeasurementVisibility,
+		IdempotencyKeyTTL:             cfg.IdempotencyKeyTTL,
+		MaxFutureSkew:                 cfg.MeasurementMaxFutureSkew,
+		MetadataSchema:                cfg.MeasurementMetadataSchema,
+		MetadataMaxBytes:              cfg.MeasurementMetadataMaxBytes,
+		RejectBeforeBabyCreated:       cfg.RejectBeforeBabyCreated,
+		BabyCreatedGrace:              cfg.BabyCreatedGrace,
+		PublishYellowAlerts:           cfg.PublishYellowAlerts,
+		AlertThrottleWindow:           cfg.AlertThrottleWindow,
+		FailOnAlertPublishFailure:     cfg.FailOnAlertPublishFailure,
+		AlertMutes:                    sqlRepo,
+		NurseAssignments:              nurseAssignments,
+		Metrics:                       middleware.NewMeasurementMetricsCollector(prometheus.DefaultRegisterer),
+		Audit:                         sqlRepo,
+		Logger:                        logger,
+	})
+	// Deferred before the broker connections close, so queued alerts are still published on shutdown
+	defer measurementService.Close()
 
 	// Initialize RabbitMQ consumer for baby creation
 	// This consumer runs in the same pod as the care-service and processes
 	// baby creation requests from the identity-service via RabbitMQ
-	babyConsumer, err := repository.NewBabyConsumer(cfg.RabbitMQURL, cfg.BABY_QUEUE_NAME, babyService)
+	babyConsumer, err := repository.NewBabyConsumer(cfg.RabbitMQURL, cfg.BABY_QUEUE_NAME, babyService, cfg.BabyConsumerDryRun, int(cfg.BabyConsumerMaxRetries))
 	if err != nil {
 		log.Fatalf("Failed to initialize RabbitMQ baby consumer: %v", err)
 	}
 	defer babyConsumer.Close()
+	brokers["baby_consumer"] = babyConsumer
 
 	// Start baby consumer in background goroutine (non-blocking)
 	// The consumer will process messages asynchronously while the HTTP server runs
@@ -66,13 +145,72 @@ func main() {
 	}()
 	log.Println("Baby consumer started in background, listening for baby creation requests")
 
+	// Parents read-model projected from identity-service user events (opt-in)
+	if cfg.ParentProjectionQueueName != "" {
+		parentConsumer, err := repository.NewParentProjectionConsumer(cfg.RabbitMQURL, cfg.ParentProjectionQueueName, sqlRepo)
+		if err != nil {
+			log.Fatalf("Failed to initialize RabbitMQ parent projection consumer: %v", err)
+		}
+		defer parentConsumer.Close()
+		brokers["parent_projection_consumer"] = parentConsumer
+
+		go func() {
+			if err := parentConsumer.StartConsuming(consumerCtx); err != nil {
+				log.Printf("Parent projection consumer error: %v", err)
+			}
+		}()
+		log.Println("Parent projection consumer started in background, listening for user events")
+	} else {
+		log.Println("Parent projection consumer disabled (set PARENT_PROJECTION_QUEUE_NAME to enable)")
+	}
+
+	// Business KPI gauges, refreshed in the background and served on /metrics
+	if cfg.BusinessMetricsInterval > 0 {
+		businessMetrics := middleware.NewBusinessMetricsCollector(sqlRepo, cfg.BusinessMetricsInterval, prometheus.DefaultRegisterer)
+		go businessMetrics.Start(consumerCtx)
+	}
+
 	// Initialize handlers
 	babyHandler := handler.NewBabyHandler(babyService)
-	measurementHandler := handler.NewMeasurementHandler(measurementService)
-	healthHandler := handler.NewHealthHandler(db)
+	measurementHandler := handler.NewMeasurementHandlerWithConfig(measurementService, handler.MeasurementHandlerConfig{
+		ExportStatementTimeout: cfg.ExportStatementTimeout,
+	})
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+	alertMuteHandler := handler.NewAlertMuteHandler(alertMuteService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	enumsHandler := handler.NewEnumsHandler(cfg.NoteTemplates)
+	schemaHandler := handler.NewSchemaHandler()
+	healthHandler := handler.NewHealthHandlerWithBrokers(db, brokers)
+	babyHandler.SetLogger(logger)
+	measurementHandler.SetLogger(logger)
+	assignmentHandler.SetLogger(logger)
+	alertMuteHandler.SetLogger(logger)
+	auditHandler.SetLogger(logger)
+	enumsHandler.SetLogger(logger)
+	schemaHandler.SetLogger(logger)
 
 	// Initialize JWT middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTPublicKey)
+	authMiddleware := middleware.NewAuthMiddlewareWithKeys(cfg.JWTPublicKeys, cfg.JWTCacheMaxTTL, cfg.JWTClockSkewLeeway)
+	revocationHandler := handler.NewRevocationHandler(authMiddleware)
+	revocationHandler.SetLogger(logger)
+
+	// Per-user rate limit on measurement creation (guards against clients stuck in a retry loop)
+	createMeasurement := measurementHandler.CreateMeasurement
+	if cfg.MeasurementRateLimitPerMinute > 0 {
+		measurementRateLimiter := middleware.NewUserRateLimiter(rate.Limit(cfg.MeasurementRateLimitPerMinute/60), int(cfg.MeasurementRateBurst))
+		defer measurementRateLimiter.Stop()
+		createMeasurement = measurementRateLimiter.RateLimitByUser(createMeasurement)
+	}
+
+	// Measurement writes are held to the 2s SLA; the deadline also cancels their database calls
+	updateMeasurement := measurementHandler.UpdateMeasurement
+	deleteMeasurement := measurementHandler.DeleteMeasurement
+	if cfg.MeasurementWriteTimeout > 0 {
+		writeTimeout := middleware.TimeoutMiddleware(cfg.MeasurementWriteTimeout)
+		createMeasurement = writeTimeout(createMeasurement)
+		updateMeasurement = writeTimeout(updateMeasurement)
+		deleteMeasurement = writeTimeout(deleteMeasurement)
+	}
 
 	// Setup HTTP router
 	mux := http.NewServeMux()
@@ -84,29 +222,122 @@ func main() {
 	mux.HandleFunc("GET /health/live", healthHandler.Live)
 
 	// API endpoints (require authentication)
-	// POST /babies - ADMIN only
+	// POST /babies - ADMIN only (NURSE is read-only)
 	mux.HandleFunc("POST /babies", authMiddleware.RequireRole("ADMIN", babyHandler.CreateBaby))
 
-	// GET /babies - ADMIN: all, PARENT: owned only
+	// POST /babies/batch - ADMIN only, up to 100 babies in one transaction (?atomic=false keeps the valid entries)
+	mux.HandleFunc("POST /babies/batch", authMiddleware.RequireRole("ADMIN", babyHandler.CreateBabies))
+
+	// GET /babies - ADMIN/NURSE: all (NURSE: assigned only when enforced), PARENT: owned only (?include_deleted=true is ADMIN only)
 	mux.HandleFunc("GET /babies", authMiddleware.RequireAuth(babyHandler.ListBabies))
 
-	// GET /babies/{baby_id} - ADMIN: any, PARENT: owned only
+	// GET /parents/me/summary - PARENT only (ADMIN/NURSE get 400), baby count and red measurements in the last 24h
+	mux.HandleFunc("GET /parents/me/summary", authMiddleware.RequireAuth(babyHandler.GetParentSummary))
+
+	// GET /babies/{baby_id} - ADMIN/NURSE: any, PARENT: owned only
 	mux.HandleFunc("GET /babies/{baby_id}", authMiddleware.RequireAuth(babyHandler.GetBaby))
 
-	// POST /babies/{baby_id}/measurements - PARENT: owned only (ADMIN cannot create)
-	mux.HandleFunc("POST /babies/{baby_id}/measurements", authMiddleware.RequireAuth(measurementHandler.CreateMeasurement))
+	// PUT /babies/{baby_id} - ADMIN only, updates room number and/or last name
+	mux.HandleFunc("PUT /babies/{baby_id}", authMiddleware.RequireRole("ADMIN", babyHandler.UpdateBaby))
+
+	// DELETE /babies/{baby_id} - ADMIN only, soft delete (measurements are retained)
+	mux.HandleFunc("DELETE /babies/{baby_id}", authMiddleware.RequireRole("ADMIN", babyHandler.DeleteBaby))
+
+	// POST /babies/{baby_id}/alerts/mute - PARENT: owned only, ADMIN: any (NURSE cannot mute)
+	mux.HandleFunc("POST /babies/{baby_id}/alerts/mute", authMiddleware.RequireAuth(alertMuteHandler.MuteAlerts))
+
+	// GET /babies/{baby_id}/audit - ADMIN: any, NURSE: any (assigned only when enforced), PARENT: forbidden
+	mux.HandleFunc("GET /babies/{baby_id}/audit", authMiddleware.RequireAuth(auditHandler.ListAuditEntries))
+
+	// GET /me/assignments - NURSE only, babies the nurse is assigned to
+	mux.HandleFunc("GET /me/assignments", authMiddleware.RequireRole("NURSE", assignmentHandler.ListMyAssignedBabies))
 
-	// GET /babies/{baby_id}/measurements - ADMIN: any, PARENT: owned only
+	// POST /nurses/{nurse_id}/assignments - ADMIN only, assigns a baby (baby_id) or a ward (room_prefix)
+	mux.HandleFunc("POST /nurses/{nurse_id}/assignments", authMiddleware.RequireRole("ADMIN", assignmentHandler.CreateAssignment))
+
+	// GET /nurses/{nurse_id}/assignments - ADMIN only
+	mux.HandleFunc("GET /nurses/{nurse_id}/assignments", authMiddleware.RequireRole("ADMIN", assignmentHandler.ListAssignments))
+
+	// DELETE /nurses/{nurse_id}/assignments/{assignment_id} - ADMIN only
+	mux.HandleFunc("DELETE /nurses/{nurse_id}/assignments/{assignment_id}", authMiddleware.RequireRole("ADMIN", assignmentHandler.DeleteAssignment))
+
+	// POST /babies/{baby_id}/measurements - PARENT: owned only (ADMIN and NURSE cannot create), rate limited per user
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", authMiddleware.RequireAuth(createMeasurement))
+
+	// POST /babies/{baby_id}/measurements/validate - PARENT: owned only, dry-run without insert
+	mux.HandleFunc("POST /babies/{baby_id}/measurements/validate", authMiddleware.RequireAuth(measurementHandler.ValidateMeasurement))
+
+	// GET /babies/{baby_id}/measurements - ADMIN/NURSE: any, PARENT: owned only
 	mux.HandleFunc("GET /babies/{baby_id}/measurements", authMiddleware.RequireAuth(measurementHandler.GetMeasurements))
 
-	// GET /measurements/{measurement_id} - ADMIN: any, PARENT: owned only
+	// GET /babies/{baby_id}/measurements/stats - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/stats", authMiddleware.RequireAuth(measurementHandler.GetMeasurementStats))
+
+	// GET /babies/{baby_id}/measurements/{type}/trend - ADMIN/NURSE: any, PARENT: owned only, numeric types only
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/{type}/trend", authMiddleware.RequireAuth(measurementHandler.GetMeasurementTrend))
+
+	// GET /babies/{baby_id}/measurements/latest - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/latest", authMiddleware.RequireAuth(measurementHandler.GetLatestMeasurements))
+
+	// GET /babies/{baby_id}/measurements/export - ADMIN/NURSE: any, PARENT: owned only, CSV download
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/export", authMiddleware.RequireAuth(measurementHandler.ExportMeasurements))
+
+	// GET /babies/{baby_id}/measurements/status-distribution - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/status-distribution", authMiddleware.RequireAuth(measurementHandler.GetSafetyStatusDistribution))
+
+	// GET /babies/{baby_id}/feeding/balance - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/balance", authMiddleware.RequireAuth(measurementHandler.GetFeedingBalance))
+
+	// GET /babies/{baby_id}/feeding/compare - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/compare", authMiddleware.RequireAuth(measurementHandler.CompareFeeding))
+
+	// GET /babies/{baby_id}/feeding/hourly - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/hourly", authMiddleware.RequireAuth(measurementHandler.GetHourlyFeeding))
+
+	// GET /babies/{baby_id}/daily-report - ADMIN/NURSE: any, PARENT: owned only
+	mux.HandleFunc("GET /babies/{baby_id}/daily-report", authMiddleware.RequireAuth(measurementHandler.GetDailyReport))
+
+	// GET /wards/{room_prefix}/overview - ADMIN/NURSE only (NURSE: assigned babies when enforced)
+	mux.HandleFunc("GET /wards/{room_prefix}/overview", authMiddleware.RequireAuth(measurementHandler.GetWardOverview))
+
+	// GET /measurements/enums - All authenticated roles: accepted enum values and note templates
+	mux.HandleFunc("GET /measurements/enums", authMiddleware.RequireAuth(enumsHandler.GetEnums))
+
+	// GET /schema - All authenticated roles: JSON Schemas of the baby and measurement request and response bodies
+	mux.HandleFunc("GET /schema", authMiddleware.RequireAuth(schemaHandler.GetSchema))
+
+	// GET /measurements/{measurement_id} - ADMIN/NURSE: any, PARENT: owned only
 	mux.HandleFunc("GET /measurements/{measurement_id}", authMiddleware.RequireAuth(measurementHandler.GetMeasurementByID))
 
-	// DELETE /measurements/{measurement_id} - PARENT: only measurements they created (ADMIN cannot delete)
-	mux.HandleFunc("DELETE /measurements/{measurement_id}", authMiddleware.RequireAuth(measurementHandler.DeleteMeasurement))
+	// PATCH /measurements/{measurement_id} - PARENT: only measurements they created, note and timestamp only (ADMIN and NURSE cannot update)
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", authMiddleware.RequireAuth(updateMeasurement))
+
+	// DELETE /measurements/{measurement_id} - PARENT: only measurements they created (ADMIN and NURSE cannot delete)
+	mux.HandleFunc("DELETE /measurements/{measurement_id}", authMiddleware.RequireAuth(deleteMeasurement))
+
+	// POST /admin/measurements/backfill-status - ADMIN only: recompute NULL or non-canonical safety statuses
+	mux.HandleFunc("POST /admin/measurements/backfill-status", authMiddleware.RequireRole("ADMIN", measurementHandler.BackfillSafetyStatus))
+
+	// POST /admin/revoke - ADMIN only: deny a still-valid token by JTI (in memory, per replica)
+	mux.HandleFunc("POST /admin/revoke", authMiddleware.RequireRole("ADMIN", revocationHandler.RevokeToken))
+
+	// Calendar feed (only when FEED_TOKEN_SECRET is set)
+	if cfg.FeedTokenSecret != "" {
+		calendarHandler := handler.NewCalendarHandler(measurementService, babyService, middleware.NewFeedTokenSigner(cfg.FeedTokenSecret, cfg.FeedTokenTTL))
+		calendarHandler.SetLogger(logger)
+
+		// POST /babies/{baby_id}/calendar-token - PARENT: owned only (ADMIN and NURSE cannot issue feed tokens)
+		mux.HandleFunc("POST /babies/{baby_id}/calendar-token", authMiddleware.RequireAuth(calendarHandler.IssueFeedToken))
+
+		// GET /babies/{baby_id}/measurements.ics - feed token in ?token= instead of JWT, scoped to one baby and its PARENT
+		mux.HandleFunc("GET /babies/{baby_id}/measurements.ics", calendarHandler.GetMeasurementsFeed)
+	} else {
+		log.Println("FEED_TOKEN_SECRET not set, calendar feed endpoints disabled")
+	}
 
 	// Wrap mux with metrics middleware to track all HTTP requests
-	loggedRouter := middleware.MetricsMiddleware(mux)
+	// and name the serving replica in X-Served-By when enabled
+	loggedRouter := middleware.MetricsMiddleware(middleware.ServedByMiddleware(cfg.ServedBy, mux))
 
 	// Create HTTP server
 	server := &http.Server{
@@ -138,7 +369,7 @@ func main() {
 
 	// Cancel consumer context first to stop processing new messages
 	consumerCancel()
-	log.Println("Baby consumer stopped")
+	log.Println("Consumers stopped")
 
 	// Shutdown HTTP server with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// AlertMuteHandler handles HTTP requests for muting a baby's alerts
+type AlertMuteHandler struct {
+	alertMuteService ports.AlertMuteService
+
+	requestLogger
+}
+
+// NewAlertMuteHandler creates a new alert mute handler
+func NewAlertMuteHandler(alertMuteService ports.AlertMuteService) *AlertMuteHandler {
+	return &AlertMuteHandler{
+		alertMuteService: alertMuteService,
+	}
+}
+
+// MuteAlertsRequest represents the request body for muting a baby's alerts
+type MuteAlertsRequest struct {
+	DurationMinutes int    `json:"duration_minutes"` // How long alerts stay muted
+	Reason          string `json:"reason,omitempty"` // Optional clinical context
+}
+
+// MuteAlerts handles POST /babies/{baby_id}/alerts/mute
+// PARENT: owned babies only, ADMIN: any baby - suppresses alert broadcasts until the mute expires
+func (h *AlertMuteHandler) MuteAlerts(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req MuteAlertsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	// Mute alerts
+	mute, err := h.alertMuteService.MuteAlerts(r.Context(), babyID, time.Duration(req.DurationMinutes)*time.Minute, req.Reason, userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to mute alerts: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, userRole, babyIDStr, err)
+		switch {
+		case err.Error() == "forbidden: only the baby's parent or ADMIN can mute alerts":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case err.Error() == "baby not found":
+			http.Error(w, "baby not found", http.StatusNotFound)
+		case strings.HasPrefix(err.Error(), "duration_minutes must be"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies/"+babyIDStr+"/alerts/mute", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, mute)
+}
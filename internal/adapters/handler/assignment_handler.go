@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// AssignmentHandler handles HTTP requests for nurse assignments
+type AssignmentHandler struct {
+	assignmentService ports.AssignmentService
+
+	requestLogger
+}
+
+// NewAssignmentHandler creates a new assignment handler
+func NewAssignmentHandler(assignmentService ports.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+	}
+}
+
+// CreateAssignmentRequest represents the request body for assigning a nurse
+// Exactly one of baby_id and room_prefix must be set
+type CreateAssignmentRequest struct {
+	BabyID     *uuid.UUID `json:"baby_id"`
+	RoomPrefix string     `json:"room_prefix"`
+}
+
+// CreateAssignment handles POST /nurses/{nurse_id}/assignments
+// ADMIN only - assigns the nurse to a baby or to every room starting with a prefix
+func (h *AssignmentHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract nurse_id from URL path
+	nurseIDStr := r.PathValue("nurse_id")
+	nurseID, err := uuid.Parse(nurseIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid nurse ID: %v", requestID, err)
+		http.Error(w, "invalid nurse ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req CreateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	// Create assignment
+	assignment, err := h.assignmentService.CreateAssignment(r.Context(), nurseID, req.BabyID, req.RoomPrefix, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to create assignment: user_id=%s, role=%s, nurse_id=%s, error=%v", requestID, userIDStr, userRole, nurseIDStr, err)
+		switch err.Error() {
+		case "forbidden: only ADMIN can manage assignments":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case "baby not found":
+			http.Error(w, "baby not found", http.StatusNotFound)
+		case "exactly one of baby_id or room_prefix is required":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/nurses/"+nurseIDStr+"/assignments", http.StatusCreated, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusCreated, assignment)
+}
+
+// ListAssignments handles GET /nurses/{nurse_id}/assignments
+// ADMIN only - lists the nurse's assignments
+func (h *AssignmentHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract nurse_id from URL path
+	nurseIDStr := r.PathValue("nurse_id")
+	nurseID, err := uuid.Parse(nurseIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid nurse ID: %v", requestID, err)
+		http.Error(w, "invalid nurse ID", http.StatusBadRequest)
+		return
+	}
+
+	assignments, err := h.assignmentService.ListAssignments(r.Context(), nurseID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to list assignments: user_id=%s, role=%s, nurse_id=%s, error=%v", requestID, userIDStr, userRole, nurseIDStr, err)
+		if err.Error() == "forbidden: only ADMIN can manage assignments" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/nurses/"+nurseIDStr+"/assignments", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSONList(w, r, requestID, assignments, len(assignments), "")
+}
+
+// DeleteAssignment handles DELETE /nurses/{nurse_id}/assignments/{assignment_id}
+// ADMIN only - removes one of the nurse's assignments
+func (h *AssignmentHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract nurse_id and assignment_id from URL path
+	nurseIDStr := r.PathValue("nurse_id")
+	nurseID, err := uuid.Parse(nurseIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid nurse ID: %v", requestID, err)
+		http.Error(w, "invalid nurse ID", http.StatusBadRequest)
+		return
+	}
+	assignmentIDStr := r.PathValue("assignment_id")
+	assignmentID, err := uuid.Parse(assignmentIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid assignment ID: %v", requestID, err)
+		http.Error(w, "invalid assignment ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.assignmentService.DeleteAssignment(r.Context(), assignmentID, nurseID, userRole); err != nil {
+		log.Printf("[%s] Failed to delete assignment: user_id=%s, role=%s, assignment_id=%s, error=%v", requestID, userIDStr, userRole, assignmentIDStr, err)
+		switch err.Error() {
+		case "forbidden: only ADMIN can manage assignments":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case "assignment not found":
+			http.Error(w, "assignment not found", http.StatusNotFound)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "DELETE", "/nurses/"+nurseIDStr+"/assignments/"+assignmentIDStr, http.StatusNoContent, time.Since(startTime))
+
+	// Return success response
+	writeNoContent(w, r, requestID)
+}
+
+// ListMyAssignedBabies handles GET /me/assignments
+// NURSE only - lists the babies the calling nurse is responsible for
+func (h *AssignmentHandler) ListMyAssignedBabies(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	babies, err := h.assignmentService.ListAssignedBabies(r.Context(), userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to list assigned babies: user_id=%s, role=%s, error=%v", requestID, userIDStr, userRole, err)
+		if err.Error() == "forbidden: only NURSE has assignments" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/me/assignments", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSONList(w, r, requestID, babies, len(babies), "")
+}
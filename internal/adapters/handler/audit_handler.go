@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// auditPage is the response of GET /babies/{baby_id}/audit
+// NextCursor is omitted on the last page
+type auditPage struct {
+	Entries    []*domain.AuditEntry `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// AuditHandler handles HTTP requests for the measurement audit log
+type AuditHandler struct {
+	auditService ports.AuditService
+
+	requestLogger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService ports.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditEntries handles GET /babies/{baby_id}/audit
+// ADMIN and NURSE only - who created, updated or deleted the baby's measurements, newest first
+// Query params: limit (default 50), cursor (opaque next_cursor of the previous page)
+func (h *AuditHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultMeasurementPageSize
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil {
+			log.Printf("[%s] Invalid limit parameter: %s", requestID, limitParam)
+			http.Error(w, "invalid limit parameter (must be positive integer)", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var before *ports.AuditCursor
+	if cursorParam := r.URL.Query().Get("cursor"); cursorParam != "" {
+		before, err = decodeAuditCursor(cursorParam)
+		if err != nil {
+			log.Printf("[%s] Invalid cursor parameter: %v", requestID, err)
+			http.Error(w, "invalid cursor parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// List audit entries
+	entries, next, err := h.auditService.ListAuditEntries(r.Context(), babyID, before, limit, userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to list audit entries: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, userRole, babyIDStr, err)
+		switch {
+		case err.Error() == "forbidden: only ADMIN and NURSE can read the audit log":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case err.Error() == "baby not found":
+			http.Error(w, "baby not found", http.StatusNotFound)
+		case strings.HasPrefix(err.Error(), "limit must be"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var nextCursor string
+	if next != nil {
+		nextCursor = encodeAuditCursor(next)
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/audit", http.StatusOK, time.Since(startTime))
+
+	// Return response - the envelope carries the cursor in its meta instead of a page wrapper
+	if !wantsEnvelope(r) {
+		writeJSON(w, r, requestID, http.StatusOK, auditPage{Entries: entries, NextCursor: nextCursor})
+		return
+	}
+	writeJSONList(w, r, requestID, entries, len(entries), nextCursor)
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
 )
@@ -14,6 +17,8 @@ import (
 // BabyHandler handles HTTP requests for baby operations
 type BabyHandler struct {
 	babyService ports.BabyService
+
+	requestLogger
 }
 
 // NewBabyHandler creates a new baby handler
@@ -25,15 +30,24 @@ func NewBabyHandler(babyService ports.BabyService) *BabyHandler {
 
 // CreateBabyRequest represents the request body for creating a baby
 type CreateBabyRequest struct {
-	LastName     string    `json:"last_name"`
-	RoomNumber   string    `json:"room_number"`
-	ParentUserID uuid.UUID `json:"parent_user_id"`
+	LastName     string    `json:"last_name" schema:"required"`
+	RoomNumber   string    `json:"room_number" schema:"required"`
+	ParentUserID uuid.UUID `json:"parent_user_id" schema:"required"`
+	DateOfBirth  string    `json:"date_of_birth,omitempty"` // YYYY-MM-DD; selects age-adjusted temperature bands
+}
+
+// UpdateBabyRequest represents the request body for updating a baby
+// Empty or omitted fields are left unchanged
+type UpdateBabyRequest struct {
+	LastName    string `json:"last_name"`
+	RoomNumber  string `json:"room_number"`
+	DateOfBirth string `json:"date_of_birth,omitempty"` // YYYY-MM-DD
 }
 
 // CreateBaby handles POST /babies
 // ADMIN only - creates a baby and assigns to parent_user_id
 func (h *BabyHandler) CreateBaby(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -51,20 +65,20 @@ func (h *BabyHandler) CreateBaby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 
 	// Parse request body
 	var req CreateBabyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("[%s] Failed to decode request: %v", requestID, err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
 		return
 	}
 
 	// Create baby
-	baby, err := h.babyService.CreateBaby(r.Context(), req.LastName, req.RoomNumber, req.ParentUserID, userID, isAdmin)
+	baby, err := h.babyService.CreateBaby(r.Context(), req.LastName, req.RoomNumber, req.ParentUserID, req.DateOfBirth, userID, userRole)
 	if err != nil {
-		log.Printf("[%s] Failed to create baby: user_id=%s, role=%v, error=%v", requestID, userIDStr, isAdmin, err)
+		log.Printf("[%s] Failed to create baby: user_id=%s, role=%s, error=%v", requestID, userIDStr, userRole, err)
 		if err.Error() == "forbidden: only ADMIN can create babies" {
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
@@ -74,20 +88,202 @@ func (h *BabyHandler) CreateBaby(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "POST", "/babies", http.StatusCreated, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies", http.StatusCreated, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusCreated, baby)
+}
+
+// CreateBabies handles POST /babies/batch
+// ADMIN only - creates a whole ward at once from an array of CreateBabyRequest
+// Query params: atomic (default true; false creates the valid entries and reports the invalid ones)
+func (h *BabyHandler) CreateBabies(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	atomic := true
+	if atomicParam := r.URL.Query().Get("atomic"); atomicParam != "" {
+		parsed, err := strconv.ParseBool(atomicParam)
+		if err != nil {
+			http.Error(w, "invalid atomic parameter (must be true or false)", http.StatusBadRequest)
+			return
+		}
+		atomic = parsed
+	}
+
+	// Parse request body
+	var reqs []CreateBabyRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body (expected an array of babies)"), http.StatusBadRequest)
+		return
+	}
+
+	requests := make([]ports.CreateBabyRequest, len(reqs))
+	for i, req := range reqs {
+		requests[i] = ports.CreateBabyRequest{LastName: req.LastName, RoomNumber: req.RoomNumber, ParentUserID: req.ParentUserID, DateOfBirth: req.DateOfBirth}
+	}
+
+	// Create babies
+	result, err := h.babyService.CreateBabies(r.Context(), requests, userID, userRole, atomic)
+	if err != nil {
+		log.Printf("[%s] Failed to create babies: user_id=%s, role=%s, error=%v", requestID, userIDStr, userRole, err)
+		if err.Error() == "forbidden: only ADMIN can create babies" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "batch must contain") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Nothing created means every entry was rejected (or an atomic batch had an invalid entry)
+	status := http.StatusCreated
+	if result.Created == 0 {
+		status = http.StatusBadRequest
+	}
+	log.Printf("[%s] Baby batch: atomic=%t, created=%d, failed=%d", requestID, atomic, result.Created, result.Failed)
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies/batch", status, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, status, result)
+}
+
+// UpdateBaby handles PUT /babies/{baby_id}
+// ADMIN only - changes the room number and/or last name of a baby
+func (h *BabyHandler) UpdateBaby(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req UpdateBabyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	// Update baby
+	baby, err := h.babyService.UpdateBaby(r.Context(), babyID, req.LastName, req.RoomNumber, req.DateOfBirth, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to update baby: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, userRole, babyIDStr, err)
+		if err.Error() == "forbidden: only ADMIN can update babies" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "at least one of last_name, room_number or date_of_birth is required" ||
+			err.Error() == "baby last_name cannot be empty" || err.Error() == "baby room_number cannot be empty" ||
+			strings.HasPrefix(err.Error(), "invalid room_number") || strings.HasPrefix(err.Error(), "invalid date_of_birth") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "PUT", "/babies/"+babyIDStr, http.StatusOK, time.Since(startTime))
 
 	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(baby); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	writeJSON(w, r, requestID, http.StatusOK, baby)
+}
+
+// DeleteBaby handles DELETE /babies/{baby_id}
+// ADMIN only - soft-deletes the baby; the row and its measurements are retained
+func (h *BabyHandler) DeleteBaby(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
 	}
+
+	// Delete baby
+	if err := h.babyService.DeleteBaby(r.Context(), babyID, userRole); err != nil {
+		log.Printf("[%s] Failed to delete baby: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, userRole, babyIDStr, err)
+		if err.Error() == "forbidden: only ADMIN can delete babies" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "DELETE", "/babies/"+babyIDStr, http.StatusNoContent, time.Since(startTime))
+
+	// Return success response
+	writeNoContent(w, r, requestID)
 }
 
 // GetBaby handles GET /babies/{baby_id}
 // ADMIN: any baby, PARENT: owned only
 func (h *BabyHandler) GetBaby(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -105,12 +301,12 @@ func (h *BabyHandler) GetBaby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 	role, _ := middleware.GetRole(r.Context())
 
 	// Extract baby_id from URL path
 	babyIDStr := r.PathValue("baby_id")
-	log.Printf("[%s] GetBaby - user_id=%s, role=%s, isAdmin=%v, baby_id=%s", requestID, userIDStr, role, isAdmin, babyIDStr)
+	log.Printf("[%s] GetBaby - user_id=%s, role=%s, baby_id=%s", requestID, userIDStr, role, babyIDStr)
 	babyID, err := uuid.Parse(babyIDStr)
 	if err != nil {
 		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
@@ -119,9 +315,9 @@ func (h *BabyHandler) GetBaby(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get baby
-	baby, err := h.babyService.GetBaby(r.Context(), babyID, userID, isAdmin)
+	baby, err := h.babyService.GetBaby(r.Context(), babyID, userID, userRole)
 	if err != nil {
-		log.Printf("[%s] Failed to get baby: user_id=%s, role=%s, isAdmin=%v, baby_id=%s, error=%v", requestID, userIDStr, role, isAdmin, babyIDStr, err)
+		log.Printf("[%s] Failed to get baby: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, role, babyIDStr, err)
 		if err.Error() == "baby not found" {
 			http.Error(w, "baby not found", http.StatusNotFound)
 			return
@@ -131,19 +327,17 @@ func (h *BabyHandler) GetBaby(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "GET", "/babies/"+babyIDStr, http.StatusOK, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr, http.StatusOK, time.Since(startTime))
 
 	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(baby); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
-	}
+	writeJSON(w, r, requestID, http.StatusOK, baby)
 }
 
 // ListBabies handles GET /babies
 // ADMIN: all babies, PARENT: owned only
+// Optional ?include_deleted=true also returns soft-deleted babies (ADMIN only)
 func (h *BabyHandler) ListBabies(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -161,23 +355,76 @@ func (h *BabyHandler) ListBabies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
+
+	includeDeleted := false
+	if includeDeletedStr := r.URL.Query().Get("include_deleted"); includeDeletedStr != "" {
+		includeDeleted, err = strconv.ParseBool(includeDeletedStr)
+		if err != nil {
+			log.Printf("[%s] Invalid include_deleted: %v", requestID, err)
+			http.Error(w, "invalid include_deleted", http.StatusBadRequest)
+			return
+		}
+	}
 
 	// List babies
-	babies, err := h.babyService.ListBabies(r.Context(), userID, isAdmin)
+	babies, err := h.babyService.ListBabies(r.Context(), userID, userRole, includeDeleted)
 	if err != nil {
-		log.Printf("[%s] Failed to list babies: user_id=%s, role=%v, error=%v", requestID, userIDStr, isAdmin, err)
+		log.Printf("[%s] Failed to list babies: user_id=%s, role=%s, error=%v", requestID, userIDStr, userRole, err)
+		if err.Error() == "forbidden: only ADMIN can list deleted babies" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "GET", "/babies", http.StatusOK, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies", http.StatusOK, time.Since(startTime))
 
 	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(babies); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	writeJSONList(w, r, requestID, babies, len(babies), "")
+}
+
+// GetParentSummary handles GET /parents/me/summary
+// Returns the authenticated parent's baby count and red measurements across their babies in the last 24h
+// PARENT only: ADMIN and NURSE get 400 since they have no babies of their own
+func (h *BabyHandler) GetParentSummary(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	summary, err := h.babyService.GetParentSummary(r.Context(), userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to get parent summary: user_id=%s, role=%s, error=%v", requestID, userIDStr, userRole, err)
+		if err.Error() == "parent summary is only available to PARENT accounts" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
 	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/parents/me/summary", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, summary)
 }
 
@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// calendarFeedDays is how far back the .ics feed reaches
+const calendarFeedDays = 90
+
+// icsTimeFormat is the iCalendar UTC date-time form (RFC 5545 section 3.3.5)
+const icsTimeFormat = "20060102T150405Z"
+
+// CalendarTokenResponse is returned when a parent requests a calendar feed token
+type CalendarTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	FeedURL   string    `json:"feed_url"`
+}
+
+// CalendarHandler serves the read-only iCalendar measurement feed
+type CalendarHandler struct {
+	measurementService ports.MeasurementService
+	babyService        ports.BabyService
+	signer             *middleware.FeedTokenSigner
+
+	requestLogger
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(measurementService ports.MeasurementService, babyService ports.BabyService, signer *middleware.FeedTokenSigner) *CalendarHandler {
+	return &CalendarHandler{
+		measurementService: measurementService,
+		babyService:        babyService,
+		signer:             signer,
+	}
+}
+
+// IssueFeedToken handles POST /babies/{baby_id}/calendar-token
+// PARENT: owned only; the token grants read access to that baby's .ics feed
+func (h *CalendarHandler) IssueFeedToken(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	// Feed tokens are tied to a parent's ownership, which is re-checked on every fetch
+	if userRole != domain.RoleParent {
+		log.Printf("[%s] Forbidden: role=%s cannot issue calendar feed tokens", requestID, userRole)
+		http.Error(w, "forbidden: only PARENT can issue calendar feed tokens", http.StatusForbidden)
+		return
+	}
+
+	if _, err := h.babyService.GetBaby(r.Context(), babyID, userID, userRole); err != nil {
+		log.Printf("[%s] Failed to get baby: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	token, expiresAt := h.signer.Issue(babyID, userID, time.Now())
+	response := CalendarTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		FeedURL:   "/babies/" + babyIDStr + "/measurements.ics?token=" + token,
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies/"+babyIDStr+"/calendar-token", http.StatusCreated, time.Since(startTime))
+
+	writeJSON(w, r, requestID, http.StatusCreated, response)
+}
+
+// GetMeasurementsFeed handles GET /babies/{baby_id}/measurements.ics?token=...
+// Authenticated by the feed token instead of a JWT; ownership is enforced as the token's PARENT
+// Optional ?type= limits the feed to one measurement type
+func (h *CalendarHandler) GetMeasurementsFeed(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	tokenBabyID, userID, err := h.signer.Verify(r.URL.Query().Get("token"), time.Now())
+	if err != nil {
+		log.Printf("[%s] Rejected calendar feed token: baby_id=%s, error=%v", requestID, babyIDStr, err)
+		http.Error(w, "invalid feed token", http.StatusUnauthorized)
+		return
+	}
+	if tokenBabyID != babyID {
+		log.Printf("[%s] Calendar feed token for baby_id=%s used for baby_id=%s", requestID, tokenBabyID, babyIDStr)
+		http.Error(w, "forbidden: feed token is not valid for this baby", http.StatusForbidden)
+		return
+	}
+
+	from := time.Now().UTC().AddDate(0, 0, -calendarFeedDays)
+	filter := ports.MeasurementFilter{From: &from}
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		filter.Type = &typeParam
+	}
+
+	// Read as the parent the token was issued to, so revoked ownership also revokes the feed
+	measurements, err := h.measurementService.GetMeasurements(r.Context(), babyID, userID, domain.RoleParent, filter)
+	if err != nil {
+		log.Printf("[%s] Failed to get measurements for calendar feed: user_id=%s, baby_id=%s, error=%v", requestID, userID, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userID.String(), domain.RoleParent, "GET", "/babies/"+babyIDStr+"/measurements.ics", http.StatusOK, time.Since(startTime))
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="measurements.ics"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(buildMeasurementCalendar(measurements))); err != nil {
+		log.Printf("[%s] Failed to write calendar feed: %v", requestID, err)
+	}
+}
+
+// buildMeasurementCalendar renders the measurements as an RFC 5545 VCALENDAR with one VEVENT each
+func buildMeasurementCalendar(measurements []*domain.Measurement) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//care-service//measurements//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:Baby measurements")
+
+	for _, m := range measurements {
+		start, end := measurementEventSpan(m)
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+m.ID.String()+"@care-service")
+		writeICSLine(&b, "DTSTAMP:"+m.CreatedAt.UTC().Format(icsTimeFormat))
+		writeICSLine(&b, "DTSTART:"+start.UTC().Format(icsTimeFormat))
+		if end != nil {
+			writeICSLine(&b, "DTEND:"+end.UTC().Format(icsTimeFormat))
+		}
+		writeICSLine(&b, "SUMMARY:"+escapeICSText(measurementEventSummary(m)))
+		description := "Safety status: " + string(m.SafetyStatus)
+		if m.Note != "" {
+			description += "\n" + m.Note
+		}
+		writeICSLine(&b, "DESCRIPTION:"+escapeICSText(description))
+		writeICSLine(&b, "CATEGORIES:"+escapeICSText(m.Type))
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// measurementEventSpan returns the event start and, for sleep and timed breast feeding, its end
+func measurementEventSpan(m *domain.Measurement) (time.Time, *time.Time) {
+	switch {
+	case m.Type == domain.MeasurementTypeSleep && m.SleepStart != nil && m.SleepEnd != nil:
+		return *m.SleepStart, m.SleepEnd
+	case m.Type == domain.MeasurementTypeFeeding && m.FeedingType == domain.FeedingTypeBreast:
+		seconds := 0
+		if m.Duration != nil {
+			seconds = *m.Duration
+		} else {
+			if m.LeftDuration != nil {
+				seconds += *m.LeftDuration
+			}
+			if m.RightDuration != nil {
+				seconds += *m.RightDuration
+			}
+		}
+		if seconds > 0 {
+			end := m.Timestamp.Add(time.Duration(seconds) * time.Second)
+			return m.Timestamp, &end
+		}
+	}
+	return m.Timestamp, nil
+}
+
+// measurementEventSummary returns a short human-readable event title
+func measurementEventSummary(m *domain.Measurement) string {
+	switch m.Type {
+	case domain.MeasurementTypeFeeding:
+		if m.FeedingType == domain.FeedingTypeBottle && m.VolumeML != nil {
+			return fmt.Sprintf("Feeding (bottle, %d ml)", *m.VolumeML)
+		}
+		if m.FeedingType == domain.FeedingTypeBreast && m.Side != nil {
+			return fmt.Sprintf("Feeding (breast, %s)", *m.Side)
+		}
+		if m.FeedingType != "" {
+			return fmt.Sprintf("Feeding (%s)", m.FeedingType)
+		}
+		return "Feeding"
+	case domain.MeasurementTypeTemperature:
+		return fmt.Sprintf("Temperature %.1f°C", m.Value)
+	case domain.MeasurementTypeWeight:
+		return fmt.Sprintf("Weight %.0f g", m.Value)
+	case domain.MeasurementTypeDiaper:
+		if m.DiaperStatus != nil {
+			return fmt.Sprintf("Diaper (%s)", *m.DiaperStatus)
+		}
+		return "Diaper"
+	case domain.MeasurementTypeSleep:
+		return "Sleep"
+	case domain.MeasurementTypeHeight:
+		return fmt.Sprintf("Height %.1f cm", m.Value)
+	case domain.MeasurementTypeHeadCircumference:
+		return fmt.Sprintf("Head circumference %.1f cm", m.Value)
+	case domain.MeasurementTypeHeartRate:
+		return fmt.Sprintf("Heart rate %.0f bpm", m.Value)
+	case domain.MeasurementTypeSpO2:
+		return fmt.Sprintf("SpO2 %.0f%%", m.Value)
+	}
+	return m.Type
+}
+
+// icsTextEscaper escapes a TEXT value per RFC 5545 section 3.3.11
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeICSText escapes backslashes, separators and newlines in a TEXT property value
+func escapeICSText(value string) string {
+	return icsTextEscaper.Replace(value)
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it at 75 octets
+// Continuation lines start with a single space; folds never split a UTF-8 sequence
+func writeICSLine(b *strings.Builder, line string) {
+	const maxOctets = 75
+	limit := maxOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Boundary(line, cut) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the continuation line's length
+		limit = maxOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// isUTF8Boundary reports whether index i of s starts a UTF-8 sequence
+func isUTF8Boundary(s string, i int) bool {
+	return i >= len(s) || s[i]&0xC0 != 0x80
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+)
+
+// EnumsHandler serves the values clients need to build measurement forms
+type EnumsHandler struct {
+	noteTemplates domain.NoteTemplates
+
+	requestLogger
+}
+
+// NewEnumsHandler creates a new enums handler; noteTemplates may be nil when none are configured
+func NewEnumsHandler(noteTemplates domain.NoteTemplates) *EnumsHandler {
+	return &EnumsHandler{
+		noteTemplates: noteTemplates,
+	}
+}
+
+// EnumsResponse lists the accepted values of the measurement enums
+type EnumsResponse struct {
+	MeasurementTypes       []string                       `json:"measurement_types"`
+	SafetyStatuses         []domain.SafetyStatus          `json:"safety_statuses"`
+	BreastfeedingPositions []domain.BreastfeedingPosition `json:"breastfeeding_positions"`
+	BreastfeedingSides     []domain.BreastfeedingSide     `json:"breastfeeding_sides"`
+	SpitUpSeverities       []domain.SpitUpSeverity        `json:"spit_up_severities"`
+	DiaperStatuses         []domain.DiaperStatus          `json:"diaper_statuses"`
+	NoteTemplates          map[string]string              `json:"note_templates"` // Default note per type, for pre-filling the note field
+}
+
+// GetEnums handles GET /measurements/enums
+// Any authenticated role
+func (h *EnumsHandler) GetEnums(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRole := middleware.GetUserRole(r.Context())
+
+	noteTemplates := map[string]string{}
+	for measurementType, template := range h.noteTemplates {
+		noteTemplates[measurementType] = template
+	}
+
+	response := EnumsResponse{
+		MeasurementTypes:       domain.ValidMeasurementTypes(),
+		SafetyStatuses:         domain.ValidSafetyStatuses(),
+		BreastfeedingPositions: domain.ValidBreastfeedingPositions(),
+		BreastfeedingSides:     domain.ValidBreastfeedingSides(),
+		SpitUpSeverities:       domain.ValidSpitUpSeverities(),
+		DiaperStatuses:         domain.ValidDiaperStatuses(),
+		NoteTemplates:          noteTemplates,
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/measurements/enums", http.StatusOK, time.Since(startTime))
+
+	writeJSON(w, r, requestID, http.StatusOK, response)
+}
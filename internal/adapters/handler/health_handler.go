@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -15,19 +16,36 @@ import (
 // OpenShift compatible: /health, /health/ready, /health/live
 type HealthHandler struct {
 	db *sql.DB
+	// Broker connections checked by the readiness probe, keyed by component name
+	brokers map[string]ports.ConnectionChecker
 }
 
-// NewHealthHandler creates a new health handler
+// NewHealthHandler creates a new health handler that only checks the database
 func NewHealthHandler(db *sql.DB) *HealthHandler {
+	return NewHealthHandlerWithBrokers(db, nil)
+}
+
+// NewHealthHandlerWithBrokers creates a health handler whose readiness also requires
+// every broker connection (e.g. {"rabbitmq_publisher": publisher}) to be up
+func NewHealthHandlerWithBrokers(db *sql.DB, brokers map[string]ports.ConnectionChecker) *HealthHandler {
 	return &HealthHandler{
-		db: db,
+		db:      db,
+		brokers: brokers,
 	}
 }
 
+// Component statuses reported by the readiness probe
+const (
+	ComponentUp   = "up"
+	ComponentDown = "down"
+)
+
 // HealthResponse represents the health check response
+// Components is only set by the readiness probe
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
+	Status     string            `json:"status"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Components map[string]string `json:"components,omitempty"`
 }
 
 // Health handles GET /health - general health check
@@ -45,32 +63,37 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 }
 
 // Ready handles GET /health/ready - readiness probe
-// Checks database connectivity
+// Checks database connectivity and every broker connection; 503 if any component is down
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := h.db.PingContext(ctx)
-	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		response := HealthResponse{
-			Status:    "not ready",
-			Timestamp: time.Now(),
-		}
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			// Log error but don't fail health check
-			http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	components := map[string]string{"database": ComponentUp}
+	ready := true
+	if err := h.db.PingContext(ctx); err != nil {
+		components["database"] = ComponentDown
+		ready = false
+	}
+	for name, broker := range h.brokers {
+		components[name] = ComponentUp
+		if !broker.IsConnected() {
+			components[name] = ComponentDown
+			ready = false
 		}
-		return
 	}
 
 	response := HealthResponse{
-		Status:    "ready",
-		Timestamp: time.Now(),
+		Status:     "ready",
+		Timestamp:  time.Now(),
+		Components: components,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		response.Status = "not ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		// Log error but don't fail health check
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
@@ -1,11 +1,25 @@
 package handler
 
 import (
+	"context"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
 )
 
 // generateRequestID generates a unique request ID for tracing
@@ -18,30 +32,301 @@ func generateRequestID() string {
 	return hex.EncodeToString(b)
 }
 
-// logStructured logs structured JSON with request metadata
-// Includes: request_id, user_id, role, endpoint, status_code, duration
-func logStructured(requestID, userID string, isAdmin bool, method, endpoint string, statusCode int, duration time.Duration) {
-	role := "PARENT"
-	if isAdmin {
-		role = "ADMIN"
+// decodeErrorMessage is the 400 message for a request body that failed to decode
+// An empty body is reported as missing rather than as malformed JSON; otherwise invalid is returned
+func decodeErrorMessage(err error, invalid string) string {
+	if errors.Is(err, io.EOF) {
+		return "request body is required"
 	}
+	return invalid
+}
+
+// requestLogger writes the per-request log line of a handler
+// Embedded by the API handlers; the zero value logs to slog.Default()
+type requestLogger struct {
+	logger *slog.Logger
+}
 
-	logEntry := map[string]interface{}{
-		"request_id":  requestID,
-		"user_id":     userID,
-		"role":        role,
-		"method":      method,
-		"endpoint":    endpoint,
-		"status_code": statusCode,
-		"duration_ms": duration.Milliseconds(),
+// SetLogger replaces the logger the handler's request lines are written to
+func (l *requestLogger) SetLogger(logger *slog.Logger) {
+	l.logger = logger
+}
+
+// logStructured logs one request with its metadata as attributes
+// Includes: request_id, user_id, role, method, endpoint, status_code, duration_ms
+// Server errors are logged at error level, client errors at warn, everything else at info
+func (l *requestLogger) logStructured(requestID, userID string, role domain.Role, method, endpoint string, statusCode int, duration time.Duration) {
+	logger := l.logger
+	if logger == nil {
+		logger = slog.Default()
 	}
 
-	jsonBytes, err := json.Marshal(logEntry)
+	level := slog.LevelInfo
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case statusCode >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	logger.LogAttrs(context.Background(), level, "request completed",
+		slog.String("request_id", requestID),
+		slog.String("user_id", userID),
+		slog.String("role", string(role)),
+		slog.String("method", method),
+		slog.String("endpoint", endpoint),
+		slog.Int("status_code", statusCode),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+}
+
+// parseTimeWindow reads the from/to/tz query parameters of a report endpoint
+// from and to accept RFC3339 timestamps or YYYY-MM-DD dates interpreted in tz (IANA name, default UTC)
+// A date-only `to` is inclusive of that whole day; missing bounds default to the defaultSpan ending now
+func parseTimeWindow(r *http.Request, defaultSpan time.Duration) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	loc, err := parseLocation(r)
 	if err != nil {
-		log.Printf("[%s] Failed to marshal log entry: %v", requestID, err)
-		return
+		return time.Time{}, time.Time{}, err
 	}
 
-	log.Printf("%s", string(jsonBytes))
+	to := time.Now().In(loc)
+	if toParam := query.Get("to"); toParam != "" {
+		t, dateOnly, err := parseTimeParam(toParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to parameter: %s", toParam)
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultSpan)
+	if fromParam := query.Get("from"); fromParam != "" {
+		t, _, err := parseTimeParam(fromParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from parameter: %s", fromParam)
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
 }
 
+// parseOptionalTimeWindow reads optional from/to/tz query parameters; a missing bound is returned as nil
+// Values are parsed like parseTimeWindow, including the inclusive date-only `to`
+func parseOptionalTimeWindow(r *http.Request) (*time.Time, *time.Time, error) {
+	query := r.URL.Query()
+
+	loc, err := parseLocation(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var from, to *time.Time
+	if fromParam := query.Get("from"); fromParam != "" {
+		t, _, err := parseTimeParam(fromParam, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from parameter: %s", fromParam)
+		}
+		from = &t
+	}
+	if toParam := query.Get("to"); toParam != "" {
+		t, dateOnly, err := parseTimeParam(toParam, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to parameter: %s", toParam)
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = &t
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
+}
+
+// parseDay reads the date/tz query parameters of a daily endpoint
+// Returns midnight of the requested date (YYYY-MM-DD, default today) in tz (IANA name, default UTC)
+func parseDay(r *http.Request) (time.Time, error) {
+	loc, err := parseLocation(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseDateParam(r, "date", loc, time.Now().In(loc))
+}
+
+// parseDateParam reads a YYYY-MM-DD query parameter as midnight in loc
+// Falls back to the calendar day of def when the parameter is absent
+func parseDateParam(r *http.Request, name string, loc *time.Location, def time.Time) (time.Time, error) {
+	dateParam := r.URL.Query().Get(name)
+	if dateParam == "" {
+		dateParam = def.In(loc).Format("2006-01-02")
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", dateParam, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter (expected YYYY-MM-DD): %s", name, dateParam)
+	}
+	return day, nil
+}
+
+// parseLocation reads the tz query parameter (IANA name), defaulting to UTC
+func parseLocation(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz parameter: %s", tz)
+	}
+	return loc, nil
+}
+
+// parseTimeParam parses an RFC3339 timestamp or a YYYY-MM-DD date in loc
+// Reports whether the value was a date without a time component
+func parseTimeParam(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// encodeMeasurementCursor builds the opaque ?cursor= value pointing just past m
+func encodeMeasurementCursor(m *domain.Measurement) string {
+	return encodeCursor(m.Timestamp, m.ID)
+}
+
+// decodeMeasurementCursor parses a ?cursor= value produced by encodeMeasurementCursor
+func decodeMeasurementCursor(value string) (*ports.MeasurementCursor, error) {
+	timestamp, id, err := decodeCursor(value)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.MeasurementCursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// encodeAuditCursor builds the opaque ?cursor= value for an audit log position
+func encodeAuditCursor(c *ports.AuditCursor) string {
+	return encodeCursor(c.CreatedAt, c.ID)
+}
+
+// decodeAuditCursor parses a ?cursor= value produced by encodeAuditCursor
+func decodeAuditCursor(value string) (*ports.AuditCursor, error) {
+	createdAt, id, err := decodeCursor(value)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.AuditCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// encodeCursor builds an opaque keyset cursor for a (time DESC, id DESC) ordering
+// Format: base64url("<RFC3339Nano time>,<id>")
+func encodeCursor(t time.Time, id uuid.UUID) string {
+	raw := t.UTC().Format(time.RFC3339Nano) + "," + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor
+func decodeCursor(value string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("cursor is not valid base64: %w", err)
+	}
+
+	tsPart, idPart, found := strings.Cut(string(raw), ",")
+	if !found {
+		return time.Time{}, uuid.Nil, fmt.Errorf("cursor is missing the id")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, tsPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("cursor has an invalid timestamp: %w", err)
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("cursor has an invalid id: %w", err)
+	}
+
+	return t, id, nil
+}
+
+// responseEnvelope is the opt-in (?envelope=true) wrapper for successful responses
+type responseEnvelope struct {
+	Data interface{}  `json:"data"`
+	Meta envelopeMeta `json:"meta"`
+}
+
+// envelopeMeta carries request metadata, plus pagination details for lists
+type envelopeMeta struct {
+	RequestID  string          `json:"request_id"`
+	Pagination *paginationMeta `json:"pagination,omitempty"`
+}
+
+// paginationMeta describes the page of a list response
+type paginationMeta struct {
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"` // Omitted on the last page or when not paginating
+}
+
+// wantsEnvelope reports whether the client asked for enveloped responses via ?envelope=true
+// Bare responses stay the default for backward compatibility
+func wantsEnvelope(r *http.Request) bool {
+	envelope, err := strconv.ParseBool(r.URL.Query().Get("envelope"))
+	return err == nil && envelope
+}
+
+// writeJSON writes a successful JSON response, wrapped in the envelope when requested
+func writeJSON(w http.ResponseWriter, r *http.Request, requestID string, status int, data interface{}) {
+	writeResponse(w, r, requestID, status, data, nil)
+}
+
+// writeJSONList writes a successful list response; the envelope adds pagination meta
+// Empty lists are always encoded as [] inside the envelope
+func writeJSONList(w http.ResponseWriter, r *http.Request, requestID string, items interface{}, count int, nextCursor string) {
+	if v := reflect.ValueOf(items); wantsEnvelope(r) && (items == nil || (v.Kind() == reflect.Slice && v.IsNil())) {
+		items = []interface{}{}
+	}
+	writeResponse(w, r, requestID, http.StatusOK, items, &paginationMeta{Count: count, NextCursor: nextCursor})
+}
+
+// writeNoContent writes 204 No Content, or 200 with a null data envelope when requested
+func writeNoContent(w http.ResponseWriter, r *http.Request, requestID string) {
+	if !wantsEnvelope(r) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeResponse(w, r, requestID, http.StatusOK, nil, nil)
+}
+
+// writeResponse encodes data as JSON with the given status, wrapping it when ?envelope=true
+func writeResponse(w http.ResponseWriter, r *http.Request, requestID string, status int, data interface{}, pagination *paginationMeta) {
+	body := data
+	if wantsEnvelope(r) {
+		body = responseEnvelope{
+			Data: data,
+			Meta: envelopeMeta{RequestID: requestID, Pagination: pagination},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	}
+}
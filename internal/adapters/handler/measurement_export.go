@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// measurementCSVHeader is the header row of a measurement CSV export
+// Type-specific columns are left empty for measurements of other types
+var measurementCSVHeader = []string{
+	"timestamp", "type", "value", "safety_status", "note",
+	"feeding_type", "volume_ml", "position", "side", "left_duration", "right_duration", "duration",
+	"diaper_status", "sleep_start", "sleep_end", "spit_up", "spit_up_severity",
+}
+
+// ExportMeasurements handles GET /babies/{baby_id}/measurements/export
+// Query params: format (csv, the default and only format), from, to (RFC3339 or YYYY-MM-DD), tz
+// ADMIN: any baby, NURSE: any baby, PARENT: owned only
+func (h *MeasurementHandler) ExportMeasurements(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	if format := r.URL.Query().Get("format"); format != "" && format != "csv" {
+		http.Error(w, "invalid format parameter (must be csv)", http.StatusBadRequest)
+		return
+	}
+
+	// Optional date range, same params as the measurement list
+	var filter ports.MeasurementFilter
+	filter.From, filter.To, err = parseOptionalTimeWindow(r)
+	if err != nil {
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.StatementTimeout = h.config.ExportStatementTimeout
+
+	// The service enforces ownership and the visibility policy, as for the JSON list
+	measurements, err := h.measurementService.GetMeasurements(r.Context(), babyID, userID, userRole, filter)
+	if err != nil {
+		log.Printf("[%s] Failed to get measurements for export: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, userRole, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="measurements-`+babyID.String()+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	// Rows are streamed straight to the response; once the header is sent errors can only be logged
+	writer := csv.NewWriter(w)
+	if err := writer.Write(measurementCSVHeader); err != nil {
+		log.Printf("[%s] Failed to write CSV export: %v", requestID, err)
+		return
+	}
+	for _, m := range measurements {
+		if err := writer.Write(measurementCSVRow(m)); err != nil {
+			log.Printf("[%s] Failed to write CSV export: %v", requestID, err)
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("[%s] Failed to write CSV export: %v", requestID, err)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements/export", http.StatusOK, time.Since(startTime))
+}
+
+// measurementCSVRow renders a measurement in the column order of measurementCSVHeader
+func measurementCSVRow(m *domain.Measurement) []string {
+	row := []string{
+		m.Timestamp.UTC().Format(time.RFC3339),
+		m.Type,
+		strconv.FormatFloat(m.Value, 'f', -1, 64),
+		string(m.SafetyStatus),
+		m.Note,
+		string(m.FeedingType),
+		csvInt(m.VolumeML),
+		"",
+		"",
+		csvInt(m.LeftDuration),
+		csvInt(m.RightDuration),
+		csvInt(m.Duration),
+		"",
+		csvTime(m.SleepStart),
+		csvTime(m.SleepEnd),
+		csvBool(m.SpitUp),
+		"",
+	}
+	if m.Position != nil {
+		row[7] = string(*m.Position)
+	}
+	if m.Side != nil {
+		row[8] = string(*m.Side)
+	}
+	if m.DiaperStatus != nil {
+		row[12] = string(*m.DiaperStatus)
+	}
+	if m.SpitUpSeverity != nil {
+		row[16] = string(*m.SpitUpSeverity)
+	}
+	return row
+}
+
+// csvInt formats an optional integer column (empty when unset)
+func csvInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// csvBool formats an optional boolean column as true or false (empty when unset)
+func csvBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+// csvTime formats an optional timestamp column as RFC3339 UTC (empty when unset)
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -9,29 +11,67 @@ import (
 	"time"
 
 	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
 )
 
+// Window bounds for GET /babies/{baby_id}/feeding/hourly
+const (
+	defaultHourlyFeedingDays = 14
+	maxHourlyFeedingDays     = 90
+)
+
+// defaultTrendDays is the window of GET /babies/{baby_id}/measurements/{type}/trend without ?from=
+const defaultTrendDays = 30
+
+// defaultMeasurementPageSize is the page size for cursor-paginated lists without ?limit=
+const defaultMeasurementPageSize = 50
+
+// measurementPage is the wrapped response of a cursor-paginated measurement list
+// NextCursor is omitted on the last page
+type measurementPage struct {
+	Measurements interface{} `json:"measurements"`
+	NextCursor   string      `json:"next_cursor,omitempty"`
+}
+
 // MeasurementHandler handles HTTP requests for measurement operations
 type MeasurementHandler struct {
 	measurementService ports.MeasurementService
+	config             MeasurementHandlerConfig
+
+	requestLogger
+}
+
+// MeasurementHandlerConfig holds optional measurement handler settings
+type MeasurementHandlerConfig struct {
+	// ExportStatementTimeout replaces DB_STATEMENT_TIMEOUT for the CSV export query (0 keeps it)
+	// An export has no limit, so a baby with a long history can need longer than a paged list
+	ExportStatementTimeout time.Duration
 }
 
 // NewMeasurementHandler creates a new measurement handler
 func NewMeasurementHandler(measurementService ports.MeasurementService) *MeasurementHandler {
+	return NewMeasurementHandlerWithConfig(measurementService, MeasurementHandlerConfig{})
+}
+
+// NewMeasurementHandlerWithConfig creates a new measurement handler with optional settings
+func NewMeasurementHandlerWithConfig(measurementService ports.MeasurementService, config MeasurementHandlerConfig) *MeasurementHandler {
 	return &MeasurementHandler{
 		measurementService: measurementService,
+		config:             config,
 	}
 }
 
 // CreateMeasurementRequest represents the request body for creating a measurement
-// This matches the ports.CreateMeasurementRequest structure
+// This matches the ports.CreateMeasurementRequest structure; schema:"required" marks fields every type needs
 type CreateMeasurementRequest struct {
-	Type        string    `json:"type"`          // feeding, weight, temperature, diaper
+	Type        string    `json:"type" schema:"required"` // feeding, weight, temperature, diaper, sleep
 	Value       float64   `json:"value"`         // Numeric value (weight in grams, temperature in Celsius)
 	Note        string    `json:"note"`         // Optional contextual metadata
 	Timestamp   time.Time `json:"timestamp"`    // When the measurement was taken
+	DeviceID    string    `json:"device_id,omitempty"` // Optional external device ID
+	Metadata    json.RawMessage `json:"metadata,omitempty"` // Optional JSON object of deployment-specific fields
 	
 	// Feeding-specific fields
 	FeedingType     string   `json:"feeding_type,omitempty"`     // "bottle" or "breast"
@@ -41,19 +81,129 @@ type CreateMeasurementRequest struct {
 	LeftDuration    *int     `json:"left_duration,omitempty"`    // Duration in seconds for left side
 	RightDuration   *int     `json:"right_duration,omitempty"`  // Duration in seconds for right side
 	Duration        *int     `json:"duration,omitempty"`         // Total duration in seconds (for single side)
+	SpitUp          *bool    `json:"spit_up,omitempty"`          // Whether the baby spat up after the feeding
+	SpitUpSeverity  string   `json:"spit_up_severity,omitempty"` // "mild", "moderate", or "severe" (requires spit_up)
 	
 	// Temperature-specific fields
 	ValueCelsius    *float64 `json:"value_celsius,omitempty"`   // Temperature in Celsius
 	
+	// Weight-specific fields
+	ValueGrams      *float64 `json:"value_grams,omitempty"`     // Weight in grams
+	
 	// Diaper-specific fields
 	DiaperStatus    string   `json:"diaper_status,omitempty"`   // "dry", "wet", "dirty", or "both"
+	
+	// Sleep-specific fields
+	SleepStart      *time.Time `json:"sleep_start,omitempty"`   // When the baby fell asleep
+	SleepEnd        *time.Time `json:"sleep_end,omitempty"`     // When the baby woke up
+}
+
+// toServiceRequest converts the HTTP request body into the service-layer request
+func (req CreateMeasurementRequest) toServiceRequest() ports.CreateMeasurementRequest {
+	return ports.CreateMeasurementRequest{
+		Type:           req.Type,
+		Value:          req.Value,
+		Note:           req.Note,
+		Timestamp:      req.Timestamp,
+		DeviceID:       req.DeviceID,
+		Metadata:       req.Metadata,
+		FeedingType:    req.FeedingType,
+		VolumeML:       req.VolumeML,
+		Position:       req.Position,
+		Side:           req.Side,
+		LeftDuration:   req.LeftDuration,
+		RightDuration:  req.RightDuration,
+		Duration:       req.Duration,
+		SpitUp:         req.SpitUp,
+		SpitUpSeverity: req.SpitUpSeverity,
+		ValueCelsius:   req.ValueCelsius,
+		ValueGrams:     req.ValueGrams,
+		DiaperStatus:   req.DiaperStatus,
+		SleepStart:     req.SleepStart,
+		SleepEnd:       req.SleepEnd,
+	}
+}
+
+// measurementFields is the allowlist for ?fields= projection on measurement lists
+// Names match the JSON field names of domain.Measurement
+var measurementFields = map[string]bool{
+	"id": true, "parent_id": true, "baby_id": true, "type": true, "value": true,
+	"safety_status": true, "note": true, "device_id": true, "metadata": true, "timestamp": true, "created_at": true,
+	"feeding_type": true, "volume_ml": true, "position": true, "side": true,
+	"left_duration": true, "right_duration": true, "duration": true,
+	"spit_up": true, "spit_up_severity": true,
+	"value_celsius": true, "value_grams": true, "diaper_status": true,
+	"sleep_start": true, "sleep_end": true,
+	"display_value": true, "display_unit": true, "display_text": true,
+}
+
+// parseWeightUnitParam parses the optional ?unit= display unit for weights (default grams)
+func parseWeightUnitParam(r *http.Request) (domain.WeightUnit, error) {
+	unitParam := r.URL.Query().Get("unit")
+	if unitParam == "" {
+		return domain.WeightUnitGrams, nil
+	}
+	unit := domain.WeightUnit(unitParam)
+	if !domain.IsValidWeightUnit(unit) {
+		return "", fmt.Errorf("invalid unit parameter (must be g, lb or oz)")
+	}
+	return unit, nil
+}
+
+// parseFieldsParam parses a comma-separated ?fields= value against the allowlist
+// Returns nil when no projection was requested
+func parseFieldsParam(fieldsParam string) ([]string, error) {
+	if fieldsParam == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, f := range strings.Split(fieldsParam, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !measurementFields[f] {
+			return nil, fmt.Errorf("unknown field: %s", f)
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields parameter must list at least one field")
+	}
+	return fields, nil
+}
+
+// projectMeasurements keeps only the requested JSON fields of each measurement
+// Fields that are omitted for a measurement (omitempty) stay absent
+func projectMeasurements(measurements []*domain.Measurement, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(measurements))
+	for _, m := range measurements {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := all[f]; ok {
+				item[f] = v
+			}
+		}
+		projected = append(projected, item)
+	}
+	return projected, nil
 }
 
 // CreateMeasurement handles POST /babies/{baby_id}/measurements
 // PARENT: owned only (ADMIN cannot create measurements)
 // Response time < 2s
 func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -71,14 +221,14 @@ func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 	role, roleOk := middleware.GetRole(r.Context())
 	if !roleOk {
 		log.Printf("[%s] WARNING: CreateMeasurement - role not found in context for user_id=%s", requestID, userIDStr)
 		http.Error(w, "internal server error: missing role", http.StatusInternalServerError)
 		return
 	}
-	log.Printf("[%s] CreateMeasurement - user_id=%s, role=%s (len=%d), isAdmin=%v", requestID, userIDStr, role, len(role), isAdmin)
+	log.Printf("[%s] CreateMeasurement - user_id=%s, role=%s (len=%d)", requestID, userIDStr, role, len(role))
 
 	// Extract baby_id from URL path
 	babyIDStr := r.PathValue("baby_id")
@@ -89,11 +239,19 @@ func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Optional Idempotency-Key: retries with the same key return the original measurement
+	idempotencyKeys := r.Header.Values("Idempotency-Key")
+	if len(idempotencyKeys) > 1 || (len(idempotencyKeys) == 1 && !domain.IsValidIdempotencyKey(idempotencyKeys[0])) {
+		log.Printf("[%s] Invalid Idempotency-Key header", requestID)
+		http.Error(w, fmt.Sprintf("invalid Idempotency-Key header (1-%d visible ASCII characters)", domain.MaxIdempotencyKeyLength), http.StatusBadRequest)
+		return
+	}
+
 	// Parse request body
 	var req CreateMeasurementRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("[%s] Failed to decode request: %v", requestID, err)
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
 		return
 	}
 
@@ -103,30 +261,16 @@ func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Re
 	}
 
 	// Create measurement with full details (supports feeding, temperature, and diaper types)
-	measurement, err := h.measurementService.CreateMeasurementWithDetails(
-		r.Context(),
-		babyID,
-		ports.CreateMeasurementRequest{
-			Type:          req.Type,
-			Value:         req.Value,
-			Note:          req.Note,
-			Timestamp:     req.Timestamp,
-			FeedingType:   req.FeedingType,
-			VolumeML:      req.VolumeML,
-			Position:      req.Position,
-			Side:          req.Side,
-			LeftDuration:  req.LeftDuration,
-			RightDuration: req.RightDuration,
-			Duration:      req.Duration,
-			ValueCelsius:  req.ValueCelsius,
-			DiaperStatus:  req.DiaperStatus,
-		},
-		userID,
-		isAdmin,
-	)
+	var measurement *domain.Measurement
+	replayed := false
+	if len(idempotencyKeys) == 1 {
+		measurement, replayed, err = h.measurementService.CreateWithIdempotency(r.Context(), babyID, req.toServiceRequest(), userID, userRole, idempotencyKeys[0])
+	} else {
+		measurement, err = h.measurementService.CreateMeasurementWithDetails(r.Context(), babyID, req.toServiceRequest(), userID, userRole)
+	}
 	if err != nil {
 		roleStr, _ := middleware.GetRole(r.Context())
-		log.Printf("[%s] Failed to create measurement: user_id=%s, role=%s, isAdmin=%v, baby_id=%s, error=%v", requestID, userIDStr, roleStr, isAdmin, babyIDStr, err)
+		log.Printf("[%s] Failed to create measurement: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, roleStr, babyIDStr, err)
 		if err.Error() == "baby not found" {
 			http.Error(w, "baby not found", http.StatusNotFound)
 			return
@@ -135,25 +279,110 @@ func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Re
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
+		if errors.Is(err, domain.ErrConflict) {
+			http.Error(w, "measurement already exists", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrTooFrequent) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, domain.ErrAlertUnavailable) {
+			http.Error(w, domain.ErrAlertUnavailable.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// A replay returns the original 201 response; the header lets clients tell it apart
+	if replayed {
+		log.Printf("[%s] Idempotent replay: user_id=%s, measurement_id=%s", requestID, userIDStr, measurement.ID)
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "POST", "/babies/"+babyIDStr+"/measurements", http.StatusCreated, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies/"+babyIDStr+"/measurements", http.StatusCreated, time.Since(startTime))
 
 	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(measurement); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	writeJSON(w, r, requestID, http.StatusCreated, measurement)
+}
+
+// ValidateMeasurement handles POST /babies/{baby_id}/measurements/validate
+// PARENT: owned only - runs all creation rules without inserting (dry-run)
+// Returns 200 with the computed safety_status, or the field errors when invalid
+func (h *MeasurementHandler) ValidateMeasurement(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req CreateMeasurementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	// Validate measurement without creating it
+	result, err := h.measurementService.ValidateMeasurement(r.Context(), babyID, req.toServiceRequest(), userID, userRole)
+	if err != nil {
+		roleStr, _ := middleware.GetRole(r.Context())
+		log.Printf("[%s] Failed to validate measurement: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, roleStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "forbidden: only PARENT can create measurements" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/babies/"+babyIDStr+"/measurements/validate", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, result)
 }
 
 // GetMeasurements handles GET /babies/{baby_id}/measurements
+// Query params: type, device_id, from, to, tz, limit, fields, unit (g, lb or oz for weights), cursor (opaque; presence switches to a {measurements, next_cursor} page)
+// from/to accept RFC3339 or YYYY-MM-DD (a date-only to includes that whole day)
 // ADMIN: any baby, PARENT: owned only
 func (h *MeasurementHandler) GetMeasurements(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -171,7 +400,7 @@ func (h *MeasurementHandler) GetMeasurements(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 
 	// Extract baby_id from URL path
 	babyIDStr := r.PathValue("baby_id")
@@ -183,11 +412,22 @@ func (h *MeasurementHandler) GetMeasurements(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Parse query parameters for filtering
-	var measurementType *string
-	var limit *int
+	var filter ports.MeasurementFilter
 
 	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
-		measurementType = &typeParam
+		filter.Type = &typeParam
+	}
+
+	if deviceParam := r.URL.Query().Get("device_id"); deviceParam != "" {
+		filter.DeviceID = &deviceParam
+	}
+
+	// Optional time window, e.g. a shift from 8am to 8pm
+	filter.From, filter.To, err = parseOptionalTimeWindow(r)
+	if err != nil {
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
@@ -197,14 +437,51 @@ func (h *MeasurementHandler) GetMeasurements(w http.ResponseWriter, r *http.Requ
 			http.Error(w, "invalid limit parameter (must be positive integer)", http.StatusBadRequest)
 			return
 		}
-		limit = &limitInt
+		filter.Limit = &limitInt
+	}
+
+	// Cursor pagination: the presence of ?cursor= (empty for the first page) switches to the wrapped response
+	_, paginate := r.URL.Query()["cursor"]
+	pageSize := defaultMeasurementPageSize
+	if paginate {
+		if cursorParam := r.URL.Query().Get("cursor"); cursorParam != "" {
+			cursor, err := decodeMeasurementCursor(cursorParam)
+			if err != nil {
+				log.Printf("[%s] Invalid cursor parameter: %v", requestID, err)
+				http.Error(w, "invalid cursor parameter", http.StatusBadRequest)
+				return
+			}
+			filter.Before = cursor
+		}
+		if filter.Limit != nil {
+			pageSize = *filter.Limit
+		}
+		// Fetch one extra row to know whether another page exists
+		fetch := pageSize + 1
+		filter.Limit = &fetch
+	}
+
+	// Optional response projection (e.g. ?fields=timestamp,value)
+	fields, err := parseFieldsParam(r.URL.Query().Get("fields"))
+	if err != nil {
+		log.Printf("[%s] Invalid fields parameter: %v", requestID, err)
+		http.Error(w, "invalid fields parameter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Optional display unit for weights; stored grams are returned unchanged
+	unit, err := parseWeightUnitParam(r)
+	if err != nil {
+		log.Printf("[%s] Invalid unit parameter: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Get measurements with optional filters
-	measurements, err := h.measurementService.GetMeasurements(r.Context(), babyID, userID, isAdmin, measurementType, limit)
+	measurements, err := h.measurementService.GetMeasurements(r.Context(), babyID, userID, userRole, filter)
 	if err != nil {
 		roleStr, _ := middleware.GetRole(r.Context())
-		log.Printf("[%s] Failed to get measurements: user_id=%s, role=%s, isAdmin=%v, baby_id=%s, error=%v", requestID, userIDStr, roleStr, isAdmin, babyIDStr, err)
+		log.Printf("[%s] Failed to get measurements: user_id=%s, role=%s, baby_id=%s, error=%v", requestID, userIDStr, roleStr, babyIDStr, err)
 		if err.Error() == "baby not found" {
 			http.Error(w, "baby not found", http.StatusNotFound)
 			return
@@ -213,20 +490,48 @@ func (h *MeasurementHandler) GetMeasurements(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	var nextCursor string
+	if paginate {
+		if measurements == nil {
+			measurements = []*domain.Measurement{}
+		}
+		if len(measurements) > pageSize {
+			measurements = measurements[:pageSize]
+			nextCursor = encodeMeasurementCursor(measurements[pageSize-1])
+		}
+	}
+
+	for _, m := range measurements {
+		m.ApplyWeightUnit(unit)
+	}
+
+	var items interface{} = measurements
+	if fields != nil {
+		projected, err := projectMeasurements(measurements, fields)
+		if err != nil {
+			log.Printf("[%s] Failed to project measurements: %v", requestID, err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		items = projected
+	}
+
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "GET", "/babies/"+babyIDStr+"/measurements", http.StatusOK, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements", http.StatusOK, time.Since(startTime))
 
-	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(measurements); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	// Return response - the envelope carries the cursor in its meta instead of a page wrapper
+	if paginate && !wantsEnvelope(r) {
+		writeJSON(w, r, requestID, http.StatusOK, measurementPage{Measurements: items, NextCursor: nextCursor})
+		return
 	}
+	writeJSONList(w, r, requestID, items, len(measurements), nextCursor)
 }
 
-// GetMeasurementByID handles GET /measurements/{measurement_id}
-// ADMIN: any measurement, PARENT: owned only
-func (h *MeasurementHandler) GetMeasurementByID(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+// GetFeedingBalance handles GET /babies/{baby_id}/feeding/balance
+// Query params: from, to (RFC3339 or YYYY-MM-DD), tz (IANA name, default UTC); defaults to the last 7 days
+// ADMIN: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetFeedingBalance(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -244,45 +549,127 @@ func (h *MeasurementHandler) GetMeasurementByID(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 
-	// Extract measurement_id from URL path
-	measurementIDStr := r.PathValue("measurement_id")
-	measurementID, err := uuid.Parse(measurementIDStr)
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
 	if err != nil {
-		log.Printf("[%s] Invalid measurement ID: %v", requestID, err)
-		http.Error(w, "invalid measurement ID", http.StatusBadRequest)
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
 		return
 	}
 
-	// Get measurement
-	measurement, err := h.measurementService.GetMeasurementByID(r.Context(), measurementID, userID, isAdmin)
+	from, to, err := parseTimeWindow(r, 7*24*time.Hour)
 	if err != nil {
-		roleStr, _ := middleware.GetRole(r.Context())
-		log.Printf("[%s] Failed to get measurement: user_id=%s, role=%s, isAdmin=%v, measurement_id=%s, error=%v", requestID, userIDStr, roleStr, isAdmin, measurementIDStr, err)
-		errStr := err.Error()
-		if errStr == "measurement not found" || strings.Contains(errStr, "measurement not found") {
-			http.Error(w, "measurement not found", http.StatusNotFound)
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	balance, err := h.measurementService.GetFeedingBalance(r.Context(), babyID, userID, userRole, from, to)
+	if err != nil {
+		log.Printf("[%s] Failed to get feeding balance: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err.Error() == "forbidden: feeding measurements are not visible to this role" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "GET", "/measurements/"+measurementIDStr, http.StatusOK, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/feeding/balance", http.StatusOK, time.Since(startTime))
 
 	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(measurement); err != nil {
-		log.Printf("[%s] Failed to encode response: %v", requestID, err)
+	writeJSON(w, r, requestID, http.StatusOK, balance)
+}
+
+// CompareFeeding handles GET /babies/{baby_id}/feeding/compare
+// Query params: day1 (YYYY-MM-DD, default yesterday), day2 (YYYY-MM-DD, default today), tz (IANA name, default UTC)
+// ADMIN/NURSE: any baby, PARENT: owned only
+func (h *MeasurementHandler) CompareFeeding(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
 	}
+
+	loc, err := parseLocation(r)
+	if err != nil {
+		log.Printf("[%s] Invalid timezone: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().In(loc)
+	day2, err := parseDateParam(r, "day2", loc, now)
+	if err != nil {
+		log.Printf("[%s] Invalid day2: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	day1, err := parseDateParam(r, "day1", loc, day2.AddDate(0, 0, -1))
+	if err != nil {
+		log.Printf("[%s] Invalid day1: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.measurementService.CompareFeedingDays(r.Context(), babyID, userID, userRole, day1, day2)
+	if err != nil {
+		log.Printf("[%s] Failed to compare feeding days: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "forbidden: feeding measurements are not visible to this role" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/feeding/compare", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, comparison)
 }
 
-// DeleteMeasurement handles DELETE /measurements/{measurement_id}
-// PARENT: only measurements they created (ADMIN cannot delete measurements)
-func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now()
+// GetHourlyFeeding handles GET /babies/{baby_id}/feeding/hourly
+// Query params: days (1-90, default 14), tz (IANA name, default UTC)
+// ADMIN: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetHourlyFeeding(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
 	requestID := generateRequestID()
 
 	// Extract user info from context
@@ -300,38 +687,703 @@ func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	isAdmin := middleware.IsAdmin(r.Context())
+	userRole := middleware.GetUserRole(r.Context())
 
-	// Extract measurement_id from URL path
-	measurementIDStr := r.PathValue("measurement_id")
-	measurementID, err := uuid.Parse(measurementIDStr)
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
 	if err != nil {
-		log.Printf("[%s] Invalid measurement ID: %v", requestID, err)
-		http.Error(w, "invalid measurement ID", http.StatusBadRequest)
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
 		return
 	}
 
-	// Delete measurement
-	err = h.measurementService.DeleteMeasurement(r.Context(), measurementID, userID, isAdmin)
+	days := defaultHourlyFeedingDays
+	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days < 1 || days > maxHourlyFeedingDays {
+			log.Printf("[%s] Invalid days parameter: %s", requestID, daysParam)
+			http.Error(w, fmt.Sprintf("invalid days parameter (expected 1-%d)", maxHourlyFeedingDays), http.StatusBadRequest)
+			return
+		}
+	}
+
+	loc, err := parseLocation(r)
 	if err != nil {
-		roleStr, _ := middleware.GetRole(r.Context())
-		log.Printf("[%s] Failed to delete measurement: user_id=%s, role=%s, isAdmin=%v, measurement_id=%s, error=%v", requestID, userIDStr, roleStr, isAdmin, measurementIDStr, err)
-		if err.Error() == "measurement not found" {
-			http.Error(w, "measurement not found", http.StatusNotFound)
+		log.Printf("[%s] Invalid timezone: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to := time.Now().In(loc)
+	from := to.AddDate(0, 0, -days)
+
+	distribution, err := h.measurementService.GetHourlyFeedingDistribution(r.Context(), babyID, userID, userRole, from, to, loc)
+	if err != nil {
+		log.Printf("[%s] Failed to get hourly feeding distribution: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
 			return
 		}
-		if err.Error() == "forbidden: only PARENT can delete measurements" {
+		if err.Error() == "forbidden: feeding measurements are not visible to this role" {
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	// Log structured JSON
-	logStructured(requestID, userIDStr, isAdmin, "DELETE", "/measurements/"+measurementIDStr, http.StatusNoContent, time.Since(startTime))
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/feeding/hourly", http.StatusOK, time.Since(startTime))
 
-	// Return success response
-	w.WriteHeader(http.StatusNoContent)
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, distribution)
 }
 
+// GetMeasurementStats handles GET /babies/{baby_id}/measurements/stats
+// Query params: from, to (RFC3339 or YYYY-MM-DD, both optional), tz (IANA name, default UTC)
+// Returns per-type count, min/max/avg value and last timestamp; [] when the baby has no measurements
+// ADMIN: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetMeasurementStats(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseOptionalTimeWindow(r)
+	if err != nil {
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.measurementService.GetMeasurementStats(r.Context(), babyID, userID, userRole, from, to)
+	if err != nil {
+		log.Printf("[%s] Failed to get measurement stats: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements/stats", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSONList(w, r, requestID, stats, len(stats), "")
+}
+
+// GetMeasurementTrend handles GET /babies/{baby_id}/measurements/{type}/trend
+// Query params: bucket (hour, day or week, default day), from, to (RFC3339 or YYYY-MM-DD, default the last 30 days),
+// tz (IANA name, default UTC)
+// Only temperature, weight, height and head_circumference have trends
+// ADMIN/NURSE: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetMeasurementTrend(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id and type from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+	measurementType := r.PathValue("type")
+
+	bucket := domain.TrendBucketDay
+	if bucketParam := r.URL.Query().Get("bucket"); bucketParam != "" {
+		bucket = domain.TrendBucket(bucketParam)
+	}
+
+	loc, err := parseLocation(r)
+	if err != nil {
+		log.Printf("[%s] Invalid timezone: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fromParam, toParam, err := parseOptionalTimeWindow(r)
+	if err != nil {
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to := time.Now().In(loc)
+	if toParam != nil {
+		to = *toParam
+	}
+	from := to.AddDate(0, 0, -defaultTrendDays)
+	if fromParam != nil {
+		from = *fromParam
+	}
+
+	trend, err := h.measurementService.GetMeasurementTrend(r.Context(), babyID, userID, userRole, measurementType, bucket, from, to, loc)
+	if err != nil {
+		log.Printf("[%s] Failed to get measurement trend: user_id=%s, baby_id=%s, type=%s, error=%v", requestID, userIDStr, babyIDStr, measurementType, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "forbidden") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid trend type") || strings.HasPrefix(err.Error(), "invalid bucket") ||
+			strings.HasPrefix(err.Error(), "time window too large") || err.Error() == "from must be before to" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements/"+measurementType+"/trend", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, trend)
+}
+
+// GetLatestMeasurements handles GET /babies/{baby_id}/measurements/latest
+// Returns an object keyed by type with the newest measurement of each ({} when there are none)
+// Query params: unit (g, lb or oz for weights, default g)
+// ADMIN/NURSE: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetLatestMeasurements(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	unit, err := parseWeightUnitParam(r)
+	if err != nil {
+		log.Printf("[%s] Invalid unit parameter: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	latest, err := h.measurementService.GetLatestMeasurements(r.Context(), babyID, userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to get latest measurements: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if latest == nil {
+		latest = map[string]*domain.Measurement{}
+	}
+	for _, m := range latest {
+		m.ApplyWeightUnit(unit)
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements/latest", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, latest)
+}
+
+// GetWardOverview handles GET /wards/{room_prefix}/overview
+// ADMIN/NURSE only - latest value of each type and worst recent status for every baby in the ward
+func (h *MeasurementHandler) GetWardOverview(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+	roomPrefix := r.PathValue("room_prefix")
+
+	overview, err := h.measurementService.GetWardOverview(r.Context(), roomPrefix, userID, userRole)
+	if err != nil {
+		log.Printf("[%s] Failed to get ward overview: user_id=%s, role=%s, room_prefix=%s, error=%v", requestID, userIDStr, userRole, roomPrefix, err)
+		if strings.HasPrefix(err.Error(), "forbidden") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "room_prefix must be") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/wards/"+roomPrefix+"/overview", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, overview)
+}
+
+// GetSafetyStatusDistribution handles GET /babies/{baby_id}/measurements/status-distribution
+// Query params: type (optional), from/to (optional RFC3339 or YYYY-MM-DD, tz for dates)
+// ADMIN/NURSE: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetSafetyStatusDistribution(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseOptionalTimeWindow(r)
+	if err != nil {
+		log.Printf("[%s] Invalid time window: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := ports.MeasurementFilter{From: from, To: to}
+	if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+		filter.Type = &typeParam
+	}
+
+	distribution, err := h.measurementService.GetSafetyStatusDistribution(r.Context(), babyID, userID, userRole, filter)
+	if err != nil {
+		log.Printf("[%s] Failed to get safety status distribution: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid measurement type filter") || err.Error() == "from must be before to" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/measurements/status-distribution", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, distribution)
+}
+
+// GetDailyReport handles GET /babies/{baby_id}/daily-report
+// Query params: date (YYYY-MM-DD, default today), tz (IANA name, default UTC)
+// ADMIN: any baby, PARENT: owned only
+func (h *MeasurementHandler) GetDailyReport(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract baby_id from URL path
+	babyIDStr := r.PathValue("baby_id")
+	babyID, err := uuid.Parse(babyIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid baby ID: %v", requestID, err)
+		http.Error(w, "invalid baby ID", http.StatusBadRequest)
+		return
+	}
+
+	day, err := parseDay(r)
+	if err != nil {
+		log.Printf("[%s] Invalid report date: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.measurementService.GetDailyReport(r.Context(), babyID, userID, userRole, day)
+	if err != nil {
+		log.Printf("[%s] Failed to get daily report: user_id=%s, baby_id=%s, error=%v", requestID, userIDStr, babyIDStr, err)
+		if err.Error() == "baby not found" {
+			http.Error(w, "baby not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/babies/"+babyIDStr+"/daily-report", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, report)
+}
+
+// GetMeasurementByID handles GET /measurements/{measurement_id}
+// Query params: unit (g, lb or oz for weights, default g)
+// ADMIN: any measurement, PARENT: owned only
+func (h *MeasurementHandler) GetMeasurementByID(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract measurement_id from URL path
+	measurementIDStr := r.PathValue("measurement_id")
+	measurementID, err := uuid.Parse(measurementIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid measurement ID: %v", requestID, err)
+		http.Error(w, "invalid measurement ID", http.StatusBadRequest)
+		return
+	}
+
+	unit, err := parseWeightUnitParam(r)
+	if err != nil {
+		log.Printf("[%s] Invalid unit parameter: %v", requestID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Get measurement
+	measurement, err := h.measurementService.GetMeasurementByID(r.Context(), measurementID, userID, userRole)
+	if err != nil {
+		roleStr, _ := middleware.GetRole(r.Context())
+		log.Printf("[%s] Failed to get measurement: user_id=%s, role=%s, measurement_id=%s, error=%v", requestID, userIDStr, roleStr, measurementIDStr, err)
+		errStr := err.Error()
+		if errStr == "measurement not found" || strings.Contains(errStr, "measurement not found") {
+			http.Error(w, "measurement not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	measurement.ApplyWeightUnit(unit)
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/measurements/"+measurementIDStr, http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, measurement)
+}
+
+// UpdateMeasurement handles PATCH /measurements/{measurement_id}
+// PARENT: only measurements they created (ADMIN cannot update measurements)
+// Only note and timestamp can be changed; any other field is rejected
+func (h *MeasurementHandler) UpdateMeasurement(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract measurement_id from URL path
+	measurementIDStr := r.PathValue("measurement_id")
+	measurementID, err := uuid.Parse(measurementIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid measurement ID: %v", requestID, err)
+		http.Error(w, "invalid measurement ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body - unknown fields (e.g. type, value) are not editable
+	var req ports.UpdateMeasurementRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body: only note and timestamp can be updated"), http.StatusBadRequest)
+		return
+	}
+
+	// Update measurement
+	measurement, err := h.measurementService.UpdateMeasurement(r.Context(), measurementID, req, userID, userRole)
+	if err != nil {
+		roleStr, _ := middleware.GetRole(r.Context())
+		log.Printf("[%s] Failed to update measurement: user_id=%s, role=%s, measurement_id=%s, error=%v", requestID, userIDStr, roleStr, measurementIDStr, err)
+		if err.Error() == "measurement not found" {
+			http.Error(w, "measurement not found", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "forbidden: only PARENT can update measurements" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "failed to") {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "PATCH", "/measurements/"+measurementIDStr, http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, measurement)
+}
+
+// DeleteMeasurement handles DELETE /measurements/{measurement_id}
+// PARENT: only measurements they created (ADMIN cannot delete measurements)
+// Query params: return (minimal: 204 without body, the default; representation: 200 with the deleted measurement)
+func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid user ID: %v", requestID, err)
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Extract measurement_id from URL path
+	measurementIDStr := r.PathValue("measurement_id")
+	measurementID, err := uuid.Parse(measurementIDStr)
+	if err != nil {
+		log.Printf("[%s] Invalid measurement ID: %v", requestID, err)
+		http.Error(w, "invalid measurement ID", http.StatusBadRequest)
+		return
+	}
+
+	returnRepresentation := false
+	switch r.URL.Query().Get("return") {
+	case "", "minimal":
+	case "representation":
+		returnRepresentation = true
+	default:
+		http.Error(w, "invalid return parameter (must be minimal or representation)", http.StatusBadRequest)
+		return
+	}
+
+	// Delete measurement
+	deleted, err := h.measurementService.DeleteMeasurement(r.Context(), measurementID, userID, userRole)
+	if err != nil {
+		roleStr, _ := middleware.GetRole(r.Context())
+		log.Printf("[%s] Failed to delete measurement: user_id=%s, role=%s, measurement_id=%s, error=%v", requestID, userIDStr, roleStr, measurementIDStr, err)
+		if err.Error() == "measurement not found" {
+			http.Error(w, "measurement not found", http.StatusNotFound)
+			return
+		}
+		if err.Error() == "forbidden: only PARENT can delete measurements" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if returnRepresentation {
+		h.logStructured(requestID, userIDStr, userRole, "DELETE", "/measurements/"+measurementIDStr, http.StatusOK, time.Since(startTime))
+		writeJSON(w, r, requestID, http.StatusOK, deleted)
+		return
+	}
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "DELETE", "/measurements/"+measurementIDStr, http.StatusNoContent, time.Since(startTime))
+
+	// Return success response
+	writeNoContent(w, r, requestID)
+}
+
+// BackfillSafetyStatus handles POST /admin/measurements/backfill-status
+// Recomputes safety_status for rows with a NULL or non-canonical status and reports the counts
+// Query params: batch_size (1-1000, default 500)
+// ADMIN only
+func (h *MeasurementHandler) BackfillSafetyStatus(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// 0 lets the service use its default batch size
+	batchSize := 0
+	if batchParam := r.URL.Query().Get("batch_size"); batchParam != "" {
+		parsed, err := strconv.Atoi(batchParam)
+		if err != nil {
+			log.Printf("[%s] Invalid batch_size parameter: %s", requestID, batchParam)
+			http.Error(w, "invalid batch_size parameter (must be an integer)", http.StatusBadRequest)
+			return
+		}
+		batchSize = parsed
+	}
+
+	result, err := h.measurementService.BackfillSafetyStatus(r.Context(), userRole, batchSize)
+	if err != nil {
+		log.Printf("[%s] Failed to backfill safety status: user_id=%s, error=%v", requestID, userIDStr, err)
+		if strings.HasPrefix(err.Error(), "forbidden") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "batch_size must be") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[%s] Safety status backfill: updated=%d, batches=%d, green=%d, yellow=%d, red=%d",
+		requestID, result.Updated, result.Batches, result.Green, result.Yellow, result.Red)
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/admin/measurements/backfill-status", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, result)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+)
+
+// DefaultRevocationTTL is how long a JTI stays revoked when the request gives no until
+// Long enough for any token issued before the revocation to have expired
+const DefaultRevocationTTL = 24 * time.Hour
+
+// RevocationHandler handles HTTP requests for revoking JWTs before they expire
+type RevocationHandler struct {
+	authMiddleware *middleware.AuthMiddleware
+
+	requestLogger
+}
+
+// NewRevocationHandler creates a new revocation handler for the middleware's denylist
+func NewRevocationHandler(authMiddleware *middleware.AuthMiddleware) *RevocationHandler {
+	return &RevocationHandler{
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RevokeTokenRequest represents the request body for revoking a token
+type RevokeTokenRequest struct {
+	JTI   string     `json:"jti"`             // JWT ID of the token to revoke
+	Until *time.Time `json:"until,omitempty"` // When the revocation lapses, normally the token's exp (default 24h)
+}
+
+// RevokeTokenResponse confirms a revocation
+type RevokeTokenResponse struct {
+	JTI   string    `json:"jti"`
+	Until time.Time `json:"until"`
+}
+
+// RevokeToken handles POST /admin/revoke
+// ADMIN only - e.g. when the identity service deactivates a user whose token is still valid
+func (h *RevocationHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+	if userRole != domain.RoleAdmin {
+		log.Printf("[%s] Forbidden token revocation: user_id=%s, role=%s", requestID, userIDStr, userRole)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Parse request body
+	var req RevokeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[%s] Failed to decode request: %v", requestID, err)
+		http.Error(w, decodeErrorMessage(err, "invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	req.JTI = strings.TrimSpace(req.JTI)
+	if req.JTI == "" {
+		http.Error(w, "jti is required", http.StatusBadRequest)
+		return
+	}
+
+	until := time.Now().Add(DefaultRevocationTTL)
+	if req.Until != nil {
+		if !req.Until.After(time.Now()) {
+			http.Error(w, "until must be in the future", http.StatusBadRequest)
+			return
+		}
+		until = *req.Until
+	}
+
+	h.authMiddleware.RevokeJTI(req.JTI, until)
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "POST", "/admin/revoke", http.StatusOK, time.Since(startTime))
+
+	// Return response
+	writeJSON(w, r, requestID, http.StatusOK, RevokeTokenResponse{JTI: req.JTI, Until: until.UTC()})
+}
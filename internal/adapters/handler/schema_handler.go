@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// jsonSchemaDialect is the JSON Schema version GET /schema documents are written in
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaDocument is the response of GET /schema: one JSON Schema per request and response body
+type SchemaDocument struct {
+	Schema string                            `json:"$schema"`
+	Defs   map[string]map[string]interface{} `json:"$defs"`
+}
+
+// SchemaHandler serves JSON Schemas of the API's request and response bodies
+// The schemas are generated from the DTOs' json tags, so they can't drift from what the handlers decode
+type SchemaHandler struct {
+	document SchemaDocument
+
+	requestLogger
+}
+
+// NewSchemaHandler creates a new schema handler, generating the schemas once
+func NewSchemaHandler() *SchemaHandler {
+	// Enum constraints by JSON property name, from the same helpers the services validate against
+	enums := map[string]interface{}{
+		"type":             domain.ValidMeasurementTypes(),
+		"safety_status":    domain.ValidSafetyStatuses(),
+		"feeding_type":     domain.ValidFeedingTypes(),
+		"position":         domain.ValidBreastfeedingPositions(),
+		"side":             domain.ValidBreastfeedingSides(),
+		"spit_up_severity": domain.ValidSpitUpSeverities(),
+		"diaper_status":    domain.ValidDiaperStatuses(),
+	}
+
+	document := SchemaDocument{
+		Schema: jsonSchemaDialect,
+		Defs: map[string]map[string]interface{}{
+			"CreateBabyRequest":        objectSchema(reflect.TypeOf(CreateBabyRequest{}), enums),
+			"UpdateBabyRequest":        objectSchema(reflect.TypeOf(UpdateBabyRequest{}), enums),
+			"CreateMeasurementRequest": objectSchema(reflect.TypeOf(CreateMeasurementRequest{}), enums),
+			"UpdateMeasurementRequest": objectSchema(reflect.TypeOf(ports.UpdateMeasurementRequest{}), enums),
+			"Baby":                     objectSchema(reflect.TypeOf(domain.Baby{}), enums),
+			"Measurement":              objectSchema(reflect.TypeOf(domain.Measurement{}), enums),
+		},
+	}
+	// PATCH /measurements/{measurement_id} is the only body decoded with unknown fields rejected
+	document.Defs["UpdateMeasurementRequest"]["additionalProperties"] = false
+
+	return &SchemaHandler{
+		document: document,
+	}
+}
+
+// GetSchema handles GET /schema
+// Any authenticated role
+func (h *SchemaHandler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	startTime := domain.RequestStart(r.Context())
+	requestID := generateRequestID()
+
+	// Extract user info from context
+	userIDStr, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		log.Printf("[%s] Failed to get user ID from context", requestID)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Log structured JSON
+	h.logStructured(requestID, userIDStr, userRole, "GET", "/schema", http.StatusOK, time.Since(startTime))
+
+	writeJSON(w, r, requestID, http.StatusOK, h.document)
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// objectSchema builds the JSON Schema of a struct from its json tags
+// Fields tagged schema:"required" are listed as required; enums constrains properties by name
+func objectSchema(t reflect.Type, enums map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := typeSchema(field.Type, enums)
+		if values, ok := enums[name]; ok {
+			property["enum"] = values
+		}
+		properties[name] = property
+
+		if field.Tag.Get("schema") == "required" {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// typeSchema maps a Go field type to its JSON Schema; pointers are optional values of the pointed-to type
+func typeSchema(t reflect.Type, enums map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	case rawMessageType:
+		return map[string]interface{}{"type": "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), enums)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object"}
+	case reflect.Struct:
+		return objectSchema(t, enums)
+	default:
+		return map[string]interface{}{}
+	}
+}
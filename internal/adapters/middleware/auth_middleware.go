@@ -3,41 +3,80 @@ package middleware
 import (
 	"context"
 	"crypto/rsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // cacheEntry stores cached JWT claims keyed by JTI (JWT ID)
+// exp is when the entry expires: the token's exp, or earlier when a max cache TTL is set
 type cacheEntry struct {
 	claims jwt.MapClaims
 	exp    int64
 }
 
 // AuthMiddleware handles JWT validation and RBAC enforcement
-// Validates tokens signed by Identity Service using mounted public key
+// Validates tokens signed by Identity Service using mounted public keys
 // Uses JTI-based caching for performance optimization
 type AuthMiddleware struct {
-	publicKey *rsa.PublicKey
+	// Trusted signing keys by key ID (the JWT kid header); "" holds a key without a known ID
+	publicKeys map[string]*rsa.PublicKey
 	// L1 cache: in-memory cache keyed by JTI (JWT ID) for fast lookups
 	cache sync.Map
+	// Upper bound on how long claims stay cached (0 caches until token expiry)
+	maxCacheTTL time.Duration
+	// Tolerance for clock drift between the Identity Service and this service on exp and nbf
+	leeway time.Duration
+	// Revoked JTIs mapped to the unix time their revocation lapses (the token's expiry at the latest)
+	denylist sync.Map
 	// Background janitor for cache cleanup
 	janitorStop chan bool
 }
 
 const CacheCleanupInterval = 10 * time.Minute
 
+// DefaultClockSkewLeeway is how far past exp (or before nbf) a token is still accepted when not configured
+const DefaultClockSkewLeeway = 30 * time.Second
+
+// ErrTokenRevoked is returned for a token whose JTI was revoked with RevokeJTI
+var ErrTokenRevoked = errors.New("token revoked")
+
 // NewAuthMiddleware creates a new JWT authentication middleware
 // publicKey: RSA public key from Identity Service (mounted via ConfigMap)
+// Cached claims are reused until the token expires
 func NewAuthMiddleware(publicKey *rsa.PublicKey) *AuthMiddleware {
+	return NewAuthMiddlewareWithCacheTTL(publicKey, 0)
+}
+
+// NewAuthMiddlewareWithCacheTTL creates a JWT middleware whose cached claims expire at min(token exp + leeway, now + maxCacheTTL)
+// Bounds how long a revocation or role change can go unnoticed for long-lived tokens (0 disables the bound)
+func NewAuthMiddlewareWithCacheTTL(publicKey *rsa.PublicKey, maxCacheTTL time.Duration) *AuthMiddleware {
+	publicKeys := map[string]*rsa.PublicKey{}
+	if publicKey != nil {
+		publicKeys[""] = publicKey
+	}
+	return NewAuthMiddlewareWithKeys(publicKeys, maxCacheTTL, DefaultClockSkewLeeway)
+}
+
+// NewAuthMiddlewareWithKeys creates a JWT middleware trusting several signing keys, keyed by key ID
+// A token is verified with the key named by its kid header, or with each key in turn when it has none,
+// so tokens signed with the previous key keep working while the Identity Service rotates to a new one.
+// A key under "" has no known ID and is also tried for tokens whose kid matches no other key
+// leeway tolerates clock drift: tokens are accepted until exp + leeway and from nbf - leeway
+func NewAuthMiddlewareWithKeys(publicKeys map[string]*rsa.PublicKey, maxCacheTTL time.Duration, leeway time.Duration) *AuthMiddleware {
 	m := &AuthMiddleware{
-		publicKey:   publicKey,
+		publicKeys:  publicKeys,
+		maxCacheTTL: maxCacheTTL,
+		leeway:      leeway,
 		janitorStop: make(chan bool),
 	}
 
@@ -65,8 +104,7 @@ const (
 // Public method for use in WebSocket handlers and other contexts
 func (m *AuthMiddleware) GetClaimsFromCacheOrParse(tokenString string) (jwt.MapClaims, string, error) {
 	// Peek at the JTI without verifying the signature yet (performance optimization)
-	parser := new(jwt.Parser)
-	unverifiedToken, _, err := parser.ParseUnverified(tokenString, jwt.MapClaims{})
+	unverifiedToken, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
 	if err != nil {
 		return nil, "", err
 	}
@@ -88,18 +126,23 @@ func (m *AuthMiddleware) GetClaimsFromCacheOrParse(tokenString string) (jwt.MapC
 		log.Printf("Token missing JTI, using fallback key: %s (role: %s, userID: %s)", jti[:min(30, len(jti))], role, userID)
 	}
 
+	// Denylist check comes before the cache so revoked tokens are rejected even when cached
+	if m.isRevoked(jti) {
+		return nil, "", ErrTokenRevoked
+	}
+
 	// Extract expiration for early validation
-	var exp int64
-	if expFloat, ok := claims["exp"].(float64); ok {
-		exp = int64(expFloat)
-	} else if expInt, ok := claims["exp"].(int64); ok {
-		exp = expInt
-	} else {
+	// Some identity libraries serialize numeric claims as strings, so accept both
+	exp, ok, err := numericClaim(claims, "exp")
+	if err != nil {
+		return nil, "", errors.New("invalid expiration claim")
+	}
+	if !ok {
 		return nil, "", errors.New("missing expiration claim")
 	}
 
-	// Immediate expiry check (fastest fail path)
-	if time.Now().Unix() > exp {
+	// Immediate expiry check (fastest fail path), tolerating clock drift up to the leeway
+	if time.Now().After(time.Unix(exp, 0).Add(m.leeway)) {
 		return nil, "", errors.New("token expired")
 	}
 
@@ -119,11 +162,14 @@ func (m *AuthMiddleware) GetClaimsFromCacheOrParse(tokenString string) (jwt.MapC
 	}
 
 	// Full RSA Validation (Cold path - only when cache miss)
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+	// Time-based claims are validated here with the leeway, since the jwt library rejects string-typed values
+	// (so jwt.WithLeeway would never apply)
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	token, err := parser.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, jwt.ErrSignatureInvalid
 		}
-		return m.publicKey, nil
+		return m.verificationKeys(t)
 	})
 
 	if err != nil {
@@ -140,12 +186,51 @@ func (m *AuthMiddleware) GetClaimsFromCacheOrParse(tokenString string) (jwt.MapC
 		return nil, "", errors.New("invalid token claims")
 	}
 
-	// Store verified claims in cache for future requests
-	m.cache.Store(jti, cacheEntry{claims: verifiedClaims, exp: exp})
+	// Normalize time-based claims to numbers and enforce not-before
+	if err := normalizeTimeClaims(verifiedClaims); err != nil {
+		return nil, "", err
+	}
+	if nbf, ok, _ := numericClaim(verifiedClaims, "nbf"); ok && time.Now().Add(m.leeway).Before(time.Unix(nbf, 0)) {
+		return nil, "", errors.New("token not valid yet")
+	}
+
+	// Store verified claims in cache for future requests, re-validating after maxCacheTTL at the latest
+	// The entry lasts as long as the token is accepted, so a token inside the leeway window is still a cache hit
+	cacheExp := time.Unix(exp, 0).Add(m.leeway).Unix()
+	if m.maxCacheTTL > 0 {
+		if ttlExp := time.Now().Add(m.maxCacheTTL).Unix(); ttlExp < cacheExp {
+			cacheExp = ttlExp
+		}
+	}
+	m.cache.Store(jti, cacheEntry{claims: verifiedClaims, exp: cacheExp})
 
 	return verifiedClaims, jti, nil
 }
 
+// verificationKeys selects the keys a token's signature is checked against
+// The key matching its kid header, every key when it has no kid, or the key without an ID as a last resort
+func (m *AuthMiddleware) verificationKeys(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid != "" {
+		if key, ok := m.publicKeys[kid]; ok {
+			return key, nil
+		}
+		if key, ok := m.publicKeys[""]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	keySet := jwt.VerificationKeySet{}
+	for _, key := range m.publicKeys {
+		keySet.Keys = append(keySet.Keys, key)
+	}
+	if len(keySet.Keys) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+	return keySet, nil
+}
+
 	// Authenticate validates JWT token and extracts claims
 	// Returns userID and role, or error if token is invalid
 	// Maintains backward compatibility with existing code
@@ -170,6 +255,20 @@ func (m *AuthMiddleware) GetClaimsFromCacheOrParse(tokenString string) (jwt.MapC
 	return userIDClaim, roleClaim, nil
 }
 
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+// The scheme is case-insensitive per RFC 6750 ("bearer" and "BEARER" work too)
+func bearerToken(authHeader string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authHeader), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", false
+	}
+	return token, true
+}
+
 // RequireAuth is middleware that validates JWT token from Authorization header
 // Adds userID and role to request context
 func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
@@ -184,17 +283,11 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Support both "Bearer token" and "Bearer token" formats
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			// Try splitting if TrimPrefix didn't work
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				log.Printf("Invalid Authorization header format")
-				http.Error(w, "invalid authorization header", http.StatusUnauthorized)
-				return
-			}
-			tokenString = parts[1]
+		tokenString, ok := bearerToken(authHeader)
+		if !ok {
+			log.Printf("Invalid Authorization header format")
+			http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+			return
 		}
 
 		// Get claims from cache or parse
@@ -290,7 +383,72 @@ func (m *AuthMiddleware) RequireAnyRole(allowedRoles []string, next http.Handler
 	})
 }
 
-// startJanitor periodically cleans up expired cache entries
+// numericClaim extracts a NumericDate claim (exp, iat, nbf) as Unix seconds
+// Accepts JSON numbers as well as numeric strings (e.g. "exp": "1735689600")
+// Returns false if the claim is absent
+func numericClaim(claims jwt.MapClaims, key string) (int64, bool, error) {
+	raw, ok := claims[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), true, nil
+	case int64:
+		return v, true, nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid %s claim: %w", key, err)
+		}
+		return int64(f), true, nil
+	case string:
+		str := strings.TrimSpace(v)
+		if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return i, true, nil
+		}
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid %s claim: %q", key, v)
+		}
+		return int64(f), true, nil
+	default:
+		return 0, true, fmt.Errorf("invalid %s claim type: %T", key, raw)
+	}
+}
+
+// normalizeTimeClaims converts string-typed exp/iat/nbf claims to numbers in place
+// so downstream consumers of the claims see the standard JSON number representation
+func normalizeTimeClaims(claims jwt.MapClaims) error {
+	for _, key := range []string{"exp", "iat", "nbf"} {
+		value, ok, err := numericClaim(claims, key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			claims[key] = float64(value)
+		}
+	}
+	return nil
+}
+
+// RevokeJTI rejects tokens with the given JTI until the given time and drops their cached claims
+// until should be no earlier than the token's expiry; afterwards the token is rejected as expired anyway
+// The denylist is in memory, so each replica must be told about a revocation
+func (m *AuthMiddleware) RevokeJTI(jti string, until time.Time) {
+	m.denylist.Store(jti, until.Unix())
+	m.cache.Delete(jti)
+	log.Printf("Token revoked - JTI: %s, until: %s", jti[:min(20, len(jti))], until.UTC().Format(time.RFC3339))
+}
+
+// isRevoked reports whether the JTI is on the denylist and its revocation has not lapsed
+func (m *AuthMiddleware) isRevoked(jti string) bool {
+	until, ok := m.denylist.Load(jti)
+	return ok && time.Now().Unix() < until.(int64)
+}
+
+// startJanitor periodically cleans up expired cache and denylist entries
 func (m *AuthMiddleware) startJanitor(interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -310,6 +468,17 @@ func (m *AuthMiddleware) startJanitor(interval time.Duration) {
 			if deleted > 0 {
 				log.Printf("L1 Cache Janitor: Purged %d expired entries", deleted)
 			}
+			lapsed := 0
+			m.denylist.Range(func(key, value interface{}) bool {
+				if until, ok := value.(int64); ok && now >= until {
+					m.denylist.Delete(key)
+					lapsed++
+				}
+				return true
+			})
+			if lapsed > 0 {
+				log.Printf("L1 Cache Janitor: Purged %d lapsed revocations", lapsed)
+			}
 		case <-m.janitorStop:
 			return
 		}
@@ -345,6 +514,17 @@ func IsAdmin(ctx context.Context) bool {
 	return ok && role == "ADMIN"
 }
 
+// GetUserRole returns the role in context as a domain role (empty when missing)
+func GetUserRole(ctx context.Context) domain.Role {
+	role, _ := GetRole(ctx)
+	return domain.Role(role)
+}
+
+// CanReadAllBabies checks if the user in context may read any baby (ADMIN or NURSE)
+func CanReadAllBabies(ctx context.Context) bool {
+	return GetUserRole(ctx).CanReadAllBabies()
+}
+
 // GetUserEmail extracts user email from request context
 func GetUserEmail(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value(UserEmailKey).(string)
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BusinessMetricsCollector periodically refreshes product KPI gauges from the database
+// Exposed on the existing /metrics endpoint alongside the HTTP metrics
+type BusinessMetricsCollector struct {
+	statsRepo ports.StatsRepository
+	interval  time.Duration
+
+	activeBabies         prometheus.Gauge
+	measurementsLastHour prometheus.Gauge
+	activeRedAlerts      prometheus.Gauge
+}
+
+// NewBusinessMetricsCollector creates the business gauges and registers them with registerer
+// Pass prometheus.DefaultRegisterer to expose them on promhttp.Handler()
+func NewBusinessMetricsCollector(statsRepo ports.StatsRepository, interval time.Duration, registerer prometheus.Registerer) *BusinessMetricsCollector {
+	c := &BusinessMetricsCollector{
+		statsRepo: statsRepo,
+		interval:  interval,
+		activeBabies: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "care_active_babies",
+			Help: "Number of babies that are not soft-deleted",
+		}),
+		measurementsLastHour: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "care_measurements_last_hour",
+			Help: "Number of measurements created in the last hour",
+		}),
+		activeRedAlerts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "care_active_red_alerts",
+			Help: "Number of active babies whose most recent measurement is Red",
+		}),
+	}
+	registerer.MustRegister(c.activeBabies, c.measurementsLastHour, c.activeRedAlerts)
+	return c
+}
+
+// Refresh queries the current statistics once and updates the gauges
+// On error the gauges keep their previous values
+func (c *BusinessMetricsCollector) Refresh(ctx context.Context) error {
+	stats, err := c.statsRepo.GetBusinessStats(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+
+	c.activeBabies.Set(float64(stats.ActiveBabies))
+	c.measurementsLastHour.Set(float64(stats.MeasurementsLastHour))
+	c.activeRedAlerts.Set(float64(stats.ActiveRedAlerts))
+	return nil
+}
+
+// Start refreshes the gauges immediately and then every interval until ctx is cancelled
+// Blocks, so call it in a goroutine
+func (c *BusinessMetricsCollector) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			log.Printf("Failed to refresh business metrics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFeedToken is returned when a feed token is malformed, tampered with or expired
+var ErrInvalidFeedToken = errors.New("invalid feed token")
+
+// FeedTokenSigner issues and verifies read-only calendar feed tokens
+// Calendar clients can't send an Authorization header, so the token travels in ?token=
+// A token is scoped to one baby and the parent it was issued to, and expires after ttl
+// Format: base64url("<baby_id>,<user_id>,<exp unix>") + "." + base64url(HMAC-SHA256)
+type FeedTokenSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewFeedTokenSigner creates a signer using the shared secret (must be identical on all replicas)
+func NewFeedTokenSigner(secret string, ttl time.Duration) *FeedTokenSigner {
+	return &FeedTokenSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// Issue creates a feed token for the baby and user, valid until the returned expiry
+func (s *FeedTokenSigner) Issue(babyID uuid.UUID, userID uuid.UUID, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).UTC().Truncate(time.Second)
+	payload := fmt.Sprintf("%s,%s,%d", babyID, userID, expiresAt.Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.sign(encoded), expiresAt
+}
+
+// Verify checks the token signature and expiry and returns the baby and user it was issued for
+func (s *FeedTokenSigner) Verify(token string, now time.Time) (uuid.UUID, uuid.UUID, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+
+	parts := strings.Split(string(payload), ",")
+	if len(parts) != 3 {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+	babyID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+	userID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.Unix() >= exp {
+		return uuid.Nil, uuid.Nil, ErrInvalidFeedToken
+	}
+
+	return babyID, userID, nil
+}
+
+// sign returns the base64url HMAC-SHA256 of the encoded payload
+func (s *FeedTokenSigner) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MeasurementMetricsCollector counts created measurements in Prometheus
+// Gives a live breakdown of activity by type and severity without querying the database
+type MeasurementMetricsCollector struct {
+	created        *prometheus.CounterVec
+	orphaned       prometheus.Counter
+	suppressed     *prometheus.CounterVec
+	dropped        *prometheus.CounterVec
+	createDuration *prometheus.HistogramVec
+	auditFailures  *prometheus.CounterVec
+}
+
+// NewMeasurementMetricsCollector creates the measurement counters and registers them with registerer
+// Pass prometheus.DefaultRegisterer to expose them on promhttp.Handler()
+func NewMeasurementMetricsCollector(registerer prometheus.Registerer) *MeasurementMetricsCollector {
+	c := &MeasurementMetricsCollector{
+		created: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "measurements_created_total",
+				Help: "Total number of measurements created, by type and safety status",
+			},
+			[]string{"type", "safety_status"},
+		),
+		orphaned: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "measurements_orphaned_total",
+				Help: "Total number of measurements read whose baby row no longer exists (data integrity violation)",
+			},
+		),
+		suppressed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "alerts_suppressed_total",
+				Help: "Total number of alerts not published because the baby had an alert of the same type within the throttle window",
+			},
+			[]string{"type"},
+		),
+		dropped: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "alerts_dropped_total",
+				Help: "Total number of alerts not published because the background alert queue was full",
+			},
+			[]string{"type"},
+		),
+		createDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "measurement_create_duration_seconds",
+				Help:    "Time to create a measurement in seconds, from the start of the request, by type",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"type"},
+		),
+		auditFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "audit_write_failures_total",
+				Help: "Total number of measurement audit entries that could not be written, by action",
+			},
+			[]string{"action"},
+		),
+	}
+	registerer.MustRegister(c.created, c.orphaned, c.suppressed, c.dropped, c.createDuration, c.auditFailures)
+	return c
+}
+
+// MeasurementCreated counts a stored measurement by type and safety status
+// Label values are bounded by the measurement types and safety statuses the service accepts
+func (c *MeasurementMetricsCollector) MeasurementCreated(measurementType string, status domain.SafetyStatus) {
+	c.created.WithLabelValues(measurementType, string(status)).Inc()
+}
+
+// OrphanedMeasurement counts a measurement read without any baby row
+func (c *MeasurementMetricsCollector) OrphanedMeasurement() {
+	c.orphaned.Inc()
+}
+
+// AlertSuppressed counts an alert dropped by the per-baby alert throttle
+func (c *MeasurementMetricsCollector) AlertSuppressed(measurementType string) {
+	c.suppressed.WithLabelValues(measurementType).Inc()
+}
+
+// AlertDropped counts an alert shed because the background alert queue was full
+// Any increase means nurses missed alerts; raise ALERT_WORKERS or ALERT_QUEUE_SIZE, or check the broker
+func (c *MeasurementMetricsCollector) AlertDropped(measurementType string) {
+	c.dropped.WithLabelValues(measurementType).Inc()
+}
+
+// MeasurementCreateDuration observes the latency of a successful create under its measurement type
+// Unlike http_request_duration_seconds this separates e.g. feedings, which validate more fields, from temperatures
+func (c *MeasurementMetricsCollector) MeasurementCreateDuration(measurementType string, duration time.Duration) {
+	c.createDuration.WithLabelValues(measurementType).Observe(duration.Seconds())
+}
+
+// AuditRecordFailed counts a measurement mutation whose audit entry was lost
+// Any increase means the audit log is incomplete and should be reconciled
+func (c *MeasurementMetricsCollector) AuditRecordFailed(action domain.AuditAction) {
+	c.auditFailures.WithLabelValues(string(action)).Inc()
+}
+
+var _ ports.MeasurementMetrics = (*MeasurementMetricsCollector)(nil)
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -45,6 +46,9 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		// Share the start time with handlers, services and the alert publisher
+		r = r.WithContext(domain.WithRequestStart(r.Context(), start))
+
 		// Wrap the writer to capture the status code (default 200)
 		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
 
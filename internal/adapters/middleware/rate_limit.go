@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiterIdleTTL is how long a user's limiter is kept after their last request
+// With any practical limit an idle bucket has refilled by then, so dropping it changes nothing
+const RateLimiterIdleTTL = 10 * time.Minute
+
+// limiterEntry stores a user's token bucket and when it was last used (unix seconds)
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+// UserRateLimiter enforces a token-bucket rate limit per authenticated user
+// Protects write endpoints from clients stuck in a retry loop
+type UserRateLimiter struct {
+	limit rate.Limit
+	burst int
+	// Limiters keyed by user ID
+	limiters sync.Map
+	// Background janitor for idle limiter cleanup
+	janitorStop chan bool
+}
+
+// NewUserRateLimiter creates a limiter allowing limit requests per second per user, with bursts of up to burst
+func NewUserRateLimiter(limit rate.Limit, burst int) *UserRateLimiter {
+	l := &UserRateLimiter{
+		limit:       limit,
+		burst:       burst,
+		janitorStop: make(chan bool),
+	}
+
+	// Start background janitor to drop limiters of idle users
+	go l.startJanitor(CacheCleanupInterval)
+
+	return l
+}
+
+// RateLimitByUser is middleware that rejects requests with 429 once the caller's bucket is empty
+// Must run inside RequireAuth, since the bucket is keyed by the userID it puts in the context
+func (l *UserRateLimiter) RateLimitByUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetUserID(r.Context())
+		if !ok || userID == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		reservation := l.limiterFor(userID).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			// Give the token back so rejected requests don't push the next allowed one further out
+			reservation.Cancel()
+			log.Printf("Rate limit exceeded - UserID: %s, Path: %s", userID, r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// limiterFor returns the user's limiter, creating it on first use
+func (l *UserRateLimiter) limiterFor(userID string) *rate.Limiter {
+	value, ok := l.limiters.Load(userID)
+	if !ok {
+		value, _ = l.limiters.LoadOrStore(userID, &limiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)})
+	}
+	entry := value.(*limiterEntry)
+	entry.lastSeen.Store(time.Now().Unix())
+	return entry.limiter
+}
+
+// startJanitor periodically removes limiters that have been idle for RateLimiterIdleTTL
+func (l *UserRateLimiter) startJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cutoff := time.Now().Add(-RateLimiterIdleTTL).Unix()
+			deleted := 0
+			l.limiters.Range(func(key, value interface{}) bool {
+				if entry, ok := value.(*limiterEntry); ok && entry.lastSeen.Load() < cutoff {
+					l.limiters.Delete(key)
+					deleted++
+				}
+				return true
+			})
+			if deleted > 0 {
+				log.Printf("Rate Limiter Janitor: Purged %d idle limiters", deleted)
+			}
+		case <-l.janitorStop:
+			return
+		}
+	}
+}
+
+// Stop stops the background janitor (for graceful shutdown)
+func (l *UserRateLimiter) Stop() {
+	close(l.janitorStop)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import "net/http"
+
+// ServedByHeader is the response header naming the replica that served the request
+const ServedByHeader = "X-Served-By"
+
+// ServedByMiddleware sets X-Served-By to nodeName on every response
+// Lets a misbehaving response be traced to a replica; an empty nodeName disables the header
+func ServedByMiddleware(nodeName string, next http.Handler) http.Handler {
+	if nodeName == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Set before the handler runs so it is sent with whatever status the handler writes
+		w.Header().Set(ServedByHeader, nodeName)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// timeoutResponse is the JSON body sent when a handler misses its deadline
+type timeoutResponse struct {
+	Error   string `json:"error"`
+	Timeout string `json:"timeout"`
+}
+
+// TimeoutMiddleware bounds how long a handler may take to respond
+// The request context gets a deadline of d, so database calls made with it are cancelled too.
+// A handler that has not finished by then is answered with 503 and a JSON body; its late
+// writes are discarded. Must run inside RequireAuth so the user is known when logging.
+func TimeoutMiddleware(d time.Duration) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				// Re-raise on the serving goroutine so net/http handles it as usual
+				panic(p)
+			case <-done:
+				tw.flushTo(w)
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+
+				if r.Context().Err() != nil {
+					// The client went away first; there is nobody to answer
+					return
+				}
+
+				userID, _ := GetUserID(r.Context())
+				log.Printf("Request timeout - UserID: %s, Method: %s, Path: %s, Timeout: %s", userID, r.Method, r.URL.Path, d)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_ = json.NewEncoder(w).Encode(timeoutResponse{Error: "request timed out", Timeout: d.String()})
+			}
+		}
+	}
+}
+
+// timeoutWriter buffers a handler's response so it can be dropped if the deadline passes first
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+// Header returns the buffered response headers
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader records the status code; only the first call counts
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+// Write buffers body bytes, failing with http.ErrHandlerTimeout once the deadline has passed
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+// flushTo copies the buffered response to the real writer
+func (tw *timeoutWriter) flushTo(w http.ResponseWriter) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	dst := w.Header()
+	for k, vv := range tw.header {
+		dst[k] = vv
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	w.WriteHeader(tw.code)
+	_, _ = w.Write(tw.buf.Bytes())
+}
@@ -3,23 +3,41 @@ package repository
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
-	"sync"
+	"strings"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/config" //nolint:staticcheck // config package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
 	"github.com/rabbitmq/amqp091-go"
 )
 
+// BabyCreationDLQ receives baby creation messages that failed more than the allowed number of retries
+const BabyCreationDLQ = "baby.creation.dlq"
+
+// RetryCountHeader counts how often a message was requeued after a failed attempt
+// RabbitMQ only maintains x-death when a message is dead-lettered, not when it is requeued by a nack
+const RetryCountHeader = "x-retry-count"
+
+// DefaultBabyCreationMaxRetries is how often a failing baby creation is retried when not configured
+const DefaultBabyCreationMaxRetries = 5
+
+// MessagePublisher publishes a message to a queue through the default exchange
+type MessagePublisher interface {
+	Publish(ctx context.Context, queue string, msg amqp091.Publishing) error
+}
+
 // BabyCreationRequest represents a message from RabbitMQ for creating a baby
 // This matches the message format sent by the identity-service
 // Identity service sends: { "user_id": "uuid-string", "last_name": "string", "room_number": "string" }
+// and optionally "date_of_birth": "YYYY-MM-DD"
 type BabyCreationRequest struct {
-	UserID     string `json:"user_id"`      // Parent user ID (UUID as string from identity service)
-	LastName   string `json:"last_name"`    // Baby's last name
-	RoomNumber string `json:"room_number"`  // Room number
+	UserID      string `json:"user_id"`                 // Parent user ID (UUID as string from identity service)
+	LastName    string `json:"last_name"`               // Baby's last name
+	RoomNumber  string `json:"room_number"`             // Room number
+	DateOfBirth string `json:"date_of_birth,omitempty"` // Optional date of birth (YYYY-MM-DD)
 }
 
 // BabyConsumer consumes messages from RabbitMQ for automatic baby creation
@@ -27,231 +45,71 @@ type BabyCreationRequest struct {
 // Duplicate prevention checks ensure only one consumer per pod instance
 // (For multi-replica deployments, RabbitMQ distributes messages across replicas)
 type BabyConsumer struct {
-	conn          *amqp091.Connection
-	channel       *amqp091.Channel
-	queueName     string
-	babyService   ports.BabyService
-	connMutex     sync.RWMutex
-	reconnectCh   chan bool
-	stopReconnect chan bool
-	maxRetries    int
-	retryDelay    time.Duration
-	consumingCtx  context.Context
-	consumingMutex sync.Mutex
-	isConsuming   bool
+	*queueConsumer
 }
 
 // NewBabyConsumer creates a new RabbitMQ consumer for baby creation
-func NewBabyConsumer(rabbitMQURL string, queueName string, babyService ports.BabyService) (*BabyConsumer, error) {
+// In dryRun mode messages are parsed and logged but no baby is created
+// A message whose creation fails more than maxRetries times is moved to BabyCreationDLQ
+func NewBabyConsumer(rabbitMQURL string, queueName string, babyService ports.BabyService, dryRun bool, maxRetries int) (*BabyConsumer, error) {
 	if queueName == "" {
-		queueName = "babies"
-	}
-
-	consumer := &BabyConsumer{
-		queueName:     queueName,
-		babyService:   babyService,
-		maxRetries:    3,
-		retryDelay:    1 * time.Second,
-		reconnectCh:   make(chan bool, 1),
-		stopReconnect: make(chan bool),
-	}
-
-	// Connect to RabbitMQ
-	if err := consumer.connect(rabbitMQURL); err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
-
-	// Start reconnection handler
-	go consumer.handleReconnection(rabbitMQURL)
-
-	return consumer, nil
-}
-
-// connect establishes connection to RabbitMQ
-func (c *BabyConsumer) connect(rabbitMQURL string) error {
-	var err error
-	for i := 0; i < c.maxRetries; i++ {
-		c.conn, err = amqp091.Dial(rabbitMQURL)
-		if err == nil {
-			break
-		}
-		log.Printf("Failed to connect to RabbitMQ (attempt %d/%d): %v", i+1, c.maxRetries, err)
-		if i < c.maxRetries-1 {
-			time.Sleep(c.retryDelay)
-		}
-	}
-
-	if err != nil {
-		return err
+		queueName = config.DefaultBabyQueueName
 	}
-
-	c.channel, err = c.conn.Channel()
-	if err != nil {
-		c.conn.Close()
-		return err
+	if maxRetries <= 0 {
+		maxRetries = DefaultBabyCreationMaxRetries
 	}
 
-	// Declare queue (idempotent)
-	_, err = c.channel.QueueDeclare(
-		c.queueName, // name
-		true,        // durable
-		false,       // delete when unused
-		false,       // exclusive
-		false,       // no-wait
-		nil,         // arguments
-	)
-
+	processor := NewBabyMessageProcessor(babyService, dryRun)
+	consumer, err := newQueueConsumerWithDeadLetter(rabbitMQURL, queueName, BabyCreationDLQ, "Baby consumer", "baby-consumer", processor.Process)
 	if err != nil {
-		c.channel.Close()
-		c.conn.Close()
-		return err
+		return nil, err
 	}
+	processor.publisher = consumer
+	processor.queueName = queueName
+	processor.maxRetries = maxRetries
 
-	log.Println("Baby consumer connected to RabbitMQ successfully")
-	return nil
+	return &BabyConsumer{queueConsumer: consumer}, nil
 }
 
-// handleReconnection handles automatic reconnection to RabbitMQ
-func (c *BabyConsumer) handleReconnection(rabbitMQURL string) {
-	for {
-		select {
-		case <-c.reconnectCh:
-			log.Println("Attempting to reconnect to RabbitMQ...")
-			c.connMutex.Lock()
-			if c.conn != nil && !c.conn.IsClosed() {
-				c.conn.Close()
-			}
-			if c.channel != nil && !c.channel.IsClosed() {
-				c.channel.Close()
-			}
-			c.connMutex.Unlock()
+// BabyMessageProcessor handles individual baby creation messages
+// Separated from the connection handling so it can be tested without a broker
+type BabyMessageProcessor struct {
+	babyService ports.BabyService
+	dryRun      bool
 
-			if err := c.connect(rabbitMQURL); err != nil {
-				log.Printf("Reconnection failed: %v", err)
-				time.Sleep(5 * time.Second)
-				c.reconnectCh <- true
-			} else {
-				// Restart consuming after reconnection using the original context
-				c.consumingMutex.Lock()
-				if c.consumingCtx != nil && c.consumingCtx.Err() == nil {
-					// Only restart if we have a valid context and we're not already consuming
-					if !c.isConsuming {
-						go func() {
-							if err := c.StartConsuming(c.consumingCtx); err != nil {
-								log.Printf("Error restarting consumer: %v", err)
-							}
-						}()
-					}
-				}
-				c.consumingMutex.Unlock()
-			}
-		case <-c.stopReconnect:
-			return
-		}
-	}
+	// Retry handling (nil publisher: failed messages are nacked and requeued)
+	publisher  MessagePublisher
+	queueName  string
+	maxRetries int
 }
 
-// StartConsuming starts consuming messages from the queue in a background goroutine
-// This method is called from main.go and runs asynchronously
-// Duplicate prevention: ensures only one consumer per pod instance
-// (In multi-replica scenarios, each replica will have its own consumer, and RabbitMQ
-// will distribute messages across them using round-robin)
-func (c *BabyConsumer) StartConsuming(ctx context.Context) error {
-	// Prevent multiple consumers from starting in the same pod instance
-	// This check is important when there are multiple care-service replicas
-	c.consumingMutex.Lock()
-	if c.isConsuming {
-		c.consumingMutex.Unlock()
-		log.Println("Baby consumer is already running in this pod, skipping duplicate start")
-		return nil
-	}
-	c.isConsuming = true
-	c.consumingCtx = ctx
-	c.consumingMutex.Unlock()
-
-	c.connMutex.RLock()
-	channel := c.channel
-	conn := c.conn
-	c.connMutex.RUnlock()
-
-	if channel == nil || channel.IsClosed() || conn == nil || conn.IsClosed() {
-		c.consumingMutex.Lock()
-		c.isConsuming = false
-		c.consumingMutex.Unlock()
-		return fmt.Errorf("RabbitMQ connection is closed")
+// NewBabyMessageProcessor creates a processor that creates babies via the service
+// In dryRun mode the parsed request is logged and acked without calling CreateBaby
+func NewBabyMessageProcessor(babyService ports.BabyService, dryRun bool) *BabyMessageProcessor {
+	return &BabyMessageProcessor{
+		babyService: babyService,
+		dryRun:      dryRun,
 	}
+}
 
-	// Set QoS to process one message at a time (ensures only one unacknowledged message per consumer)
-	err := channel.Qos(
-		1,     // prefetch count - only one message at a time
-		0,     // prefetch size
-		false, // global
-	)
-	if err != nil {
-		c.consumingMutex.Lock()
-		c.isConsuming = false
-		c.consumingMutex.Unlock()
-		return fmt.Errorf("failed to set QoS: %w", err)
-	}
-
-	// Register consumer with a unique consumer tag to identify this instance
-	consumerTag := fmt.Sprintf("baby-consumer-%d", time.Now().UnixNano())
-	msgs, err := channel.Consume(
-		c.queueName, // queue
-		consumerTag, // consumer tag (unique identifier)
-		false,       // auto-ack (manual ack - we acknowledge only after successful baby creation)
-		false,       // exclusive
-		false,       // no-local
-		false,       // no-wait
-		nil,         // args
-	)
-	if err != nil {
-		c.consumingMutex.Lock()
-		c.isConsuming = false
-		c.consumingMutex.Unlock()
-		return fmt.Errorf("failed to register consumer: %w", err)
-	}
-
-	log.Printf("Baby consumer started (tag: %s), waiting for messages on queue: %s", consumerTag, c.queueName)
-
-	// Process messages sequentially (QoS=1 ensures only one message is delivered at a time)
-	go func() {
-		defer func() {
-			c.consumingMutex.Lock()
-			c.isConsuming = false
-			c.consumingMutex.Unlock()
-		}()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Baby consumer context cancelled")
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					log.Println("Baby consumer channel closed, attempting reconnection...")
-					c.reconnectCh <- true
-					return
-				}
-
-				// Process message sequentially (no goroutine - ensures only one message at a time)
-				// Acknowledgment happens only after successful baby creation in processMessage
-				c.processMessage(ctx, msg)
-			}
-		}
-	}()
-
-	return nil
+// NewBabyMessageProcessorWithRetry creates a processor that republishes failed messages to queueName
+// with an incremented RetryCountHeader, and moves them to BabyCreationDLQ after maxRetries retries
+func NewBabyMessageProcessorWithRetry(babyService ports.BabyService, dryRun bool, publisher MessagePublisher, queueName string, maxRetries int) *BabyMessageProcessor {
+	processor := NewBabyMessageProcessor(babyService, dryRun)
+	processor.publisher = publisher
+	processor.queueName = queueName
+	processor.maxRetries = maxRetries
+	return processor
 }
 
-// processMessage processes a single message from RabbitMQ
+// Process processes a single message from RabbitMQ
 // IMPORTANT: Message is acknowledged ONLY after successful baby creation
-// If baby creation fails, message is nacked and requeued for retry
-func (c *BabyConsumer) processMessage(ctx context.Context, msg amqp091.Delivery) {
+// If baby creation fails, message is retried and eventually dead-lettered (see retryOrDeadLetter)
+func (p *BabyMessageProcessor) Process(ctx context.Context, msg amqp091.Delivery) {
 	var req BabyCreationRequest
 	if err := json.Unmarshal(msg.Body, &req); err != nil {
 		log.Printf("Failed to unmarshal baby creation request: %v", err)
-		// Invalid message format - reject and don't requeue (will be lost)
+		// Invalid message format - reject and don't requeue (dead-lettered to BabyCreationDLQ)
 		if err := msg.Nack(false, false); err != nil {
 			log.Printf("Failed to nack message: %v", err)
 		}
@@ -261,7 +119,9 @@ func (c *BabyConsumer) processMessage(ctx context.Context, msg amqp091.Delivery)
 	log.Printf("Received baby creation request: user_id=%s, last_name=%s, room_number=%s",
 		req.UserID, req.LastName, req.RoomNumber)
 
-	// Validate request
+	// Validate request (whitespace-only names count as missing)
+	req.LastName = strings.TrimSpace(req.LastName)
+	req.RoomNumber = strings.TrimSpace(req.RoomNumber)
 	if req.UserID == "" {
 		log.Printf("Invalid baby creation request: user_id is required")
 		// Invalid data - reject and don't requeue
@@ -298,24 +158,45 @@ func (c *BabyConsumer) processMessage(ctx context.Context, msg amqp091.Delivery)
 		return
 	}
 
+	// A malformed date_of_birth won't parse on a retry either - reject and don't requeue
+	if _, err := domain.ParseDateOfBirth(req.DateOfBirth, time.Now()); err != nil {
+		log.Printf("Invalid baby creation request: %v", err)
+		if err := msg.Nack(false, false); err != nil {
+			log.Printf("Failed to nack message: %v", err)
+		}
+		return
+	}
+
+	// Dry-run: show what would be created, then ack so the message isn't redelivered
+	if p.dryRun {
+		log.Printf("[dry-run] Would create baby: last_name=%s, room_number=%s, parent_user_id=%s",
+			req.LastName, req.RoomNumber, parentUserID)
+		if err := msg.Ack(false); err != nil {
+			log.Printf("Failed to acknowledge message in dry-run mode: %v", err)
+		}
+		return
+	}
+
 	// Create baby using the service (ADMIN context - automated creation)
 	// Note: We use a system/admin context for automated creation
 	// In production, you might want to pass a system user ID or use a different approach
+	// Delivery is at-least-once, so a request matching a live baby (same parent, last name and room)
+	// is treated as a duplicate: nothing is created and the message is acked so the queue drains
 	adminUserID := uuid.Nil // System user for automated creation
-	baby, err := c.babyService.CreateBaby(ctx, req.LastName, req.RoomNumber, parentUserID, adminUserID, true)
+	baby, created, err := p.babyService.EnsureBaby(ctx, req.LastName, req.RoomNumber, parentUserID, req.DateOfBirth, adminUserID, domain.RoleAdmin)
 	if err != nil {
 		log.Printf("Failed to create baby from RabbitMQ message: %v", err)
-		// Baby creation failed - reject and requeue for retry
-		// This ensures the message will be redelivered and we can try again
-		if err := msg.Nack(false, true); err != nil {
-			log.Printf("Failed to nack message: %v", err)
-		}
+		p.retryOrDeadLetter(ctx, msg, err)
 		return
 	}
 
-	// Baby creation succeeded - log success
-	log.Printf("Successfully created baby from RabbitMQ: id=%s, last_name=%s, room_number=%s",
-		baby.ID, baby.LastName, baby.RoomNumber)
+	if created {
+		log.Printf("Successfully created baby from RabbitMQ: id=%s, last_name=%s, room_number=%s",
+			baby.ID, baby.LastName, baby.RoomNumber)
+	} else {
+		log.Printf("Skipping duplicate baby creation request: existing id=%s, last_name=%s, room_number=%s",
+			baby.ID, baby.LastName, baby.RoomNumber)
+	}
 
 	// CRITICAL: Acknowledge message ONLY after successful baby creation
 	// This ensures the message is removed from the queue only when baby creation succeeds
@@ -326,33 +207,64 @@ func (c *BabyConsumer) processMessage(ctx context.Context, msg amqp091.Delivery)
 	}
 }
 
-// Close closes the RabbitMQ connection and stops consuming
-// Note: The consuming context is cancelled by main.go during graceful shutdown
-func (c *BabyConsumer) Close() error {
-	// Stop reconnection handler
-	close(c.stopReconnect)
-
-	// Mark as not consuming (context cancellation is handled by main.go)
-	c.consumingMutex.Lock()
-	c.isConsuming = false
-	c.consumingMutex.Unlock()
+// retryOrDeadLetter settles a message whose baby creation failed
+// The message is republished with an incremented retry count, or to BabyCreationDLQ once maxRetries
+// retries have failed; the original is acked only after that publish succeeds
+// Without a publisher (or when the publish fails) the message is nacked and requeued as before
+func (p *BabyMessageProcessor) retryOrDeadLetter(ctx context.Context, msg amqp091.Delivery, cause error) {
+	if p.publisher == nil {
+		// Baby creation failed - reject and requeue for retry
+		if err := msg.Nack(false, true); err != nil {
+			log.Printf("Failed to nack message: %v", err)
+		}
+		return
+	}
 
-	// Close RabbitMQ connection
-	c.connMutex.Lock()
-	defer c.connMutex.Unlock()
+	retries := retryCount(msg)
+	target := p.queueName
+	headers := amqp091.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	if retries >= p.maxRetries {
+		target = BabyCreationDLQ
+		headers["x-last-error"] = cause.Error()
+		log.Printf("Baby creation failed after %d retries, moving message to %s: %v", retries, BabyCreationDLQ, cause)
+	} else {
+		headers[RetryCountHeader] = int32(retries + 1)
+		log.Printf("Retrying baby creation message (retry %d/%d)", retries+1, p.maxRetries)
+	}
 
-	if c.channel != nil && !c.channel.IsClosed() {
-		if err := c.channel.Close(); err != nil {
-			log.Printf("Error closing RabbitMQ channel: %v", err)
+	err := p.publisher.Publish(ctx, target, amqp091.Publishing{
+		Headers:     headers,
+		ContentType: msg.ContentType,
+		MessageId:   msg.MessageId,
+		Timestamp:   time.Now(),
+		Body:        msg.Body,
+	})
+	if err != nil {
+		log.Printf("Failed to publish message to %s, requeueing: %v", target, err)
+		if err := msg.Nack(false, true); err != nil {
+			log.Printf("Failed to nack message: %v", err)
 		}
+		return
 	}
 
-	if c.conn != nil && !c.conn.IsClosed() {
-		if err := c.conn.Close(); err != nil {
-			log.Printf("Error closing RabbitMQ connection: %v", err)
-		}
+	// The copy is queued, so the original can go
+	if err := msg.Ack(false); err != nil {
+		log.Printf("Failed to acknowledge message after republishing: %v", err)
 	}
+}
 
-	log.Println("Baby consumer closed")
-	return nil
+// retryCount reads RetryCountHeader from a delivery (0 when missing or not an integer)
+func retryCount(msg amqp091.Delivery) int {
+	switch v := msg.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+)
+
+// circuitBreakerStateChanges counts breaker transitions so outages show up as trips, not silent slowness
+var circuitBreakerStateChanges = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "circuit_breaker_state_changes_total",
+		Help: "Total number of circuit breaker state transitions",
+	},
+	[]string{"name", "from", "to"},
+)
+
+// alertsPublished counts alerts delivered to RabbitMQ, e.g. to chart high temperature alerts per day
+var alertsPublished = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "alerts_published_total",
+		Help: "Total number of alerts published to RabbitMQ, by alert type",
+	},
+	[]string{"alert_type"},
+)
+
+// RegisterRepositoryMetrics registers the repository metrics with registerer
+// Called once from main.go with prometheus.DefaultRegisterer
+func RegisterRepositoryMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{circuitBreakerStateChanges, alertsPublished} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstrumentCircuitBreaker makes the breaker count its state changes and log them at warn level
+// Any OnStateChange callback already set on settings is still called
+func InstrumentCircuitBreaker(settings gobreaker.Settings) gobreaker.Settings {
+	next := settings.OnStateChange
+	settings.OnStateChange = func(name string, from gobreaker.State, to gobreaker.State) {
+		circuitBreakerStateChanges.WithLabelValues(name, from.String(), to.String()).Inc()
+
+		slog.Warn("circuit breaker state change",
+			slog.String("event", "circuit_breaker_state_change"),
+			slog.String("breaker", name),
+			slog.String("from", from.String()),
+			slog.String("to", to.String()))
+
+		if next != nil {
+			next(name, from, to)
+		}
+	}
+	return settings
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/config" //nolint:staticcheck // config package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Identity Service user event types projected into the parents table
+const (
+	UserEventCreated = "user.created"
+	UserEventUpdated = "user.updated"
+)
+
+// UserEvent represents a user lifecycle event published by the identity-service
+// Identity service sends: { "event_type": "user.created", "user_id": "uuid-string", "role": "PARENT",
+// "name": "string", "email": "string", "occurred_at": "RFC3339" }
+type UserEvent struct {
+	EventType  string     `json:"event_type"`            // user.created or user.updated
+	UserID     string     `json:"user_id"`               // User ID (UUID as string from identity service)
+	Role       string     `json:"role,omitempty"`        // Only PARENT users are projected; empty is treated as PARENT
+	Name       string     `json:"name"`                  // Display name
+	Email      string     `json:"email"`                 // Contact email
+	OccurredAt *time.Time `json:"occurred_at,omitempty"` // Orders events for the same user; receive time when missing
+}
+
+// ParentProjectionConsumer consumes identity-service user events into the local parents read-model
+// Lets features that need parent data (names, existence checks) read locally instead of
+// calling the identity service synchronously
+type ParentProjectionConsumer struct {
+	*queueConsumer
+}
+
+// NewParentProjectionConsumer creates a new RabbitMQ consumer for the parents projection
+func NewParentProjectionConsumer(rabbitMQURL string, queueName string, parentRepo ports.ParentRepository) (*ParentProjectionConsumer, error) {
+	if queueName == "" {
+		queueName = config.DefaultUserEventsQueueName
+	}
+
+	processor := NewParentEventProcessor(parentRepo)
+	consumer, err := newQueueConsumer(rabbitMQURL, queueName, "Parent projection consumer", "parent-projection-consumer", processor.Process)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParentProjectionConsumer{queueConsumer: consumer}, nil
+}
+
+// ParentEventProcessor projects individual user events into the parents table
+// Separated from the connection handling so it can be tested without a broker
+type ParentEventProcessor struct {
+	parentRepo ports.ParentRepository
+}
+
+// NewParentEventProcessor creates a processor that upserts parents via the repository
+func NewParentEventProcessor(parentRepo ports.ParentRepository) *ParentEventProcessor {
+	return &ParentEventProcessor{
+		parentRepo: parentRepo,
+	}
+}
+
+// Process processes a single user event from RabbitMQ
+// Malformed events are rejected without requeue; events that are not for parents are acked and skipped
+// The message is acknowledged only after the projection is written, otherwise it is requeued
+func (p *ParentEventProcessor) Process(ctx context.Context, msg amqp091.Delivery) {
+	var event UserEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		log.Printf("Failed to unmarshal user event: %v", err)
+		// Invalid message format - reject and don't requeue (will be lost)
+		if err := msg.Nack(false, false); err != nil {
+			log.Printf("Failed to nack message: %v", err)
+		}
+		return
+	}
+
+	// Other user events (and users who are not parents) are not part of this projection
+	if (event.EventType != UserEventCreated && event.EventType != UserEventUpdated) ||
+		(event.Role != "" && domain.Role(event.Role) != domain.RoleParent) {
+		log.Printf("Skipping user event: event_type=%s, role=%s, user_id=%s", event.EventType, event.Role, event.UserID)
+		if err := msg.Ack(false); err != nil {
+			log.Printf("Failed to acknowledge skipped user event: %v", err)
+		}
+		return
+	}
+
+	parentID, err := uuid.Parse(event.UserID)
+	if err != nil {
+		log.Printf("Invalid user event: user_id is not a valid UUID: %v", err)
+		// Invalid data - reject and don't requeue
+		if err := msg.Nack(false, false); err != nil {
+			log.Printf("Failed to nack message: %v", err)
+		}
+		return
+	}
+
+	updatedAt := time.Now()
+	if event.OccurredAt != nil {
+		updatedAt = *event.OccurredAt
+	}
+
+	parent := &domain.Parent{
+		ID:        parentID,
+		Name:      event.Name,
+		Email:     event.Email,
+		UpdatedAt: updatedAt,
+	}
+	if err := p.parentRepo.UpsertParent(ctx, parent); err != nil {
+		log.Printf("Failed to project user event: event_type=%s, user_id=%s, error=%v", event.EventType, parentID, err)
+		// Projection failed - reject and requeue for retry
+		if err := msg.Nack(false, true); err != nil {
+			log.Printf("Failed to nack message: %v", err)
+		}
+		return
+	}
+
+	log.Printf("Projected user event: event_type=%s, user_id=%s", event.EventType, parentID)
+
+	// Upserts are idempotent, so a failed ack and redelivery is safe
+	if err := msg.Ack(false); err != nil {
+		log.Printf("Failed to acknowledge user event after projection: %v", err)
+	}
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// queueConsumer owns the RabbitMQ connection of a single-queue consumer
+// Handles connecting, queue declaration, automatic reconnection and the delivery loop;
+// each message is handed to process, which is responsible for acking or nacking it
+type queueConsumer struct {
+	name           string // Used in log lines, e.g. "Baby consumer"
+	tagPrefix      string // Consumer tag prefix, e.g. "baby-consumer"
+	conn           *amqp091.Connection
+	channel        *amqp091.Channel
+	queueName      string
+	deadLetter     string // Declared on connect and set as the queue's dead-letter target (empty: none)
+	process        func(ctx context.Context, msg amqp091.Delivery)
+	connMutex      sync.RWMutex
+	reconnectCh    chan bool
+	stopReconnect  chan bool
+	maxRetries     int
+	retryDelay     time.Duration
+	consumingCtx   context.Context
+	consumingMutex sync.Mutex
+	isConsuming    bool
+}
+
+// newQueueConsumer connects to RabbitMQ, declares the queue and starts the reconnection handler
+func newQueueConsumer(rabbitMQURL string, queueName string, name string, tagPrefix string, process func(ctx context.Context, msg amqp091.Delivery)) (*queueConsumer, error) {
+	return newQueueConsumerWithDeadLetter(rabbitMQURL, queueName, "", name, tagPrefix, process)
+}
+
+// newQueueConsumerWithDeadLetter is newQueueConsumer with a dead-letter queue for the consumed queue
+func newQueueConsumerWithDeadLetter(rabbitMQURL string, queueName string, deadLetter string, name string, tagPrefix string, process func(ctx context.Context, msg amqp091.Delivery)) (*queueConsumer, error) {
+	consumer := &queueConsumer{
+		name:          name,
+		tagPrefix:     tagPrefix,
+		queueName:     queueName,
+		deadLetter:    deadLetter,
+		process:       process,
+		maxRetries:    3,
+		retryDelay:    1 * time.Second,
+		reconnectCh:   make(chan bool, 1),
+		stopReconnect: make(chan bool),
+	}
+
+	// Connect to RabbitMQ
+	if err := consumer.connect(rabbitMQURL); err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	// Start reconnection handler
+	go consumer.handleReconnection(rabbitMQURL)
+
+	return consumer, nil
+}
+
+// connect establishes connection to RabbitMQ
+func (c *queueConsumer) connect(rabbitMQURL string) error {
+	var err error
+	for i := 0; i < c.maxRetries; i++ {
+		c.conn, err = amqp091.Dial(rabbitMQURL)
+		if err == nil {
+			break
+		}
+		log.Printf("Failed to connect to RabbitMQ (attempt %d/%d): %v", i+1, c.maxRetries, err)
+		if i < c.maxRetries-1 {
+			time.Sleep(c.retryDelay)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.channel, err = c.conn.Channel()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	// Declare the dead-letter queue first so rejected messages always have somewhere to go
+	var args amqp091.Table
+	if c.deadLetter != "" {
+		_, err = c.channel.QueueDeclare(
+			c.deadLetter, // name
+			true,         // durable
+			false,        // delete when unused
+			false,        // exclusive
+			false,        // no-wait
+			nil,          // arguments
+		)
+		if err != nil {
+			c.channel.Close()
+			c.conn.Close()
+			return err
+		}
+
+		// Messages nacked without requeue are routed to the dead-letter queue via the default exchange
+		args = amqp091.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": c.deadLetter,
+		}
+	}
+
+	// Declare queue (idempotent)
+	_, err = c.channel.QueueDeclare(
+		c.queueName, // name
+		true,        // durable
+		false,       // delete when unused
+		false,       // exclusive
+		false,       // no-wait
+		args,        // arguments
+	)
+
+	if err != nil {
+		c.channel.Close()
+		c.conn.Close()
+		return err
+	}
+
+	log.Printf("%s connected to RabbitMQ successfully", c.name)
+	return nil
+}
+
+// handleReconnection handles automatic reconnection to RabbitMQ
+func (c *queueConsumer) handleReconnection(rabbitMQURL string) {
+	for {
+		select {
+		case <-c.reconnectCh:
+			log.Printf("%s attempting to reconnect to RabbitMQ...", c.name)
+			c.connMutex.Lock()
+			if c.conn != nil && !c.conn.IsClosed() {
+				c.conn.Close()
+			}
+			if c.channel != nil && !c.channel.IsClosed() {
+				c.channel.Close()
+			}
+			c.connMutex.Unlock()
+
+			if err := c.connect(rabbitMQURL); err != nil {
+				log.Printf("Reconnection failed: %v", err)
+				time.Sleep(5 * time.Second)
+				c.reconnectCh <- true
+			} else {
+				// Restart consuming after reconnection using the original context
+				c.consumingMutex.Lock()
+				if c.consumingCtx != nil && c.consumingCtx.Err() == nil {
+					// Only restart if we have a valid context and we're not already consuming
+					if !c.isConsuming {
+						go func() {
+							if err := c.StartConsuming(c.consumingCtx); err != nil {
+								log.Printf("Error restarting %s: %v", c.tagPrefix, err)
+							}
+						}()
+					}
+				}
+				c.consumingMutex.Unlock()
+			}
+		case <-c.stopReconnect:
+			return
+		}
+	}
+}
+
+// StartConsuming starts consuming messages from the queue in a background goroutine
+// This method is called from main.go and runs asynchronously
+// Duplicate prevention: ensures only one consumer per pod instance
+// (In multi-replica scenarios, each replica will have its own consumer, and RabbitMQ
+// will distribute messages across them using round-robin)
+func (c *queueConsumer) StartConsuming(ctx context.Context) error {
+	// Prevent multiple consumers from starting in the same pod instance
+	// This check is important when there are multiple care-service replicas
+	c.consumingMutex.Lock()
+	if c.isConsuming {
+		c.consumingMutex.Unlock()
+		log.Printf("%s is already running in this pod, skipping duplicate start", c.name)
+		return nil
+	}
+	c.isConsuming = true
+	c.consumingCtx = ctx
+	c.consumingMutex.Unlock()
+
+	c.connMutex.RLock()
+	channel := c.channel
+	conn := c.conn
+	c.connMutex.RUnlock()
+
+	if channel == nil || channel.IsClosed() || conn == nil || conn.IsClosed() {
+		c.consumingMutex.Lock()
+		c.isConsuming = false
+		c.consumingMutex.Unlock()
+		return fmt.Errorf("RabbitMQ connection is closed")
+	}
+
+	// Set QoS to process one message at a time (ensures only one unacknowledged message per consumer)
+	err := channel.Qos(
+		1,     // prefetch count - only one message at a time
+		0,     // prefetch size
+		false, // global
+	)
+	if err != nil {
+		c.consumingMutex.Lock()
+		c.isConsuming = false
+		c.consumingMutex.Unlock()
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	// Register consumer with a unique consumer tag to identify this instance
+	consumerTag := fmt.Sprintf("%s-%d", c.tagPrefix, time.Now().UnixNano())
+	msgs, err := channel.Consume(
+		c.queueName, // queue
+		consumerTag, // consumer tag (unique identifier)
+		false,       // auto-ack (manual ack - process acknowledges only after the message is handled)
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
+	)
+	if err != nil {
+		c.consumingMutex.Lock()
+		c.isConsuming = false
+		c.consumingMutex.Unlock()
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	log.Printf("%s started (tag: %s), waiting for messages on queue: %s", c.name, consumerTag, c.queueName)
+
+	// Process messages sequentially (QoS=1 ensures only one message is delivered at a time)
+	go func() {
+		defer func() {
+			c.consumingMutex.Lock()
+			c.isConsuming = false
+			c.consumingMutex.Unlock()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("%s context cancelled", c.name)
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					log.Printf("%s channel closed, attempting reconnection...", c.name)
+					c.reconnectCh <- true
+					return
+				}
+
+				// Process message sequentially (no goroutine - ensures only one message at a time)
+				c.process(ctx, msg)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Publish sends a persistent message to a queue through the default exchange
+// Used by processors to requeue a message with an updated retry count or move it to the dead-letter queue
+func (c *queueConsumer) Publish(ctx context.Context, queue string, msg amqp091.Publishing) error {
+	c.connMutex.RLock()
+	channel := c.channel
+	c.connMutex.RUnlock()
+
+	if channel == nil || channel.IsClosed() {
+		return fmt.Errorf("RabbitMQ channel is closed")
+	}
+
+	msg.DeliveryMode = amqp091.Persistent
+	return channel.PublishWithContext(ctx, "", queue, false, false, msg)
+}
+
+// IsConnected reports whether the connection and channel to RabbitMQ are open
+func (c *queueConsumer) IsConnected() bool {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+
+	return c.conn != nil && !c.conn.IsClosed() && c.channel != nil && !c.channel.IsClosed()
+}
+
+// Close closes the RabbitMQ connection and stops consuming
+// Note: The consuming context is cancelled by main.go during graceful shutdown
+func (c *queueConsumer) Close() error {
+	// Stop reconnection handler
+	close(c.stopReconnect)
+
+	// Mark as not consuming (context cancellation is handled by main.go)
+	c.consumingMutex.Lock()
+	c.isConsuming = false
+	c.consumingMutex.Unlock()
+
+	// Close RabbitMQ connection
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.channel != nil && !c.channel.IsClosed() {
+		if err := c.channel.Close(); err != nil {
+			log.Printf("Error closing RabbitMQ channel: %v", err)
+		}
+	}
+
+	if c.conn != nil && !c.conn.IsClosed() {
+		if err := c.conn.Close(); err != nil {
+			log.Printf("Error closing RabbitMQ connection: %v", err)
+		}
+	}
+
+	log.Printf("%s closed", c.name)
+	return nil
+}
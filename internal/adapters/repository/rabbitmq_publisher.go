@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/config" //nolint:staticcheck // config package contains non-deprecated code
 	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
@@ -38,13 +40,51 @@ type AlertEvent struct {
 	Timestamp    time.Time            `json:"timestamp"`
 	AlertType    string               `json:"alert_type"`
 	SafetyStatus string               `json:"safety_status"`
-	Severity     string               `json:"severity"` // "critical" for Red status
+	Severity     string               `json:"severity"` // "critical" for Red status, "warning" for Yellow
+}
+
+// NewAlertEvent builds the alert event for a Red or Yellow measurement
+// Red alerts are "critical"; Yellow alerts are early warnings with "warning" severity
+func NewAlertEvent(babyID uuid.UUID, measurement *domain.Measurement) AlertEvent {
+	event := AlertEvent{
+		BabyID:       babyID,
+		Measurement:  measurement,
+		Timestamp:    time.Now(),
+		SafetyStatus: string(measurement.SafetyStatus),
+	}
+
+	if measurement.SafetyStatus == domain.SafetyStatusYellow {
+		event.Severity = "warning"
+		event.AlertType = "warning_measurement"
+		if measurement.Type == domain.MeasurementTypeTemperature {
+			if measurement.Value > domain.TemperatureNormalMax {
+				event.AlertType = "high_temperature_warning"
+			} else if measurement.Value < domain.TemperatureNormalMin {
+				event.AlertType = "low_temperature_warning"
+			}
+		}
+		return event
+	}
+
+	// Determine alert type based on measurement type and safety status
+	event.Severity = "critical" // Red status alerts are always critical
+	event.AlertType = "critical_measurement"
+	if measurement.Type == domain.MeasurementTypeTemperature {
+		if measurement.Value > domain.TemperatureYellowMax {
+			event.AlertType = "high_temperature_critical"
+		} else if measurement.Value < domain.TemperatureYellowMin {
+			event.AlertType = "low_temperature_critical"
+		}
+	} else if measurement.Type == domain.MeasurementTypeWeight {
+		event.AlertType = "invalid_weight"
+	}
+	return event
 }
 
 // NewRabbitMQPublisher creates a new RabbitMQ publisher with circuit breaker
-func NewRabbitMQPublisher(rabbitMQURL string, queueName string) (*RabbitMQPublisher, error) {
+func NewRabbitMQPublisher(rabbitMQURL string, queueName string, settings gobreaker.Settings) (*RabbitMQPublisher, error) {
 	if queueName == "" {
-		queueName = "baby_alerts"
+		queueName = config.DefaultAlertsQueueName
 	}
 
 	publisher := &RabbitMQPublisher{
@@ -56,16 +96,10 @@ func NewRabbitMQPublisher(rabbitMQURL string, queueName string) (*RabbitMQPublis
 	}
 
 	// Circuit breaker settings
-	settings := gobreaker.Settings{
-		Name:        "rabbitmq",
-		MaxRequests: 5,
-		Interval:    60 * time.Second,
-		Timeout:     30 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			return counts.ConsecutiveFailures > 5
-		},
+	if settings.Name == "" {
+		settings.Name = "rabbitmq"
 	}
-	publisher.cb = gobreaker.NewCircuitBreaker(settings)
+	publisher.cb = gobreaker.NewCircuitBreaker(InstrumentCircuitBreaker(settings))
 
 	// Connect to RabbitMQ
 	if err := publisher.connect(rabbitMQURL); err != nil {
@@ -157,40 +191,17 @@ func (p *RabbitMQPublisher) PublishAlert(ctx context.Context, babyID uuid.UUID,
 
 // publishWithRetry publishes with retry logic
 func (p *RabbitMQPublisher) publishWithRetry(ctx context.Context, babyID uuid.UUID, measurement *domain.Measurement) error {
-	startTime := time.Now()
+	// Latency is measured from the original request, not from the async publish
+	startTime := domain.RequestStart(ctx)
 
-	// Determine alert type based on measurement type and safety status
-	alertType := "critical_measurement"
-	if measurement.Type == domain.MeasurementTypeTemperature {
-		if measurement.Value > domain.TemperatureYellowMax {
-			alertType = "high_temperature_critical"
-		} else if measurement.Value < domain.TemperatureYellowMin {
-			alertType = "low_temperature_critical"
-		}
-	} else if measurement.Type == domain.MeasurementTypeWeight {
-		alertType = "invalid_weight"
-	}
+	event := NewAlertEvent(babyID, measurement)
 
-	event := AlertEvent{
-		BabyID:       babyID,
-		Measurement:  measurement,
-		Timestamp:    time.Now(),
-		AlertType:    alertType,
-		SafetyStatus: string(measurement.SafetyStatus),
-		Severity:     "critical", // Red status alerts are always critical
-	}
-
-	// Log structured JSON for alert publishing
-	logEntry := map[string]interface{}{
-		"event":         "alert_publish_attempt",
-		"baby_id":       babyID.String(),
-		"measurement_id": measurement.ID.String(),
-		"alert_type":    alertType,
-		"safety_status":  string(measurement.SafetyStatus),
-		"timestamp":      time.Now().Format(time.RFC3339),
-	}
-	jsonBytes, _ := json.Marshal(logEntry)
-	log.Printf("%s", string(jsonBytes))
+	slog.Info("alert publish attempt",
+		slog.String("event", "alert_publish_attempt"),
+		slog.String("baby_id", babyID.String()),
+		slog.String("measurement_id", measurement.ID.String()),
+		slog.String("alert_type", event.AlertType),
+		slog.String("safety_status", string(measurement.SafetyStatus)))
 
 	body, err := json.Marshal(event)
 	if err != nil {
@@ -229,6 +240,7 @@ func (p *RabbitMQPublisher) publishWithRetry(ctx context.Context, babyID uuid.UU
 		)
 
 		if err == nil {
+			alertsPublished.WithLabelValues(event.AlertType).Inc()
 			latency := time.Since(startTime)
 			if latency > 15*time.Second {
 				log.Printf("Warning: Alert publishing latency exceeded 15s: %v", latency)
@@ -252,6 +264,14 @@ func (p *RabbitMQPublisher) publishWithRetry(ctx context.Context, babyID uuid.UU
 	return fmt.Errorf("failed to publish alert after %d retries: %w", p.maxRetries, lastErr)
 }
 
+// IsConnected reports whether the connection and channel to RabbitMQ are open
+func (p *RabbitMQPublisher) IsConnected() bool {
+	p.connMutex.RLock()
+	defer p.connMutex.RUnlock()
+
+	return p.conn != nil && !p.conn.IsClosed() && p.channel != nil && !p.channel.IsClosed()
+}
+
 // Close closes the RabbitMQ connection
 func (p *RabbitMQPublisher) Close() error {
 	close(p.stopReconnect)
@@ -267,6 +287,7 @@ func (p *RabbitMQPublisher) Close() error {
 	return nil
 }
 
-// Ensure RabbitMQPublisher implements the interface
+// Ensure RabbitMQPublisher implements the interfaces
 var _ ports.AlertPublisher = (*RabbitMQPublisher)(nil)
+var _ ports.ConnectionChecker = (*RabbitMQPublisher)(nil)
 
@@ -3,69 +3,269 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sony/gobreaker"
 )
 
-// SQLRepository implements BabyRepository and MeasurementRepository using PostgreSQL
+// SQLRepository implements BabyRepository, MeasurementRepository and ParentRepository using PostgreSQL
 // Includes retry logic and circuit breaker for resilience
 type SQLRepository struct {
 	db            *sql.DB
+	readDB        *sql.DB // Read replica for list, summary and trend queries; the primary when none is configured
 	babyCB        *gobreaker.CircuitBreaker
 	measurementCB *gobreaker.CircuitBreaker
-	maxRetries    int
-	retryDelay    time.Duration
+	parentCB      *gobreaker.CircuitBreaker
+	retry         RetrySettings
 }
 
-// NewSQLRepository creates a new PostgreSQL repository with circuit breakers
-func NewSQLRepository(db *sql.DB) *SQLRepository {
-	// Circuit breaker settings
-	settings := gobreaker.Settings{
-		Name:        "database",
-		MaxRequests: 5,
-		Interval:    60 * time.Second,
-		Timeout:     30 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			return counts.ConsecutiveFailures > 5
-		},
+// queryContexter is satisfied by both *sql.DB and *sql.Tx
+type queryContexter interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Default retry settings, used for zero-valued RetrySettings fields
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 250 * time.Millisecond
+	DefaultRetryMaxDelay    = 2 * time.Second
+)
+
+// RetrySettings controls how transient database errors are retried
+// Delays grow exponentially from BaseDelay up to MaxDelay, with jitter
+type RetrySettings struct {
+	MaxAttempts int           // Attempts per operation, including the first
+	BaseDelay   time.Duration // Delay before the first retry; doubles for each later one
+	MaxDelay    time.Duration // Cap on a single delay
+	// Retryable decides whether a failed attempt is worth repeating; defaults to IsRetryableError
+	Retryable func(err error) bool
+}
+
+// withDefaults fills zero-valued fields with the package defaults
+func (s RetrySettings) withDefaults() RetrySettings {
+	if s.MaxAttempts <= 0 {
+		s.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if s.BaseDelay <= 0 {
+		s.BaseDelay = DefaultRetryBaseDelay
+	}
+	if s.MaxDelay <= 0 {
+		s.MaxDelay = DefaultRetryMaxDelay
+	}
+	if s.Retryable == nil {
+		s.Retryable = IsRetryableError
+	}
+	return s
+}
+
+// Backoff returns the delay ceiling before the given retry (1 for the first retry)
+// BaseDelay doubles per retry and is capped at MaxDelay; the actual wait is jittered below it
+func (s RetrySettings) Backoff(retry int) time.Duration {
+	s = s.withDefaults()
+	delay := s.BaseDelay
+	for i := 1; i < retry && delay < s.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.MaxDelay {
+		delay = s.MaxDelay
+	}
+	return delay
+}
+
+// jittered picks a wait in [delay/2, delay] so replicas retrying together spread out
+func jittered(delay time.Duration) time.Duration {
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// NewSQLRepository creates a new PostgreSQL repository with circuit breakers and the default retry settings
+// settings come from configuration; conflicts are always classified as successes
+func NewSQLRepository(db *sql.DB, settings gobreaker.Settings) *SQLRepository {
+	return NewSQLRepositoryWithRetry(db, settings, RetrySettings{})
+}
+
+// NewSQLRepositoryWithRetry creates a new PostgreSQL repository with circuit breakers and the given retry settings
+// Zero-valued retry fields fall back to the defaults
+func NewSQLRepositoryWithRetry(db *sql.DB, settings gobreaker.Settings, retry RetrySettings) *SQLRepository {
+	return NewSQLRepositoryWithReadReplica(db, nil, settings, retry)
+}
+
+// NewSQLRepositoryWithReadReplica creates a PostgreSQL repository that sends read-only endpoint queries
+// (lists, summaries, stats, trends) to readDB and everything else to db. Lookups that gate or precede
+// a write (ownership and access checks, idempotency keys, reads before an update) stay on the primary
+// so replica lag can't hide a row that was just written. A nil readDB runs every query on db
+func NewSQLRepositoryWithReadReplica(db *sql.DB, readDB *sql.DB, settings gobreaker.Settings, retry RetrySettings) *SQLRepository {
+	if readDB == nil {
+		readDB = db
+	}
+	if settings.Name == "" {
+		settings.Name = "database"
+	}
+	// Conflicts and constraint violations are client errors, not database health problems,
+	// and neither is a client that disconnected mid-request
+	settings.IsSuccessful = func(err error) bool {
+		return err == nil || errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrConstraintViolation) ||
+			errors.Is(err, context.Canceled)
+	}
+
+	settings = InstrumentCircuitBreaker(settings)
+
+	// Each breaker gets its own name so state change metrics tell them apart
+	named := func(table string) gobreaker.Settings {
+		s := settings
+		s.Name = settings.Name + "_" + table
+		return s
 	}
 
 	return &SQLRepository{
 		db:            db,
-		babyCB:        gobreaker.NewCircuitBreaker(settings),
-		measurementCB: gobreaker.NewCircuitBreaker(settings),
-		maxRetries:    3,
-		retryDelay:    1 * time.Second,
+		readDB:        readDB,
+		babyCB:        gobreaker.NewCircuitBreaker(named("babies")),
+		measurementCB: gobreaker.NewCircuitBreaker(named("measurements")),
+		parentCB:      gobreaker.NewCircuitBreaker(named("parents")),
+		retry:         retry.withDefaults(),
 	}
 }
 
 // executeWithRetry executes a database operation with retry logic
+// Waits between attempts back off exponentially; a retry that would not fit before the
+// ctx deadline is not attempted, and a cancelled ctx returns ctx.Err() without waiting
+// Operations scanning rows check ctx.Err() before each row, so a client that disconnects
+// mid-scan releases its connection at the next row instead of after the whole result
 func (r *SQLRepository) executeWithRetry(ctx context.Context, operation func() error) error {
+	// The client may already be gone; don't spend a connection on it
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	var lastErr error
-	for i := 0; i < r.maxRetries; i++ {
+	for i := 0; i < r.retry.MaxAttempts; i++ {
 		err := operation()
 		if err == nil {
 			return nil
 		}
 		lastErr = err
-		// Don't retry on sql.ErrNoRows - it's not a transient error
-		// Check both the error itself and its string representation
-		if errors.Is(err, sql.ErrNoRows) || err == sql.ErrNoRows || 
-			strings.Contains(strings.ToLower(err.Error()), "no rows") {
+		// Only transient errors are retried; anything else fails the same way on every attempt
+		if !r.retry.Retryable(err) {
 			return err
 		}
-		if i < r.maxRetries-1 {
-			time.Sleep(r.retryDelay)
+		if i == r.retry.MaxAttempts-1 {
+			break
+		}
+
+		delay := jittered(r.retry.Backoff(i + 1))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return fmt.Errorf("operation failed after %d attempts, no time left to retry: %w", i+1, lastErr)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
 		}
 	}
-	return fmt.Errorf("operation failed after %d retries: %w", r.maxRetries, lastErr)
+	return fmt.Errorf("operation failed after %d retries: %w", r.retry.MaxAttempts, lastErr)
+}
+
+// retryableSQLStates are the Postgres error codes of transient failures worth retrying
+// Connection exceptions (class 08) are retried as a whole
+var retryableSQLStates = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"55P03": true, // lock_not_available
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// pqConnectionException is the Postgres error class of connection failures
+const pqConnectionException = "08"
+
+// IsRetryableError reports whether a failed database operation may succeed when repeated
+// Retryable: connection failures, deadlocks, serialization failures and server restarts
+// Not retryable: constraint violations, invalid input, syntax errors, missing rows and cancellations
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrConstraintViolation) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class() == pqConnectionException || retryableSQLStates[pqErr.Code]
+	}
+
+	// Without a Postgres error code, only failures of the connection itself are transient
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// pqUniqueViolation is the Postgres error code for unique constraint violations
+const pqUniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a Postgres unique-violation error
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
+
+// pqCheckViolation is the Postgres error code for CHECK constraint violations
+const pqCheckViolation = "23514"
+
+// measurementConstraintMessages explains each CHECK constraint on the measurements table
+var measurementConstraintMessages = map[string]string{
+	"chk_feeding_fields":          "feeding measurements require a feeding_type, and only feedings may have feeding fields",
+	"chk_temperature_fields":      "temperature measurements require value_celsius, and only temperatures may have it",
+	"chk_diaper_fields":           "diaper measurements require a diaper_status, and only diapers may have it",
+	"chk_breastfeeding_durations": "breastfeeding both-sides requires left and right durations",
+	"chk_sleep_fields":            "sleep measurements require sleep_start before sleep_end, and only sleeps may have them",
+	"chk_growth_values":           "height must be 20-120 cm and head circumference 25-60 cm",
+	"chk_spit_up_fields":          "only feedings may record a spit-up",
+}
+
+// checkViolationError maps a Postgres CHECK violation to domain.ErrConstraintViolation
+// naming the constraint; returns nil when err is not a CHECK violation
+func checkViolationError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != pqCheckViolation {
+		return nil
+	}
+	message, ok := measurementConstraintMessages[pqErr.Constraint]
+	if !ok {
+		message = "violates a data integrity check"
+	}
+	return fmt.Errorf("%w: %s (%s)", domain.ErrConstraintViolation, message, pqErr.Constraint)
+}
+
+// setBabyDateOfBirth copies a nullable date_of_birth onto the baby and derives its age in days
+func setBabyDateOfBirth(baby *domain.Baby, dateOfBirth sql.NullTime) {
+	if !dateOfBirth.Valid {
+		return
+	}
+	dob := dateOfBirth.Time
+	ageDays := domain.AgeInDays(dob, time.Now())
+	baby.DateOfBirth = &dob
+	baby.AgeDays = &ageDays
 }
 
 // BabyRepository implementation
@@ -73,21 +273,47 @@ func (r *SQLRepository) executeWithRetry(ctx context.Context, operation func() e
 func (r *SQLRepository) CreateBaby(ctx context.Context, baby *domain.Baby) error {
 	_, err := r.babyCB.Execute(func() (interface{}, error) {
 		return nil, r.executeWithRetry(ctx, func() error {
-			query := `INSERT INTO babies (id, last_name, room_number, parent_user_id, created_at) VALUES ($1, $2, $3, $4, $5)`
-			_, err := r.db.ExecContext(ctx, query, baby.ID, baby.LastName, baby.RoomNumber, baby.ParentUserID, baby.CreatedAt)
+			query := `INSERT INTO babies (id, last_name, room_number, parent_user_id, date_of_birth, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+			_, err := r.db.ExecContext(ctx, query, baby.ID, baby.LastName, baby.RoomNumber, baby.ParentUserID, baby.DateOfBirth, baby.CreatedAt)
 			return err
 		})
 	})
 	return err
 }
 
+func (r *SQLRepository) CreateBabies(ctx context.Context, babies []*domain.Baby) error {
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			tx, err := r.db.BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+
+			query := `INSERT INTO babies (id, last_name, room_number, parent_user_id, date_of_birth, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+			for _, baby := range babies {
+				if _, err := tx.ExecContext(ctx, query, baby.ID, baby.LastName, baby.RoomNumber, baby.ParentUserID, baby.DateOfBirth, baby.CreatedAt); err != nil {
+					return err
+				}
+			}
+			return tx.Commit()
+		})
+	})
+	return err
+}
+
 func (r *SQLRepository) GetBabyByID(ctx context.Context, babyID uuid.UUID) (*domain.Baby, error) {
 	result, err := r.babyCB.Execute(func() (interface{}, error) {
 		var baby domain.Baby
 		err := r.executeWithRetry(ctx, func() error {
-			query := `SELECT id, last_name, room_number, parent_user_id, created_at FROM babies WHERE id = $1`
+			query := `SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at FROM babies WHERE id = $1 AND deleted_at IS NULL`
 			row := r.db.QueryRowContext(ctx, query, babyID)
-			return row.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &baby.CreatedAt)
+			var dateOfBirth sql.NullTime
+			if err := row.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &dateOfBirth, &baby.CreatedAt); err != nil {
+				return err
+			}
+			setBabyDateOfBirth(&baby, dateOfBirth)
+			return nil
 		})
 		if err != nil {
 			return nil, err
@@ -105,7 +331,91 @@ func (r *SQLRepository) GetBabyByID(ctx context.Context, babyID uuid.UUID) (*dom
 	return result.(*domain.Baby), nil
 }
 
-func (r *SQLRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool) ([]*domain.Baby, error) {
+func (r *SQLRepository) FindBabyByParentAndRoom(ctx context.Context, parentUserID uuid.UUID, lastName string, roomNumber string) (*domain.Baby, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var found *domain.Baby
+		err := r.executeWithRetry(ctx, func() error {
+			found = nil
+			query := `SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at FROM babies
+				WHERE parent_user_id = $1 AND last_name = $2 AND room_number = $3 AND deleted_at IS NULL
+				ORDER BY created_at ASC LIMIT 1`
+			var baby domain.Baby
+			var dateOfBirth sql.NullTime
+			err := r.db.QueryRowContext(ctx, query, parentUserID, lastName, roomNumber).
+				Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &dateOfBirth, &baby.CreatedAt)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			setBabyDateOfBirth(&baby, dateOfBirth)
+			found = &baby
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return found, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*domain.Baby), nil
+}
+
+func (r *SQLRepository) ListBabiesByRoomPrefix(ctx context.Context, roomPrefix string) ([]*domain.Baby, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var babies []*domain.Baby
+		err := r.executeWithRetry(ctx, func() error {
+			babies = nil
+			// Same prefix match as ward assignments (assignmentCoversBaby)
+			query := `SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at FROM babies
+				WHERE deleted_at IS NULL AND left(room_number, length($1)) = $1
+				ORDER BY room_number, last_name, id`
+			rows, queryErr := r.readDB.QueryContext(ctx, query, roomPrefix)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var baby domain.Baby
+				var dateOfBirth sql.NullTime
+				if err := rows.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &dateOfBirth, &baby.CreatedAt); err != nil {
+					return err
+				}
+				setBabyDateOfBirth(&baby, dateOfBirth)
+				babies = append(babies, &baby)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return babies, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.Baby), nil
+}
+
+func (r *SQLRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool, includeDeleted bool) ([]*domain.Baby, error) {
+	// Soft-deleted babies are hidden unless explicitly requested
+	adminWhere, parentWhere := ` WHERE deleted_at IS NULL`, ` WHERE parent_user_id = $1 AND deleted_at IS NULL`
+	if includeDeleted {
+		adminWhere, parentWhere = ``, ` WHERE parent_user_id = $1`
+	}
+
 	result, err := r.babyCB.Execute(func() (interface{}, error) {
 		var babies []*domain.Baby
 		err := r.executeWithRetry(ctx, func() error {
@@ -114,10 +424,10 @@ func (r *SQLRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID,
 
 			if isAdmin {
 				// ADMIN can see all babies
-				rows, queryErr = r.db.QueryContext(ctx, `SELECT id, last_name, room_number, parent_user_id, created_at FROM babies ORDER BY created_at DESC`)
+				rows, queryErr = r.readDB.QueryContext(ctx, `SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at, deleted_at FROM babies`+adminWhere+` ORDER BY created_at DESC`)
 			} else {
 				// PARENT can only see their own babies
-				rows, queryErr = r.db.QueryContext(ctx, `SELECT id, last_name, room_number, parent_user_id, created_at FROM babies WHERE parent_user_id = $1 ORDER BY created_at DESC`, parentUserID)
+				rows, queryErr = r.readDB.QueryContext(ctx, `SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at, deleted_at FROM babies`+parentWhere+` ORDER BY created_at DESC`, parentUserID)
 			}
 
 			if queryErr != nil {
@@ -126,10 +436,18 @@ func (r *SQLRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID,
 			defer rows.Close()
 
 			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
 				var baby domain.Baby
-				if err := rows.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &baby.CreatedAt); err != nil {
+				var dateOfBirth, deletedAt sql.NullTime
+				if err := rows.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &dateOfBirth, &baby.CreatedAt, &deletedAt); err != nil {
 					return err
 				}
+				setBabyDateOfBirth(&baby, dateOfBirth)
+				if deletedAt.Valid {
+					baby.DeletedAt = &deletedAt.Time
+				}
 				babies = append(babies, &baby)
 			}
 
@@ -153,7 +471,7 @@ func (r *SQLRepository) BabyExists(ctx context.Context, babyID uuid.UUID) (bool,
 		var exists bool
 		err := r.executeWithRetry(ctx, func() error {
 			var count int
-			query := `SELECT COUNT(*) FROM babies WHERE id = $1`
+			query := `SELECT COUNT(*) FROM babies WHERE id = $1 AND deleted_at IS NULL`
 			err := r.db.QueryRowContext(ctx, query, babyID).Scan(&count)
 			exists = count > 0
 			return err
@@ -171,20 +489,20 @@ func (r *SQLRepository) BabyExists(ctx context.Context, babyID uuid.UUID) (bool,
 	return result.(bool), nil
 }
 
-func (r *SQLRepository) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error) {
+func (r *SQLRepository) BabyRecordExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
 	result, err := r.babyCB.Execute(func() (interface{}, error) {
-		var owned bool
+		var exists bool
 		err := r.executeWithRetry(ctx, func() error {
 			var count int
-			query := `SELECT COUNT(*) FROM babies WHERE id = $1 AND parent_user_id = $2`
-			err := r.db.QueryRowContext(ctx, query, babyID, parentUserID).Scan(&count)
-			owned = count > 0
+			query := `SELECT COUNT(*) FROM babies WHERE id = $1`
+			err := r.db.QueryRowContext(ctx, query, babyID).Scan(&count)
+			exists = count > 0
 			return err
 		})
 		if err != nil {
 			return nil, err
 		}
-		return owned, nil
+		return exists, nil
 	})
 
 	if err != nil {
@@ -194,160 +512,964 @@ func (r *SQLRepository) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID
 	return result.(bool), nil
 }
 
-// MeasurementRepository implementation
-
-func (r *SQLRepository) CreateMeasurement(ctx context.Context, measurement *domain.Measurement) error {
-	_, err := r.measurementCB.Execute(func() (interface{}, error) {
-		return nil, r.executeWithRetry(ctx, func() error {
-			query := `INSERT INTO measurements (
-				id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
-				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
-				value_celsius, diaper_status
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
-			
-			var feedingType interface{}
-			if measurement.FeedingType != "" {
-				feedingType = string(measurement.FeedingType)
-			}
-			
-			var position interface{}
-			if measurement.Position != nil {
-				position = string(*measurement.Position)
-			}
-			
-			var side interface{}
-			if measurement.Side != nil {
-				side = string(*measurement.Side)
-			}
-			
-			var diaperStatus interface{}
-			if measurement.DiaperStatus != nil {
-				diaperStatus = string(*measurement.DiaperStatus)
-			}
-			
-			_, err := r.db.ExecContext(ctx, query,
-				measurement.ID,
-				measurement.ParentID,
-				measurement.BabyID,
-				measurement.Type,
-				measurement.Value,
-				string(measurement.SafetyStatus),
-				measurement.Note,
-				measurement.Timestamp,
-				measurement.CreatedAt,
-				feedingType,
-				measurement.VolumeML,
-				position,
-				side,
-				measurement.LeftDuration,
-				measurement.RightDuration,
-				measurement.Duration,
-				measurement.ValueCelsius,
-				diaperStatus,
-			)
-			return err
+// CountBabiesForParent counts a parent's live babies
+func (r *SQLRepository) CountBabiesForParent(ctx context.Context, parentUserID uuid.UUID) (int, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var count int
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT COUNT(*) FROM babies WHERE parent_user_id = $1 AND deleted_at IS NULL`
+			return r.readDB.QueryRowContext(ctx, query, parentUserID).Scan(&count)
 		})
+		if err != nil {
+			return nil, err
+		}
+		return count, nil
 	})
-	return err
-}
 
-func (r *SQLRepository) GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, measurementType *string, limit *int) ([]*domain.Measurement, error) {
-	result, err := r.measurementCB.Execute(func() (interface{}, error) {
-		var measurements []*domain.Measurement
-		err := r.executeWithRetry(ctx, func() error {
-			// Build query with optional filters
-			query := `SELECT id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
-				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
-				value_celsius, diaper_status
-				FROM measurements WHERE baby_id = $1`
-			
-			args := []interface{}{babyID}
-			argIndex := 2
-			
-			// Add type filter if provided
-			if measurementType != nil {
-				query += fmt.Sprintf(" AND type = $%d", argIndex)
-				args = append(args, *measurementType)
-				argIndex++
-			}
-			
-			// Add ordering
-			query += " ORDER BY timestamp DESC, created_at DESC"
-			
-			// Add limit if provided
-			if limit != nil {
-				query += fmt.Sprintf(" LIMIT $%d", argIndex)
-				args = append(args, *limit)
-			}
-			
-			rows, queryErr := r.db.QueryContext(ctx, query, args...)
-			if queryErr != nil {
-				return queryErr
-			}
-			defer rows.Close()
+	if err != nil {
+		return 0, err
+	}
 
-			for rows.Next() {
-				m, err := r.scanMeasurement(rows)
-				if err != nil {
-					return err
-				}
-				measurements = append(measurements, m)
-			}
+	return result.(int), nil
+}
 
-			return rows.Err()
+// CountRedMeasurementsForParent counts red measurements since a point in time across a parent's live babies
+// timestamp is stored without a time zone in UTC
+func (r *SQLRepository) CountRedMeasurementsForParent(ctx context.Context, parentUserID uuid.UUID, since time.Time) (int, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var count int
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT COUNT(*)
+				FROM measurements m
+				JOIN babies b ON b.id = m.baby_id
+				WHERE b.parent_user_id = $1 AND b.deleted_at IS NULL
+					AND m.safety_status = $2 AND m.timestamp >= $3`
+			return r.readDB.QueryRowContext(ctx, query, parentUserID, domain.SafetyStatusRed, since.UTC()).Scan(&count)
 		})
 		if err != nil {
 			return nil, err
 		}
-		return measurements, nil
+		return count, nil
 	})
 
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return result.([]*domain.Measurement), nil
+	return result.(int), nil
 }
 
-// scanMeasurement scans a measurement row from the database
-func (r *SQLRepository) scanMeasurement(rows *sql.Rows) (*domain.Measurement, error) {
-	var m domain.Measurement
-	var safetyStatusStr string
-	var timestamp sql.NullTime
-	
-	// Feeding fields
-	var feedingTypeStr sql.NullString
-	var volumeML sql.NullInt64
-	var positionStr sql.NullString
-	var sideStr sql.NullString
-	var leftDuration sql.NullInt64
-	var rightDuration sql.NullInt64
-	var duration sql.NullInt64
-	
-	// Temperature fields
-	var valueCelsius sql.NullFloat64
-	
-	// Diaper fields
-	var diaperStatusStr sql.NullString
+func (r *SQLRepository) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var owned bool
+		err := r.executeWithRetry(ctx, func() error {
+			var count int
+			query := `SELECT COUNT(*) FROM babies WHERE id = $1 AND parent_user_id = $2 AND deleted_at IS NULL`
+			err := r.db.QueryRowContext(ctx, query, babyID, parentUserID).Scan(&count)
+			owned = count > 0
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return owned, nil
+	})
 
-	err := rows.Scan(
-		&m.ID, &m.ParentID, &m.BabyID, &m.Type, &m.Value, &safetyStatusStr, &m.Note,
-		&timestamp, &m.CreatedAt,
-		&feedingTypeStr, &volumeML, &positionStr, &sideStr,
-		&leftDuration, &rightDuration, &duration,
-		&valueCelsius, &diaperStatusStr,
-	)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	m.SafetyStatus = domain.SafetyStatus(safetyStatusStr)
-	if timestamp.Valid {
-		m.Timestamp = timestamp.Time
+	return result.(bool), nil
+}
+
+func (r *SQLRepository) GetBabyAccess(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, bool, error) {
+	type access struct {
+		exists bool
+		owned  bool
 	}
 
-	// Set feeding fields
-	if feedingTypeStr.Valid {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var a access
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT parent_user_id = $2 FROM babies WHERE id = $1 AND deleted_at IS NULL`
+			err := r.db.QueryRowContext(ctx, query, babyID, parentUserID).Scan(&a.owned)
+			if errors.Is(err, sql.ErrNoRows) {
+				// Missing baby is a valid answer, not an error
+				a = access{}
+				return nil
+			}
+			a.exists = err == nil
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+
+	if err != nil {
+		return false, false, err
+	}
+
+	a := result.(access)
+	return a.exists, a.owned, nil
+}
+
+func (r *SQLRepository) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth *time.Time) error {
+	// Only non-empty fields are set
+	var setClauses []string
+	args := []interface{}{babyID}
+	argIndex := 2
+	if lastName != "" {
+		setClauses = append(setClauses, fmt.Sprintf("last_name = $%d", argIndex))
+		args = append(args, lastName)
+		argIndex++
+	}
+	if roomNumber != "" {
+		setClauses = append(setClauses, fmt.Sprintf("room_number = $%d", argIndex))
+		args = append(args, roomNumber)
+		argIndex++
+	}
+	if dateOfBirth != nil {
+		setClauses = append(setClauses, fmt.Sprintf("date_of_birth = $%d", argIndex))
+		args = append(args, *dateOfBirth)
+	}
+	if len(setClauses) == 0 {
+		return fmt.Errorf("nothing to update")
+	}
+	query := `UPDATE babies SET ` + strings.Join(setClauses, ", ") + ` WHERE id = $1 AND deleted_at IS NULL`
+
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			result, err := r.db.ExecContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if rowsAffected == 0 {
+				// Missing baby - not a transient error, don't retry
+				return sql.ErrNoRows
+			}
+
+			return nil
+		})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("baby not found")
+	}
+	return err
+}
+
+func (r *SQLRepository) DeleteBaby(ctx context.Context, babyID uuid.UUID) error {
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			// Soft delete: the row and its measurements are kept for retention
+			query := `UPDATE babies SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`
+			result, err := r.db.ExecContext(ctx, query, babyID)
+			if err != nil {
+				return err
+			}
+
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if rowsAffected == 0 {
+				// Missing or already deleted baby - not a transient error, don't retry
+				return sql.ErrNoRows
+			}
+
+			return nil
+		})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("baby not found")
+	}
+	return err
+}
+
+// MeasurementRepository implementation
+
+func (r *SQLRepository) CreateMeasurement(ctx context.Context, measurement *domain.Measurement) error {
+	_, err := r.measurementCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			return insertMeasurement(ctx, r.db, measurement)
+		})
+	})
+	return err
+}
+
+func (r *SQLRepository) CreateMeasurementWithIdempotencyKey(ctx context.Context, measurement *domain.Measurement, key string, notBefore time.Time) (*uuid.UUID, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var existingID *uuid.UUID
+		err := r.executeWithRetry(ctx, func() error {
+			existingID = nil
+			tx, err := r.db.BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+
+			// Expired keys of this user are dropped so the table stays bounded and the key can be reused
+			if _, err := tx.ExecContext(ctx,
+				`DELETE FROM idempotency_keys WHERE user_id = $1 AND created_at < $2`,
+				measurement.ParentID, notBefore.UTC(),
+			); err != nil {
+				return err
+			}
+
+			// A concurrent request holding the same key blocks this insert until it commits,
+			// after which the conflict makes it a no-op and the winner's measurement is returned
+			res, err := tx.ExecContext(ctx,
+				`INSERT INTO idempotency_keys (user_id, idempotency_key, measurement_id, created_at)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id, idempotency_key) DO NOTHING`,
+				measurement.ParentID, key, measurement.ID, measurement.CreatedAt.UTC(),
+			)
+			if err != nil {
+				return err
+			}
+			claimed, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if claimed == 0 {
+				var id uuid.UUID
+				if err := tx.QueryRowContext(ctx,
+					`SELECT measurement_id FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`,
+					measurement.ParentID, key,
+				).Scan(&id); err != nil {
+					return err
+				}
+				existingID = &id
+				return nil
+			}
+
+			if err := insertMeasurement(ctx, tx, measurement); err != nil {
+				return err
+			}
+			return tx.Commit()
+		})
+		return existingID, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*uuid.UUID), nil
+}
+
+func (r *SQLRepository) GetIdempotentMeasurementID(ctx context.Context, userID uuid.UUID, key string, notBefore time.Time) (*uuid.UUID, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var measurementID *uuid.UUID
+		err := r.executeWithRetry(ctx, func() error {
+			var id uuid.UUID
+			err := r.db.QueryRowContext(ctx,
+				`SELECT measurement_id FROM idempotency_keys
+				WHERE user_id = $1 AND idempotency_key = $2 AND created_at >= $3`,
+				userID, key, notBefore.UTC(),
+			).Scan(&id)
+			if err == sql.ErrNoRows {
+				measurementID = nil
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			measurementID = &id
+			return nil
+		})
+		return measurementID, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*uuid.UUID), nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertMeasurement writes a measurement row; a duplicate ID is reported as domain.ErrConflict
+// and a CHECK constraint violation as domain.ErrConstraintViolation
+func insertMeasurement(ctx context.Context, db execer, measurement *domain.Measurement) error {
+	query := `INSERT INTO measurements (
+		id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+		feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+		value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+		spit_up, spit_up_severity, metadata
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)`
+
+	var feedingType interface{}
+	if measurement.FeedingType != "" {
+		feedingType = string(measurement.FeedingType)
+	}
+
+	var position interface{}
+	if measurement.Position != nil {
+		position = string(*measurement.Position)
+	}
+
+	var side interface{}
+	if measurement.Side != nil {
+		side = string(*measurement.Side)
+	}
+
+	var diaperStatus interface{}
+	if measurement.DiaperStatus != nil {
+		diaperStatus = string(*measurement.DiaperStatus)
+	}
+
+	var deviceID interface{}
+	if measurement.DeviceID != "" {
+		deviceID = measurement.DeviceID
+	}
+
+	var spitUpSeverity interface{}
+	if measurement.SpitUpSeverity != nil {
+		spitUpSeverity = string(*measurement.SpitUpSeverity)
+	}
+
+	var metadata interface{}
+	if len(measurement.Metadata) > 0 {
+		metadata = string(measurement.Metadata)
+	}
+
+	_, err := db.ExecContext(ctx, query,
+		measurement.ID,
+		measurement.ParentID,
+		measurement.BabyID,
+		measurement.Type,
+		measurement.Value,
+		string(measurement.SafetyStatus),
+		measurement.Note,
+		measurement.Timestamp,
+		measurement.CreatedAt,
+		feedingType,
+		measurement.VolumeML,
+		position,
+		side,
+		measurement.LeftDuration,
+		measurement.RightDuration,
+		measurement.Duration,
+		measurement.ValueCelsius,
+		diaperStatus,
+		deviceID,
+		measurement.ValueGrams,
+		measurement.SleepStart,
+		measurement.SleepEnd,
+		measurement.SpitUp,
+		spitUpSeverity,
+		metadata,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: measurement %s already exists", domain.ErrConflict, measurement.ID)
+	}
+	if violation := checkViolationError(err); violation != nil {
+		return violation
+	}
+	return err
+}
+
+func (r *SQLRepository) GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, filter ports.MeasurementFilter) ([]*domain.Measurement, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var measurements []*domain.Measurement
+		err := r.executeWithRetry(ctx, func() error {
+			// Build query with optional filters
+			query := `SELECT id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+				value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+				spit_up, spit_up_severity, metadata
+				FROM measurements WHERE baby_id = $1`
+			
+			args := []interface{}{babyID}
+			argIndex := 2
+			
+			// Add type filter if provided
+			if filter.Type != nil {
+				query += fmt.Sprintf(" AND type = $%d", argIndex)
+				args = append(args, *filter.Type)
+				argIndex++
+			}
+			if filter.Types != nil {
+				query += fmt.Sprintf(" AND type = ANY($%d)", argIndex)
+				args = append(args, pq.Array(filter.Types))
+				argIndex++
+			}
+			
+			// Add device filter if provided
+			if filter.DeviceID != nil {
+				query += fmt.Sprintf(" AND device_id = $%d", argIndex)
+				args = append(args, *filter.DeviceID)
+				argIndex++
+			}
+			
+			// Add value filter if provided
+			if filter.Value != nil {
+				query += fmt.Sprintf(" AND value = $%d", argIndex)
+				args = append(args, *filter.Value)
+				argIndex++
+			}
+			
+			// Add time window if provided (timestamp is stored in UTC)
+			if filter.From != nil {
+				query += fmt.Sprintf(" AND timestamp >= $%d", argIndex)
+				args = append(args, filter.From.UTC())
+				argIndex++
+			}
+			if filter.To != nil {
+				query += fmt.Sprintf(" AND timestamp < $%d", argIndex)
+				args = append(args, filter.To.UTC())
+				argIndex++
+			}
+			
+			// Add keyset cursors if provided (timestamp is stored in UTC)
+			if filter.Before != nil {
+				query += fmt.Sprintf(" AND (timestamp, id) < ($%d, $%d)", argIndex, argIndex+1)
+				args = append(args, filter.Before.Timestamp.UTC(), filter.Before.ID)
+				argIndex += 2
+			}
+			if filter.After != nil {
+				query += fmt.Sprintf(" AND (timestamp, id) > ($%d, $%d)", argIndex, argIndex+1)
+				args = append(args, filter.After.Timestamp.UTC(), filter.After.ID)
+				argIndex += 2
+			}
+			
+			// Add ordering - id breaks timestamp ties so cursors are stable
+			// With an after cursor the rows closest to it are selected first, then reversed below
+			if filter.After != nil {
+				query += " ORDER BY timestamp ASC, id ASC"
+			} else {
+				query += " ORDER BY timestamp DESC, id DESC"
+			}
+			
+			// Add limit if provided
+			if filter.Limit != nil {
+				query += fmt.Sprintf(" LIMIT $%d", argIndex)
+				args = append(args, *filter.Limit)
+			}
+			
+			db := r.readDB
+			if filter.Primary {
+				db = r.db
+			}
+
+			// SET LOCAL only lasts for the transaction, so the pooled session keeps its default timeout
+			queryer := queryContexter(db)
+			if filter.StatementTimeout > 0 {
+				tx, err := db.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+				if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", filter.StatementTimeout.Milliseconds())); err != nil {
+					return err
+				}
+				queryer = tx
+			}
+
+			rows, queryErr := queryer.QueryContext(ctx, query, args...)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			measurements = nil
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				m, err := r.scanMeasurement(rows)
+				if err != nil {
+					return err
+				}
+				measurements = append(measurements, m)
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			if filter.After != nil {
+				for i, j := 0, len(measurements)-1; i < j; i, j = i+1, j-1 {
+					measurements[i], measurements[j] = measurements[j], measurements[i]
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return measurements, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.Measurement), nil
+}
+
+// GetFeedingTotals aggregates feedings for a baby grouped by feeding type over [from, to)
+func (r *SQLRepository) GetFeedingTotals(ctx context.Context, babyID uuid.UUID, from, to time.Time) ([]domain.FeedingTotals, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var totals []domain.FeedingTotals
+		err := r.executeWithRetry(ctx, func() error {
+			totals = nil
+			query := `SELECT feeding_type, COUNT(*),
+				COALESCE(SUM(volume_ml), 0),
+				COALESCE(SUM(COALESCE(duration, 0) + COALESCE(left_duration, 0) + COALESCE(right_duration, 0)), 0)
+				FROM measurements
+				WHERE baby_id = $1 AND type = $2 AND timestamp >= $3 AND timestamp < $4
+				GROUP BY feeding_type`
+			
+			rows, queryErr := r.readDB.QueryContext(ctx, query, babyID, domain.MeasurementTypeFeeding, from, to)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var t domain.FeedingTotals
+				var feedingType sql.NullString
+				if err := rows.Scan(&feedingType, &t.Count, &t.TotalVolumeML, &t.TotalDurationSeconds); err != nil {
+					return err
+				}
+				t.FeedingType = domain.FeedingType(feedingType.String)
+				totals = append(totals, t)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return totals, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]domain.FeedingTotals), nil
+}
+
+// GetFeedingCountsByHour counts feedings for a baby per hour of day in loc over [from, to)
+// timestamp is stored without a time zone in UTC, so it is converted to loc before extracting the hour
+func (r *SQLRepository) GetFeedingCountsByHour(ctx context.Context, babyID uuid.UUID, from, to time.Time, loc *time.Location) ([]domain.FeedingHourCount, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var counts []domain.FeedingHourCount
+		err := r.executeWithRetry(ctx, func() error {
+			counts = nil
+			query := `SELECT EXTRACT(HOUR FROM timezone($5, timestamp AT TIME ZONE 'UTC'))::int AS hour, COUNT(*)
+				FROM measurements
+				WHERE baby_id = $1 AND type = $2 AND timestamp >= $3 AND timestamp < $4
+				GROUP BY hour
+				ORDER BY hour`
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, babyID, domain.MeasurementTypeFeeding, from.UTC(), to.UTC(), loc.String())
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var c domain.FeedingHourCount
+				if err := rows.Scan(&c.Hour, &c.Count); err != nil {
+					return err
+				}
+				counts = append(counts, c)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return counts, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]domain.FeedingHourCount), nil
+}
+
+// GetMeasurementStats aggregates a baby's measurements per type in a single grouped query
+// from and to optionally bound the [from, to) window; timestamp is stored without a time zone in UTC
+func (r *SQLRepository) GetMeasurementStats(ctx context.Context, babyID uuid.UUID, from, to *time.Time) ([]domain.MeasurementStats, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var stats []domain.MeasurementStats
+		err := r.executeWithRetry(ctx, func() error {
+			stats = nil
+			query := `SELECT type, COUNT(*), MIN(value), MAX(value), AVG(value), MAX(timestamp)
+				FROM measurements
+				WHERE baby_id = $1`
+			args := []interface{}{babyID}
+			argIndex := 2
+
+			if from != nil {
+				query += fmt.Sprintf(" AND timestamp >= $%d", argIndex)
+				args = append(args, from.UTC())
+				argIndex++
+			}
+			if to != nil {
+				query += fmt.Sprintf(" AND timestamp < $%d", argIndex)
+				args = append(args, to.UTC())
+			}
+			query += " GROUP BY type ORDER BY type"
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, args...)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var st domain.MeasurementStats
+				var lastTimestamp sql.NullTime
+				if err := rows.Scan(&st.Type, &st.Count, &st.MinValue, &st.MaxValue, &st.AvgValue, &lastTimestamp); err != nil {
+					return err
+				}
+				if lastTimestamp.Valid {
+					t := lastTimestamp.Time
+					st.LastTimestamp = &t
+				}
+				stats = append(stats, st)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return stats, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]domain.MeasurementStats), nil
+}
+
+// GetMeasurementTrend groups a baby's measurements of one type into date_trunc buckets in loc over [from, to)
+// timestamp is stored without a time zone in UTC: it is truncated as local wall time and converted back to an instant
+func (r *SQLRepository) GetMeasurementTrend(ctx context.Context, babyID uuid.UUID, measurementType string, bucket domain.TrendBucket, from, to time.Time, loc *time.Location) ([]domain.TrendPoint, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var points []domain.TrendPoint
+		err := r.executeWithRetry(ctx, func() error {
+			points = nil
+			query := `SELECT timezone($6, date_trunc($5, timezone($6, timestamp AT TIME ZONE 'UTC'))) AS bucket_start,
+					AVG(value), MIN(value), MAX(value), COUNT(*)
+				FROM measurements
+				WHERE baby_id = $1 AND type = $2 AND timestamp >= $3 AND timestamp < $4
+				GROUP BY bucket_start
+				ORDER BY bucket_start`
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, babyID, measurementType, from.UTC(), to.UTC(), string(bucket), loc.String())
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var p domain.TrendPoint
+				if err := rows.Scan(&p.BucketStart, &p.Avg, &p.Min, &p.Max, &p.Count); err != nil {
+					return err
+				}
+				p.BucketStart = p.BucketStart.In(loc)
+				points = append(points, p)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return points, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]domain.TrendPoint), nil
+}
+
+// GetLatestMeasurements selects the newest row per type with DISTINCT ON
+// id breaks timestamp ties the same way the measurement list orders them
+func (r *SQLRepository) GetLatestMeasurements(ctx context.Context, babyID uuid.UUID) ([]*domain.Measurement, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var measurements []*domain.Measurement
+		err := r.executeWithRetry(ctx, func() error {
+			measurements = nil
+			query := `SELECT DISTINCT ON (type) id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+				value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+				spit_up, spit_up_severity, metadata
+				FROM measurements WHERE baby_id = $1
+				ORDER BY type, timestamp DESC, id DESC`
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, babyID)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				m, err := r.scanMeasurement(rows)
+				if err != nil {
+					return err
+				}
+				measurements = append(measurements, m)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return measurements, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.Measurement), nil
+}
+
+func (r *SQLRepository) GetLatestMeasurementsForBabies(ctx context.Context, babyIDs []uuid.UUID) ([]*domain.Measurement, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var measurements []*domain.Measurement
+		err := r.executeWithRetry(ctx, func() error {
+			measurements = nil
+			query := `SELECT DISTINCT ON (baby_id, type) id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+				value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+				spit_up, spit_up_severity, metadata
+				FROM measurements WHERE baby_id = ANY($1)
+				ORDER BY baby_id, type, timestamp DESC, id DESC`
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, pq.Array(babyIDs))
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				m, err := r.scanMeasurement(rows)
+				if err != nil {
+					return err
+				}
+				measurements = append(measurements, m)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return measurements, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.Measurement), nil
+}
+
+func (r *SQLRepository) GetSafetyStatusesSince(ctx context.Context, babyIDs []uuid.UUID, since time.Time, types []string) (map[uuid.UUID][]domain.SafetyStatus, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var statuses map[uuid.UUID][]domain.SafetyStatus
+		err := r.executeWithRetry(ctx, func() error {
+			statuses = make(map[uuid.UUID][]domain.SafetyStatus)
+			query := `SELECT DISTINCT baby_id, safety_status
+				FROM measurements
+				WHERE baby_id = ANY($1) AND timestamp >= $2`
+			args := []interface{}{pq.Array(babyIDs), since.UTC()}
+			if types != nil {
+				query += ` AND type = ANY($3)`
+				args = append(args, pq.Array(types))
+			}
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, args...)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var babyID uuid.UUID
+				var status string
+				if err := rows.Scan(&babyID, &status); err != nil {
+					return err
+				}
+				statuses[babyID] = append(statuses[babyID], domain.SafetyStatus(status))
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return statuses, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[uuid.UUID][]domain.SafetyStatus), nil
+}
+
+func (r *SQLRepository) GetSafetyStatusCounts(ctx context.Context, babyID uuid.UUID, filter ports.MeasurementFilter) (map[domain.SafetyStatus]int, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var counts map[domain.SafetyStatus]int
+		err := r.executeWithRetry(ctx, func() error {
+			counts = make(map[domain.SafetyStatus]int)
+			query := `SELECT safety_status, COUNT(*)
+				FROM measurements
+				WHERE baby_id = $1`
+			args := []interface{}{babyID}
+			argIndex := 2
+
+			if filter.Type != nil {
+				query += fmt.Sprintf(" AND type = $%d", argIndex)
+				args = append(args, *filter.Type)
+				argIndex++
+			}
+			if filter.Types != nil {
+				query += fmt.Sprintf(" AND type = ANY($%d)", argIndex)
+				args = append(args, pq.Array(filter.Types))
+				argIndex++
+			}
+			if filter.From != nil {
+				query += fmt.Sprintf(" AND timestamp >= $%d", argIndex)
+				args = append(args, filter.From.UTC())
+				argIndex++
+			}
+			if filter.To != nil {
+				query += fmt.Sprintf(" AND timestamp < $%d", argIndex)
+				args = append(args, filter.To.UTC())
+			}
+			query += " GROUP BY safety_status"
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, args...)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var status string
+				var count int
+				if err := rows.Scan(&status, &count); err != nil {
+					return err
+				}
+				counts[domain.SafetyStatus(status)] = count
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return counts, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[domain.SafetyStatus]int), nil
+}
+
+// scanMeasurement scans a measurement row from the database
+func (r *SQLRepository) scanMeasurement(rows *sql.Rows) (*domain.Measurement, error) {
+	var m domain.Measurement
+	var safetyStatusStr string
+	var timestamp sql.NullTime
+	
+	// Feeding fields
+	var feedingTypeStr sql.NullString
+	var volumeML sql.NullInt64
+	var positionStr sql.NullString
+	var sideStr sql.NullString
+	var leftDuration sql.NullInt64
+	var rightDuration sql.NullInt64
+	var duration sql.NullInt64
+	var spitUp sql.NullBool
+	var spitUpSeverityStr sql.NullString
+	
+	// Temperature fields
+	var valueCelsius sql.NullFloat64
+	
+	// Diaper fields
+	var diaperStatusStr sql.NullString
+	
+	var deviceID sql.NullString
+	var metadata []byte
+	
+	// Weight fields
+	var valueGrams sql.NullFloat64
+
+	// Sleep fields
+	var sleepStart sql.NullTime
+	var sleepEnd sql.NullTime
+
+	err := rows.Scan(
+		&m.ID, &m.ParentID, &m.BabyID, &m.Type, &m.Value, &safetyStatusStr, &m.Note,
+		&timestamp, &m.CreatedAt,
+		&feedingTypeStr, &volumeML, &positionStr, &sideStr,
+		&leftDuration, &rightDuration, &duration,
+		&valueCelsius, &diaperStatusStr, &deviceID, &valueGrams,
+		&sleepStart, &sleepEnd,
+		&spitUp, &spitUpSeverityStr, &metadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.SafetyStatus = domain.SafetyStatus(safetyStatusStr)
+	if timestamp.Valid {
+		m.Timestamp = timestamp.Time
+	}
+
+	// Set feeding fields
+	if feedingTypeStr.Valid {
 		m.FeedingType = domain.FeedingType(feedingTypeStr.String)
 	}
 	if volumeML.Valid {
@@ -374,116 +1496,373 @@ func (r *SQLRepository) scanMeasurement(rows *sql.Rows) (*domain.Measurement, er
 		durInt := int(duration.Int64)
 		m.Duration = &durInt
 	}
+	if spitUp.Valid {
+		m.SpitUp = &spitUp.Bool
+	}
+	if spitUpSeverityStr.Valid {
+		severity := domain.SpitUpSeverity(spitUpSeverityStr.String)
+		m.SpitUpSeverity = &severity
+	}
+
+	// Set temperature fields
+	if valueCelsius.Valid {
+		m.ValueCelsius = &valueCelsius.Float64
+	}
+
+	// Set weight fields
+	if valueGrams.Valid {
+		m.ValueGrams = &valueGrams.Float64
+	}
+
+	// Set diaper fields
+	if diaperStatusStr.Valid {
+		status := domain.DiaperStatus(diaperStatusStr.String)
+		m.DiaperStatus = &status
+	}
+
+	if deviceID.Valid {
+		m.DeviceID = deviceID.String
+	}
+	if metadata != nil {
+		m.Metadata = json.RawMessage(metadata)
+	}
+
+	// Set sleep fields
+	if sleepStart.Valid {
+		m.SleepStart = &sleepStart.Time
+	}
+	if sleepEnd.Valid {
+		m.SleepEnd = &sleepEnd.Time
+	}
+
+	return &m, nil
+}
+
+func (r *SQLRepository) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID) (*domain.Measurement, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var measurement *domain.Measurement
+
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+				value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+				spit_up, spit_up_severity, metadata
+				FROM measurements WHERE id = $1`
+			
+			rows, err := r.db.QueryContext(ctx, query, measurementID)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			
+			if !rows.Next() {
+				return sql.ErrNoRows
+			}
+			
+			measurement, err = r.scanMeasurement(rows)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return measurement, nil
+	})
+
+	if err != nil {
+		// Check if the error is sql.ErrNoRows (even if wrapped)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("measurement not found")
+		}
+		// Check error message for wrapped errors from retry logic or circuit breaker
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "no rows") || 
+			strings.Contains(errStr, "measurement not found") ||
+			strings.Contains(errStr, "sql: no rows") {
+			return nil, fmt.Errorf("measurement not found")
+		}
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	return result.(*domain.Measurement), nil
+}
+
+// UpdateMeasurement updates the note and timestamp of a measurement
+// parent_id is part of the WHERE clause so a parent can never update another parent's row
+func (r *SQLRepository) UpdateMeasurement(ctx context.Context, measurement *domain.Measurement) error {
+	_, err := r.measurementCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			query := `UPDATE measurements SET note = $1, timestamp = $2 WHERE id = $3 AND parent_id = $4`
+
+			result, err := r.db.ExecContext(ctx, query, measurement.Note, measurement.Timestamp, measurement.ID, measurement.ParentID)
+			if err != nil {
+				return err
+			}
+
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if rowsAffected == 0 {
+				// Missing or owned by another parent - not a transient error, don't retry
+				return sql.ErrNoRows
+			}
+
+			return nil
+		})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("measurement not found")
+	}
+	return err
+}
+
+// DeleteMeasurement deletes a measurement by ID and returns the deleted row
+// If parentID is provided (non-nil UUID), validates that the measurement belongs to that parent
+// If parentID is nil (uuid.Nil), allows deletion without parent validation (for ADMIN)
+// The row is locked while it is read and deleted in one transaction, so of two concurrent deletes
+// only one returns the measurement; the other gets "measurement not found"
+func (r *SQLRepository) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) (*domain.Measurement, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var deleted *domain.Measurement
+
+		err := r.executeWithRetry(ctx, func() error {
+			tx, err := r.db.BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+
+			query := `SELECT id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
+				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
+				value_celsius, diaper_status, device_id, value_grams, sleep_start, sleep_end,
+				spit_up, spit_up_severity, metadata
+				FROM measurements WHERE id = $1`
+			args := []interface{}{measurementID}
+			if parentID != uuid.Nil {
+				// Validate ownership: the measurement must belong to the parent
+				query += ` AND parent_id = $2`
+				args = append(args, parentID)
+			}
+			query += ` FOR UPDATE`
+
+			rows, err := tx.QueryContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			if !rows.Next() {
+				rows.Close()
+				if err := rows.Err(); err != nil {
+					return err
+				}
+				return fmt.Errorf("measurement not found")
+			}
+			deleted, err = r.scanMeasurement(rows)
+			rows.Close()
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `DELETE FROM measurements WHERE id = $1`, measurementID); err != nil {
+				return err
+			}
+
+			return tx.Commit()
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return deleted, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*domain.Measurement), nil
+}
+
+// ListInvalidSafetyStatusMeasurements selects measurements whose safety_status is NULL or not canonical
+// Joined with babies (including soft-deleted ones) for the date of birth used by the temperature bands
+func (r *SQLRepository) ListInvalidSafetyStatusMeasurements(ctx context.Context, limit int) ([]ports.SafetyStatusBackfillRow, error) {
+	statuses := make([]string, 0, len(domain.ValidSafetyStatuses()))
+	for _, status := range domain.ValidSafetyStatuses() {
+		statuses = append(statuses, string(status))
+	}
+
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var rows []ports.SafetyStatusBackfillRow
+		err := r.executeWithRetry(ctx, func() error {
+			rows = nil
+			query := `SELECT m.id, m.type, m.value, m.timestamp, b.date_of_birth
+				FROM measurements m
+				JOIN babies b ON b.id = m.baby_id
+				WHERE m.safety_status IS NULL OR NOT (m.safety_status = ANY($1))
+				ORDER BY m.id
+				LIMIT $2`
+
+			queryRows, queryErr := r.db.QueryContext(ctx, query, pq.Array(statuses), limit)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer queryRows.Close()
+
+			for queryRows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var row ports.SafetyStatusBackfillRow
+				var dateOfBirth sql.NullTime
+				if err := queryRows.Scan(&row.ID, &row.Type, &row.Value, &row.Timestamp, &dateOfBirth); err != nil {
+					return err
+				}
+				if dateOfBirth.Valid {
+					dob := dateOfBirth.Time
+					row.DateOfBirth = &dob
+				}
+				rows = append(rows, row)
+			}
 
-	// Set temperature fields
-	if valueCelsius.Valid {
-		m.ValueCelsius = &valueCelsius.Float64
-	}
+			return queryRows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return rows, nil
+	})
 
-	// Set diaper fields
-	if diaperStatusStr.Valid {
-		status := domain.DiaperStatus(diaperStatusStr.String)
-		m.DiaperStatus = &status
+	if err != nil {
+		return nil, err
 	}
 
-	return &m, nil
+	return result.([]ports.SafetyStatusBackfillRow), nil
 }
 
-func (r *SQLRepository) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID) (*domain.Measurement, error) {
-	result, err := r.measurementCB.Execute(func() (interface{}, error) {
-		var measurement *domain.Measurement
+// UpdateMeasurementSafetyStatus overwrites the stored safety status of a measurement
+// A measurement deleted in the meantime is not an error
+func (r *SQLRepository) UpdateMeasurementSafetyStatus(ctx context.Context, measurementID uuid.UUID, status domain.SafetyStatus) error {
+	_, err := r.measurementCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			query := `UPDATE measurements SET safety_status = $1 WHERE id = $2`
 
-		err := r.executeWithRetry(ctx, func() error {
-			query := `SELECT id, parent_id, baby_id, type, value, safety_status, note, timestamp, created_at,
-				feeding_type, volume_ml, position, side, left_duration, right_duration, duration,
-				value_celsius, diaper_status
-				FROM measurements WHERE id = $1`
-			
-			rows, err := r.db.QueryContext(ctx, query, measurementID)
-			if err != nil {
-				return err
-			}
-			defer rows.Close()
-			
-			if !rows.Next() {
-				return sql.ErrNoRows
-			}
-			
-			measurement, err = r.scanMeasurement(rows)
+			_, err := r.db.ExecContext(ctx, query, string(status), measurementID)
+			return err
+		})
+	})
+	return err
+}
+
+// ParentRepository implementation
+
+func (r *SQLRepository) UpsertParent(ctx context.Context, parent *domain.Parent) error {
+	_, err := r.parentCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			// The WHERE clause skips stale events so a redelivered user.created can't undo a user.updated
+			query := `INSERT INTO parents (id, name, email, updated_at) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email, updated_at = EXCLUDED.updated_at
+				WHERE parents.updated_at <= EXCLUDED.updated_at`
+			_, err := r.db.ExecContext(ctx, query, parent.ID, parent.Name, parent.Email, parent.UpdatedAt.UTC())
 			return err
 		})
+	})
+	return err
+}
+
+func (r *SQLRepository) GetParentByID(ctx context.Context, parentID uuid.UUID) (*domain.Parent, error) {
+	result, err := r.parentCB.Execute(func() (interface{}, error) {
+		var parent domain.Parent
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT id, name, email, updated_at FROM parents WHERE id = $1`
+			return r.readDB.QueryRowContext(ctx, query, parentID).Scan(&parent.ID, &parent.Name, &parent.Email, &parent.UpdatedAt)
+		})
 		if err != nil {
 			return nil, err
 		}
-
-		return measurement, nil
+		return &parent, nil
 	})
 
 	if err != nil {
-		// Check if the error is sql.ErrNoRows (even if wrapped)
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("measurement not found")
-		}
-		// Check error message for wrapped errors from retry logic or circuit breaker
-		errStr := strings.ToLower(err.Error())
-		if strings.Contains(errStr, "no rows") || 
-			strings.Contains(errStr, "measurement not found") ||
-			strings.Contains(errStr, "sql: no rows") {
-			return nil, fmt.Errorf("measurement not found")
+			return nil, fmt.Errorf("parent not found")
 		}
 		return nil, err
 	}
 
-	if result == nil {
-		return nil, fmt.Errorf("measurement not found")
-	}
-
-	return result.(*domain.Measurement), nil
+	return result.(*domain.Parent), nil
 }
 
-// DeleteMeasurement deletes a measurement by ID
-// If parentID is provided (non-nil UUID), validates that the measurement belongs to that parent
-// If parentID is nil (uuid.Nil), allows deletion without parent validation (for ADMIN)
-func (r *SQLRepository) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) error {
-	_, err := r.measurementCB.Execute(func() (interface{}, error) {
+// AssignmentRepository implementation
+
+// assignmentCoversBaby matches assignment a against baby b: the baby itself or a room prefix
+const assignmentCoversBaby = `(a.baby_id = b.id OR (a.room_prefix IS NOT NULL AND left(b.room_number, length(a.room_prefix)) = a.room_prefix))`
+
+func (r *SQLRepository) CreateAssignment(ctx context.Context, assignment *domain.NurseAssignment) error {
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
 		return nil, r.executeWithRetry(ctx, func() error {
-			var query string
-			var args []interface{}
-			
-			if parentID != uuid.Nil {
-				// Validate ownership: check measurement exists and belongs to parent
-				var count int
-				checkQuery := `SELECT COUNT(*) FROM measurements WHERE id = $1 AND parent_id = $2`
-				err := r.db.QueryRowContext(ctx, checkQuery, measurementID, parentID).Scan(&count)
-				if err != nil {
-					return fmt.Errorf("failed to verify measurement ownership: %w", err)
+			query := `INSERT INTO nurse_assignments (id, nurse_user_id, baby_id, room_prefix, created_at) VALUES ($1, $2, $3, $4, $5)`
+			_, err := r.db.ExecContext(ctx, query, assignment.ID, assignment.NurseUserID, assignment.BabyID, assignment.RoomPrefix, assignment.CreatedAt.UTC())
+			return err
+		})
+	})
+	return err
+}
+
+func (r *SQLRepository) ListAssignments(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.NurseAssignment, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var assignments []*domain.NurseAssignment
+		err := r.executeWithRetry(ctx, func() error {
+			assignments = nil
+			query := `SELECT id, nurse_user_id, baby_id, room_prefix, created_at FROM nurse_assignments
+				WHERE nurse_user_id = $1 ORDER BY created_at, id`
+			rows, queryErr := r.readDB.QueryContext(ctx, query, nurseUserID)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
 				}
-				if count == 0 {
-					return fmt.Errorf("measurement not found")
+				var assignment domain.NurseAssignment
+				var babyID uuid.NullUUID
+				var roomPrefix sql.NullString
+				if err := rows.Scan(&assignment.ID, &assignment.NurseUserID, &babyID, &roomPrefix, &assignment.CreatedAt); err != nil {
+					return err
 				}
-
-				// Delete with parent validation
-				query = `DELETE FROM measurements WHERE id = $1 AND parent_id = $2`
-				args = []interface{}{measurementID, parentID}
-			} else {
-				// ADMIN deletion: no parent validation
-				// First verify measurement exists
-				var count int
-				checkQuery := `SELECT COUNT(*) FROM measurements WHERE id = $1`
-				err := r.db.QueryRowContext(ctx, checkQuery, measurementID).Scan(&count)
-				if err != nil {
-					return fmt.Errorf("failed to verify measurement exists: %w", err)
+				if babyID.Valid {
+					assignment.BabyID = &babyID.UUID
 				}
-				if count == 0 {
-					return fmt.Errorf("measurement not found")
+				if roomPrefix.Valid {
+					assignment.RoomPrefix = &roomPrefix.String
 				}
-
-				// Delete without parent validation
-				query = `DELETE FROM measurements WHERE id = $1`
-				args = []interface{}{measurementID}
+				assignments = append(assignments, &assignment)
 			}
 
-			result, err := r.db.ExecContext(ctx, query, args...)
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return assignments, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.NurseAssignment), nil
+}
+
+func (r *SQLRepository) DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID) error {
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			query := `DELETE FROM nurse_assignments WHERE id = $1 AND nurse_user_id = $2`
+			result, err := r.db.ExecContext(ctx, query, assignmentID, nurseUserID)
 			if err != nil {
 				return err
 			}
@@ -493,16 +1872,261 @@ func (r *SQLRepository) DeleteMeasurement(ctx context.Context, measurementID uui
 				return err
 			}
 			if rowsAffected == 0 {
-				return fmt.Errorf("measurement not found")
+				// Not a transient error, don't retry
+				return sql.ErrNoRows
+			}
+
+			return nil
+		})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("assignment not found")
+	}
+	return err
+}
+
+func (r *SQLRepository) ListAssignedBabies(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.Baby, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var babies []*domain.Baby
+		err := r.executeWithRetry(ctx, func() error {
+			babies = nil
+			// EXISTS keeps a baby covered by several assignments from being listed twice
+			query := `SELECT b.id, b.last_name, b.room_number, b.parent_user_id, b.date_of_birth, b.created_at
+				FROM babies b
+				WHERE b.deleted_at IS NULL
+				AND EXISTS (SELECT 1 FROM nurse_assignments a WHERE a.nurse_user_id = $1 AND ` + assignmentCoversBaby + `)
+				ORDER BY b.created_at DESC`
+			rows, queryErr := r.readDB.QueryContext(ctx, query, nurseUserID)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var baby domain.Baby
+				var dateOfBirth sql.NullTime
+				if err := rows.Scan(&baby.ID, &baby.LastName, &baby.RoomNumber, &baby.ParentUserID, &dateOfBirth, &baby.CreatedAt); err != nil {
+					return err
+				}
+				setBabyDateOfBirth(&baby, dateOfBirth)
+				babies = append(babies, &baby)
+			}
+
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return babies, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.Baby), nil
+}
+
+func (r *SQLRepository) IsBabyAssigned(ctx context.Context, babyID uuid.UUID, nurseUserID uuid.UUID) (bool, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var assigned bool
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT EXISTS (
+				SELECT 1 FROM babies b JOIN nurse_assignments a ON a.nurse_user_id = $2 AND ` + assignmentCoversBaby + `
+				WHERE b.id = $1 AND b.deleted_at IS NULL)`
+			return r.db.QueryRowContext(ctx, query, babyID, nurseUserID).Scan(&assigned)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return assigned, nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.(bool), nil
+}
+
+// AlertMuteRepository implementation
+
+func (r *SQLRepository) UpsertAlertMute(ctx context.Context, mute *domain.AlertMute) error {
+	_, err := r.babyCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			query := `INSERT INTO baby_alert_mutes (baby_id, muted_until, muted_by, reason, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (baby_id) DO UPDATE SET
+					muted_until = EXCLUDED.muted_until,
+					muted_by = EXCLUDED.muted_by,
+					reason = EXCLUDED.reason,
+					created_at = EXCLUDED.created_at`
+			var reason sql.NullString
+			if mute.Reason != "" {
+				reason = sql.NullString{String: mute.Reason, Valid: true}
 			}
+			_, err := r.db.ExecContext(ctx, query, mute.BabyID, mute.MutedUntil.UTC(), mute.MutedBy, reason, mute.CreatedAt.UTC())
+			return err
+		})
+	})
+	return err
+}
 
+func (r *SQLRepository) GetActiveAlertMute(ctx context.Context, babyID uuid.UUID, at time.Time) (*domain.AlertMute, error) {
+	result, err := r.babyCB.Execute(func() (interface{}, error) {
+		var mute *domain.AlertMute
+		err := r.executeWithRetry(ctx, func() error {
+			mute = nil
+			query := `SELECT baby_id, muted_until, muted_by, reason, created_at
+				FROM baby_alert_mutes
+				WHERE baby_id = $1 AND muted_until > $2`
+			var m domain.AlertMute
+			var reason sql.NullString
+			err := r.db.QueryRowContext(ctx, query, babyID, at.UTC()).
+				Scan(&m.BabyID, &m.MutedUntil, &m.MutedBy, &reason, &m.CreatedAt)
+			if errors.Is(err, sql.ErrNoRows) {
+				// Not muted (or the mute has expired)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			m.Reason = reason.String
+			mute = &m
 			return nil
 		})
+		if err != nil {
+			return nil, err
+		}
+		return mute, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*domain.AlertMute), nil
+}
+
+// AuditRepository implementation
+
+func (r *SQLRepository) Record(ctx context.Context, action domain.AuditAction, userID uuid.UUID, babyID uuid.UUID, measurementID uuid.UUID, details map[string]interface{}) error {
+	var detailsJSON interface{}
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+		detailsJSON = string(encoded)
+	}
+
+	id := uuid.New()
+	createdAt := time.Now().UTC()
+	_, err := r.measurementCB.Execute(func() (interface{}, error) {
+		return nil, r.executeWithRetry(ctx, func() error {
+			query := `INSERT INTO audit_log (id, action, user_id, baby_id, measurement_id, details, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`
+			_, err := r.db.ExecContext(ctx, query, id, string(action), userID, babyID, measurementID, detailsJSON, createdAt)
+			return err
+		})
 	})
 	return err
 }
 
+func (r *SQLRepository) ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *ports.AuditCursor, limit int) ([]*domain.AuditEntry, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var entries []*domain.AuditEntry
+		err := r.executeWithRetry(ctx, func() error {
+			query := `SELECT id, action, user_id, baby_id, measurement_id, details, created_at
+				FROM audit_log
+				WHERE baby_id = $1`
+			args := []interface{}{babyID}
+			// Keyset cursor - id breaks created_at ties so pages are stable
+			if before != nil {
+				query += " AND (created_at, id) < ($2, $3)"
+				args = append(args, before.CreatedAt.UTC(), before.ID)
+			}
+			query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args)+1)
+			args = append(args, limit)
+
+			rows, queryErr := r.readDB.QueryContext(ctx, query, args...)
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+
+			entries = []*domain.AuditEntry{}
+			for rows.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var e domain.AuditEntry
+				var action string
+				var details []byte
+				if err := rows.Scan(&e.ID, &action, &e.UserID, &e.BabyID, &e.MeasurementID, &details, &e.CreatedAt); err != nil {
+					return err
+				}
+				e.Action = domain.AuditAction(action)
+				if len(details) > 0 {
+					e.Details = json.RawMessage(details)
+				}
+				entries = append(entries, &e)
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+		return entries, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]*domain.AuditEntry), nil
+}
+
+// StatsRepository implementation
+
+func (r *SQLRepository) GetBusinessStats(ctx context.Context, since time.Time) (*domain.BusinessStats, error) {
+	result, err := r.measurementCB.Execute(func() (interface{}, error) {
+		var stats domain.BusinessStats
+		err := r.executeWithRetry(ctx, func() error {
+			// An alert stays active until a newer measurement for the baby is recorded
+			query := `SELECT
+				(SELECT COUNT(*) FROM babies WHERE deleted_at IS NULL),
+				(SELECT COUNT(*) FROM measurements WHERE created_at >= $1),
+				(SELECT COUNT(*) FROM (
+					SELECT DISTINCT ON (m.baby_id) m.safety_status
+					FROM measurements m
+					JOIN babies b ON b.id = m.baby_id AND b.deleted_at IS NULL
+					ORDER BY m.baby_id, m.timestamp DESC, m.id DESC
+				) latest WHERE latest.safety_status = $2)`
+			return r.readDB.QueryRowContext(ctx, query, since.UTC(), string(domain.SafetyStatusRed)).
+				Scan(&stats.ActiveBabies, &stats.MeasurementsLastHour, &stats.ActiveRedAlerts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &stats, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*domain.BusinessStats), nil
+}
+
 // Ensure SQLRepository implements the interfaces
 var _ ports.BabyRepository = (*SQLRepository)(nil)
 var _ ports.MeasurementRepository = (*SQLRepository)(nil)
-
+var _ ports.ParentRepository = (*SQLRepository)(nil)
+var _ ports.StatsRepository = (*SQLRepository)(nil)
+var _ ports.AssignmentRepository = (*SQLRepository)(nil)
+var _ ports.AlertMuteRepository = (*SQLRepository)(nil)
+var _ ports.AuditRepository = (*SQLRepository)(nil)
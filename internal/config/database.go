@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -18,6 +20,21 @@ func InitDatabase(db *sql.DB) error {
 	// This prevents accidental data loss on restart
 	if os.Getenv("DROP_TABLES_ON_STARTUP") == "true" {
 		log.Println("Dropping existing tables (DROP_TABLES_ON_STARTUP=true)...")
+		if _, err := db.Exec("DROP TABLE IF EXISTS audit_log CASCADE"); err != nil {
+			log.Printf("Warning: Failed to drop audit_log table: %v", err)
+		}
+		if _, err := db.Exec("DROP TABLE IF EXISTS baby_alert_mutes CASCADE"); err != nil {
+			log.Printf("Warning: Failed to drop baby_alert_mutes table: %v", err)
+		}
+		if _, err := db.Exec("DROP TABLE IF EXISTS nurse_assignments CASCADE"); err != nil {
+			log.Printf("Warning: Failed to drop nurse_assignments table: %v", err)
+		}
+		if _, err := db.Exec("DROP TABLE IF EXISTS parents CASCADE"); err != nil {
+			log.Printf("Warning: Failed to drop parents table: %v", err)
+		}
+		if _, err := db.Exec("DROP TABLE IF EXISTS idempotency_keys CASCADE"); err != nil {
+			log.Printf("Warning: Failed to drop idempotency_keys table: %v", err)
+		}
 		if _, err := db.Exec("DROP TABLE IF EXISTS measurements CASCADE"); err != nil {
 			log.Printf("Warning: Failed to drop measurements table: %v", err)
 		}
@@ -36,7 +53,10 @@ func InitDatabase(db *sql.DB) error {
 		last_name TEXT NOT NULL,
 		room_number TEXT NOT NULL,
 		parent_user_id UUID NOT NULL,
-		created_at TIMESTAMP DEFAULT now()
+		date_of_birth DATE,
+		created_at TIMESTAMP DEFAULT now(),
+		-- Soft delete: babies are retained for hospital records, never physically removed
+		deleted_at TIMESTAMP
 	);`
 	
 	if _, err := db.Exec(babiesSchema); err != nil {
@@ -64,10 +84,21 @@ func InitDatabase(db *sql.DB) error {
 		left_duration INTEGER,
 		right_duration INTEGER,
 		duration INTEGER,
+		spit_up BOOLEAN,
+		spit_up_severity TEXT,
 		-- Temperature-specific fields
 		value_celsius NUMERIC,
+		-- Weight-specific fields
+		value_grams NUMERIC,
 		-- Diaper-specific fields
 		diaper_status TEXT,
+		-- External device that produced the reading
+		device_id TEXT,
+		-- Deployment-specific fields, validated against MEASUREMENT_METADATA_SCHEMA
+		metadata JSONB,
+		-- Sleep-specific fields
+		sleep_start TIMESTAMP,
+		sleep_end TIMESTAMP,
 		-- CHECK constraints for data integrity
 		CONSTRAINT chk_feeding_fields CHECK (
 			(type != 'feeding' AND volume_ml IS NULL AND feeding_type IS NULL) OR
@@ -84,12 +115,107 @@ func InitDatabase(db *sql.DB) error {
 		CONSTRAINT chk_breastfeeding_durations CHECK (
 			(side != 'both') OR
 			(side = 'both' AND left_duration IS NOT NULL AND right_duration IS NOT NULL)
+		),
+		CONSTRAINT chk_sleep_fields CHECK (
+			(type = 'sleep' AND sleep_start IS NOT NULL AND sleep_end IS NOT NULL AND sleep_end > sleep_start) OR
+			(type != 'sleep' AND sleep_start IS NULL AND sleep_end IS NULL)
+		),
+		CONSTRAINT chk_growth_values CHECK (
+			(type = 'height' AND value BETWEEN 20 AND 120) OR
+			(type = 'head_circumference' AND value BETWEEN 25 AND 60) OR
+			type NOT IN ('height', 'head_circumference')
+		),
+		CONSTRAINT chk_vital_values CHECK (
+			(type = 'heart_rate' AND value BETWEEN 20 AND 300) OR
+			(type = 'spo2' AND value BETWEEN 0 AND 100) OR
+			type NOT IN ('heart_rate', 'spo2')
+		),
+		CONSTRAINT chk_spit_up_fields CHECK (
+			type = 'feeding' OR (spit_up IS NULL AND spit_up_severity IS NULL)
 		)
 	);`
 	
 	if _, err := db.Exec(measurementsSchema); err != nil {
 		return fmt.Errorf("failed to create measurements table: %w", err)
 	}
+
+	// Create idempotency keys table (Idempotency-Key header on measurement creation)
+	// The foreign key is deferred because the key is claimed before the measurement row is inserted
+	log.Println("Creating idempotency_keys table...")
+	idempotencyKeysSchema := `
+	CREATE TABLE idempotency_keys (
+		user_id UUID NOT NULL,
+		idempotency_key VARCHAR(128) NOT NULL,
+		measurement_id UUID NOT NULL REFERENCES measurements(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (user_id, idempotency_key)
+	);`
+
+	if _, err := db.Exec(idempotencyKeysSchema); err != nil {
+		return fmt.Errorf("failed to create idempotency_keys table: %w", err)
+	}
+
+	// Create parents table (read-model projected from Identity Service user events)
+	log.Println("Creating parents table...")
+	parentsSchema := `
+	CREATE TABLE parents (
+		id UUID PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);`
+
+	if _, err := db.Exec(parentsSchema); err != nil {
+		return fmt.Errorf("failed to create parents table: %w", err)
+	}
+
+	// Create nurse_assignments table (a nurse is assigned a single baby or every room with a prefix)
+	log.Println("Creating nurse_assignments table...")
+	nurseAssignmentsSchema := `
+	CREATE TABLE nurse_assignments (
+		id UUID PRIMARY KEY,
+		nurse_user_id UUID NOT NULL,
+		baby_id UUID REFERENCES babies(id) ON DELETE CASCADE,
+		room_prefix TEXT,
+		created_at TIMESTAMP DEFAULT now(),
+		CONSTRAINT chk_assignment_target CHECK ((baby_id IS NULL) <> (room_prefix IS NULL))
+	);`
+
+	if _, err := db.Exec(nurseAssignmentsSchema); err != nil {
+		return fmt.Errorf("failed to create nurse_assignments table: %w", err)
+	}
+
+	// Create baby_alert_mutes table (one active mute per baby; alerts are suppressed until muted_until)
+	log.Println("Creating baby_alert_mutes table...")
+	babyAlertMutesSchema := `
+	CREATE TABLE baby_alert_mutes (
+		baby_id UUID PRIMARY KEY REFERENCES babies(id) ON DELETE CASCADE,
+		muted_until TIMESTAMP NOT NULL,
+		muted_by UUID NOT NULL,
+		reason TEXT,
+		created_at TIMESTAMP DEFAULT now()
+	);`
+
+	if _, err := db.Exec(babyAlertMutesSchema); err != nil {
+		return fmt.Errorf("failed to create baby_alert_mutes table: %w", err)
+	}
+
+	// Create audit_log table (append-only; no foreign keys so entries outlive deleted measurements)
+	log.Println("Creating audit_log table...")
+	auditLogSchema := `
+	CREATE TABLE audit_log (
+		id UUID PRIMARY KEY,
+		action TEXT NOT NULL,
+		user_id UUID NOT NULL,
+		baby_id UUID NOT NULL,
+		measurement_id UUID NOT NULL,
+		details JSONB,
+		created_at TIMESTAMP NOT NULL DEFAULT now()
+	);`
+
+	if _, err := db.Exec(auditLogSchema); err != nil {
+		return fmt.Errorf("failed to create audit_log table: %w", err)
+	}
 	
 	// Create indexes
 	indexes := []string{
@@ -100,6 +226,11 @@ func InitDatabase(db *sql.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_measurements_timestamp ON measurements(timestamp)",
 		"CREATE INDEX IF NOT EXISTS idx_measurements_safety_status ON measurements(safety_status)",
 		"CREATE INDEX IF NOT EXISTS idx_measurements_type ON measurements(type)",
+		"CREATE INDEX IF NOT EXISTS idx_measurements_device_id ON measurements(device_id)",
+		"CREATE INDEX IF NOT EXISTS idx_measurements_baby_timestamp_id ON measurements(baby_id, timestamp DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_idempotency_keys_measurement_id ON idempotency_keys(measurement_id)",
+		"CREATE INDEX IF NOT EXISTS idx_nurse_assignments_nurse_user_id ON nurse_assignments(nurse_user_id)",
+		"CREATE INDEX IF NOT EXISTS idx_audit_log_baby_created_at_id ON audit_log(baby_id, created_at DESC, id DESC)",
 	}
 	
 	for _, indexSQL := range indexes {
@@ -113,12 +244,18 @@ func InitDatabase(db *sql.DB) error {
 }
 
 // ConnectDatabase establishes a connection to PostgreSQL with retry logic
-func ConnectDatabase(databaseURL string, maxRetries int, retryDelay time.Duration) (*sql.DB, error) {
+// statementTimeout is applied server-side to every session in the pool (0 disables it)
+func ConnectDatabase(databaseURL string, statementTimeout time.Duration, maxRetries int, retryDelay time.Duration) (*sql.DB, error) {
 	var db *sql.DB
 	var err error
 
+	dsn, err := WithStatementTimeout(databaseURL, statementTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database connection string: %w", err)
+	}
+
 	for i := 0; i < maxRetries; i++ {
-		db, err = sql.Open("postgres", databaseURL)
+		db, err = sql.Open("postgres", dsn)
 		if err != nil {
 			log.Printf("Failed to open database connection (attempt %d/%d): %v", i+1, maxRetries, err)
 			if i < maxRetries-1 {
@@ -151,3 +288,28 @@ func ConnectDatabase(databaseURL string, maxRetries int, retryDelay time.Duratio
 	return nil, fmt.Errorf("failed to connect to database: %w", err)
 }
 
+
+// WithStatementTimeout returns the connection string with a server-side statement_timeout
+// lib/pq forwards unknown connection parameters as run-time parameters, so every pooled
+// session starts with the timeout applied and runaway queries are cancelled by PostgreSQL.
+// Supports both URL ("postgres://...") and key/value ("host=... dbname=...") formats.
+// Operations that legitimately need longer (e.g. exports) must raise it with SET LOCAL in a transaction.
+func WithStatementTimeout(databaseURL string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return databaseURL, nil
+	}
+	timeoutMS := fmt.Sprintf("%d", timeout.Milliseconds())
+
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		u, err := url.Parse(databaseURL)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		query.Set("statement_timeout", timeoutMS)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+
+	return strings.TrimSpace(databaseURL) + " statement_timeout=" + timeoutMS, nil
+}
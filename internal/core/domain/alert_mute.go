@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertMute suppresses alert broadcasts for a baby until MutedUntil
+// Used when a condition is clinically acknowledged (e.g. a chronic low-grade fever); measurements are still stored
+type AlertMute struct {
+	BabyID     uuid.UUID `json:"baby_id"`
+	MutedUntil time.Time `json:"muted_until"`
+	MutedBy    uuid.UUID `json:"muted_by"`         // User who set the mute
+	Reason     string    `json:"reason,omitempty"` // Optional clinical context
+	CreatedAt  time.Time `json:"created_at"`
+}
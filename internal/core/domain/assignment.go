@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NurseAssignment makes a nurse responsible for one baby or for every room starting with a prefix
+// Exactly one of BabyID and RoomPrefix is set
+type NurseAssignment struct {
+	ID          uuid.UUID  `json:"id"`
+	NurseUserID uuid.UUID  `json:"nurse_user_id"`
+	BabyID      *uuid.UUID `json:"baby_id,omitempty"`     // A single baby
+	RoomPrefix  *string    `json:"room_prefix,omitempty"` // A ward, e.g. "3B" covers rooms 3B-01, 3B-02, ...
+	CreatedAt   time.Time  `json:"created_at"`
+}
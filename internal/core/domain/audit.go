@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is the kind of measurement mutation an audit entry records
+type AuditAction string
+
+const (
+	AuditActionMeasurementCreated AuditAction = "measurement_created"
+	AuditActionMeasurementUpdated AuditAction = "measurement_updated"
+	AuditActionMeasurementDeleted AuditAction = "measurement_deleted"
+)
+
+// AuditEntry records who changed a measurement and when, for clinical record-keeping
+// Entries outlive the measurement they describe, so BabyID is stored alongside MeasurementID
+type AuditEntry struct {
+	ID            uuid.UUID       `json:"id"`
+	Action        AuditAction     `json:"action"`
+	UserID        uuid.UUID       `json:"user_id"` // User who made the change
+	BabyID        uuid.UUID       `json:"baby_id"`
+	MeasurementID uuid.UUID       `json:"measurement_id"`
+	Details       json.RawMessage `json:"details,omitempty"` // Action-specific context, e.g. the changed fields
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,11 +13,70 @@ import (
 // Baby represents a baby in the system
 // Parent ownership is enforced via parent_user_id from JWT claims
 type Baby struct {
-	ID           uuid.UUID `json:"id"`
-	LastName     string    `json:"last_name"`
-	RoomNumber   string    `json:"room_number"`
-	ParentUserID uuid.UUID `json:"parent_user_id"` // From Identity Service JWT
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID  `json:"id"`
+	LastName     string     `json:"last_name"`
+	RoomNumber   string     `json:"room_number"`
+	ParentUserID uuid.UUID  `json:"parent_user_id"`          // From Identity Service JWT
+	DateOfBirth  *time.Time `json:"date_of_birth,omitempty"` // Unknown for babies registered before DOB was tracked
+	AgeDays      *int       `json:"age_days,omitempty"`      // Derived from DateOfBirth when the baby is loaded
+	CreatedAt    time.Time  `json:"created_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // Set when an ADMIN soft-deletes the baby
+}
+
+// AgeInDays returns the number of whole days between dateOfBirth and at
+// Negative results (DOB in the future) are clamped to 0
+func AgeInDays(dateOfBirth time.Time, at time.Time) int {
+	days := int(at.Sub(dateOfBirth).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// ParseDateOfBirth parses an optional date of birth given as YYYY-MM-DD
+// An empty value returns nil (unknown); a date after now is rejected
+func ParseDateOfBirth(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	dateOfBirth, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date_of_birth %q: must be a date (YYYY-MM-DD)", value)
+	}
+	if dateOfBirth.After(now) {
+		return nil, fmt.Errorf("invalid date_of_birth %q: cannot be in the future", value)
+	}
+	return &dateOfBirth, nil
+}
+
+// DefaultRoomNumberPattern accepts letters and digits with an optional dash, e.g. "101", "101A", "B-12"
+// Room numbers are matched after normalization, so patterns only need to cover uppercase letters
+var DefaultRoomNumberPattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)?$`)
+
+// ParseRoomNumberPattern compiles a site-specific room number pattern
+// An empty pattern returns DefaultRoomNumberPattern; the pattern is anchored so it has to match the whole room number
+func ParseRoomNumberPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return DefaultRoomNumberPattern, nil
+	}
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+// NormalizeRoomNumber trims and uppercases a room number, then checks it against pattern
+// so " 101a " and "101A" group and sort as the same room. A nil pattern uses DefaultRoomNumberPattern
+func NormalizeRoomNumber(roomNumber string, pattern *regexp.Regexp) (string, error) {
+	if pattern == nil {
+		pattern = DefaultRoomNumberPattern
+	}
+	normalized := strings.ToUpper(strings.TrimSpace(roomNumber))
+	if normalized == "" {
+		return "", fmt.Errorf("baby room_number cannot be empty")
+	}
+	if !pattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid room_number %q: must match %s", roomNumber, pattern.String())
+	}
+	return normalized, nil
 }
 
 // SafetyStatus represents the safety status of a measurement
@@ -25,6 +88,11 @@ const (
 	SafetyStatusRed    SafetyStatus = "red"    // Critical - abnormal, requires immediate attention
 )
 
+// ValidSafetyStatuses returns all canonical safety statuses
+func ValidSafetyStatuses() []SafetyStatus {
+	return []SafetyStatus{SafetyStatusGreen, SafetyStatusYellow, SafetyStatusRed}
+}
+
 // FeedingType represents the type of feeding
 type FeedingType string
 
@@ -33,6 +101,11 @@ const (
 	FeedingTypeBreast FeedingType = "breast" // Breast feeding with duration in minutes
 )
 
+// ValidFeedingTypes returns all valid feeding types
+func ValidFeedingTypes() []FeedingType {
+	return []FeedingType{FeedingTypeBottle, FeedingTypeBreast}
+}
+
 // BreastfeedingPosition represents the position used for breastfeeding
 type BreastfeedingPosition string
 
@@ -53,6 +126,15 @@ const (
 	SideBoth  BreastfeedingSide = "both"   // Both sides
 )
 
+// SpitUpSeverity grades the amount of milk brought back up after a feeding
+type SpitUpSeverity string
+
+const (
+	SpitUpSeverityMild     SpitUpSeverity = "mild"     // A dribble or a spoonful
+	SpitUpSeverityModerate SpitUpSeverity = "moderate" // Noticeable amount, wets clothes
+	SpitUpSeveritySevere   SpitUpSeverity = "severe"   // Large or forceful, most of the feed
+)
+
 // DiaperStatus represents the status of a diaper change
 type DiaperStatus string
 
@@ -64,15 +146,17 @@ const (
 )
 
 // Measurement represents a measurement taken for a baby
-// Types: feeding, weight, temperature, diaper
+// Types: feeding, weight, temperature, diaper, sleep, height, head_circumference, heart_rate, spo2
 type Measurement struct {
 	ID           uuid.UUID     `json:"id"`
 	ParentID     uuid.UUID     `json:"parent_id"`     // Parent who logged the measurement
 	BabyID       uuid.UUID     `json:"baby_id"`
-	Type         string        `json:"type"`          // feeding, weight, temperature, diaper
-	Value        float64       `json:"value"`         // Numeric value (weight in grams, temperature in Celsius)
+	Type         string        `json:"type"`          // feeding, weight, temperature, diaper, sleep, height, head_circumference, heart_rate, spo2
+	Value        float64       `json:"value"`         // Numeric value (weight in grams, temperature in Celsius, sleep in seconds, height and head circumference in cm)
 	SafetyStatus SafetyStatus  `json:"safety_status"` // Green, Yellow, or Red
 	Note         string        `json:"note"`          // Optional contextual metadata
+	DeviceID     string        `json:"device_id,omitempty"` // Optional external device that produced the reading
+	Metadata     json.RawMessage `json:"metadata,omitempty"` // Optional deployment-specific fields, stored and returned as-is
 	Timestamp    time.Time     `json:"timestamp"`    // When the measurement was taken
 	CreatedAt    time.Time     `json:"created_at"`   // When the record was created
 	
@@ -84,12 +168,29 @@ type Measurement struct {
 	LeftDuration     *int                `json:"left_duration,omitempty"`  // Duration in seconds for left side
 	RightDuration    *int                `json:"right_duration,omitempty"` // Duration in seconds for right side
 	Duration         *int                `json:"duration,omitempty"`       // Total duration in seconds (for single side)
+	SpitUp           *bool               `json:"spit_up,omitempty"`          // Whether the baby spat up after the feeding
+	SpitUpSeverity   *SpitUpSeverity     `json:"spit_up_severity,omitempty"` // How much was spat up (only with spit_up)
 	
 	// Temperature-specific fields (only used when Type == "temperature")
 	ValueCelsius     *float64           `json:"value_celsius,omitempty"`  // Temperature in Celsius
 	
+	// Weight-specific fields (only used when Type == "weight")
+	ValueGrams       *float64           `json:"value_grams,omitempty"`    // Weight in grams
+	
 	// Diaper-specific fields (only used when Type == "diaper")
 	DiaperStatus     *DiaperStatus      `json:"diaper_status,omitempty"`  // Status of diaper change
+	
+	// Sleep-specific fields (only used when Type == "sleep")
+	SleepStart       *time.Time         `json:"sleep_start,omitempty"`    // When the baby fell asleep
+	SleepEnd         *time.Time         `json:"sleep_end,omitempty"`      // When the baby woke up
+	
+	// Non-blocking data quality notes returned on creation (not persisted)
+	Warnings         []string           `json:"warnings,omitempty"`
+
+	// Weight converted to the unit requested with ?unit= on reads (not persisted)
+	DisplayValue     *float64           `json:"display_value,omitempty"`
+	DisplayUnit      string             `json:"display_unit,omitempty"`
+	DisplayText      string             `json:"display_text,omitempty"` // e.g. "7 lb 8 oz" for lb
 }
 
 // MeasurementType constants for validation
@@ -98,8 +199,18 @@ const (
 	MeasurementTypeWeight      = "weight"
 	MeasurementTypeTemperature = "temperature"
 	MeasurementTypeDiaper      = "diaper"
+	MeasurementTypeSleep       = "sleep"
+
+	MeasurementTypeHeight            = "height"             // Height/length in cm
+	MeasurementTypeHeadCircumference = "head_circumference" // Head circumference in cm
+
+	MeasurementTypeHeartRate = "heart_rate" // Heart rate in beats per minute
+	MeasurementTypeSpO2      = "spo2"       // Oxygen saturation in percent
 )
 
+// MaxSleepDuration is the longest sleep session accepted as a single measurement
+const MaxSleepDuration = 24 * time.Hour
+
 // ValidMeasurementTypes returns a slice of valid measurement types
 func ValidMeasurementTypes() []string {
 	return []string{
@@ -107,6 +218,11 @@ func ValidMeasurementTypes() []string {
 		MeasurementTypeWeight,
 		MeasurementTypeTemperature,
 		MeasurementTypeDiaper,
+		MeasurementTypeSleep,
+		MeasurementTypeHeight,
+		MeasurementTypeHeadCircumference,
+		MeasurementTypeHeartRate,
+		MeasurementTypeSpO2,
 	}
 }
 
@@ -121,6 +237,28 @@ func IsValidMeasurementType(measurementType string) bool {
 	return false
 }
 
+// MaxDeviceIDLength is the maximum accepted length of an external device ID
+const MaxDeviceIDLength = 64
+
+// deviceIDPattern allows serial-number style identifiers (letters, digits, '.', '_', ':', '-')
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// IsValidDeviceID checks if an external device ID has an acceptable format and length
+func IsValidDeviceID(deviceID string) bool {
+	return len(deviceID) > 0 && len(deviceID) <= MaxDeviceIDLength && deviceIDPattern.MatchString(deviceID)
+}
+
+// MaxIdempotencyKeyLength is the maximum accepted length of an Idempotency-Key header
+const MaxIdempotencyKeyLength = 128
+
+// idempotencyKeyPattern allows UUIDs and other opaque tokens made of visible ASCII characters
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7E]+$`)
+
+// IsValidIdempotencyKey checks if a client-supplied idempotency key has an acceptable format and length
+func IsValidIdempotencyKey(key string) bool {
+	return len(key) > 0 && len(key) <= MaxIdempotencyKeyLength && idempotencyKeyPattern.MatchString(key)
+}
+
 // TemperatureNormalRange defines the normal temperature range in Celsius
 const (
 	TemperatureNormalMin = 36.5
@@ -129,23 +267,60 @@ const (
 	TemperatureYellowMax = 38.0 // Above this is yellow
 )
 
+// Newborns are more sensitive to fever, so the upper yellow band is tighter
+const (
+	NeonatalMaxAgeDays           = 28   // Babies younger than this use the neonatal bands
+	NeonatalTemperatureYellowMax = 37.8 // Above this is red for newborns
+)
+
+// Physiologically possible growth measurements for babies and toddlers in centimeters
+const (
+	HeightMinCm            = 20.0
+	HeightMaxCm            = 120.0
+	HeadCircumferenceMinCm = 25.0
+	HeadCircumferenceMaxCm = 60.0
+)
+
+// Neonatal vital sign bands (pulse oximeter readings)
+const (
+	HeartRateNormalMin = 100.0 // bpm; 100-160 is Green
+	HeartRateNormalMax = 160.0
+	HeartRateYellowMin = 80.0  // Below this is red
+	HeartRateYellowMax = 200.0 // Above this is red
+	HeartRateMinBpm    = 20.0  // Readings outside 20-300 bpm are rejected as sensor errors
+	HeartRateMaxBpm    = 300.0
+
+	SpO2NormalMin = 95.0 // percent; 95 and above is Green
+	SpO2YellowMin = 90.0 // Below this is red
+)
+
 // CalculateSafetyStatus calculates the safety status based on measurement type and value
+// ageDays is the baby's age in days, nil when the date of birth is unknown (default bands apply)
 // Temperature: Green (36.5-37.5°C), Yellow (36.0-36.5 or 37.5-38.0°C), Red (<36.0 or >38.0°C)
+// Temperature (under 28 days old): Red above 37.8°C instead of 38.0°C
 // Weight: Green (valid positive value), Yellow (0 or negative), Red (not applicable for weight)
 // Feeding: Green (valid feeding), Yellow/Red (not applicable for feeding)
-func CalculateSafetyStatus(measurementType string, value float64) SafetyStatus {
+// Diaper, Sleep: always Green
+// Height (20-120cm), Head circumference (25-60cm): Green, Red outside the physiologically possible range
+// Heart rate: Green (100-160 bpm), Yellow (80-100 or 160-200 bpm), Red (<80 or >200 bpm)
+// SpO2: Green (>=95%), Yellow (90-95%), Red (<90%)
+func CalculateSafetyStatus(measurementType string, value float64, ageDays *int) SafetyStatus {
 	switch measurementType {
 	case MeasurementTypeTemperature:
+		yellowMax := TemperatureYellowMax
+		if ageDays != nil && *ageDays < NeonatalMaxAgeDays {
+			yellowMax = NeonatalTemperatureYellowMax
+		}
 		if value >= TemperatureNormalMin && value <= TemperatureNormalMax {
 			return SafetyStatusGreen
 		}
 		if value >= TemperatureYellowMin && value < TemperatureNormalMin {
 			return SafetyStatusYellow // Slightly below normal
 		}
-		if value > TemperatureNormalMax && value <= TemperatureYellowMax {
+		if value > TemperatureNormalMax && value <= yellowMax {
 			return SafetyStatusYellow // Slightly above normal
 		}
-		return SafetyStatusRed // Critical: <36.0 or >38.0°C
+		return SafetyStatusRed // Critical: <36.0 or above the upper yellow band
 	case MeasurementTypeWeight:
 		if value > 0 {
 			return SafetyStatusGreen // Valid weight
@@ -158,11 +333,43 @@ func CalculateSafetyStatus(measurementType string, value float64) SafetyStatus {
 	case MeasurementTypeDiaper:
 		// Diaper changes are always considered safe (Green)
 		return SafetyStatusGreen
+	case MeasurementTypeSleep:
+		// Sleep sessions are always considered safe (Green)
+		return SafetyStatusGreen
+	case MeasurementTypeHeight:
+		return growthSafetyStatus(value, HeightMinCm, HeightMaxCm)
+	case MeasurementTypeHeadCircumference:
+		return growthSafetyStatus(value, HeadCircumferenceMinCm, HeadCircumferenceMaxCm)
+	case MeasurementTypeHeartRate:
+		if value >= HeartRateNormalMin && value <= HeartRateNormalMax {
+			return SafetyStatusGreen
+		}
+		if value >= HeartRateYellowMin && value <= HeartRateYellowMax {
+			return SafetyStatusYellow // Bradycardia or tachycardia borderline
+		}
+		return SafetyStatusRed // Critical: <80 or >200 bpm
+	case MeasurementTypeSpO2:
+		if value >= SpO2NormalMin {
+			return SafetyStatusGreen
+		}
+		if value >= SpO2YellowMin {
+			return SafetyStatusYellow // Mild desaturation
+		}
+		return SafetyStatusRed // Critical: <90%
 	default:
 		return SafetyStatusGreen // Default to safe
 	}
 }
 
+// growthSafetyStatus flags growth values outside the physiologically possible range as Red
+// Creation rejects such values; this keeps stored or recomputed statuses honest
+func growthSafetyStatus(value, minCm, maxCm float64) SafetyStatus {
+	if value < minCm || value > maxCm {
+		return SafetyStatusRed
+	}
+	return SafetyStatusGreen
+}
+
 // IsAbnormalMeasurement checks if a measurement requires an alert (Red status)
 // Returns true if SafetyStatus is Red
 func IsAbnormalMeasurement(m *Measurement) bool {
@@ -0,0 +1,20 @@
+package domain
+
+// MaxBabyBatchSize caps how many babies one batch creation request may contain
+const MaxBabyBatchSize = 100
+
+// BabyBatchItemResult is the outcome of one entry of a batch creation, in request order
+type BabyBatchItemResult struct {
+	Index int    `json:"index"`           // Position of the entry in the request array
+	Baby  *Baby  `json:"baby,omitempty"`  // Created baby, nil when the entry failed or was rolled back
+	Error string `json:"error,omitempty"` // Validation error of the entry
+}
+
+// BabyBatchResult summarizes a batch creation
+// In atomic mode a single invalid entry means nothing is created
+type BabyBatchResult struct {
+	Atomic  bool                  `json:"atomic"`
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Results []BabyBatchItemResult `json:"results"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+// ErrConflict is returned when a write collides with an existing record
+// (e.g. a duplicate primary key); handlers map it to 409 Conflict
+var ErrConflict = errors.New("conflict")
+
+// ErrTooFrequent is returned when a measurement is logged sooner after the previous one
+// of the same type than the configured minimum interval; handlers map it to 409 Conflict
+var ErrTooFrequent = errors.New("measurement too frequent")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed for a different
+// baby or measurement type than the request that first used it; handlers map it to 422
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used for a different request")
+
+// ErrAlertUnavailable is returned when a Red measurement's alert could not be published in strict mode
+// The measurement is not kept; handlers map it to 503 Service Unavailable
+var ErrAlertUnavailable = errors.New("alert delivery unavailable, measurement not saved")
+
+// ErrConstraintViolation is returned when a write is rejected by a database CHECK constraint
+// Go validation should catch these first; handlers map it to 400 with the constraint's explanation
+var ErrConstraintViolation = errors.New("invalid measurement")
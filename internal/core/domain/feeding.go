@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"math"
+	"time"
+)
+
 // ValidBreastfeedingPositions returns all valid breastfeeding positions
 func ValidBreastfeedingPositions() []BreastfeedingPosition {
 	return []BreastfeedingPosition{
@@ -42,6 +47,25 @@ func IsValidBreastfeedingSide(side BreastfeedingSide) bool {
 	return false
 }
 
+// ValidSpitUpSeverities returns all valid spit-up severities
+func ValidSpitUpSeverities() []SpitUpSeverity {
+	return []SpitUpSeverity{
+		SpitUpSeverityMild,
+		SpitUpSeverityModerate,
+		SpitUpSeveritySevere,
+	}
+}
+
+// IsValidSpitUpSeverity checks if a spit-up severity is valid
+func IsValidSpitUpSeverity(severity SpitUpSeverity) bool {
+	for _, s := range ValidSpitUpSeverities() {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidDiaperStatuses returns all valid diaper statuses
 func ValidDiaperStatuses() []DiaperStatus {
 	return []DiaperStatus{
@@ -63,4 +87,150 @@ func IsValidDiaperStatus(status DiaperStatus) bool {
 	return false
 }
 
+// FeedingTotals holds aggregated feeding figures for one feeding type
+// Produced by a grouped query over a time window
+type FeedingTotals struct {
+	FeedingType          FeedingType
+	Count                int
+	TotalVolumeML        int // Sum of volume_ml (bottle)
+	TotalDurationSeconds int // Sum of durations (breast)
+}
+
+// FeedingBalance represents the breast vs bottle split over a time window
+type FeedingBalance struct {
+	From                       time.Time `json:"from"`
+	To                         time.Time `json:"to"`
+	TotalFeedings              int       `json:"total_feedings"`
+	BottleCount                int       `json:"bottle_count"`
+	BreastCount                int       `json:"breast_count"`
+	BottleRatio                float64   `json:"bottle_ratio"` // Share of feedings that were bottle (0-1)
+	BreastRatio                float64   `json:"breast_ratio"` // Share of feedings that were breast (0-1)
+	TotalBottleML              int       `json:"total_bottle_ml"`
+	TotalBreastDurationSeconds int       `json:"total_breast_duration_seconds"`
+}
+
+// CalculateFeedingBalance builds a FeedingBalance from per-type totals
+// Ratios are 0 when there are no feedings in the window
+func CalculateFeedingBalance(totals []FeedingTotals, from, to time.Time) *FeedingBalance {
+	balance := &FeedingBalance{From: from, To: to}
+
+	for _, t := range totals {
+		switch t.FeedingType {
+		case FeedingTypeBottle:
+			balance.BottleCount += t.Count
+			balance.TotalBottleML += t.TotalVolumeML
+		case FeedingTypeBreast:
+			balance.BreastCount += t.Count
+			balance.TotalBreastDurationSeconds += t.TotalDurationSeconds
+		}
+	}
+
+	balance.TotalFeedings = balance.BottleCount + balance.BreastCount
+	if balance.TotalFeedings > 0 {
+		balance.BottleRatio = float64(balance.BottleCount) / float64(balance.TotalFeedings)
+		balance.BreastRatio = float64(balance.BreastCount) / float64(balance.TotalFeedings)
+	}
+
+	return balance
+}
 
+// FeedingHourCount is the number of feedings that started in one hour of the day
+type FeedingHourCount struct {
+	Hour  int `json:"hour"` // 0-23 in the distribution timezone
+	Count int `json:"count"`
+}
+
+// HourlyFeedingDistribution shows at which hours of the day feedings cluster over a time window
+type HourlyFeedingDistribution struct {
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	Timezone      string             `json:"timezone"` // IANA name used for the hour buckets
+	TotalFeedings int                `json:"total_feedings"`
+	Hours         []FeedingHourCount `json:"hours"` // Always 24 entries, ordered by hour
+}
+
+// BuildHourlyFeedingDistribution fills all 24 hour buckets from the (sparse) grouped counts
+// Hours outside 0-23 are ignored
+func BuildHourlyFeedingDistribution(counts []FeedingHourCount, from, to time.Time, loc *time.Location) *HourlyFeedingDistribution {
+	distribution := &HourlyFeedingDistribution{
+		From:     from,
+		To:       to,
+		Timezone: loc.String(),
+		Hours:    make([]FeedingHourCount, 24),
+	}
+
+	for hour := range distribution.Hours {
+		distribution.Hours[hour].Hour = hour
+	}
+	for _, c := range counts {
+		if c.Hour < 0 || c.Hour > 23 {
+			continue
+		}
+		distribution.Hours[c.Hour].Count += c.Count
+		distribution.TotalFeedings += c.Count
+	}
+
+	return distribution
+}
+
+// FeedingDayTotals totals the feedings of one calendar day
+type FeedingDayTotals struct {
+	Date                       string `json:"date"` // YYYY-MM-DD in the comparison timezone
+	TotalFeedings              int    `json:"total_feedings"`
+	TotalBottleML              int    `json:"total_bottle_ml"`
+	TotalBreastDurationSeconds int    `json:"total_breast_duration_seconds"`
+}
+
+// FeedingComparison contrasts the feedings of two days, e.g. yesterday vs today
+// Deltas are day2 minus day1; percentages are relative to day1 and nil when day1 is zero
+type FeedingComparison struct {
+	Timezone                    string           `json:"timezone"` // IANA name used for the day boundaries
+	Day1                        FeedingDayTotals `json:"day1"`
+	Day2                        FeedingDayTotals `json:"day2"`
+	FeedingsDelta               int              `json:"feedings_delta"`
+	FeedingsPercentChange       *float64         `json:"feedings_percent_change"`
+	BottleMLDelta               int              `json:"bottle_ml_delta"`
+	BottleMLPercentChange       *float64         `json:"bottle_ml_percent_change"`
+	BreastDurationDeltaSeconds  int              `json:"breast_duration_delta_seconds"`
+	BreastDurationPercentChange *float64         `json:"breast_duration_percent_change"`
+}
+
+// CompareFeedingDays builds a FeedingComparison from the per-type totals of two days
+// day1 and day2 are midnight of each day in the comparison timezone
+func CompareFeedingDays(day1, day2 time.Time, totals1, totals2 []FeedingTotals) *FeedingComparison {
+	comparison := &FeedingComparison{
+		Timezone: day1.Location().String(),
+		Day1:     feedingDayTotals(day1, totals1),
+		Day2:     feedingDayTotals(day2, totals2),
+	}
+
+	comparison.FeedingsDelta = comparison.Day2.TotalFeedings - comparison.Day1.TotalFeedings
+	comparison.FeedingsPercentChange = percentChange(comparison.Day1.TotalFeedings, comparison.Day2.TotalFeedings)
+	comparison.BottleMLDelta = comparison.Day2.TotalBottleML - comparison.Day1.TotalBottleML
+	comparison.BottleMLPercentChange = percentChange(comparison.Day1.TotalBottleML, comparison.Day2.TotalBottleML)
+	comparison.BreastDurationDeltaSeconds = comparison.Day2.TotalBreastDurationSeconds - comparison.Day1.TotalBreastDurationSeconds
+	comparison.BreastDurationPercentChange = percentChange(comparison.Day1.TotalBreastDurationSeconds, comparison.Day2.TotalBreastDurationSeconds)
+
+	return comparison
+}
+
+// feedingDayTotals sums the bottle and breast totals of one day
+func feedingDayTotals(day time.Time, totals []FeedingTotals) FeedingDayTotals {
+	balance := CalculateFeedingBalance(totals, day, day.AddDate(0, 0, 1))
+	return FeedingDayTotals{
+		Date:                       day.Format("2006-01-02"),
+		TotalFeedings:              balance.TotalFeedings,
+		TotalBottleML:              balance.TotalBottleML,
+		TotalBreastDurationSeconds: balance.TotalBreastDurationSeconds,
+	}
+}
+
+// percentChange returns the change from before to after in percent, rounded to one decimal
+// Returns nil when before is zero since the change is undefined
+func percentChange(before, after int) *float64 {
+	if before == 0 {
+		return nil
+	}
+	change := math.Round(float64(after-before)/float64(before)*1000) / 10
+	return &change
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultMetadataMaxBytes is the default size limit of a measurement's metadata object
+const DefaultMetadataMaxBytes = 4096
+
+// MetadataSchema is the subset of JSON Schema supported for validating measurement metadata
+// Keywords: type (object, array, string, number, integer, boolean), properties, required,
+// additionalProperties (boolean), items, enum, minLength/maxLength and minimum/maximum
+type MetadataSchema struct {
+	Schema               string                     `json:"$schema,omitempty"` // Accepted and ignored
+	Title                string                     `json:"title,omitempty"`
+	Description          string                     `json:"description,omitempty"`
+	Type                 string                     `json:"type,omitempty"`
+	Properties           map[string]*MetadataSchema `json:"properties,omitempty"`
+	Required             []string                   `json:"required,omitempty"`
+	AdditionalProperties *bool                      `json:"additionalProperties,omitempty"` // Defaults to true
+	Items                *MetadataSchema            `json:"items,omitempty"`
+	Enum                 []interface{}              `json:"enum,omitempty"`
+	MinLength            *int                       `json:"minLength,omitempty"`
+	MaxLength            *int                       `json:"maxLength,omitempty"`
+	Minimum              *float64                   `json:"minimum,omitempty"`
+	Maximum              *float64                   `json:"maximum,omitempty"`
+}
+
+// metadataSchemaTypes are the supported values of the type keyword
+var metadataSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true,
+}
+
+// ParseMetadataSchema parses a metadata JSON schema (empty input means no schema)
+// Unsupported keywords are rejected so a schema is never silently only half enforced
+func ParseMetadataSchema(data string) (*MetadataSchema, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.DisallowUnknownFields()
+	var schema MetadataSchema
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("invalid metadata schema: %w", err)
+	}
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("invalid metadata schema: top-level type must be \"object\"")
+	}
+	if err := schema.check("$"); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// check verifies the type keywords of a schema and its subschemas
+func (s *MetadataSchema) check(path string) error {
+	if s.Type != "" && !metadataSchemaTypes[s.Type] {
+		return fmt.Errorf("invalid metadata schema: unsupported type %q at %s", s.Type, path)
+	}
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("invalid metadata schema: empty property schema at %s.%s", path, name)
+		}
+		if err := property.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+// ValidateMetadata checks a measurement metadata value: a JSON object of at most maxBytes,
+// matching schema when one is given
+func ValidateMetadata(raw json.RawMessage, schema *MetadataSchema, maxBytes int) error {
+	if len(raw) > maxBytes {
+		return fmt.Errorf("metadata exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("metadata must be a JSON object")
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return fmt.Errorf("metadata must be a JSON object")
+	}
+
+	if schema == nil {
+		return nil
+	}
+	if err := schema.validate(value, "metadata"); err != nil {
+		return fmt.Errorf("metadata does not match schema: %w", err)
+	}
+	return nil
+}
+
+// validate checks value (decoded with UseNumber) against the schema
+func (s *MetadataSchema) validate(value interface{}, path string) error {
+	if len(s.Enum) > 0 && !metadataEnumContains(s.Enum, value) {
+		return fmt.Errorf("%s is not one of the allowed values", path)
+	}
+
+	switch s.Type {
+	case "":
+		// No type constraint
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		return s.validateObject(object, path)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		length := len([]rune(str))
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Errorf("%s must be at least %d characters", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Errorf("%s must be at most %d characters", path, *s.MaxLength)
+		}
+	case "number", "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s must be a %s", path, s.Type)
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return fmt.Errorf("%s must be a %s", path, s.Type)
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s must be at least %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s must be at most %v", path, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+	}
+	return nil
+}
+
+// validateObject checks required, declared and additional properties of an object
+func (s *MetadataSchema) validateObject(object map[string]interface{}, path string) error {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s.%s is required", path, name)
+		}
+	}
+
+	// Sorted so the first reported error is deterministic
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, declared := s.Properties[name]
+		if !declared {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("%s.%s is not an allowed property", path, name)
+			}
+			continue
+		}
+		if err := property.validate(object[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metadataEnumContains reports whether value equals one of the enum values
+// Numbers are compared numerically since value was decoded with UseNumber
+func metadataEnumContains(enum []interface{}, value interface{}) bool {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		if err != nil {
+			return false
+		}
+		value = f
+	}
+	for _, allowed := range enum {
+		switch allowed.(type) {
+		case map[string]interface{}, []interface{}:
+			continue // Only scalar enum values are supported
+		}
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
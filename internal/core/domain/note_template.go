@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// NoteTemplates maps a measurement type to the note used when a new measurement of that type has none
+// e.g. temperature -> "Measured at: axillary/rectal", so clinical teams document readings consistently
+type NoteTemplates map[string]string
+
+// ParseNoteTemplates parses a JSON object of measurement type to default note (empty input means no templates)
+// Unknown types and blank templates are rejected so a typo fails at startup instead of never applying
+func ParseNoteTemplates(data string) (NoteTemplates, error) {
+	templates := NoteTemplates{}
+	if strings.TrimSpace(data) == "" {
+		return templates, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &templates); err != nil {
+		return nil, fmt.Errorf("invalid note templates (expected a JSON object of type to note): %w", err)
+	}
+	for measurementType, template := range templates {
+		if !IsValidMeasurementType(measurementType) {
+			return nil, fmt.Errorf("unknown measurement type in note templates: %s", measurementType)
+		}
+		if strings.TrimSpace(template) == "" {
+			return nil, fmt.Errorf("empty note template for measurement type: %s", measurementType)
+		}
+	}
+	return templates, nil
+}
+
+// Apply returns the note to store: the client's note, or the type's template when the note is blank
+func (t NoteTemplates) Apply(measurementType, note string) string {
+	if strings.TrimSpace(note) != "" {
+		return note
+	}
+	if template, ok := t[measurementType]; ok {
+		return template
+	}
+	return note
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parent is the local read-model of a parent account owned by the Identity Service
+// Kept up to date from user.created/user.updated events so reads never call the Identity Service
+type Parent struct {
+	ID        uuid.UUID `json:"id"` // Identity Service user ID, matches babies.parent_user_id
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"` // When the Identity Service last changed the user
+}
+
+// ParentSummary is a parent's overview across all of their babies
+type ParentSummary struct {
+	ParentUserID          uuid.UUID `json:"parent_user_id"`
+	BabyCount             int       `json:"baby_count"`              // Live (not soft-deleted) babies
+	RedMeasurementsRecent int       `json:"red_measurements_recent"` // Red measurements of those babies since Since
+	Since                 time.Time `json:"since"`
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// DailyReport is a printable summary of one calendar day for a baby
+// Structured so clients can render it (e.g. to PDF) without further aggregation
+type DailyReport struct {
+	BabyID       string              `json:"baby_id"`
+	Date         string              `json:"date"`     // YYYY-MM-DD in the report timezone
+	Timezone     string              `json:"timezone"` // IANA name used for the day boundaries
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	Feeding      DailyFeedingSummary `json:"feeding"`
+	Diapers      DailyDiaperSummary  `json:"diapers"`
+	Temperatures []TemperatureEntry  `json:"temperatures"`
+	Weight       *WeightEntry        `json:"weight,omitempty"` // Latest weight of the day, if taken
+}
+
+// DailyFeedingSummary totals the feedings of the day
+type DailyFeedingSummary struct {
+	Count                      int `json:"count"`
+	BottleCount                int `json:"bottle_count"`
+	BreastCount                int `json:"breast_count"`
+	TotalBottleML              int `json:"total_bottle_ml"`
+	TotalBreastDurationSeconds int `json:"total_breast_duration_seconds"`
+	SpitUpCount                int `json:"spit_up_count"` // Feedings followed by a spit-up
+}
+
+// DailyDiaperSummary counts the diaper changes of the day by status
+type DailyDiaperSummary struct {
+	Total int `json:"total"`
+	Dry   int `json:"dry"`
+	Wet   int `json:"wet"`
+	Dirty int `json:"dirty"`
+	Both  int `json:"both"`
+}
+
+// TemperatureEntry is a single temperature reading in a report
+type TemperatureEntry struct {
+	Timestamp    time.Time    `json:"timestamp"`
+	ValueCelsius float64      `json:"value_celsius"`
+	SafetyStatus SafetyStatus `json:"safety_status"`
+}
+
+// WeightEntry is a single weight reading in a report
+type WeightEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	ValueGrams float64   `json:"value_grams"`
+}
+
+// BuildDailyReport aggregates the measurements of one day into a DailyReport
+// Temperatures are listed in chronological order
+func BuildDailyReport(babyID string, date string, loc *time.Location, from, to time.Time, measurements []*Measurement) *DailyReport {
+	report := &DailyReport{
+		BabyID:       babyID,
+		Date:         date,
+		Timezone:     loc.String(),
+		From:         from,
+		To:           to,
+		Temperatures: []TemperatureEntry{},
+	}
+
+	for _, m := range measurements {
+		switch m.Type {
+		case MeasurementTypeFeeding:
+			report.Feeding.Count++
+			switch m.FeedingType {
+			case FeedingTypeBottle:
+				report.Feeding.BottleCount++
+				if m.VolumeML != nil {
+					report.Feeding.TotalBottleML += *m.VolumeML
+				}
+			case FeedingTypeBreast:
+				report.Feeding.BreastCount++
+				report.Feeding.TotalBreastDurationSeconds += breastDurationSeconds(m)
+			}
+			if m.SpitUp != nil && *m.SpitUp {
+				report.Feeding.SpitUpCount++
+			}
+
+		case MeasurementTypeDiaper:
+			report.Diapers.Total++
+			if m.DiaperStatus != nil {
+				switch *m.DiaperStatus {
+				case DiaperStatusDry:
+					report.Diapers.Dry++
+				case DiaperStatusWet:
+					report.Diapers.Wet++
+				case DiaperStatusDirty:
+					report.Diapers.Dirty++
+				case DiaperStatusBoth:
+					report.Diapers.Both++
+				}
+			}
+
+		case MeasurementTypeTemperature:
+			value := m.Value
+			if m.ValueCelsius != nil {
+				value = *m.ValueCelsius
+			}
+			report.Temperatures = append(report.Temperatures, TemperatureEntry{
+				Timestamp:    m.Timestamp.In(loc),
+				ValueCelsius: value,
+				SafetyStatus: m.SafetyStatus,
+			})
+
+		case MeasurementTypeWeight:
+			if report.Weight != nil && !m.Timestamp.After(report.Weight.Timestamp) {
+				continue
+			}
+			value := m.Value
+			if m.ValueGrams != nil {
+				value = *m.ValueGrams
+			}
+			report.Weight = &WeightEntry{Timestamp: m.Timestamp.In(loc), ValueGrams: value}
+		}
+	}
+
+	sort.Slice(report.Temperatures, func(i, j int) bool {
+		return report.Temperatures[i].Timestamp.Before(report.Temperatures[j].Timestamp)
+	})
+
+	return report
+}
+
+// breastDurationSeconds returns the total duration of a breast feeding in seconds
+func breastDurationSeconds(m *Measurement) int {
+	if m.Duration != nil {
+		return *m.Duration
+	}
+	total := 0
+	if m.LeftDuration != nil {
+		total += *m.LeftDuration
+	}
+	if m.RightDuration != nil {
+		total += *m.RightDuration
+	}
+	return total
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// requestStartKey is the context key for the time a request entered the service
+type requestStartKey struct{}
+
+// WithRequestStart stores the request start time in ctx
+// Set once at the HTTP edge so handler, service and alert publish latencies share one base time
+func WithRequestStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, requestStartKey{}, start)
+}
+
+// RequestStart returns the request start time stored in ctx, or now when none was stored
+func RequestStart(ctx context.Context) time.Time {
+	if start, ok := ctx.Value(requestStartKey{}).(time.Time); ok {
+		return start
+	}
+	return time.Now()
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is the caller's role as issued by the Identity Service JWT
+type Role string
+
+const (
+	RoleAdmin  Role = "ADMIN"  // Administrator - creates babies, reads everything
+	RoleNurse  Role = "NURSE"  // Ward nurse - reads any baby and measurement, cannot create babies
+	RoleParent Role = "PARENT" // Parent - reads and logs measurements for owned babies only
+)
+
+// CanReadAllBabies reports whether the role can read any baby, not only owned ones
+func (r Role) CanReadAllBabies() bool {
+	return r == RoleAdmin || r == RoleNurse
+}
+
+// CanCreateBabies reports whether the role can register new babies
+func (r Role) CanCreateBabies() bool {
+	return r == RoleAdmin
+}
+
+// IsValidRole reports whether r is one of the roles issued by the Identity Service
+func IsValidRole(r Role) bool {
+	return r == RoleAdmin || r == RoleNurse || r == RoleParent
+}
+
+// MeasurementVisibility maps a role to the measurement types it may read
+// Roles without an entry see every type
+type MeasurementVisibility map[Role][]string
+
+// VisibleTypes returns the types the role may read, or nil when it may read all of them
+func (v MeasurementVisibility) VisibleTypes(role Role) []string {
+	types, restricted := v[role]
+	if !restricted {
+		return nil
+	}
+	if types == nil {
+		return []string{}
+	}
+	return types
+}
+
+// CanSee reports whether the role may read measurements of the given type
+func (v MeasurementVisibility) CanSee(role Role, measurementType string) bool {
+	types, restricted := v[role]
+	if !restricted {
+		return true
+	}
+	for _, t := range types {
+		if t == measurementType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMeasurementVisibility parses a policy like "NURSE=temperature,weight;ADMIN=temperature"
+// An empty spec means every role sees every type; "NURSE=" hides all types from NURSE
+func ParseMeasurementVisibility(spec string) (MeasurementVisibility, error) {
+	policy := MeasurementVisibility{}
+	if strings.TrimSpace(spec) == "" {
+		return policy, nil
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		roleName, typeList, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid visibility entry %q (expected ROLE=type,type)", entry)
+		}
+		role := Role(strings.ToUpper(strings.TrimSpace(roleName)))
+		if !IsValidRole(role) {
+			return nil, fmt.Errorf("unknown role in visibility policy: %s", roleName)
+		}
+		if _, duplicate := policy[role]; duplicate {
+			return nil, fmt.Errorf("role listed twice in visibility policy: %s", role)
+		}
+
+		types := []string{}
+		for _, t := range strings.Split(typeList, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			if !IsValidMeasurementType(t) {
+				return nil, fmt.Errorf("unknown measurement type in visibility policy: %s", t)
+			}
+			types = append(types, t)
+		}
+		policy[role] = types
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import "time"
+
+// MeasurementStats summarizes the measurements of one type for a baby
+// Values are in the type's unit (grams, Celsius, seconds, ...)
+type MeasurementStats struct {
+	Type          string     `json:"type"`
+	Count         int        `json:"count"`
+	MinValue      float64    `json:"min_value"`
+	MaxValue      float64    `json:"max_value"`
+	AvgValue      float64    `json:"avg_value"`
+	LastTimestamp *time.Time `json:"last_timestamp,omitempty"` // Latest measurement timestamp, nil if none recorded one
+}
+
+// SafetyStatusDistribution counts a baby's measurements per safety status
+// e.g. "of 200 temperature readings, 180 were green, 15 yellow and 5 red"
+type SafetyStatusDistribution struct {
+	Type   string `json:"type,omitempty"` // Measurement type the counts are limited to, empty for all types
+	Total  int    `json:"total"`
+	Green  int    `json:"green"`
+	Yellow int    `json:"yellow"`
+	Red    int    `json:"red"`
+}
+
+// BuildSafetyStatusDistribution turns per-status counts into a distribution
+// Statuses without measurements count as zero
+func BuildSafetyStatusDistribution(measurementType string, counts map[SafetyStatus]int) *SafetyStatusDistribution {
+	distribution := &SafetyStatusDistribution{
+		Type:   measurementType,
+		Green:  counts[SafetyStatusGreen],
+		Yellow: counts[SafetyStatusYellow],
+		Red:    counts[SafetyStatusRed],
+	}
+	distribution.Total = distribution.Green + distribution.Yellow + distribution.Red
+	return distribution
+}
+
+// SafetyStatusBackfillResult reports what a safety_status backfill repaired
+type SafetyStatusBackfillResult struct {
+	Updated int `json:"updated"` // Rows whose status was recomputed
+	Batches int `json:"batches"` // Batches processed
+	Green   int `json:"green"`   // Updated rows per recomputed status
+	Yellow  int `json:"yellow"`
+	Red     int `json:"red"`
+}
+
+// BusinessStats holds service-wide product KPIs exported as Prometheus gauges
+type BusinessStats struct {
+	ActiveBabies         int // Babies that are not soft-deleted
+	MeasurementsLastHour int // Measurements created in the last hour
+	ActiveRedAlerts      int // Active babies whose most recent measurement is Red
+}
+
+// TrendBucket is the period a measurement trend averages over (a Postgres date_trunc field)
+type TrendBucket string
+
+const (
+	TrendBucketHour TrendBucket = "hour"
+	TrendBucketDay  TrendBucket = "day"
+	TrendBucketWeek TrendBucket = "week"
+)
+
+// MaxTrendBuckets caps the number of buckets a single trend may span
+const MaxTrendBuckets = 1000
+
+// trendBucketDurations is the (nominal) length of each bucket, used to bound the window
+var trendBucketDurations = map[TrendBucket]time.Duration{
+	TrendBucketHour: time.Hour,
+	TrendBucketDay:  24 * time.Hour,
+	TrendBucketWeek: 7 * 24 * time.Hour,
+}
+
+// IsValidTrendBucket checks if a trend bucket is valid
+func IsValidTrendBucket(bucket TrendBucket) bool {
+	_, ok := trendBucketDurations[bucket]
+	return ok
+}
+
+// TrendBucketCount returns how many buckets of the given size [from, to) touches (rounded up)
+func TrendBucketCount(bucket TrendBucket, from, to time.Time) int {
+	size := trendBucketDurations[bucket]
+	if size == 0 || !from.Before(to) {
+		return 0
+	}
+	span := to.Sub(from)
+	return int((span + size - 1) / size)
+}
+
+// trendTypes are the measurement types with a numeric value worth averaging
+var trendTypes = map[string]bool{
+	MeasurementTypeTemperature:       true,
+	MeasurementTypeWeight:            true,
+	MeasurementTypeHeight:            true,
+	MeasurementTypeHeadCircumference: true,
+}
+
+// IsTrendMeasurementType reports whether a measurement type supports trends
+// Feedings, diapers and sleep are events rather than readings and are excluded
+func IsTrendMeasurementType(measurementType string) bool {
+	return trendTypes[measurementType]
+}
+
+// TrendPoint aggregates the measurements of one bucket
+type TrendPoint struct {
+	BucketStart time.Time `json:"bucket_start"` // Start of the bucket in the trend's time zone
+	Avg         float64   `json:"avg"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Count       int       `json:"count"`
+}
+
+// MeasurementTrend is a baby's bucketed averages of one measurement type, e.g. for charts
+type MeasurementTrend struct {
+	Type     string       `json:"type"`
+	Bucket   TrendBucket  `json:"bucket"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Timezone string       `json:"timezone"` // IANA name used for the bucket boundaries
+	Points   []TrendPoint `json:"points"`   // Ordered by bucket_start; buckets without measurements are omitted
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WardOverview is the charge-nurse view of every baby in a ward (rooms sharing a prefix, e.g. "3B")
+type WardOverview struct {
+	RoomPrefix string              `json:"room_prefix"`
+	Since      time.Time           `json:"since"` // Start of the window WorstRecentStatus covers
+	Babies     []*WardBabyOverview `json:"babies"`
+}
+
+// WardBabyOverview is one baby's row in a ward overview
+type WardBabyOverview struct {
+	BabyID            uuid.UUID               `json:"baby_id"`
+	LastName          string                  `json:"last_name"`
+	RoomNumber        string                  `json:"room_number"`
+	Latest            map[string]*Measurement `json:"latest"`                        // Newest measurement of each type, keyed by type
+	WorstRecentStatus SafetyStatus            `json:"worst_recent_status,omitempty"` // Empty when nothing was measured since the window start
+}
+
+// safetyStatusSeverity ranks safety statuses from least to most severe (unknown statuses rank lowest)
+var safetyStatusSeverity = map[SafetyStatus]int{
+	SafetyStatusGreen:  1,
+	SafetyStatusYellow: 2,
+	SafetyStatusRed:    3,
+}
+
+// WorseSafetyStatus returns the more severe of two safety statuses
+func WorseSafetyStatus(a, b SafetyStatus) SafetyStatus {
+	if safetyStatusSeverity[b] > safetyStatusSeverity[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// WeightUnit is a unit weights can be displayed in; weights are always stored in grams
+type WeightUnit string
+
+const (
+	WeightUnitGrams  WeightUnit = "g"
+	WeightUnitPounds WeightUnit = "lb"
+	WeightUnitOunces WeightUnit = "oz"
+)
+
+// Conversion factors (international avoirdupois pound)
+const (
+	GramsPerPound = 453.59237
+	GramsPerOunce = GramsPerPound / 16
+)
+
+// IsValidWeightUnit checks if a display unit is supported
+func IsValidWeightUnit(unit WeightUnit) bool {
+	return unit == WeightUnitGrams || unit == WeightUnitPounds || unit == WeightUnitOunces
+}
+
+// ApplyWeightUnit fills the display fields of a weight measurement in the given unit
+// Value and ValueGrams keep the canonical grams; grams and non-weight measurements are left unchanged
+func (m *Measurement) ApplyWeightUnit(unit WeightUnit) {
+	if m.Type != MeasurementTypeWeight || unit == WeightUnitGrams || !IsValidWeightUnit(unit) {
+		return
+	}
+
+	var value float64
+	switch unit {
+	case WeightUnitPounds:
+		value = math.Round(m.Value/GramsPerPound*100) / 100
+		m.DisplayText = FormatPoundsOunces(m.Value)
+	case WeightUnitOunces:
+		value = math.Round(m.Value/GramsPerOunce*10) / 10
+	}
+	m.DisplayValue = &value
+	m.DisplayUnit = string(unit)
+}
+
+// FormatPoundsOunces formats grams the way US scales read, e.g. 3400 -> "7 lb 8 oz"
+// Ounces are rounded to the nearest whole ounce
+func FormatPoundsOunces(grams float64) string {
+	totalOunces := int(math.Round(grams / GramsPerOunce))
+	return fmt.Sprintf("%d lb %d oz", totalOunces/16, totalOunces%16)
+}
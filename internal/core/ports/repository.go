@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/google/uuid"
@@ -12,20 +13,118 @@ type BabyRepository interface {
 	// CreateBaby creates a new baby (ADMIN only)
 	CreateBaby(ctx context.Context, baby *domain.Baby) error
 
+	// CreateBabies inserts babies in a single transaction: either all of them are created or none
+	CreateBabies(ctx context.Context, babies []*domain.Baby) error
+
 	// GetBabyByID retrieves a baby by ID
 	// Returns error if baby doesn't exist or user doesn't have access
 	GetBabyByID(ctx context.Context, babyID uuid.UUID) (*domain.Baby, error)
 
+	// FindBabyByParentAndRoom retrieves the oldest live baby with the given parent, last name and room number
+	// Returns nil when there is none
+	FindBabyByParentAndRoom(ctx context.Context, parentUserID uuid.UUID, lastName string, roomNumber string) (*domain.Baby, error)
+
+	// ListBabiesByRoomPrefix retrieves the live babies whose room number starts with roomPrefix, ordered by room
+	ListBabiesByRoomPrefix(ctx context.Context, roomPrefix string) ([]*domain.Baby, error)
+
 	// ListBabies retrieves babies based on role:
 	// ADMIN: all babies
 	// PARENT: only babies where parent_user_id matches
-	ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool) ([]*domain.Baby, error)
+	// Soft-deleted babies are only returned when includeDeleted is true
+	ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool, includeDeleted bool) ([]*domain.Baby, error)
 
-	// BabyExists checks if a baby exists
+	// BabyExists checks if a baby exists (soft-deleted babies do not)
 	BabyExists(ctx context.Context, babyID uuid.UUID) (bool, error)
 
+	// BabyRecordExists checks if a baby row exists at all, soft-deleted or not
+	// Used to tell a soft-deleted baby apart from one that is missing entirely
+	BabyRecordExists(ctx context.Context, babyID uuid.UUID) (bool, error)
+
 	// CheckBabyOwnership checks if a baby belongs to a specific parent
 	CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error)
+
+	// GetBabyAccess reports in a single read whether a baby exists and whether it belongs to the parent
+	// Avoids inconsistent results when the baby is deleted between separate existence and ownership checks
+	GetBabyAccess(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (exists bool, owned bool, err error)
+
+	// CountBabiesForParent counts the live babies of a parent
+	CountBabiesForParent(ctx context.Context, parentUserID uuid.UUID) (int, error)
+
+	// CountRedMeasurementsForParent counts the red measurements timestamped at or after since
+	// across all live babies of a parent
+	CountRedMeasurementsForParent(ctx context.Context, parentUserID uuid.UUID, since time.Time) (int, error)
+
+	// UpdateBaby sets the last name, room number and/or date of birth of a baby (empty or nil values are left unchanged)
+	// Returns "baby not found" when no baby has the ID
+	UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth *time.Time) error
+
+	// DeleteBaby soft-deletes a baby by setting deleted_at; the row and its measurements are kept
+	// Returns "baby not found" when no live baby has the ID
+	DeleteBaby(ctx context.Context, babyID uuid.UUID) error
+}
+
+// ParentRepository defines the interface for the local parents read-model
+// Rows are projected from Identity Service user events; the Identity Service stays the source of truth
+type ParentRepository interface {
+	// UpsertParent inserts or replaces a parent
+	// Older events (by UpdatedAt) never overwrite newer data, so redelivered or reordered events are safe
+	UpsertParent(ctx context.Context, parent *domain.Parent) error
+
+	// GetParentByID retrieves a projected parent
+	// Returns "parent not found" when no event for the user has been projected yet
+	GetParentByID(ctx context.Context, parentID uuid.UUID) (*domain.Parent, error)
+}
+
+// AssignmentRepository defines the interface for nurse assignment persistence
+type AssignmentRepository interface {
+	// CreateAssignment stores a new assignment
+	CreateAssignment(ctx context.Context, assignment *domain.NurseAssignment) error
+
+	// ListAssignments retrieves a nurse's assignments, oldest first
+	ListAssignments(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.NurseAssignment, error)
+
+	// DeleteAssignment removes one of a nurse's assignments
+	// Returns "assignment not found" when the nurse has no assignment with the ID
+	DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID) error
+
+	// ListAssignedBabies retrieves the live babies covered by any of the nurse's assignments
+	ListAssignedBabies(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.Baby, error)
+
+	// IsBabyAssigned reports whether a live baby is covered by any of the nurse's assignments
+	IsBabyAssigned(ctx context.Context, babyID uuid.UUID, nurseUserID uuid.UUID) (bool, error)
+}
+
+// AlertMuteRepository defines the interface for per-baby alert mutes
+type AlertMuteRepository interface {
+	// UpsertAlertMute stores the baby's mute, replacing any existing one
+	UpsertAlertMute(ctx context.Context, mute *domain.AlertMute) error
+
+	// GetActiveAlertMute retrieves the baby's mute when it is still in effect at the given time
+	// Returns nil when the baby is not muted or the mute has expired
+	GetActiveAlertMute(ctx context.Context, babyID uuid.UUID, at time.Time) (*domain.AlertMute, error)
+}
+
+// AuditRepository defines the interface for the measurement audit log
+type AuditRepository interface {
+	// Record appends an audit entry for a measurement mutation; details may be nil
+	Record(ctx context.Context, action domain.AuditAction, userID uuid.UUID, babyID uuid.UUID, measurementID uuid.UUID, details map[string]interface{}) error
+
+	// ListAuditEntries retrieves the audit entries of a baby's measurements, newest first
+	// before (nil for the first page) returns only entries strictly older than the cursor
+	ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *AuditCursor, limit int) ([]*domain.AuditEntry, error)
+}
+
+// AuditCursor identifies a position in the (created_at DESC, id DESC) ordering of audit entries
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// StatsRepository defines the interface for service-wide business statistics
+type StatsRepository interface {
+	// GetBusinessStats counts active babies, measurements created since the given time
+	// and active babies whose latest measurement is Red
+	GetBusinessStats(ctx context.Context, since time.Time) (*domain.BusinessStats, error)
 }
 
 // MeasurementRepository defines the interface for measurement data persistence
@@ -33,16 +132,120 @@ type MeasurementRepository interface {
 	// CreateMeasurement creates a new measurement for a baby
 	CreateMeasurement(ctx context.Context, measurement *domain.Measurement) error
 
+	// CreateMeasurementWithIdempotencyKey inserts the measurement and claims the idempotency key for
+	// measurement.ParentID in one transaction; keys created before notBefore are expired and reclaimed
+	// When a live key already exists nothing is inserted and the ID of the measurement it points to is returned
+	CreateMeasurementWithIdempotencyKey(ctx context.Context, measurement *domain.Measurement, key string, notBefore time.Time) (*uuid.UUID, error)
+
+	// GetIdempotentMeasurementID returns the measurement created under the user's idempotency key
+	// since notBefore, or nil when the key is unknown or expired
+	GetIdempotentMeasurementID(ctx context.Context, userID uuid.UUID, key string, notBefore time.Time) (*uuid.UUID, error)
+
 	// GetMeasurementsByBabyID retrieves all measurements for a baby
-	// Optional filters: measurementType (filter by type), limit (max results)
-	GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, measurementType *string, limit *int) ([]*domain.Measurement, error)
+	// Optional filters are applied from the MeasurementFilter (nil fields are ignored)
+	GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, filter MeasurementFilter) ([]*domain.Measurement, error)
 
 	// GetMeasurementByID retrieves a specific measurement
 	GetMeasurementByID(ctx context.Context, measurementID uuid.UUID) (*domain.Measurement, error)
 
-	// DeleteMeasurement deletes a measurement by ID
+	// GetFeedingTotals aggregates feedings for a baby grouped by feeding type
+	// Window is [from, to) on the measurement timestamp
+	GetFeedingTotals(ctx context.Context, babyID uuid.UUID, from, to time.Time) ([]domain.FeedingTotals, error)
+
+	// UpdateMeasurement persists the note and timestamp of an existing measurement
+	// Only updates the row when it belongs to measurement.ParentID
+	UpdateMeasurement(ctx context.Context, measurement *domain.Measurement) error
+
+	// GetFeedingCountsByHour counts feedings for a baby grouped by hour of day in loc
+	// Window is [from, to) on the measurement timestamp; hours without feedings are omitted
+	GetFeedingCountsByHour(ctx context.Context, babyID uuid.UUID, from, to time.Time, loc *time.Location) ([]domain.FeedingHourCount, error)
+
+	// GetMeasurementStats aggregates a baby's measurements grouped by type (count, min/max/avg value, last timestamp)
+	// from and to are optional bounds of the [from, to) window on the measurement timestamp
+	GetMeasurementStats(ctx context.Context, babyID uuid.UUID, from, to *time.Time) ([]domain.MeasurementStats, error)
+
+	// GetMeasurementTrend averages a baby's measurements of one type per bucket (date_trunc in loc) over [from, to)
+	// Points are ordered by bucket start; buckets without measurements are omitted
+	GetMeasurementTrend(ctx context.Context, babyID uuid.UUID, measurementType string, bucket domain.TrendBucket, from, to time.Time, loc *time.Location) ([]domain.TrendPoint, error)
+
+	// GetLatestMeasurements returns the newest measurement of each type for a baby
+	// Types without measurements are absent; a baby without measurements yields an empty slice
+	GetLatestMeasurements(ctx context.Context, babyID uuid.UUID) ([]*domain.Measurement, error)
+
+	// GetLatestMeasurementsForBabies returns the newest measurement of each type for each of the babies in one query
+	GetLatestMeasurementsForBabies(ctx context.Context, babyIDs []uuid.UUID) ([]*domain.Measurement, error)
+
+	// GetSafetyStatusesSince returns the distinct safety statuses of each baby's measurements timestamped at or after since
+	// types limits the measurement types considered (nil: all); babies without such measurements are absent
+	GetSafetyStatusesSince(ctx context.Context, babyIDs []uuid.UUID, since time.Time, types []string) (map[uuid.UUID][]domain.SafetyStatus, error)
+
+	// GetSafetyStatusCounts counts a baby's measurements grouped by safety status
+	// Only the Type, Types, From and To fields of the filter are applied
+	GetSafetyStatusCounts(ctx context.Context, babyID uuid.UUID, filter MeasurementFilter) (map[domain.SafetyStatus]int, error)
+
+	// DeleteMeasurement deletes a measurement by ID and returns the deleted row
 	// Validates that the measurement belongs to the specified parent before deletion
-	DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) error
+	// Reading and deleting the row is atomic, so concurrent deletes return it only once
+	DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) (*domain.Measurement, error)
+
+	// ListInvalidSafetyStatusMeasurements returns up to limit measurements whose safety_status is NULL
+	// or not one of the canonical statuses, with what is needed to recompute it
+	ListInvalidSafetyStatusMeasurements(ctx context.Context, limit int) ([]SafetyStatusBackfillRow, error)
+
+	// UpdateMeasurementSafetyStatus overwrites the stored safety status of a measurement
+	UpdateMeasurementSafetyStatus(ctx context.Context, measurementID uuid.UUID, status domain.SafetyStatus) error
+}
+
+// SafetyStatusBackfillRow is a measurement with an invalid stored safety status
+type SafetyStatusBackfillRow struct {
+	ID          uuid.UUID
+	Type        string
+	Value       float64
+	Timestamp   time.Time
+	DateOfBirth *time.Time // Baby's date of birth, nil when unknown
+}
+
+// MeasurementFilter holds the optional filters for listing measurements
+type MeasurementFilter struct {
+	Type     *string            // Filter by measurement type
+	Types    []string           // Restrict to these types (role visibility policy); nil means all types
+	DeviceID *string            // Filter by external device ID
+	Value    *float64           // Filter by exact value
+	From     *time.Time         // Inclusive lower bound on timestamp
+	To       *time.Time         // Exclusive upper bound on timestamp
+	Limit    *int               // Max results
+	Before   *MeasurementCursor // Only rows strictly older than the cursor (next page)
+	After    *MeasurementCursor // Only rows strictly newer than the cursor
+
+	// Primary runs the query on the primary instead of the read pool
+	// Checks that gate a write use it so replica lag can't hide a measurement that was just logged
+	Primary bool
+
+	// StatementTimeout overrides DB_STATEMENT_TIMEOUT for this query (0 keeps the session default)
+	// Unbounded reads like the CSV export use it so a long history isn't cancelled mid-query
+	StatementTimeout time.Duration
+}
+
+// MeasurementCursor identifies a position in the (timestamp DESC, id DESC) ordering of measurements
+type MeasurementCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// MeasurementMetrics records measurement activity for monitoring
+type MeasurementMetrics interface {
+	// MeasurementCreated counts a stored measurement by type and safety status
+	MeasurementCreated(measurementType string, status domain.SafetyStatus)
+	// OrphanedMeasurement counts a measurement found without any baby row, which cascade delete should prevent
+	OrphanedMeasurement()
+	// AlertSuppressed counts an alert not published because an alert for the same baby and type was just sent
+	AlertSuppressed(measurementType string)
+	// AlertDropped counts an alert not published because the background publish queue was full
+	AlertDropped(measurementType string)
+	// MeasurementCreateDuration records how long a successful create took, from the start of the request
+	MeasurementCreateDuration(measurementType string, duration time.Duration)
+	// AuditRecordFailed counts an audit entry that could not be written; the mutation itself still succeeded
+	AuditRecordFailed(action domain.AuditAction)
 }
 
 // AlertPublisher defines the interface for publishing alerts to RabbitMQ
@@ -51,3 +254,8 @@ type AlertPublisher interface {
 	PublishAlert(ctx context.Context, babyID uuid.UUID, measurement *domain.Measurement) error
 }
 
+// ConnectionChecker reports whether an adapter is connected to its broker
+// The readiness probe aggregates these so a pod that can't reach RabbitMQ is taken out of rotation
+type ConnectionChecker interface {
+	IsConnected() bool
+}
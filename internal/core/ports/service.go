@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
@@ -11,52 +12,165 @@ import (
 // BabyService defines the business logic interface for baby operations
 type BabyService interface {
 	// CreateBaby creates a new baby (ADMIN only)
-	// Validates input and enforces RBAC
-	CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, createdByUserID uuid.UUID, isAdmin bool) (*domain.Baby, error)
+	// Validates input and enforces RBAC; dateOfBirth is YYYY-MM-DD, or empty when unknown
+	CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, error)
+
+	// EnsureBaby creates a baby unless a live one with the same parent, last name and room number exists (ADMIN only)
+	// Returns the existing baby and created = false in that case; used for at-least-once creation requests
+	EnsureBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (baby *domain.Baby, created bool, err error)
 
 	// GetBaby retrieves a baby by ID
-	// Enforces ownership: ADMIN can access any, PARENT only their own
-	GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, isAdmin bool) (*domain.Baby, error)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own
+	GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Baby, error)
 
 	// ListBabies retrieves babies based on role
-	// ADMIN: all babies, PARENT: only owned babies
-	ListBabies(ctx context.Context, userID uuid.UUID, isAdmin bool) ([]*domain.Baby, error)
+	// ADMIN and NURSE: all babies, PARENT: only owned babies
+	// includeDeleted adds soft-deleted babies (ADMIN only)
+	ListBabies(ctx context.Context, userID uuid.UUID, role domain.Role, includeDeleted bool) ([]*domain.Baby, error)
+
+	// GetParentSummary returns the authenticated parent's baby count and recent red measurements (PARENT only)
+	GetParentSummary(ctx context.Context, userID uuid.UUID, role domain.Role) (*domain.ParentSummary, error)
+
+	// UpdateBaby changes a baby's last name, room number and/or date of birth (ADMIN only)
+	// Empty values are left unchanged; returns the updated baby
+	UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth string, role domain.Role) (*domain.Baby, error)
+
+	// CreateBabies creates up to domain.MaxBabyBatchSize babies in one transaction (ADMIN only)
+	// Entries are validated like CreateBaby; atomic rejects the whole batch when any entry is invalid,
+	// otherwise the valid entries are created and the invalid ones reported
+	CreateBabies(ctx context.Context, requests []CreateBabyRequest, createdByUserID uuid.UUID, role domain.Role, atomic bool) (*domain.BabyBatchResult, error)
+
+	// DeleteBaby soft-deletes a baby (ADMIN only); its measurements are retained
+	DeleteBaby(ctx context.Context, babyID uuid.UUID, role domain.Role) error
+}
+
+// CreateBabyRequest represents the input for creating one baby of a batch
+type CreateBabyRequest struct {
+	LastName     string    `json:"last_name"`
+	RoomNumber   string    `json:"room_number"`
+	ParentUserID uuid.UUID `json:"parent_user_id"`
+	DateOfBirth  string    `json:"date_of_birth,omitempty"` // YYYY-MM-DD, empty when unknown
+}
+
+// AssignmentService defines the business logic interface for nurse assignments
+type AssignmentService interface {
+	// CreateAssignment assigns a nurse to one baby or to every room starting with roomPrefix (ADMIN only)
+	// Exactly one of babyID and roomPrefix must be given
+	CreateAssignment(ctx context.Context, nurseUserID uuid.UUID, babyID *uuid.UUID, roomPrefix string, role domain.Role) (*domain.NurseAssignment, error)
+
+	// ListAssignments retrieves a nurse's assignments (ADMIN only)
+	ListAssignments(ctx context.Context, nurseUserID uuid.UUID, role domain.Role) ([]*domain.NurseAssignment, error)
+
+	// DeleteAssignment removes one of a nurse's assignments (ADMIN only)
+	DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID, role domain.Role) error
+
+	// ListAssignedBabies retrieves the babies the calling nurse is responsible for (NURSE only)
+	ListAssignedBabies(ctx context.Context, userID uuid.UUID, role domain.Role) ([]*domain.Baby, error)
+}
+
+// AlertMuteService defines the business logic interface for muting a baby's alerts
+type AlertMuteService interface {
+	// MuteAlerts suppresses alert broadcasts for the baby for the given duration (owning PARENT or ADMIN)
+	// Replaces any existing mute; measurements are still stored and classified while muted
+	MuteAlerts(ctx context.Context, babyID uuid.UUID, duration time.Duration, reason string, userID uuid.UUID, role domain.Role) (*domain.AlertMute, error)
+}
+
+// AuditService defines the business logic interface for reading the measurement audit log
+type AuditService interface {
+	// ListAuditEntries retrieves a page of at most limit audit entries for the baby's measurements, newest first (ADMIN and NURSE only)
+	// next points past the last entry, or is nil on the last page
+	ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *AuditCursor, limit int, userID uuid.UUID, role domain.Role) (entries []*domain.AuditEntry, next *AuditCursor, err error)
 }
 
 // MeasurementService defines the business logic interface for measurement operations
 type MeasurementService interface {
 	// CreateMeasurement creates a new measurement for a baby (backward compatible)
 	// Enforces ownership: Only PARENT can add measurements to their own babies
-	// ADMIN cannot create measurements (read-only access)
+	// ADMIN and NURSE cannot create measurements (read-only access)
 	// Publishes alerts for Red status measurements
-	CreateMeasurement(ctx context.Context, babyID uuid.UUID, measurementType string, value float64, note string, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error)
+	CreateMeasurement(ctx context.Context, babyID uuid.UUID, measurementType string, value float64, note string, userID uuid.UUID, role domain.Role) (*domain.Measurement, error)
 
 	// CreateMeasurementWithDetails creates a measurement with full details including feeding-specific fields
 	// This method supports feeding types (bottle/breast) with amount/duration
 	// Only PARENT can create measurements for their own babies
-	CreateMeasurementWithDetails(ctx context.Context, babyID uuid.UUID, req CreateMeasurementRequest, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error)
+	CreateMeasurementWithDetails(ctx context.Context, babyID uuid.UUID, req CreateMeasurementRequest, userID uuid.UUID, role domain.Role) (*domain.Measurement, error)
+
+	// CreateWithIdempotency creates a measurement at most once per user and Idempotency-Key
+	// A repeated key returns the originally created measurement with replayed = true
+	CreateWithIdempotency(ctx context.Context, babyID uuid.UUID, req CreateMeasurementRequest, userID uuid.UUID, role domain.Role, idempotencyKey string) (*domain.Measurement, bool, error)
+
+	// ValidateMeasurement runs all create-time validation without persisting anything
+	// Enforces the same ownership rules as creation, returns the computed safety status or field errors
+	ValidateMeasurement(ctx context.Context, babyID uuid.UUID, req CreateMeasurementRequest, userID uuid.UUID, role domain.Role) (*MeasurementValidationResult, error)
 
 	// GetMeasurements retrieves all measurements for a baby
-	// Enforces ownership: ADMIN can access any, PARENT only their own babies
-	// Optional filters: measurementType (filter by type), limit (max results)
-	GetMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, isAdmin bool, measurementType *string, limit *int) ([]*domain.Measurement, error)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	// Optional filters: type, device ID, limit (max results), before/after cursors
+	GetMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, filter MeasurementFilter) ([]*domain.Measurement, error)
+
+	// GetFeedingBalance computes the breast vs bottle split for a baby over [from, to)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetFeedingBalance(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to time.Time) (*domain.FeedingBalance, error)
+
+	// CompareFeedingDays totals the feedings of two calendar days (day1 and day2 are midnight in the same location)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	CompareFeedingDays(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, day1, day2 time.Time) (*domain.FeedingComparison, error)
+
+	// GetHourlyFeedingDistribution counts feedings per hour of day (0-23 in loc) over [from, to)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetHourlyFeedingDistribution(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to time.Time, loc *time.Location) (*domain.HourlyFeedingDistribution, error)
+
+	// GetMeasurementStats returns per-type aggregates of a baby's measurements over an optional [from, to) window
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetMeasurementStats(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to *time.Time) ([]domain.MeasurementStats, error)
+
+	// GetMeasurementTrend returns hourly, daily or weekly averages of a numeric measurement type over [from, to)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetMeasurementTrend(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, measurementType string, bucket domain.TrendBucket, from, to time.Time, loc *time.Location) (*domain.MeasurementTrend, error)
+
+	// GetLatestMeasurements returns the newest measurement of each type keyed by type
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetLatestMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (map[string]*domain.Measurement, error)
+
+	// GetWardOverview returns the latest measurement of each type and the worst recent status of every baby
+	// whose room starts with roomPrefix (ADMIN and NURSE only)
+	GetWardOverview(ctx context.Context, roomPrefix string, userID uuid.UUID, role domain.Role) (*domain.WardOverview, error)
+
+	// GetSafetyStatusDistribution counts a baby's measurements per safety status
+	// Honors the Type, From and To filters; enforces ownership like GetMeasurementStats
+	GetSafetyStatusDistribution(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, filter MeasurementFilter) (*domain.SafetyStatusDistribution, error)
+
+	// GetDailyReport builds the printable summary for the calendar day starting at day (midnight in its location)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+	GetDailyReport(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, day time.Time) (*domain.DailyReport, error)
 
 	// GetMeasurementByID retrieves a specific measurement by ID
-	// Enforces ownership: ADMIN can access any, PARENT only their own babies' measurements
-	GetMeasurementByID(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error)
+	// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies' measurements
+	GetMeasurementByID(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Measurement, error)
+
+	// UpdateMeasurement edits the note and/or timestamp of a measurement
+	// Enforces ownership: Only the parent who created the measurement can update it
+	// ADMIN and NURSE cannot update measurements (read-only access)
+	UpdateMeasurement(ctx context.Context, measurementID uuid.UUID, req UpdateMeasurementRequest, userID uuid.UUID, role domain.Role) (*domain.Measurement, error)
 
-	// DeleteMeasurement deletes a measurement by ID
+	// DeleteMeasurement deletes a measurement by ID and returns the deleted measurement
 	// Enforces ownership: Only the parent who created the measurement can delete it
-	// ADMIN cannot delete measurements (read-only access)
-	DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, isAdmin bool) error
+	// ADMIN and NURSE cannot delete measurements (read-only access)
+	DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Measurement, error)
+
+	// BackfillSafetyStatus recomputes the safety status of rows whose stored status is NULL or not canonical
+	// Processes batches of batchSize (0 for the default) until none are left (ADMIN only)
+	BackfillSafetyStatus(ctx context.Context, role domain.Role, batchSize int) (*domain.SafetyStatusBackfillResult, error)
 }
 
 // CreateMeasurementRequest represents the input for creating a measurement with full details
 type CreateMeasurementRequest struct {
-	Type        string    `json:"type"`          // feeding, weight, temperature, diaper
+	Type        string    `json:"type"`          // feeding, weight, temperature, diaper, sleep
 	Value       float64   `json:"value"`        // Numeric value (weight in grams, temperature in Celsius)
 	Note        string    `json:"note"`         // Optional contextual metadata
 	Timestamp   time.Time `json:"timestamp"`    // When the measurement was taken
+	DeviceID    string    `json:"device_id,omitempty"` // Optional external device ID
+	Metadata    json.RawMessage `json:"metadata,omitempty"` // Optional JSON object of deployment-specific fields
 	
 	// Feeding-specific fields
 	FeedingType     string   `json:"feeding_type,omitempty"`     // "bottle" or "breast"
@@ -66,11 +180,33 @@ type CreateMeasurementRequest struct {
 	LeftDuration    *int     `json:"left_duration,omitempty"`    // Duration in seconds for left side
 	RightDuration   *int     `json:"right_duration,omitempty"`  // Duration in seconds for right side
 	Duration        *int     `json:"duration,omitempty"`         // Total duration in seconds (for single side)
+	SpitUp          *bool    `json:"spit_up,omitempty"`          // Whether the baby spat up after the feeding
+	SpitUpSeverity  string   `json:"spit_up_severity,omitempty"` // "mild", "moderate", or "severe" (requires spit_up)
 	
 	// Temperature-specific fields
 	ValueCelsius    *float64 `json:"value_celsius,omitempty"`   // Temperature in Celsius
 	
+	// Weight-specific fields
+	ValueGrams      *float64 `json:"value_grams,omitempty"`     // Weight in grams
+	
 	// Diaper-specific fields
 	DiaperStatus    string   `json:"diaper_status,omitempty"`   // "dry", "wet", "dirty", or "both"
+	
+	// Sleep-specific fields
+	SleepStart      *time.Time `json:"sleep_start,omitempty"`   // When the baby fell asleep
+	SleepEnd        *time.Time `json:"sleep_end,omitempty"`     // When the baby woke up
+}
+
+// UpdateMeasurementRequest represents a partial update of a measurement
+// Only note and timestamp are editable: type and value drive the safety status
+type UpdateMeasurementRequest struct {
+	Note      *string    `json:"note,omitempty"`      // New note (empty string clears it)
+	Timestamp *time.Time `json:"timestamp,omitempty"` // Corrected time the measurement was taken
 }
 
+// MeasurementValidationResult represents the outcome of validating a measurement payload (dry-run)
+type MeasurementValidationResult struct {
+	Valid        bool                `json:"valid"`
+	SafetyStatus domain.SafetyStatus `json:"safety_status,omitempty"` // Computed status when valid
+	Errors       []string            `json:"errors,omitempty"`        // Validation errors when invalid
+}
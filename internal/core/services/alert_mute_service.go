@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MaxAlertMuteDuration caps how long alerts for a baby can be muted at once
+const MaxAlertMuteDuration = 7 * 24 * time.Hour
+
+// AlertMuteService implements business logic for muting a baby's alerts
+// The baby's parent or an ADMIN mutes alerts for a clinically acknowledged condition
+type AlertMuteService struct {
+	muteRepo ports.AlertMuteRepository
+	babyRepo ports.BabyRepository
+}
+
+// NewAlertMuteService creates a new alert mute service
+func NewAlertMuteService(muteRepo ports.AlertMuteRepository, babyRepo ports.BabyRepository) *AlertMuteService {
+	return &AlertMuteService{
+		muteRepo: muteRepo,
+		babyRepo: babyRepo,
+	}
+}
+
+// MuteAlerts suppresses alert broadcasts for the baby for the given duration (owning PARENT or ADMIN)
+// Replaces any existing mute; measurements are still stored and classified while muted
+func (s *AlertMuteService) MuteAlerts(ctx context.Context, babyID uuid.UUID, duration time.Duration, reason string, userID uuid.UUID, role domain.Role) (*domain.AlertMute, error) {
+	// Input validation
+	if duration < time.Minute || duration > MaxAlertMuteDuration {
+		return nil, fmt.Errorf("duration_minutes must be between 1 and %d", int(MaxAlertMuteDuration/time.Minute))
+	}
+
+	// Existence and ownership are read together so a concurrent delete can't split them
+	exists, owned, err := s.babyRepo.GetBabyAccess(ctx, babyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check baby access: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("baby not found")
+	}
+
+	// RBAC enforcement: Only ADMIN or the baby's own PARENT can mute alerts
+	if role != domain.RoleAdmin && role != domain.RoleParent {
+		return nil, fmt.Errorf("forbidden: only the baby's parent or ADMIN can mute alerts")
+	}
+	if role == domain.RoleParent && !owned {
+		// Don't leak ownership info - return generic not found
+		return nil, fmt.Errorf("baby not found")
+	}
+
+	now := time.Now()
+	mute := &domain.AlertMute{
+		BabyID:     babyID,
+		MutedUntil: now.Add(duration),
+		MutedBy:    userID,
+		Reason:     strings.TrimSpace(reason),
+		CreatedAt:  now,
+	}
+	if err := s.muteRepo.UpsertAlertMute(ctx, mute); err != nil {
+		return nil, fmt.Errorf("failed to mute alerts: %w", err)
+	}
+
+	return mute, nil
+}
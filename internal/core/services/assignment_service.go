@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// AssignmentService implements business logic for nurse assignments
+// ADMIN manages assignments; a NURSE lists the babies they are responsible for
+type AssignmentService struct {
+	assignmentRepo ports.AssignmentRepository
+	babyRepo       ports.BabyRepository
+}
+
+// NewAssignmentService creates a new assignment service
+func NewAssignmentService(assignmentRepo ports.AssignmentRepository, babyRepo ports.BabyRepository) *AssignmentService {
+	return &AssignmentService{
+		assignmentRepo: assignmentRepo,
+		babyRepo:       babyRepo,
+	}
+}
+
+// CreateAssignment assigns a nurse to one baby or to every room starting with roomPrefix (ADMIN only)
+// Exactly one of babyID and roomPrefix must be given
+func (s *AssignmentService) CreateAssignment(ctx context.Context, nurseUserID uuid.UUID, babyID *uuid.UUID, roomPrefix string, role domain.Role) (*domain.NurseAssignment, error) {
+	// RBAC enforcement: Only ADMIN can manage assignments
+	if role != domain.RoleAdmin {
+		return nil, fmt.Errorf("forbidden: only ADMIN can manage assignments")
+	}
+
+	// Input validation
+	roomPrefix = strings.TrimSpace(roomPrefix)
+	if (babyID == nil) == (roomPrefix == "") {
+		return nil, fmt.Errorf("exactly one of baby_id or room_prefix is required")
+	}
+
+	assignment := &domain.NurseAssignment{
+		ID:          uuid.New(),
+		NurseUserID: nurseUserID,
+		CreatedAt:   time.Now(),
+	}
+	if babyID != nil {
+		exists, err := s.babyRepo.BabyExists(ctx, *babyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check baby existence: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("baby not found")
+		}
+		assignment.BabyID = babyID
+	} else {
+		assignment.RoomPrefix = &roomPrefix
+	}
+
+	if err := s.assignmentRepo.CreateAssignment(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to create assignment: %w", err)
+	}
+
+	return assignment, nil
+}
+
+// ListAssignments retrieves a nurse's assignments (ADMIN only)
+func (s *AssignmentService) ListAssignments(ctx context.Context, nurseUserID uuid.UUID, role domain.Role) ([]*domain.NurseAssignment, error) {
+	if role != domain.RoleAdmin {
+		return nil, fmt.Errorf("forbidden: only ADMIN can manage assignments")
+	}
+
+	assignments, err := s.assignmentRepo.ListAssignments(ctx, nurseUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// DeleteAssignment removes one of a nurse's assignments (ADMIN only)
+func (s *AssignmentService) DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID, role domain.Role) error {
+	if role != domain.RoleAdmin {
+		return fmt.Errorf("forbidden: only ADMIN can manage assignments")
+	}
+
+	if err := s.assignmentRepo.DeleteAssignment(ctx, assignmentID, nurseUserID); err != nil {
+		if err.Error() == "assignment not found" {
+			return fmt.Errorf("assignment not found")
+		}
+		return fmt.Errorf("failed to delete assignment: %w", err)
+	}
+
+	return nil
+}
+
+// ListAssignedBabies retrieves the babies the calling nurse is responsible for (NURSE only)
+func (s *AssignmentService) ListAssignedBabies(ctx context.Context, userID uuid.UUID, role domain.Role) ([]*domain.Baby, error) {
+	if role != domain.RoleNurse {
+		return nil, fmt.Errorf("forbidden: only NURSE has assignments")
+	}
+
+	babies, err := s.assignmentRepo.ListAssignedBabies(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assigned babies: %w", err)
+	}
+
+	return babies, nil
+}
+
+// nurseAssignedTo reports whether the caller may read the baby under assignment scoping
+// Only NURSE reads are scoped, and only when assignments is set (enforcement enabled)
+func nurseAssignedTo(ctx context.Context, assignments ports.AssignmentRepository, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (bool, error) {
+	if assignments == nil || role != domain.RoleNurse {
+		return true, nil
+	}
+
+	assigned, err := assignments.IsBabyAssigned(ctx, babyID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check nurse assignment: %w", err)
+	}
+	return assigned, nil
+}
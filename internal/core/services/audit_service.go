@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// MaxAuditPageSize caps how many audit entries a single page may return
+const MaxAuditPageSize = 200
+
+// AuditService implements business logic for reading the measurement audit log
+// Entries are written by MeasurementService; clinical staff read them to trace changes to a baby's record
+type AuditService struct {
+	auditRepo        ports.AuditRepository
+	babyRepo         ports.BabyRepository
+	nurseAssignments ports.AssignmentRepository
+}
+
+// NewAuditService creates a new audit service
+// nurseAssignments limits NURSE reads to assigned babies (nil: NURSE reads any baby)
+func NewAuditService(auditRepo ports.AuditRepository, babyRepo ports.BabyRepository, nurseAssignments ports.AssignmentRepository) *AuditService {
+	return &AuditService{
+		auditRepo:        auditRepo,
+		babyRepo:         babyRepo,
+		nurseAssignments: nurseAssignments,
+	}
+}
+
+// ListAuditEntries retrieves a page of at most limit audit entries for the baby's measurements, newest first (ADMIN and NURSE only)
+// next points past the last entry, or is nil on the last page
+// Soft-deleted babies keep their audit trail, so only a baby without any row is not found
+func (s *AuditService) ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *ports.AuditCursor, limit int, userID uuid.UUID, role domain.Role) ([]*domain.AuditEntry, *ports.AuditCursor, error) {
+	// RBAC enforcement: PARENT cannot read the audit log
+	if role != domain.RoleAdmin && role != domain.RoleNurse {
+		return nil, nil, fmt.Errorf("forbidden: only ADMIN and NURSE can read the audit log")
+	}
+
+	// Input validation
+	if limit < 1 || limit > MaxAuditPageSize {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", MaxAuditPageSize)
+	}
+
+	exists, err := s.babyRepo.BabyRecordExists(ctx, babyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check baby existence: %w", err)
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("baby not found")
+	}
+
+	// NURSE only reads assigned babies when assignments are enforced
+	assigned, err := nurseAssignedTo(ctx, s.nurseAssignments, babyID, userID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !assigned {
+		return nil, nil, fmt.Errorf("baby not found")
+	}
+
+	// Fetch one extra entry to know whether another page exists
+	entries, err := s.auditRepo.ListAuditEntries(ctx, babyID, before, limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	if entries == nil {
+		entries = []*domain.AuditEntry{}
+	}
+
+	var next *ports.AuditCursor
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		next = &ports.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return entries, next, nil
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
@@ -13,98 +15,324 @@ import (
 // BabyService implements business logic for baby operations
 // Enforces RBAC and ownership rules
 type BabyService struct {
-	babyRepo ports.BabyRepository
+	babyRepo          ports.BabyRepository
+	assignments       ports.AssignmentRepository // nil: NURSE reads any baby
+	roomNumberPattern *regexp.Regexp             // nil: domain.DefaultRoomNumberPattern
+}
+
+// BabyServiceConfig holds optional dependencies and settings for the baby service
+// The zero value matches the default behavior
+type BabyServiceConfig struct {
+	// Assignments limits NURSE reads to assigned babies; nil disables the scoping
+	Assignments ports.AssignmentRepository
+
+	// RoomNumberPattern is the format normalized room numbers must match (nil uses domain.DefaultRoomNumberPattern)
+	RoomNumberPattern *regexp.Regexp
 }
 
 // NewBabyService creates a new baby service
 func NewBabyService(babyRepo ports.BabyRepository) *BabyService {
+	return NewBabyServiceWithAssignments(babyRepo, nil)
+}
+
+// NewBabyServiceWithAssignments creates a baby service that limits NURSE reads to assigned babies
+// A nil assignments repository disables the scoping
+func NewBabyServiceWithAssignments(babyRepo ports.BabyRepository, assignments ports.AssignmentRepository) *BabyService {
+	return NewBabyServiceWithConfig(babyRepo, BabyServiceConfig{Assignments: assignments})
+}
+
+// NewBabyServiceWithConfig creates a new baby service with the given configuration
+func NewBabyServiceWithConfig(babyRepo ports.BabyRepository, config BabyServiceConfig) *BabyService {
 	return &BabyService{
-		babyRepo: babyRepo,
+		babyRepo:          babyRepo,
+		assignments:       config.Assignments,
+		roomNumberPattern: config.RoomNumberPattern,
 	}
 }
 
 // CreateBaby creates a new baby (ADMIN only)
-// Validates input and enforces RBAC
-func (s *BabyService) CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, createdByUserID uuid.UUID, isAdmin bool) (*domain.Baby, error) {
-	// RBAC enforcement: Only ADMIN can create babies
-	if !isAdmin {
+// Validates input and enforces RBAC; dateOfBirth is YYYY-MM-DD, or empty when unknown
+func (s *BabyService) CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	// RBAC enforcement: Only ADMIN can create babies (NURSE has read-only access)
+	if !role.CanCreateBabies() {
 		return nil, fmt.Errorf("forbidden: only ADMIN can create babies")
 	}
 
-	// Input validation
+	baby, err := s.newBaby(lastName, roomNumber, parentUserID, dateOfBirth)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.babyRepo.CreateBaby(ctx, baby); err != nil {
+		return nil, fmt.Errorf("failed to create baby: %w", err)
+	}
+
+	return baby, nil
+}
+
+// newBaby validates the input of a baby creation and builds the baby to insert
+func (s *BabyService) newBaby(lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string) (*domain.Baby, error) {
+	// Input validation (whitespace-only values count as empty; padding is not stored)
+	lastName = strings.TrimSpace(lastName)
 	if lastName == "" {
 		return nil, fmt.Errorf("baby last_name cannot be empty")
 	}
-	if roomNumber == "" {
-		return nil, fmt.Errorf("baby room_number cannot be empty")
+	roomNumber, err := domain.NormalizeRoomNumber(roomNumber, s.roomNumberPattern)
+	if err != nil {
+		return nil, err
+	}
+	// The date of birth selects the age-adjusted temperature bands of the baby's measurements
+	now := time.Now()
+	dob, err := domain.ParseDateOfBirth(dateOfBirth, now)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create baby
 	baby := &domain.Baby{
 		ID:           uuid.New(),
 		LastName:     lastName,
 		RoomNumber:   roomNumber,
 		ParentUserID: parentUserID,
-		CreatedAt:    time.Now(),
+		DateOfBirth:  dob,
+		CreatedAt:    now,
 	}
+	if dob != nil {
+		ageDays := domain.AgeInDays(*dob, now)
+		baby.AgeDays = &ageDays
+	}
+	return baby, nil
+}
 
-	if err := s.babyRepo.CreateBaby(ctx, baby); err != nil {
-		return nil, fmt.Errorf("failed to create baby: %w", err)
+// CreateBabies creates a batch of babies in one transaction (ADMIN only)
+// Every entry gets the validation of CreateBaby. With atomic, one invalid entry rejects the whole batch;
+// otherwise the valid entries are created and each invalid one is reported with its error
+func (s *BabyService) CreateBabies(ctx context.Context, requests []ports.CreateBabyRequest, createdByUserID uuid.UUID, role domain.Role, atomic bool) (*domain.BabyBatchResult, error) {
+	// RBAC enforcement: Only ADMIN can create babies (NURSE has read-only access)
+	if !role.CanCreateBabies() {
+		return nil, fmt.Errorf("forbidden: only ADMIN can create babies")
 	}
 
-	return baby, nil
+	if len(requests) == 0 || len(requests) > domain.MaxBabyBatchSize {
+		return nil, fmt.Errorf("batch must contain between 1 and %d babies", domain.MaxBabyBatchSize)
+	}
+
+	result := &domain.BabyBatchResult{
+		Atomic:  atomic,
+		Results: make([]domain.BabyBatchItemResult, len(requests)),
+	}
+	babies := make([]*domain.Baby, 0, len(requests))
+	for i, req := range requests {
+		result.Results[i].Index = i
+		baby, err := s.newBaby(req.LastName, req.RoomNumber, req.ParentUserID, req.DateOfBirth)
+		if err != nil {
+			result.Results[i].Error = err.Error()
+			result.Failed++
+			continue
+		}
+		result.Results[i].Baby = baby
+		babies = append(babies, baby)
+	}
+
+	// An atomic batch with an invalid entry creates nothing; the valid entries are reported without a baby
+	if atomic && result.Failed > 0 {
+		for i := range result.Results {
+			result.Results[i].Baby = nil
+		}
+		return result, nil
+	}
+	if len(babies) == 0 {
+		return result, nil
+	}
+
+	if err := s.babyRepo.CreateBabies(ctx, babies); err != nil {
+		return nil, fmt.Errorf("failed to create babies: %w", err)
+	}
+	result.Created = len(babies)
+
+	return result, nil
+}
+
+// EnsureBaby creates a baby unless a live one with the same parent, last name and room number exists (ADMIN only)
+// Makes redelivered creation requests (at-least-once delivery) a no-op; returns the existing baby with created = false
+// Unlike CreateBaby it can't register twins sharing a last name and room, which have to be created via POST /babies
+func (s *BabyService) EnsureBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, bool, error) {
+	// RBAC enforcement: Only ADMIN can create babies (NURSE has read-only access)
+	if !role.CanCreateBabies() {
+		return nil, false, fmt.Errorf("forbidden: only ADMIN can create babies")
+	}
+
+	// Match on the values CreateBaby would store
+	lastName = strings.TrimSpace(lastName)
+	roomNumber, err := domain.NormalizeRoomNumber(roomNumber, s.roomNumberPattern)
+	if err != nil {
+		return nil, false, err
+	}
+	existing, err := s.babyRepo.FindBabyByParentAndRoom(ctx, parentUserID, lastName, roomNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up existing baby: %w", err)
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	baby, err := s.CreateBaby(ctx, lastName, roomNumber, parentUserID, dateOfBirth, createdByUserID, role)
+	if err != nil {
+		return nil, false, err
+	}
+	return baby, true, nil
 }
 
 // GetBaby retrieves a baby by ID
-// Enforces ownership: ADMIN can access any, PARENT only their own
-func (s *BabyService) GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, isAdmin bool) (*domain.Baby, error) {
-	// Check if baby exists
-	exists, err := s.babyRepo.BabyExists(ctx, babyID)
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own
+func (s *BabyService) GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	// Single read: existence and ownership come from the same row, so a concurrent
+	// delete always surfaces as "baby not found" rather than a lookup failure
+	baby, err := s.babyRepo.GetBabyByID(ctx, babyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check baby existence: %w", err)
+		if err.Error() == "baby not found" {
+			return nil, fmt.Errorf("baby not found")
+		}
+		return nil, fmt.Errorf("failed to get baby: %w", err)
 	}
-	if !exists {
+
+	// PARENT can only access their own babies
+	if !role.CanReadAllBabies() && baby.ParentUserID != userID {
 		// Don't leak ownership info - return generic not found
 		return nil, fmt.Errorf("baby not found")
 	}
 
-	// ADMIN can access any baby
-	if isAdmin {
-		baby, err := s.babyRepo.GetBabyByID(ctx, babyID)
+	// NURSE only reads assigned babies when assignments are enforced
+	assigned, err := nurseAssignedTo(ctx, s.assignments, babyID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("baby not found")
+	}
+
+	return baby, nil
+}
+
+// UpdateBaby changes a baby's last name, room number and/or date of birth (ADMIN only)
+// Babies move between rooms and names get corrected; empty values are left unchanged
+func (s *BabyService) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth string, role domain.Role) (*domain.Baby, error) {
+	// RBAC enforcement: Only ADMIN can update babies (NURSE and PARENT have read-only access)
+	if role != domain.RoleAdmin {
+		return nil, fmt.Errorf("forbidden: only ADMIN can update babies")
+	}
+
+	// Input validation: omitted (empty) fields are left unchanged, but a whitespace-only value is an error
+	trimmedLastName := strings.TrimSpace(lastName)
+	if lastName != "" && trimmedLastName == "" {
+		return nil, fmt.Errorf("baby last_name cannot be empty")
+	}
+	lastName = trimmedLastName
+	if roomNumber != "" {
+		normalized, err := domain.NormalizeRoomNumber(roomNumber, s.roomNumberPattern)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get baby: %w", err)
+			return nil, err
 		}
-		return baby, nil
+		roomNumber = normalized
 	}
-
-	// PARENT can only access their own babies
-	owned, err := s.babyRepo.CheckBabyOwnership(ctx, babyID, userID)
+	dob, err := domain.ParseDateOfBirth(dateOfBirth, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to check ownership: %w", err)
+		return nil, err
 	}
-	if !owned {
-		// Don't leak ownership info - return generic not found
-		return nil, fmt.Errorf("baby not found")
+	if lastName == "" && roomNumber == "" && dob == nil {
+		return nil, fmt.Errorf("at least one of last_name, room_number or date_of_birth is required")
+	}
+
+	if err := s.babyRepo.UpdateBaby(ctx, babyID, lastName, roomNumber, dob); err != nil {
+		if err.Error() == "baby not found" {
+			return nil, fmt.Errorf("baby not found")
+		}
+		return nil, fmt.Errorf("failed to update baby: %w", err)
 	}
 
 	baby, err := s.babyRepo.GetBabyByID(ctx, babyID)
 	if err != nil {
+		if err.Error() == "baby not found" {
+			return nil, fmt.Errorf("baby not found")
+		}
 		return nil, fmt.Errorf("failed to get baby: %w", err)
 	}
 
 	return baby, nil
 }
 
+// DeleteBaby soft-deletes a baby (ADMIN only)
+// The row and its measurements are retained for hospital records; the baby disappears from reads
+func (s *BabyService) DeleteBaby(ctx context.Context, babyID uuid.UUID, role domain.Role) error {
+	// RBAC enforcement: Only ADMIN can delete babies
+	if role != domain.RoleAdmin {
+		return fmt.Errorf("forbidden: only ADMIN can delete babies")
+	}
+
+	if err := s.babyRepo.DeleteBaby(ctx, babyID); err != nil {
+		if err.Error() == "baby not found" {
+			return fmt.Errorf("baby not found")
+		}
+		return fmt.Errorf("failed to delete baby: %w", err)
+	}
+
+	return nil
+}
+
+// ParentSummaryRecentWindow is how far back a parent summary counts red measurements
+const ParentSummaryRecentWindow = 24 * time.Hour
+
+// GetParentSummary counts the authenticated parent's live babies and their red measurements in the last 24h
+// Only PARENT has babies of their own; ADMIN and NURSE are rejected rather than given a global summary,
+// since the ward overview and business metrics already cover the whole service
+func (s *BabyService) GetParentSummary(ctx context.Context, userID uuid.UUID, role domain.Role) (*domain.ParentSummary, error) {
+	if role != domain.RoleParent {
+		return nil, fmt.Errorf("parent summary is only available to PARENT accounts")
+	}
+
+	babyCount, err := s.babyRepo.CountBabiesForParent(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count babies: %w", err)
+	}
+
+	since := time.Now().Add(-ParentSummaryRecentWindow)
+	summary := &domain.ParentSummary{ParentUserID: userID, BabyCount: babyCount, Since: since}
+	if babyCount == 0 {
+		return summary, nil
+	}
+
+	summary.RedMeasurementsRecent, err = s.babyRepo.CountRedMeasurementsForParent(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count red measurements: %w", err)
+	}
+
+	return summary, nil
+}
+
 // ListBabies retrieves babies based on role
-// ADMIN: all babies, PARENT: only owned babies
-func (s *BabyService) ListBabies(ctx context.Context, userID uuid.UUID, isAdmin bool) ([]*domain.Baby, error) {
+// ADMIN and NURSE: all babies, PARENT: only owned babies
+// includeDeleted adds soft-deleted babies and is ADMIN only
+func (s *BabyService) ListBabies(ctx context.Context, userID uuid.UUID, role domain.Role, includeDeleted bool) ([]*domain.Baby, error) {
+	if includeDeleted && role != domain.RoleAdmin {
+		return nil, fmt.Errorf("forbidden: only ADMIN can list deleted babies")
+	}
+
+	// NURSE only sees assigned babies when assignments are enforced
+	if role == domain.RoleNurse && s.assignments != nil {
+		babies, err := s.assignments.ListAssignedBabies(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list babies: %w", err)
+		}
+		return babies, nil
+	}
+
 	parentUserID := userID
-	if isAdmin {
-		// ADMIN can see all babies, parentUserID is ignored
+	readAll := role.CanReadAllBabies()
+	if readAll {
+		// ADMIN and NURSE can see all babies, parentUserID is ignored
 		parentUserID = uuid.Nil
 	}
 
-	babies, err := s.babyRepo.ListBabies(ctx, parentUserID, isAdmin)
+	babies, err := s.babyRepo.ListBabies(ctx, parentUserID, readAll, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list babies: %w", err)
 	}
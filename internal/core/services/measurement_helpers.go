@@ -1,6 +1,8 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
@@ -31,7 +33,7 @@ func (s *MeasurementService) setFeedingFields(measurement *domain.Measurement, r
 		measurement.VolumeML = req.VolumeML
 		measurement.Value = float64(*req.VolumeML) // Store volume as value for consistency
 	} else {
-		// Breast feeding: requires Side and Position
+		// Breast feeding: requires Side, and Position when RequireBreastfeedingPosition is set
 		if req.Side == "" {
 			return fmt.Errorf("breast feeding requires side (left, right, or both)")
 		}
@@ -43,6 +45,9 @@ func (s *MeasurementService) setFeedingFields(measurement *domain.Measurement, r
 
 		measurement.Side = &side
 
+		if req.Position == "" && s.config.RequireBreastfeedingPosition {
+			return fmt.Errorf("breast feeding requires position (cross_cradle, cradle, football, side_lying, or laid_back)")
+		}
 		if req.Position != "" {
 			position := domain.BreastfeedingPosition(req.Position)
 			if !domain.IsValidBreastfeedingPosition(position) {
@@ -78,21 +83,28 @@ func (s *MeasurementService) setFeedingFields(measurement *domain.Measurement, r
 		}
 	}
 
+	// Optional spit-up after either kind of feeding
+	if req.SpitUpSeverity != "" {
+		if req.SpitUp == nil || !*req.SpitUp {
+			return fmt.Errorf("spit_up_severity requires spit_up to be true")
+		}
+		severity := domain.SpitUpSeverity(req.SpitUpSeverity)
+		if !domain.IsValidSpitUpSeverity(severity) {
+			return fmt.Errorf("invalid spit_up_severity: must be 'mild', 'moderate', or 'severe'")
+		}
+		measurement.SpitUpSeverity = &severity
+	}
+	measurement.SpitUp = req.SpitUp
+
 	return nil
 }
 
 // setTemperatureFields sets temperature-specific fields on a measurement
 func (s *MeasurementService) setTemperatureFields(measurement *domain.Measurement, req ports.CreateMeasurementRequest) error {
-	// Use ValueCelsius if provided, otherwise fall back to Value
-	var tempValue float64
-	if req.ValueCelsius != nil {
-		tempValue = *req.ValueCelsius
-	} else {
-		tempValue = req.Value
-	}
+	tempValue := temperatureCelsius(req)
 
-	if tempValue < 30.0 || tempValue > 42.0 {
-		return fmt.Errorf("temperature must be between 30.0 and 42.0°C")
+	if err := s.validateTemperature(tempValue); err != nil {
+		return err
 	}
 
 	measurement.ValueCelsius = &tempValue
@@ -101,6 +113,40 @@ func (s *MeasurementService) setTemperatureFields(measurement *domain.Measuremen
 	return nil
 }
 
+// setWeightFields sets weight-specific fields on a measurement
+func (s *MeasurementService) setWeightFields(measurement *domain.Measurement, req ports.CreateMeasurementRequest) error {
+	grams := weightGrams(req)
+
+	if grams <= 0 {
+		return fmt.Errorf("weight must be greater than 0 grams")
+	}
+	if grams > 10000 {
+		return fmt.Errorf("weight exceeds reasonable maximum (10000g)")
+	}
+
+	// value_grams is the typed home for weight, value stays the generic numeric
+	measurement.ValueGrams = &grams
+	measurement.Value = grams
+
+	return nil
+}
+
+// weightGrams returns the weight from ValueGrams if provided, otherwise from Value
+func weightGrams(req ports.CreateMeasurementRequest) float64 {
+	if req.ValueGrams != nil {
+		return *req.ValueGrams
+	}
+	return req.Value
+}
+
+// temperatureCelsius returns the temperature from ValueCelsius if provided, otherwise from Value
+func temperatureCelsius(req ports.CreateMeasurementRequest) float64 {
+	if req.ValueCelsius != nil {
+		return *req.ValueCelsius
+	}
+	return req.Value
+}
+
 // setDiaperFields sets diaper-specific fields on a measurement
 func (s *MeasurementService) setDiaperFields(measurement *domain.Measurement, req ports.CreateMeasurementRequest) error {
 	if req.DiaperStatus == "" {
@@ -119,3 +165,36 @@ func (s *MeasurementService) setDiaperFields(measurement *domain.Measurement, re
 	return nil
 }
 
+
+// setSleepFields sets sleep-specific fields on a measurement
+// Value holds the session duration in seconds
+func (s *MeasurementService) setSleepFields(measurement *domain.Measurement, req ports.CreateMeasurementRequest) error {
+	if req.SleepStart == nil || req.SleepEnd == nil {
+		return fmt.Errorf("sleep requires sleep_start and sleep_end")
+	}
+
+	start, end := *req.SleepStart, *req.SleepEnd
+	if !end.After(start) {
+		return fmt.Errorf("sleep_end must be after sleep_start")
+	}
+
+	duration := end.Sub(start)
+	if duration > domain.MaxSleepDuration {
+		return fmt.Errorf("sleep session exceeds reasonable maximum (24 hours)")
+	}
+
+	measurement.SleepStart = &start
+	measurement.SleepEnd = &end
+	measurement.Value = duration.Seconds()
+
+	return nil
+}
+
+// requestMetadata returns the request's metadata, or nil when it is absent or JSON null
+func requestMetadata(req ports.CreateMeasurementRequest) json.RawMessage {
+	metadata := bytes.TrimSpace(req.Metadata)
+	if len(metadata) == 0 || bytes.Equal(metadata, []byte("null")) {
+		return nil
+	}
+	return req.Metadata
+}
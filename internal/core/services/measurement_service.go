@@ -3,11 +3,12 @@ package services
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/core/domain"
@@ -24,25 +25,228 @@ type MeasurementService struct {
 	measurementRepo ports.MeasurementRepository
 	babyRepo        ports.BabyRepository
 	alertPublisher  ports.AlertPublisher
+	config          MeasurementServiceConfig
+	logger          *slog.Logger
+
+	// lastAlerts holds when an alert was last published per alertThrottleKey (time.Time values)
+	lastAlerts sync.Map
+
+	// alerts queues background alert publishes for a fixed pool of workers
+	// closeMu guards closing it against concurrent enqueues; see Close
+	alerts       chan alertJob
+	alertWorkers sync.WaitGroup
+	closeMu      sync.RWMutex
+	closed       bool
+}
+
+// alertJob is one alert waiting in the background publish queue
+type alertJob struct {
+	babyID      uuid.UUID
+	measurement *domain.Measurement
+	startTime   time.Time
+}
+
+// alertThrottleKey identifies the alerts one throttle window applies to
+// The safety status is part of the key so a Red reading is never held back by an earlier Yellow one
+type alertThrottleKey struct {
+	babyID          uuid.UUID
+	measurementType string
+	status          domain.SafetyStatus
+}
+
+// MeasurementServiceConfig holds optional behavior switches for the measurement service
+// The zero value matches the default behavior
+type MeasurementServiceConfig struct {
+	// RequireNoteOnRed rejects Red status measurements that have no note explaining the context
+	RequireNoteOnRed bool
+
+	// RequireBreastfeedingPosition rejects breast feedings sent without a position (default: position is optional)
+	RequireBreastfeedingPosition bool
+
+	// NoteTemplates fills in the note of new measurements sent without one, per type (nil or empty: notes are kept as sent)
+	// A template does not count as the note RequireNoteOnRed asks for
+	NoteTemplates domain.NoteTemplates
+
+	// TemperatureMinCelsius and TemperatureMaxCelsius bound the temperatures accepted for storage
+	// Only physically impossible values should fall outside; extremes inside are stored as Red
+	// Both zero means the defaults (DefaultTemperatureMinCelsius-DefaultTemperatureMaxCelsius)
+	TemperatureMinCelsius float64
+	TemperatureMaxCelsius float64
+
+	// AlertQueueSize bounds the alerts waiting to be published in the background (0 means DefaultAlertQueueSize)
+	// AlertWorkers is how many alerts are published concurrently (0 means DefaultAlertWorkers)
+	// When the queue is full new alerts are dropped, logged and counted instead of piling up during an alert storm
+	AlertQueueSize int
+	AlertWorkers   int
+
+	// WeightMinInterval is the minimum time between two weight measurements of a baby (0 disables the check)
+	// Weights logged closer together get a warning, or are rejected when RejectWeightWithinMinInterval is set
+	WeightMinInterval             time.Duration
+	RejectWeightWithinMinInterval bool
+
+	// Visibility limits which measurement types each role can read (nil or empty: all types for all roles)
+	Visibility domain.MeasurementVisibility
+
+	// IdempotencyKeyTTL is how long an Idempotency-Key replays the original measurement (0 means DefaultIdempotencyKeyTTL)
+	IdempotencyKeyTTL time.Duration
+
+	// RejectBeforeBabyCreated rejects measurements timestamped earlier than the baby's created_at minus BabyCreatedGrace
+	// Trend computations assume no data predates the baby's record; the grace allows entries logged on paper before registration
+	RejectBeforeBabyCreated bool
+	BabyCreatedGrace        time.Duration
+
+	// MaxFutureSkew is how far ahead of the server clock a measurement timestamp may be (0 means DefaultMaxFutureSkew)
+	// Absorbs small device clock drift; anything further ahead would corrupt the ordering of the timeline
+	MaxFutureSkew time.Duration
+
+	// NearDuplicateWindow warns about a measurement with the same type and value as another of the baby's
+	// measurements within this window (0 disables the check)
+	NearDuplicateWindow time.Duration
+
+	// PublishYellowAlerts also publishes Yellow status measurements as "warning" alerts (Red is always published)
+	PublishYellowAlerts bool
+
+	// FailOnAlertPublishFailure publishes Red alerts before the create returns instead of in the background
+	// When publishing fails the new measurement is deleted again and ErrAlertUnavailable is returned
+	FailOnAlertPublishFailure bool
+
+	// AlertThrottleWindow publishes at most one alert per baby, type and safety status within this window
+	// (0 disables it); a flapping sensor then can't flood nurses with alerts for every reading
+	AlertThrottleWindow time.Duration
+
+	// AlertMutes suppresses alert publishing for babies with an active mute (nil: alerts are never muted)
+	AlertMutes ports.AlertMuteRepository
+
+	// NurseAssignments limits NURSE reads to babies the nurse is assigned to (nil: NURSE reads any baby)
+	NurseAssignments ports.AssignmentRepository
+
+	// Metrics counts created measurements by type and safety status and times their creation (nil: not recorded)
+	Metrics ports.MeasurementMetrics
+
+	// Audit records who created, updated or deleted each measurement (nil: no audit log)
+	// A failed audit write is logged and counted but never fails the mutation itself
+	Audit ports.AuditRepository
+
+	// MetadataSchema validates the optional metadata object of new measurements (nil: any object is accepted)
+	// MetadataMaxBytes limits its encoded size (0 means domain.DefaultMetadataMaxBytes)
+	MetadataSchema   *domain.MetadataSchema
+	MetadataMaxBytes int
+
+	// Logger receives the structured measurement and alert events (nil: slog.Default())
+	Logger *slog.Logger
 }
 
-// NewMeasurementService creates a new measurement service
+// Default storage range for temperature readings in Celsius
+const (
+	DefaultTemperatureMinCelsius = 20.0
+	DefaultTemperatureMaxCelsius = 45.0
+)
+
+// Default size of the background alert publishing pool
+const (
+	DefaultAlertQueueSize = 100
+	DefaultAlertWorkers   = 4
+)
+
+// DefaultIdempotencyKeyTTL is how long idempotency keys are remembered when not configured
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+// BackdatedWarningAge is how far in the past a measurement timestamp may be before the create response warns about it
+const BackdatedWarningAge = 24 * time.Hour
+
+// DefaultMaxFutureSkew is how far in the future a measurement timestamp may be when not configured
+const DefaultMaxFutureSkew = 5 * time.Minute
+
+// EarliestMeasurementTimestamp is the oldest timestamp accepted; anything earlier is a client bug, not a real reading
+var EarliestMeasurementTimestamp = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TemperatureBorderlineMargin is how close (in Celsius) a Green temperature may be to the edge of the normal range
+// before the create response warns that it is borderline
+const TemperatureBorderlineMargin = 0.1
+
+// WardOverviewRecentWindow is how far back a ward overview looks for each baby's worst status
+const WardOverviewRecentWindow = 24 * time.Hour
+
+// MaxRoomPrefixLength bounds the room prefix of a ward overview
+const MaxRoomPrefixLength = 32
+
+// Batch sizes for the safety_status backfill
+const (
+	DefaultBackfillBatchSize = 500
+	MaxBackfillBatchSize     = 1000
+)
+
+// NewMeasurementService creates a new measurement service with the default configuration
 func NewMeasurementService(
 	measurementRepo ports.MeasurementRepository,
 	babyRepo ports.BabyRepository,
 	alertPublisher ports.AlertPublisher,
 ) *MeasurementService {
-	return &MeasurementService{
+	return NewMeasurementServiceWithConfig(measurementRepo, babyRepo, alertPublisher, MeasurementServiceConfig{})
+}
+
+// NewMeasurementServiceWithConfig creates a new measurement service with the given configuration
+func NewMeasurementServiceWithConfig(
+	measurementRepo ports.MeasurementRepository,
+	babyRepo ports.BabyRepository,
+	alertPublisher ports.AlertPublisher,
+	config MeasurementServiceConfig,
+) *MeasurementService {
+	if config.TemperatureMinCelsius == 0 && config.TemperatureMaxCelsius == 0 {
+		config.TemperatureMinCelsius = DefaultTemperatureMinCelsius
+		config.TemperatureMaxCelsius = DefaultTemperatureMaxCelsius
+	}
+	if config.IdempotencyKeyTTL <= 0 {
+		config.IdempotencyKeyTTL = DefaultIdempotencyKeyTTL
+	}
+	if config.MaxFutureSkew <= 0 {
+		config.MaxFutureSkew = DefaultMaxFutureSkew
+	}
+	if config.MetadataMaxBytes <= 0 {
+		config.MetadataMaxBytes = domain.DefaultMetadataMaxBytes
+	}
+	if config.AlertQueueSize <= 0 {
+		config.AlertQueueSize = DefaultAlertQueueSize
+	}
+	if config.AlertWorkers <= 0 {
+		config.AlertWorkers = DefaultAlertWorkers
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &MeasurementService{
 		measurementRepo: measurementRepo,
 		babyRepo:        babyRepo,
 		alertPublisher:  alertPublisher,
+		config:          config,
+		logger:          logger,
+		alerts:          make(chan alertJob, config.AlertQueueSize),
+	}
+	for i := 0; i < config.AlertWorkers; i++ {
+		s.alertWorkers.Add(1)
+		go s.runAlertWorker()
+	}
+	return s
+}
+
+// Close stops accepting background alerts and waits until the queued ones are published
+// Call it after the HTTP server has shut down; alerts of later creates are dropped
+func (s *MeasurementService) Close() {
+	s.closeMu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.alerts)
 	}
+	s.closeMu.Unlock()
+	s.alertWorkers.Wait()
 }
 
 
 // CreateMeasurement creates a new measurement for a baby
 // Enforces ownership: Only PARENT can add measurements to their own babies
-// ADMIN cannot create measurements (read-only access)
+// ADMIN and NURSE cannot create measurements (read-only access)
 // Publishes alerts for Red status measurements (asynchronously)
 // Response time must be < 2s
 func (s *MeasurementService) CreateMeasurement(
@@ -52,13 +256,13 @@ func (s *MeasurementService) CreateMeasurement(
 	value float64,
 	note string,
 	userID uuid.UUID,
-	isAdmin bool,
+	role domain.Role,
 ) (*domain.Measurement, error) {
 	return s.CreateMeasurementWithDetails(ctx, babyID, CreateMeasurementRequest{
 		Type:  measurementType,
 		Value: value,
 		Note:  note,
-	}, userID, isAdmin)
+	}, userID, role)
 }
 
 // CreateMeasurementWithDetails creates a measurement with full details including feeding-specific fields
@@ -67,278 +271,1276 @@ func (s *MeasurementService) CreateMeasurementWithDetails(
 	babyID uuid.UUID,
 	req CreateMeasurementRequest,
 	userID uuid.UUID,
-	isAdmin bool,
+	role domain.Role,
+) (*domain.Measurement, error) {
+	return s.createMeasurement(ctx, babyID, req, userID, role, func(measurement *domain.Measurement) error {
+		return s.measurementRepo.CreateMeasurement(ctx, measurement)
+	})
+}
+
+// errIdempotentReplay signals that another request already created the measurement for an idempotency key
+var errIdempotentReplay = errors.New("idempotency key already claimed")
+
+// CreateWithIdempotency creates a measurement at most once per user and idempotency key within IdempotencyKeyTTL
+// A repeated key returns the measurement created by the first request (replayed = true) instead of inserting again
+// Keys are scoped per user; reusing a key for another baby or type returns ErrIdempotencyKeyReused
+func (s *MeasurementService) CreateWithIdempotency(
+	ctx context.Context,
+	babyID uuid.UUID,
+	req CreateMeasurementRequest,
+	userID uuid.UUID,
+	role domain.Role,
+	idempotencyKey string,
+) (*domain.Measurement, bool, error) {
+	if !domain.IsValidIdempotencyKey(idempotencyKey) {
+		return nil, false, fmt.Errorf("invalid idempotency key")
+	}
+
+	notBefore := time.Now().Add(-s.config.IdempotencyKeyTTL)
+
+	// Fast path: a retry of a request that already completed
+	existingID, err := s.measurementRepo.GetIdempotentMeasurementID(ctx, userID, idempotencyKey, notBefore)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if existingID != nil {
+		return s.replayMeasurement(ctx, *existingID, babyID, req, userID, role)
+	}
+
+	measurement, err := s.createMeasurement(ctx, babyID, req, userID, role, func(measurement *domain.Measurement) error {
+		// The key is claimed in the same transaction as the insert, so concurrent retries insert once
+		claimedBy, err := s.measurementRepo.CreateMeasurementWithIdempotencyKey(ctx, measurement, idempotencyKey, notBefore)
+		if err != nil {
+			return err
+		}
+		if claimedBy != nil {
+			existingID = claimedBy
+			return errIdempotentReplay
+		}
+		return nil
+	})
+	if errors.Is(err, errIdempotentReplay) {
+		return s.replayMeasurement(ctx, *existingID, babyID, req, userID, role)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return measurement, false, nil
+}
+
+// replayMeasurement returns the measurement an idempotency key points to
+// Read access is re-checked, and the key must have been used for the same baby and type
+func (s *MeasurementService) replayMeasurement(
+	ctx context.Context,
+	measurementID uuid.UUID,
+	babyID uuid.UUID,
+	req CreateMeasurementRequest,
+	userID uuid.UUID,
+	role domain.Role,
+) (*domain.Measurement, bool, error) {
+	measurement, err := s.GetMeasurementByID(ctx, measurementID, userID, role)
+	if err != nil {
+		return nil, false, err
+	}
+	if measurement.BabyID != babyID || measurement.Type != req.Type {
+		return nil, false, domain.ErrIdempotencyKeyReused
+	}
+	return measurement, true, nil
+}
+
+// createMeasurement validates, builds and persists a measurement, then publishes alerts for Red status
+// persist stores the built measurement (plain insert or insert with an idempotency key)
+func (s *MeasurementService) createMeasurement(
+	ctx context.Context,
+	babyID uuid.UUID,
+	req CreateMeasurementRequest,
+	userID uuid.UUID,
+	role domain.Role,
+	persist func(measurement *domain.Measurement) error,
 ) (*domain.Measurement, error) {
-	startTime := time.Now()
+	// Same base time as the handler and the alert publish latency
+	startTime := domain.RequestStart(ctx)
 
 	// Input validation
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Check existence, RBAC and ownership
+	if err := s.checkParentWriteAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	measurement, invalid, err := s.buildValidatedMeasurement(ctx, babyID, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+
+	// Non-blocking concerns are returned as warnings alongside the created measurement
+	if err := s.addCreationWarnings(ctx, measurement); err != nil {
+		return nil, err
+	}
+
+	// Save measurement
+	if err := persist(measurement); err != nil {
+		// A row that slipped past validation but broke a CHECK constraint is still a client error
+		if errors.Is(err, domain.ErrConstraintViolation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create measurement: %w", err)
+	}
+
+	// Strict mode: a Red measurement is only kept once its alert is on the way
+	publishedSync := s.config.FailOnAlertPublishFailure && measurement.SafetyStatus == domain.SafetyStatusRed
+	if publishedSync {
+		if err := s.publishAlertOrRollback(ctx, babyID, measurement); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logMeasurement(measurement, "created")
+	if s.config.Metrics != nil {
+		s.config.Metrics.MeasurementCreated(measurement.Type, measurement.SafetyStatus)
+	}
+	s.recordAudit(ctx, domain.AuditActionMeasurementCreated, userID, measurement, map[string]interface{}{
+		"type":          measurement.Type,
+		"value":         measurement.Value,
+		"safety_status": measurement.SafetyStatus,
+	})
+
+	// Check if measurement requires alert (Red status, or Yellow when enabled) and publish asynchronously
+	// The alert is queued for the worker pool to avoid blocking the response
+	if !publishedSync && (measurement.SafetyStatus == domain.SafetyStatusRed ||
+		(s.config.PublishYellowAlerts && measurement.SafetyStatus == domain.SafetyStatusYellow)) {
+		s.enqueueAlert(alertJob{babyID: babyID, measurement: measurement, startTime: startTime})
+	}
+
+	// Ensure response time < 2s
+	elapsed := time.Since(startTime)
+	if s.config.Metrics != nil {
+		s.config.Metrics.MeasurementCreateDuration(measurement.Type, elapsed)
+	}
+	if elapsed > 2*time.Second {
+		return nil, fmt.Errorf("operation exceeded 2s timeout")
+	}
+
+	return measurement, nil
+}
+
+// enqueueAlert hands an alert to the background workers without blocking
+// A full queue means the publisher can't keep up; the alert is dropped rather than holding a goroutine per reading
+func (s *MeasurementService) enqueueAlert(job alertJob) {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	msg := "alert service closed, alert dropped"
+	if !s.closed {
+		select {
+		case s.alerts <- job:
+			return
+		default:
+			msg = "alert queue full, alert dropped"
+		}
+	}
+
+	s.logger.Error(msg,
+		slog.String("measurement_id", job.measurement.ID.String()),
+		slog.String("baby_id", job.babyID.String()),
+		slog.String("safety_status", string(job.measurement.SafetyStatus)),
+		slog.Int("queue_size", s.config.AlertQueueSize))
+	if s.config.Metrics != nil {
+		s.config.Metrics.AlertDropped(job.measurement.Type)
+	}
+}
+
+// runAlertWorker publishes queued alerts until the queue is closed
+func (s *MeasurementService) runAlertWorker() {
+	defer s.alertWorkers.Done()
+	for job := range s.alerts {
+		s.publishQueuedAlert(job)
+	}
+}
+
+// publishQueuedAlert publishes one background alert; failures are logged but never reach the client
+func (s *MeasurementService) publishQueuedAlert(job alertJob) {
+	// Use background context to avoid cancellation, keeping the request start for publish latency
+	bgCtx := domain.WithRequestStart(context.Background(), job.startTime)
+	measurement := job.measurement
+	if s.alertMuted(bgCtx, measurement) {
+		return
+	}
+	release, ok := s.claimAlert(measurement)
+	if !ok {
+		return
+	}
+	if err := s.alertPublisher.PublishAlert(bgCtx, job.babyID, measurement); err != nil {
+		s.logger.Error("failed to publish alert",
+			slog.String("measurement_id", measurement.ID.String()),
+			slog.String("baby_id", job.babyID.String()),
+			slog.String("safety_status", string(measurement.SafetyStatus)),
+			slog.Any("error", err))
+		release()
+		return
+	}
+	s.logMeasurement(measurement, "alert_published")
+}
+
+// publishAlertOrRollback publishes a measurement's alert within the request
+// If publishing fails the just-inserted measurement is deleted again, so no critical reading exists un-alerted
+func (s *MeasurementService) publishAlertOrRollback(ctx context.Context, babyID uuid.UUID, measurement *domain.Measurement) error {
+	if s.alertMuted(ctx, measurement) {
+		return nil
+	}
+	// A throttled alert was already delivered for an earlier reading, so the measurement is kept
+	release, ok := s.claimAlert(measurement)
+	if !ok {
+		return nil
+	}
+
+	publishErr := s.alertPublisher.PublishAlert(ctx, babyID, measurement)
+	if publishErr == nil {
+		s.logMeasurement(measurement, "alert_published")
+		return nil
+	}
+	release()
+	s.logger.Error("failed to publish alert, rolling back measurement",
+		slog.String("measurement_id", measurement.ID.String()),
+		slog.String("baby_id", babyID.String()),
+		slog.String("safety_status", string(measurement.SafetyStatus)),
+		slog.Any("error", publishErr))
+
+	// The compensating delete must run even if the request was cancelled while publishing
+	if _, err := s.measurementRepo.DeleteMeasurement(context.WithoutCancel(ctx), measurement.ID, measurement.ParentID); err != nil {
+		s.logger.Error("un-alerted measurement could not be rolled back",
+			slog.String("measurement_id", measurement.ID.String()),
+			slog.String("baby_id", babyID.String()),
+			slog.String("safety_status", string(measurement.SafetyStatus)),
+			slog.Any("error", err))
+	}
+
+	return fmt.Errorf("%w: %v", domain.ErrAlertUnavailable, publishErr)
+}
+
+// claimAlert reserves the throttle slot of a measurement's alert
+// Returns false, after logging and counting the suppressed alert, when an alert for the same baby, type
+// and status was published within AlertThrottleWindow. Call release if publishing fails so the next
+// reading can alert instead of being throttled behind an alert that never went out
+func (s *MeasurementService) claimAlert(measurement *domain.Measurement) (release func(), ok bool) {
+	window := s.config.AlertThrottleWindow
+	if window <= 0 {
+		return func() {}, true
+	}
+
+	key := alertThrottleKey{babyID: measurement.BabyID, measurementType: measurement.Type, status: measurement.SafetyStatus}
+	now := time.Now()
+	for {
+		last, loaded := s.lastAlerts.LoadOrStore(key, now)
+		if !loaded {
+			break
+		}
+		if now.Sub(last.(time.Time)) < window {
+			s.logger.Info("alert suppressed by throttle",
+				slog.String("measurement_id", measurement.ID.String()),
+				slog.String("baby_id", measurement.BabyID.String()),
+				slog.String("type", measurement.Type),
+				slog.String("safety_status", string(measurement.SafetyStatus)),
+				slog.Time("last_alert", last.(time.Time)))
+			if s.config.Metrics != nil {
+				s.config.Metrics.AlertSuppressed(measurement.Type)
+			}
+			return nil, false
+		}
+		// The window has passed: take over the slot unless a concurrent alert just did
+		if s.lastAlerts.CompareAndSwap(key, last, now) {
+			break
+		}
+	}
+
+	return func() { s.lastAlerts.CompareAndDelete(key, now) }, true
+}
+
+// alertMuted reports whether the baby's alerts are muted, logging the suppressed alert
+// A failed lookup publishes anyway: a missed alert is worse than an unwanted one
+func (s *MeasurementService) alertMuted(ctx context.Context, measurement *domain.Measurement) bool {
+	if s.config.AlertMutes == nil {
+		return false
+	}
+
+	mute, err := s.config.AlertMutes.GetActiveAlertMute(ctx, measurement.BabyID, time.Now())
+	if err != nil {
+		s.logger.Warn("failed to check alert mute, publishing alert",
+			slog.String("baby_id", measurement.BabyID.String()),
+			slog.Any("error", err))
+		return false
+	}
+	if mute == nil {
+		return false
+	}
+
+	s.logger.Info("alert suppressed by mute",
+		slog.String("measurement_id", measurement.ID.String()),
+		slog.String("baby_id", measurement.BabyID.String()),
+		slog.Time("muted_until", mute.MutedUntil))
+	return true
+}
+
+// ValidateMeasurement runs all create-time validation for a measurement payload without persisting it
+// Enforces the same ownership rules as CreateMeasurementWithDetails
+// Validation failures are reported in the result, access failures are returned as errors
+func (s *MeasurementService) ValidateMeasurement(
+	ctx context.Context,
+	babyID uuid.UUID,
+	req CreateMeasurementRequest,
+	userID uuid.UUID,
+	role domain.Role,
+) (*ports.MeasurementValidationResult, error) {
+	if err := s.checkParentWriteAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	result := &ports.MeasurementValidationResult{Valid: true}
+
+	if err := s.validateRequest(req); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+
+	measurement, invalid, err := s.buildValidatedMeasurement(ctx, babyID, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if invalid != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, invalid.Error())
+		return result, nil
+	}
+
+	result.SafetyStatus = measurement.SafetyStatus
+	return result, nil
+}
+
+// validateRequest checks the measurement type and the type-specific fields of a request
+func (s *MeasurementService) validateRequest(req CreateMeasurementRequest) error {
 	if !domain.IsValidMeasurementType(req.Type) {
-		return nil, fmt.Errorf("invalid measurement type: %s", req.Type)
+		return fmt.Errorf("invalid measurement type: %s", req.Type)
+	}
+	return s.validateMeasurement(req)
+}
+
+// buildValidatedMeasurement runs the create-time checks that need the baby's record, shared by
+// createMeasurement and ValidateMeasurement so a dry-run accepts exactly what a create would
+// Returns the rejection reason as invalid, and lookup failures as err
+func (s *MeasurementService) buildValidatedMeasurement(
+	ctx context.Context,
+	babyID uuid.UUID,
+	req CreateMeasurementRequest,
+	userID uuid.UUID,
+) (measurement *domain.Measurement, invalid error, err error) {
+	// Temperature bands depend on the baby's age, so look it up before the safety calculation
+	ageDays, err := s.babyAgeDaysFor(ctx, babyID, req.Type, req.Timestamp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Build measurement (calculates safety status and sets type-specific fields)
+	measurement, err = s.buildMeasurement(babyID, req, userID, ageDays)
+	if err != nil {
+		return nil, err, nil
+	}
+
+	// Timestamps from devices with a wrong clock would corrupt the ordering of the timeline
+	if err := s.checkTimestampRange(measurement); err != nil {
+		return nil, err, nil
+	}
+
+	// Backdated entries must not predate the baby's record
+	earliest, err := s.earliestAllowedTimestamp(ctx, babyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkNotBefore(measurement, earliest); err != nil {
+		return nil, err, nil
+	}
+
+	// Frequent weighing produces noisy trends: warn about or reject weights logged too close together
+	if err := s.checkWeightInterval(ctx, measurement); err != nil {
+		if errors.Is(err, domain.ErrTooFrequent) {
+			return nil, err, nil
+		}
+		return nil, nil, err
+	}
+
+	return measurement, nil, nil
+}
+
+// checkParentWriteAccess verifies the baby exists and the user is the owning PARENT
+// ADMIN and NURSE cannot write measurements (read-only access)
+func (s *MeasurementService) checkParentWriteAccess(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) error {
+	// Existence and ownership are read together so a concurrent delete can't split them
+	exists, owned, err := s.babyRepo.GetBabyAccess(ctx, babyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check baby access: %w", err)
+	}
+	if !exists {
+		// Don't leak ownership info
+		return fmt.Errorf("baby not found")
+	}
+
+	// RBAC enforcement: Only PARENT can create measurements, and only for their own babies
+	// ADMIN and NURSE cannot create measurements (read-only access)
+	if role != domain.RoleParent {
+		return fmt.Errorf("forbidden: only PARENT can create measurements")
+	}
+
+	// Verify parent owns the baby
+	if !owned {
+		// Don't leak ownership info - return generic not found
+		return fmt.Errorf("baby not found")
+	}
+
+	return nil
+}
+
+// checkWeightInterval looks for another weight of the same baby within WeightMinInterval of the measurement
+// Adds a warning to the measurement, or returns ErrTooFrequent when RejectWeightWithinMinInterval is set
+func (s *MeasurementService) checkWeightInterval(ctx context.Context, measurement *domain.Measurement) error {
+	if measurement.Type != domain.MeasurementTypeWeight || s.config.WeightMinInterval <= 0 {
+		return nil
+	}
+
+	// Timestamps are stored in UTC
+	weightType := domain.MeasurementTypeWeight
+	from := measurement.Timestamp.UTC().Add(-s.config.WeightMinInterval)
+	to := measurement.Timestamp.UTC().Add(s.config.WeightMinInterval)
+	limit := 1
+	nearby, err := s.measurementRepo.GetMeasurementsByBabyID(ctx, measurement.BabyID, ports.MeasurementFilter{
+		Type:    &weightType,
+		From:    &from,
+		To:      &to,
+		Limit:   &limit,
+		Primary: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check previous weight: %w", err)
+	}
+	if len(nearby) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("weight already logged at %s, within the minimum interval of %s",
+		nearby[0].Timestamp.UTC().Format(time.RFC3339), s.config.WeightMinInterval)
+	if s.config.RejectWeightWithinMinInterval {
+		return fmt.Errorf("%w: %s", domain.ErrTooFrequent, message)
+	}
+	measurement.Warnings = append(measurement.Warnings, message)
+	return nil
+}
+
+// addCreationWarnings adds warnings for concerns that don't block creation:
+// a near-duplicate of a recent measurement, a borderline temperature and a backdated timestamp
+func (s *MeasurementService) addCreationWarnings(ctx context.Context, measurement *domain.Measurement) error {
+	if s.config.NearDuplicateWindow > 0 {
+		// Timestamps are stored in UTC
+		from := measurement.Timestamp.UTC().Add(-s.config.NearDuplicateWindow)
+		to := measurement.Timestamp.UTC().Add(s.config.NearDuplicateWindow)
+		// Matching the value in SQL keeps this to one row however many readings fall in the window
+		limit := 1
+		nearby, err := s.measurementRepo.GetMeasurementsByBabyID(ctx, measurement.BabyID, ports.MeasurementFilter{
+			Type:    &measurement.Type,
+			Value:   &measurement.Value,
+			From:    &from,
+			To:      &to,
+			Limit:   &limit,
+			Primary: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check for near-duplicate measurements: %w", err)
+		}
+		if len(nearby) > 0 {
+			measurement.Warnings = append(measurement.Warnings, fmt.Sprintf("possible duplicate: %s of %g already logged at %s",
+				measurement.Type, measurement.Value, nearby[0].Timestamp.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if measurement.Type == domain.MeasurementTypeTemperature && measurement.SafetyStatus == domain.SafetyStatusGreen {
+		switch {
+		case isBorderline(measurement.Value, domain.TemperatureNormalMin):
+			measurement.Warnings = append(measurement.Warnings, fmt.Sprintf("temperature %.1f°C is at the lower edge of the normal range (%.1f-%.1f°C)",
+				measurement.Value, domain.TemperatureNormalMin, domain.TemperatureNormalMax))
+		case isBorderline(measurement.Value, domain.TemperatureNormalMax):
+			measurement.Warnings = append(measurement.Warnings, fmt.Sprintf("temperature %.1f°C is at the upper edge of the normal range (%.1f-%.1f°C)",
+				measurement.Value, domain.TemperatureNormalMin, domain.TemperatureNormalMax))
+		}
+	}
+
+	if age := time.Since(measurement.Timestamp); age > BackdatedWarningAge {
+		measurement.Warnings = append(measurement.Warnings, fmt.Sprintf("timestamp %s is more than %s in the past",
+			measurement.Timestamp.UTC().Format(time.RFC3339), BackdatedWarningAge))
+	}
+
+	return nil
+}
+
+// isBorderline reports whether a temperature is within TemperatureBorderlineMargin of a band edge
+// The small tolerance keeps readings like 36.6 inside the margin despite float rounding
+func isBorderline(value, edge float64) bool {
+	return math.Abs(value-edge) <= TemperatureBorderlineMargin+1e-9
+}
+
+// earliestAllowedTimestamp returns the baby's created_at minus the grace window
+// Returns nil when RejectBeforeBabyCreated is not configured
+func (s *MeasurementService) earliestAllowedTimestamp(ctx context.Context, babyID uuid.UUID) (*time.Time, error) {
+	if !s.config.RejectBeforeBabyCreated {
+		return nil, nil
+	}
+
+	baby, err := s.babyRepo.GetBabyByID(ctx, babyID)
+	if err != nil {
+		if err.Error() == "baby not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get baby: %w", err)
+	}
+
+	earliest := baby.CreatedAt.Add(-s.config.BabyCreatedGrace)
+	return &earliest, nil
+}
+
+// checkTimestampRange rejects timestamps more than MaxFutureSkew ahead of now or before EarliestMeasurementTimestamp
+func (s *MeasurementService) checkTimestampRange(measurement *domain.Measurement) error {
+	if measurement.Timestamp.After(time.Now().Add(s.config.MaxFutureSkew)) {
+		return fmt.Errorf("timestamp cannot be in the future")
+	}
+	if measurement.Timestamp.Before(EarliestMeasurementTimestamp) {
+		return fmt.Errorf("timestamp cannot be before %s", EarliestMeasurementTimestamp.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// checkNotBefore rejects a measurement timestamped before earliest (nil disables the check)
+func checkNotBefore(measurement *domain.Measurement, earliest *time.Time) error {
+	if earliest == nil || !measurement.Timestamp.Before(*earliest) {
+		return nil
+	}
+	return fmt.Errorf("timestamp %s is before the baby's record was created (earliest allowed: %s)",
+		measurement.Timestamp.UTC().Format(time.RFC3339), earliest.UTC().Format(time.RFC3339))
+}
+
+// babyAgeDaysFor returns the baby's age in days at timestamp when it affects the safety bands of measurementType
+// A zero timestamp means now, as in buildMeasurement, so backdated readings get the bands of the baby's age back then
+// Returns nil when the type is age-independent or the date of birth is unknown (default bands apply)
+func (s *MeasurementService) babyAgeDaysFor(ctx context.Context, babyID uuid.UUID, measurementType string, timestamp time.Time) (*int, error) {
+	if measurementType != domain.MeasurementTypeTemperature {
+		return nil, nil
+	}
+
+	baby, err := s.babyRepo.GetBabyByID(ctx, babyID)
+	if err != nil {
+		if err.Error() == "baby not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get baby: %w", err)
+	}
+	if baby.DateOfBirth == nil {
+		return nil, nil
+	}
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	ageDays := domain.AgeInDays(*baby.DateOfBirth, timestamp)
+	return &ageDays, nil
+}
+
+// buildMeasurement creates a measurement from a validated request
+// Calculates safety status (using ageDays when known) and sets type-specific fields
+func (s *MeasurementService) buildMeasurement(babyID uuid.UUID, req CreateMeasurementRequest, userID uuid.UUID, ageDays *int) (*domain.Measurement, error) {
+	// Calculate safety status based on type and value
+	safetyStatus := domain.CalculateSafetyStatus(req.Type, req.Value, ageDays)
+
+	// Set timestamp if not provided (default to now)
+	timestamp := req.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     userID,
+		BabyID:       babyID,
+		Type:         req.Type,
+		Value:        req.Value,
+		SafetyStatus: safetyStatus,
+		Note:         s.config.NoteTemplates.Apply(req.Type, req.Note),
+		DeviceID:     req.DeviceID,
+		Metadata:     requestMetadata(req),
+		Timestamp:    timestamp,
+		CreatedAt:    time.Now(),
+	}
+
+	// Set type-specific fields based on measurement type
+	switch req.Type {
+	case domain.MeasurementTypeFeeding:
+		if err := s.setFeedingFields(measurement, req); err != nil {
+			return nil, err
+		}
+	case domain.MeasurementTypeTemperature:
+		if err := s.setTemperatureFields(measurement, req); err != nil {
+			return nil, err
+		}
+	case domain.MeasurementTypeWeight:
+		if err := s.setWeightFields(measurement, req); err != nil {
+			return nil, err
+		}
+	case domain.MeasurementTypeDiaper:
+		if err := s.setDiaperFields(measurement, req); err != nil {
+			return nil, err
+		}
+	case domain.MeasurementTypeSleep:
+		if err := s.setSleepFields(measurement, req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Recalculate from the resolved value in case a typed field (value_celsius, value_grams) was used
+	measurement.SafetyStatus = domain.CalculateSafetyStatus(req.Type, measurement.Value, ageDays)
+
+	// Clinical protocol: critical readings must be documented
+	if s.config.RequireNoteOnRed && measurement.SafetyStatus == domain.SafetyStatusRed && strings.TrimSpace(req.Note) == "" {
+		return nil, fmt.Errorf("note is required for red status measurements: please add context for this critical reading")
+	}
+
+	return measurement, nil
+}
+
+// validateMeasurement validates measurement-specific requirements
+func (s *MeasurementService) validateMeasurement(req CreateMeasurementRequest) error {
+	// Device ID is optional but must be well-formed when present
+	if req.DeviceID != "" && !domain.IsValidDeviceID(req.DeviceID) {
+		return fmt.Errorf("device_id must be 1-%d characters of letters, digits, '.', '_', ':' or '-'", domain.MaxDeviceIDLength)
+	}
+
+	// Metadata is free-form for the deployment, but bounded and checked against its schema
+	if metadata := requestMetadata(req); metadata != nil {
+		if err := domain.ValidateMetadata(metadata, s.config.MetadataSchema, s.config.MetadataMaxBytes); err != nil {
+			return err
+		}
+	}
+
+	// Spit-up is recorded against the feeding it followed
+	if req.Type != domain.MeasurementTypeFeeding && (req.SpitUp != nil || req.SpitUpSeverity != "") {
+		return fmt.Errorf("spit_up is only valid for feeding measurements")
+	}
+
+	switch req.Type {
+	case domain.MeasurementTypeTemperature:
+		// Temperature validation: only physically impossible values are rejected,
+		// alarming extremes are stored and classified Red
+		return s.validateTemperature(temperatureCelsius(req))
+
+	case domain.MeasurementTypeWeight:
+		// Weight validation: must be positive (in grams)
+		grams := weightGrams(req)
+		if grams <= 0 {
+			return fmt.Errorf("weight must be greater than 0 grams")
+		}
+		// Reasonable upper bound (e.g., 10kg = 10000g)
+		if grams > 10000 {
+			return fmt.Errorf("weight exceeds reasonable maximum (10000g)")
+		}
+		return nil
+
+	case domain.MeasurementTypeFeeding:
+		// Feeding validation is handled in setFeedingFields
+		// Basic check here
+		if req.FeedingType == "" {
+			return fmt.Errorf("feeding type must be specified (bottle or breast)")
+		}
+		return nil
+
+	case domain.MeasurementTypeDiaper:
+		// Diaper validation is handled in setDiaperFields
+		// Basic check here
+		if req.DiaperStatus == "" {
+			return fmt.Errorf("diaper status must be specified (dry, wet, dirty, or both)")
+		}
+		return nil
+
+	case domain.MeasurementTypeSleep:
+		// Sleep validation is handled in setSleepFields
+		// Basic check here
+		if req.SleepStart == nil || req.SleepEnd == nil {
+			return fmt.Errorf("sleep requires sleep_start and sleep_end")
+		}
+		return nil
+
+	case domain.MeasurementTypeHeight:
+		// Height validation: must be positive and physiologically possible (in cm)
+		return validateGrowthCm("height", req.Value, domain.HeightMinCm, domain.HeightMaxCm)
+
+	case domain.MeasurementTypeHeadCircumference:
+		// Head circumference validation: must be positive and physiologically possible (in cm)
+		return validateGrowthCm("head circumference", req.Value, domain.HeadCircumferenceMinCm, domain.HeadCircumferenceMaxCm)
+
+	case domain.MeasurementTypeHeartRate:
+		// Heart rate validation: readings outside what a monitor can measure are sensor errors (in bpm)
+		if req.Value < domain.HeartRateMinBpm || req.Value > domain.HeartRateMaxBpm {
+			return fmt.Errorf("heart rate must be between %.0f and %.0f bpm", domain.HeartRateMinBpm, domain.HeartRateMaxBpm)
+		}
+		return nil
+
+	case domain.MeasurementTypeSpO2:
+		// SpO2 validation: a percentage
+		if req.Value < 0 || req.Value > 100 {
+			return fmt.Errorf("spo2 must be between 0 and 100 percent")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported measurement type: %s", req.Type)
+	}
+}
+
+// validateGrowthCm checks a height or head circumference in centimeters against its possible range
+func validateGrowthCm(name string, cm float64, minCm, maxCm float64) error {
+	if cm <= 0 {
+		return fmt.Errorf("%s must be greater than 0 cm", name)
+	}
+	if cm < minCm || cm > maxCm {
+		return fmt.Errorf("%s must be between %.0f and %.0f cm", name, minCm, maxCm)
+	}
+	return nil
+}
+
+// validateTemperature checks a temperature against the configured storage range
+func (s *MeasurementService) validateTemperature(celsius float64) error {
+	if celsius < s.config.TemperatureMinCelsius || celsius > s.config.TemperatureMaxCelsius {
+		return fmt.Errorf("temperature must be between %.1f and %.1f°C", s.config.TemperatureMinCelsius, s.config.TemperatureMaxCelsius)
+	}
+	return nil
+}
+
+// logMeasurement logs a measurement event at info level with the measurement's fields as attributes
+func (s *MeasurementService) logMeasurement(m *domain.Measurement, event string) {
+	attrs := []slog.Attr{
+		slog.String("event", event),
+		slog.String("measurement_id", m.ID.String()),
+		slog.String("baby_id", m.BabyID.String()),
+		slog.String("type", m.Type),
+		slog.Float64("value", m.Value),
+		slog.String("safety_status", string(m.SafetyStatus)),
+		slog.Time("created_at", m.CreatedAt),
+	}
+
+	if m.Note != "" {
+		attrs = append(attrs, slog.String("note", m.Note))
+	}
+
+	if m.Type == domain.MeasurementTypeFeeding {
+		attrs = append(attrs, slog.String("feeding_type", string(m.FeedingType)))
+		if m.FeedingType == domain.FeedingTypeBottle && m.VolumeML != nil {
+			attrs = append(attrs, slog.Int("volume_ml", *m.VolumeML))
+		}
+		if m.FeedingType == domain.FeedingTypeBreast {
+			if m.Side != nil {
+				attrs = append(attrs, slog.String("side", string(*m.Side)))
+			}
+			if m.Position != nil {
+				attrs = append(attrs, slog.String("position", string(*m.Position)))
+			}
+			if m.Side != nil && *m.Side == domain.SideBoth {
+				if m.LeftDuration != nil {
+					attrs = append(attrs, slog.Int("left_duration_seconds", *m.LeftDuration))
+				}
+				if m.RightDuration != nil {
+					attrs = append(attrs, slog.Int("right_duration_seconds", *m.RightDuration))
+				}
+			} else if m.Duration != nil {
+				attrs = append(attrs, slog.Int("duration_seconds", *m.Duration))
+			}
+		}
+	}
+
+	if m.Type == domain.MeasurementTypeTemperature && m.ValueCelsius != nil {
+		attrs = append(attrs, slog.Float64("value_celsius", *m.ValueCelsius))
+	}
+
+	if m.Type == domain.MeasurementTypeDiaper && m.DiaperStatus != nil {
+		attrs = append(attrs, slog.String("diaper_status", string(*m.DiaperStatus)))
+	}
+
+	if !m.Timestamp.IsZero() {
+		attrs = append(attrs, slog.Time("timestamp", m.Timestamp))
+	}
+
+	s.logger.LogAttrs(context.Background(), slog.LevelInfo, "measurement "+event, attrs...)
+}
+
+// GetMeasurements retrieves all measurements for a baby
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+// Optional filters: type, device ID, limit (max results), before/after cursors
+// Types hidden from the role by the visibility policy are left out
+func (s *MeasurementService) GetMeasurements(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	filter ports.MeasurementFilter,
+) ([]*domain.Measurement, error) {
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	// Validate measurement type filter if provided
+	if filter.Type != nil && !domain.IsValidMeasurementType(*filter.Type) {
+		return nil, fmt.Errorf("invalid measurement type filter: %s", *filter.Type)
+	}
+
+	// Validate device ID filter if provided
+	if filter.DeviceID != nil && !domain.IsValidDeviceID(*filter.DeviceID) {
+		return nil, fmt.Errorf("invalid device_id filter: %s", *filter.DeviceID)
+	}
+
+	// Validate limit if provided
+	if filter.Limit != nil && *filter.Limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	// Validate time window if both bounds are provided
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	// Only one paging direction at a time
+	if filter.Before != nil && filter.After != nil {
+		return nil, fmt.Errorf("before and after cursors cannot be combined")
+	}
+
+	// Visibility policy: hidden types are excluded in the query so limits and cursors stay exact
+	if visible := s.config.Visibility.VisibleTypes(role); visible != nil {
+		if filter.Type != nil && !s.config.Visibility.CanSee(role, *filter.Type) {
+			return []*domain.Measurement{}, nil
+		}
+		filter.Types = visible
+	}
+
+	measurements, err := s.measurementRepo.GetMeasurementsByBabyID(ctx, babyID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+
+	return measurements, nil
+}
+
+// GetFeedingBalance computes the breast vs bottle split for a baby over [from, to)
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+func (s *MeasurementService) GetFeedingBalance(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	from time.Time,
+	to time.Time,
+) (*domain.FeedingBalance, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if !s.config.Visibility.CanSee(role, domain.MeasurementTypeFeeding) {
+		return nil, fmt.Errorf("forbidden: feeding measurements are not visible to this role")
+	}
+
+	totals, err := s.measurementRepo.GetFeedingTotals(ctx, babyID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeding totals: %w", err)
+	}
+
+	return domain.CalculateFeedingBalance(totals, from, to), nil
+}
+
+// CompareFeedingDays totals a baby's feedings on two calendar days and the change between them
+// day1 and day2 are midnight in the comparison timezone; each day is one grouped totals query
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+func (s *MeasurementService) CompareFeedingDays(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	day1 time.Time,
+	day2 time.Time,
+) (*domain.FeedingComparison, error) {
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if !s.config.Visibility.CanSee(role, domain.MeasurementTypeFeeding) {
+		return nil, fmt.Errorf("forbidden: feeding measurements are not visible to this role")
+	}
+
+	totals1, err := s.measurementRepo.GetFeedingTotals(ctx, babyID, day1, day1.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeding totals: %w", err)
+	}
+	totals2, err := s.measurementRepo.GetFeedingTotals(ctx, babyID, day2, day2.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeding totals: %w", err)
+	}
+
+	return domain.CompareFeedingDays(day1, day2, totals1, totals2), nil
+}
+
+// GetHourlyFeedingDistribution counts a baby's feedings per hour of day (in loc) over [from, to)
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+func (s *MeasurementService) GetHourlyFeedingDistribution(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	from time.Time,
+	to time.Time,
+	loc *time.Location,
+) (*domain.HourlyFeedingDistribution, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if !s.config.Visibility.CanSee(role, domain.MeasurementTypeFeeding) {
+		return nil, fmt.Errorf("forbidden: feeding measurements are not visible to this role")
+	}
+
+	counts, err := s.measurementRepo.GetFeedingCountsByHour(ctx, babyID, from, to, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly feeding counts: %w", err)
+	}
+
+	return domain.BuildHourlyFeedingDistribution(counts, from, to, loc), nil
+}
+
+// GetMeasurementStats returns per-type aggregates of a baby's measurements over an optional [from, to) window
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+// A baby without measurements yields an empty slice
+func (s *MeasurementService) GetMeasurementStats(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	from *time.Time,
+	to *time.Time,
+) ([]domain.MeasurementStats, error) {
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.measurementRepo.GetMeasurementStats(ctx, babyID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurement stats: %w", err)
+	}
+
+	// Visibility policy: drop aggregates of types the role may not read
+	visibleStats := []domain.MeasurementStats{}
+	for _, stat := range stats {
+		if s.config.Visibility.CanSee(role, stat.Type) {
+			visibleStats = append(visibleStats, stat)
+		}
+	}
+
+	return visibleStats, nil
+}
+
+// GetMeasurementTrend returns a baby's hourly, daily or weekly averages of a numeric measurement type over [from, to)
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+// Bucket boundaries follow loc, so a daily bucket is a local calendar day
+func (s *MeasurementService) GetMeasurementTrend(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	measurementType string,
+	bucket domain.TrendBucket,
+	from time.Time,
+	to time.Time,
+	loc *time.Location,
+) (*domain.MeasurementTrend, error) {
+	if !domain.IsTrendMeasurementType(measurementType) {
+		return nil, fmt.Errorf("invalid trend type: must be 'temperature', 'weight', 'height', or 'head_circumference'")
+	}
+	if !domain.IsValidTrendBucket(bucket) {
+		return nil, fmt.Errorf("invalid bucket: must be 'hour', 'day', or 'week'")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if domain.TrendBucketCount(bucket, from, to) > domain.MaxTrendBuckets {
+		return nil, fmt.Errorf("time window too large: at most %d %s buckets", domain.MaxTrendBuckets, bucket)
 	}
 
-	// Type-specific validation
-	if err := s.validateMeasurement(req); err != nil {
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
 		return nil, err
 	}
 
-	// Check if baby exists
-	exists, err := s.babyRepo.BabyExists(ctx, babyID)
+	if !s.config.Visibility.CanSee(role, measurementType) {
+		return nil, fmt.Errorf("forbidden: %s measurements are not visible to this role", measurementType)
+	}
+
+	points, err := s.measurementRepo.GetMeasurementTrend(ctx, babyID, measurementType, bucket, from, to, loc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check baby existence: %w", err)
+		return nil, fmt.Errorf("failed to get measurement trend: %w", err)
 	}
-	if !exists {
-		// Don't leak ownership info
-		return nil, fmt.Errorf("baby not found")
+	if points == nil {
+		points = []domain.TrendPoint{}
 	}
 
-	// RBAC enforcement: Only PARENT can create measurements, and only for their own babies
-	// ADMIN cannot create measurements (read-only access)
-	if isAdmin {
-		return nil, fmt.Errorf("forbidden: only PARENT can create measurements")
+	return &domain.MeasurementTrend{
+		Type:     measurementType,
+		Bucket:   bucket,
+		From:     from,
+		To:       to,
+		Timezone: loc.String(),
+		Points:   points,
+	}, nil
+}
+
+// GetLatestMeasurements returns the newest measurement of each type keyed by type, e.g. for dashboards
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+// A baby without measurements yields an empty map; types hidden from the role are left out
+func (s *MeasurementService) GetLatestMeasurements(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+) (map[string]*domain.Measurement, error) {
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
 	}
 
-	// Verify parent owns the baby
-	owned, err := s.babyRepo.CheckBabyOwnership(ctx, babyID, userID)
+	measurements, err := s.measurementRepo.GetLatestMeasurements(ctx, babyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check ownership: %w", err)
+		return nil, fmt.Errorf("failed to get latest measurements: %w", err)
 	}
-	if !owned {
-		// Don't leak ownership info - return generic not found
-		return nil, fmt.Errorf("baby not found")
+
+	latest := make(map[string]*domain.Measurement, len(measurements))
+	for _, m := range measurements {
+		if s.config.Visibility.CanSee(role, m.Type) {
+			latest[m.Type] = m
+		}
 	}
 
-	// Calculate safety status based on type and value
-	safetyStatus := domain.CalculateSafetyStatus(req.Type, req.Value)
+	return latest, nil
+}
 
-	// Set timestamp if not provided (default to now)
-	timestamp := req.Timestamp
-	if timestamp.IsZero() {
-		timestamp = time.Now()
+// GetWardOverview returns the latest measurement of each type and the worst status of the last
+// WardOverviewRecentWindow for every baby whose room starts with roomPrefix, using three queries in total
+// ADMIN and NURSE only; NURSE only sees assigned babies when assignments are enforced
+// Types hidden from the role are left out of both the latest values and the worst status
+func (s *MeasurementService) GetWardOverview(
+	ctx context.Context,
+	roomPrefix string,
+	userID uuid.UUID,
+	role domain.Role,
+) (*domain.WardOverview, error) {
+	// RBAC enforcement: the ward view spans babies of many parents
+	if !role.CanReadAllBabies() {
+		return nil, fmt.Errorf("forbidden: only ADMIN and NURSE can view ward overviews")
 	}
 
-	// Create measurement
-	measurement := &domain.Measurement{
-		ID:           uuid.New(),
-		ParentID:     userID,
-		BabyID:       babyID,
-		Type:         req.Type,
-		Value:        req.Value,
-		SafetyStatus: safetyStatus,
-		Note:         req.Note,
-		Timestamp:    timestamp,
-		CreatedAt:    time.Now(),
+	roomPrefix = strings.TrimSpace(roomPrefix)
+	if roomPrefix == "" || len(roomPrefix) > MaxRoomPrefixLength {
+		return nil, fmt.Errorf("room_prefix must be 1-%d characters", MaxRoomPrefixLength)
 	}
 
-	// Set type-specific fields based on measurement type
-	switch req.Type {
-	case domain.MeasurementTypeFeeding:
-		if err := s.setFeedingFields(measurement, req); err != nil {
-			return nil, err
+	babies, err := s.babyRepo.ListBabiesByRoomPrefix(ctx, roomPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list babies: %w", err)
+	}
+
+	// NURSE only reads assigned babies when assignments are enforced
+	if s.config.NurseAssignments != nil && role == domain.RoleNurse {
+		assigned, err := s.config.NurseAssignments.ListAssignedBabies(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list assigned babies: %w", err)
 		}
-	case domain.MeasurementTypeTemperature:
-		if err := s.setTemperatureFields(measurement, req); err != nil {
-			return nil, err
+		assignedIDs := make(map[uuid.UUID]bool, len(assigned))
+		for _, baby := range assigned {
+			assignedIDs[baby.ID] = true
 		}
-	case domain.MeasurementTypeDiaper:
-		if err := s.setDiaperFields(measurement, req); err != nil {
-			return nil, err
+		visibleBabies := babies[:0]
+		for _, baby := range babies {
+			if assignedIDs[baby.ID] {
+				visibleBabies = append(visibleBabies, baby)
+			}
 		}
+		babies = visibleBabies
 	}
 
-	// Save measurement
-	if err := s.measurementRepo.CreateMeasurement(ctx, measurement); err != nil {
-		return nil, fmt.Errorf("failed to create measurement: %w", err)
+	since := time.Now().Add(-WardOverviewRecentWindow).UTC()
+	overview := &domain.WardOverview{
+		RoomPrefix: roomPrefix,
+		Since:      since,
+		Babies:     make([]*domain.WardBabyOverview, 0, len(babies)),
 	}
-
-	// Log structured JSON for measurement creation
-	s.logMeasurement(measurement, "created")
-
-	// Check if measurement requires alert (Red status) and publish asynchronously
-	// This is done in a goroutine to avoid blocking the response
-	if measurement.SafetyStatus == domain.SafetyStatusRed {
-		go func() {
-			// Use background context to avoid cancellation
-			bgCtx := context.Background()
-			if err := s.alertPublisher.PublishAlert(bgCtx, babyID, measurement); err != nil {
-				// Log error but don't fail the request
-				log.Printf("Failed to publish alert for Red status measurement: %v", err)
-			} else {
-				s.logMeasurement(measurement, "alert_published")
-			}
-		}()
+	if len(babies) == 0 {
+		return overview, nil
 	}
 
-	// Ensure response time < 2s
-	elapsed := time.Since(startTime)
-	if elapsed > 2*time.Second {
-		return nil, fmt.Errorf("operation exceeded 2s timeout")
+	rows := make(map[uuid.UUID]*domain.WardBabyOverview, len(babies))
+	babyIDs := make([]uuid.UUID, 0, len(babies))
+	for _, baby := range babies {
+		row := &domain.WardBabyOverview{
+			BabyID:     baby.ID,
+			LastName:   baby.LastName,
+			RoomNumber: baby.RoomNumber,
+			Latest:     map[string]*domain.Measurement{},
+		}
+		rows[baby.ID] = row
+		babyIDs = append(babyIDs, baby.ID)
+		overview.Babies = append(overview.Babies, row)
 	}
 
-	return measurement, nil
-}
-
-// validateMeasurement validates measurement-specific requirements
-func (s *MeasurementService) validateMeasurement(req CreateMeasurementRequest) error {
-	switch req.Type {
-	case domain.MeasurementTypeTemperature:
-		// Temperature validation: reasonable range for babies (30-42°C)
-		if req.Value < 30.0 || req.Value > 42.0 {
-			return fmt.Errorf("temperature must be between 30.0 and 42.0°C")
+	latest, err := s.measurementRepo.GetLatestMeasurementsForBabies(ctx, babyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest measurements: %w", err)
+	}
+	for _, m := range latest {
+		if row, ok := rows[m.BabyID]; ok && s.config.Visibility.CanSee(role, m.Type) {
+			row.Latest[m.Type] = m
 		}
-		return nil
+	}
 
-	case domain.MeasurementTypeWeight:
-		// Weight validation: must be positive (in grams)
-		if req.Value <= 0 {
-			return fmt.Errorf("weight must be greater than 0 grams")
-		}
-		// Reasonable upper bound (e.g., 10kg = 10000g)
-		if req.Value > 10000 {
-			return fmt.Errorf("weight exceeds reasonable maximum (10000g)")
+	statuses, err := s.measurementRepo.GetSafetyStatusesSince(ctx, babyIDs, since, s.config.Visibility.VisibleTypes(role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent safety statuses: %w", err)
+	}
+	for babyID, babyStatuses := range statuses {
+		row, ok := rows[babyID]
+		if !ok {
+			continue
 		}
-		return nil
-
-	case domain.MeasurementTypeFeeding:
-		// Feeding validation is handled in setFeedingFields
-		// Basic check here
-		if req.FeedingType == "" {
-			return fmt.Errorf("feeding type must be specified (bottle or breast)")
+		for _, status := range babyStatuses {
+			row.WorstRecentStatus = domain.WorseSafetyStatus(row.WorstRecentStatus, status)
 		}
-		return nil
+	}
 
-	case domain.MeasurementTypeDiaper:
-		// Diaper validation is handled in setDiaperFields
-		// Basic check here
-		if req.DiaperStatus == "" {
-			return fmt.Errorf("diaper status must be specified (dry, wet, dirty, or both)")
-		}
-		return nil
+	return overview, nil
+}
 
-	default:
-		return fmt.Errorf("unsupported measurement type: %s", req.Type)
+// GetSafetyStatusDistribution counts a baby's measurements per safety status
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+// Optional filters: type and [from, to) window; types hidden from the role are not counted
+func (s *MeasurementService) GetSafetyStatusDistribution(
+	ctx context.Context,
+	babyID uuid.UUID,
+	userID uuid.UUID,
+	role domain.Role,
+	filter ports.MeasurementFilter,
+) (*domain.SafetyStatusDistribution, error) {
+	if filter.Type != nil && !domain.IsValidMeasurementType(*filter.Type) {
+		return nil, fmt.Errorf("invalid measurement type filter: %s", *filter.Type)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("from must be before to")
 	}
-}
 
-// logMeasurement logs structured JSON for measurement events
-func (s *MeasurementService) logMeasurement(m *domain.Measurement, event string) {
-	logEntry := map[string]interface{}{
-		"event":          event,
-		"measurement_id": m.ID.String(),
-		"baby_id":        m.BabyID.String(),
-		"type":           m.Type,
-		"value":          m.Value,
-		"safety_status":  string(m.SafetyStatus),
-		"created_at":     m.CreatedAt.Format(time.RFC3339),
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
 	}
 
-	if m.Note != "" {
-		logEntry["note"] = m.Note
+	measurementType := ""
+	if filter.Type != nil {
+		measurementType = *filter.Type
 	}
 
-	if m.Type == domain.MeasurementTypeFeeding {
-		logEntry["feeding_type"] = string(m.FeedingType)
-		if m.FeedingType == domain.FeedingTypeBottle && m.VolumeML != nil {
-			logEntry["volume_ml"] = *m.VolumeML
-		}
-		if m.FeedingType == domain.FeedingTypeBreast {
-			if m.Side != nil {
-				logEntry["side"] = string(*m.Side)
-			}
-			if m.Position != nil {
-				logEntry["position"] = string(*m.Position)
-			}
-			if m.Side != nil && *m.Side == domain.SideBoth {
-				if m.LeftDuration != nil {
-					logEntry["left_duration_seconds"] = *m.LeftDuration
-				}
-				if m.RightDuration != nil {
-					logEntry["right_duration_seconds"] = *m.RightDuration
-				}
-			} else if m.Duration != nil {
-				logEntry["duration_seconds"] = *m.Duration
-			}
+	// Visibility policy: hidden types are excluded from the counts
+	if visible := s.config.Visibility.VisibleTypes(role); visible != nil {
+		if filter.Type != nil && !s.config.Visibility.CanSee(role, *filter.Type) {
+			return domain.BuildSafetyStatusDistribution(measurementType, nil), nil
 		}
-	}
-	
-	if m.Type == domain.MeasurementTypeTemperature && m.ValueCelsius != nil {
-		logEntry["value_celsius"] = *m.ValueCelsius
-	}
-	
-	if m.Type == domain.MeasurementTypeDiaper && m.DiaperStatus != nil {
-		logEntry["diaper_status"] = string(*m.DiaperStatus)
-	}
-	
-	if !m.Timestamp.IsZero() {
-		logEntry["timestamp"] = m.Timestamp.Format(time.RFC3339)
+		filter.Types = visible
 	}
 
-	jsonBytes, err := json.Marshal(logEntry)
+	counts, err := s.measurementRepo.GetSafetyStatusCounts(ctx, babyID, filter)
 	if err != nil {
-		log.Printf("Failed to marshal measurement log entry: %v", err)
-		return
+		return nil, fmt.Errorf("failed to get safety status counts: %w", err)
 	}
 
-	log.Printf("%s", string(jsonBytes))
+	return domain.BuildSafetyStatusDistribution(measurementType, counts), nil
 }
 
-// GetMeasurements retrieves all measurements for a baby
-// Enforces ownership: ADMIN can access any, PARENT only their own babies
-// Optional filters: measurementType (filter by type), limit (max results)
-func (s *MeasurementService) GetMeasurements(
+// GetDailyReport builds the printable summary for the calendar day starting at day
+// day must be midnight in the report timezone; the window ends at the next midnight (DST-aware)
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies
+func (s *MeasurementService) GetDailyReport(
 	ctx context.Context,
 	babyID uuid.UUID,
 	userID uuid.UUID,
-	isAdmin bool,
-	measurementType *string,
-	limit *int,
-) ([]*domain.Measurement, error) {
-	// Check if baby exists
-	exists, err := s.babyRepo.BabyExists(ctx, babyID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check baby existence: %w", err)
-	}
-	if !exists {
-		// Don't leak ownership info
-		return nil, fmt.Errorf("baby not found")
+	role domain.Role,
+	day time.Time,
+) (*domain.DailyReport, error) {
+	if err := s.checkReadAccess(ctx, babyID, userID, role); err != nil {
+		return nil, err
 	}
 
-	// RBAC enforcement: PARENT can only access their own babies
-	if !isAdmin {
-		owned, err := s.babyRepo.CheckBabyOwnership(ctx, babyID, userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check ownership: %w", err)
-		}
-		if !owned {
-			// Don't leak ownership info - return generic not found
-			return nil, fmt.Errorf("baby not found")
-		}
+	from := day
+	to := day.AddDate(0, 0, 1)
+	filter := ports.MeasurementFilter{From: &from, To: &to, Types: s.config.Visibility.VisibleTypes(role)}
+	measurements, err := s.measurementRepo.GetMeasurementsByBabyID(ctx, babyID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
 	}
 
-	// Validate measurement type filter if provided
-	if measurementType != nil && !domain.IsValidMeasurementType(*measurementType) {
-		return nil, fmt.Errorf("invalid measurement type filter: %s", *measurementType)
+	return domain.BuildDailyReport(babyID.String(), day.Format("2006-01-02"), day.Location(), from, to, measurements), nil
+}
+
+// checkReadAccess verifies the baby exists and the user may read its data
+// ADMIN and NURSE can read any baby (NURSE only assigned ones when enforced), PARENT only their own
+func (s *MeasurementService) checkReadAccess(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) error {
+	// Existence and ownership are read together so a concurrent delete can't split them
+	exists, owned, err := s.babyRepo.GetBabyAccess(ctx, babyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check baby access: %w", err)
 	}
 
-	// Validate limit if provided
-	if limit != nil && *limit <= 0 {
-		return nil, fmt.Errorf("limit must be greater than 0")
+	// RBAC enforcement: PARENT can only access their own babies
+	// Don't leak ownership info - missing and not owned both return generic not found
+	if !exists || (!role.CanReadAllBabies() && !owned) {
+		return fmt.Errorf("baby not found")
 	}
 
-	measurements, err := s.measurementRepo.GetMeasurementsByBabyID(ctx, babyID, measurementType, limit)
+	// NURSE only reads assigned babies when assignments are enforced
+	assigned, err := nurseAssignedTo(ctx, s.config.NurseAssignments, babyID, userID, role)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get measurements: %w", err)
+		return err
+	}
+	if !assigned {
+		return fmt.Errorf("baby not found")
 	}
 
-	return measurements, nil
+	return nil
 }
 
 // GetMeasurementByID retrieves a specific measurement by ID
-// Enforces ownership: ADMIN can access any, PARENT only their own babies' measurements
+// Enforces ownership: ADMIN and NURSE can access any, PARENT only their own babies' measurements
 func (s *MeasurementService) GetMeasurementByID(
 	ctx context.Context,
 	measurementID uuid.UUID,
 	userID uuid.UUID,
-	isAdmin bool,
+	role domain.Role,
 ) (*domain.Measurement, error) {
 	// Get measurement
 	measurement, err := s.measurementRepo.GetMeasurementByID(ctx, measurementID)
@@ -364,42 +1566,158 @@ func (s *MeasurementService) GetMeasurementByID(
 		return nil, fmt.Errorf("measurement not found")
 	}
 
-	// Check if baby exists and enforce ownership
-	exists, err := s.babyRepo.BabyExists(ctx, measurement.BabyID)
+	// Check if baby exists and enforce ownership in a single read
+	exists, owned, err := s.babyRepo.GetBabyAccess(ctx, measurement.BabyID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check baby existence: %w", err)
+		return nil, fmt.Errorf("failed to check baby access: %w", err)
 	}
+
 	if !exists {
+		s.checkOrphanedMeasurement(ctx, measurement)
 		return nil, fmt.Errorf("measurement not found")
 	}
 
 	// RBAC enforcement: PARENT can only access their own babies' measurements
-	if !isAdmin {
-		owned, err := s.babyRepo.CheckBabyOwnership(ctx, measurement.BabyID, userID)
+	// Don't leak ownership info - return generic not found
+	if !role.CanReadAllBabies() && !owned {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	assigned, err := nurseAssignedTo(ctx, s.config.NurseAssignments, measurement.BabyID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	// Types hidden by the visibility policy look the same as missing ones
+	if !s.config.Visibility.CanSee(role, measurement.Type) {
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	return measurement, nil
+}
+
+// checkOrphanedMeasurement reports a measurement whose baby row is gone entirely
+// Measurements of soft-deleted babies are kept on purpose, but cascade delete should never leave
+// one without a baby row, so that case is logged as a data-integrity warning and counted
+func (s *MeasurementService) checkOrphanedMeasurement(ctx context.Context, measurement *domain.Measurement) {
+	recorded, err := s.babyRepo.BabyRecordExists(ctx, measurement.BabyID)
+	if err != nil {
+		s.logger.Error("failed to check baby record for measurement",
+			slog.String("measurement_id", measurement.ID.String()),
+			slog.String("baby_id", measurement.BabyID.String()),
+			slog.Any("error", err))
+		return
+	}
+	if recorded {
+		return
+	}
+
+	s.logger.Warn("data integrity: measurement has no baby row",
+		slog.String("measurement_id", measurement.ID.String()),
+		slog.String("baby_id", measurement.BabyID.String()))
+	if s.config.Metrics != nil {
+		s.config.Metrics.OrphanedMeasurement()
+	}
+}
+
+// UpdateMeasurement edits the note and/or timestamp of a measurement
+// Enforces ownership: Only the parent who created the measurement can update it
+// Type and value are immutable, so the stored safety status stays valid
+func (s *MeasurementService) UpdateMeasurement(
+	ctx context.Context,
+	measurementID uuid.UUID,
+	req ports.UpdateMeasurementRequest,
+	userID uuid.UUID,
+	role domain.Role,
+) (*domain.Measurement, error) {
+	// RBAC enforcement: ADMIN and NURSE cannot update measurements
+	if role != domain.RoleParent {
+		return nil, fmt.Errorf("forbidden: only PARENT can update measurements")
+	}
+
+	if req.Note == nil && req.Timestamp == nil {
+		return nil, fmt.Errorf("nothing to update: provide note and/or timestamp")
+	}
+	if req.Timestamp != nil && req.Timestamp.IsZero() {
+		return nil, fmt.Errorf("timestamp must be a valid time")
+	}
+
+	measurement, err := s.measurementRepo.GetMeasurementByID(ctx, measurementID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(strings.ToLower(err.Error()), "measurement not found") {
+			return nil, fmt.Errorf("measurement not found")
+		}
+		return nil, fmt.Errorf("failed to get measurement: %w", err)
+	}
+
+	// RBAC enforcement: Only the parent who created the measurement can update
+	if measurement.ParentID != userID {
+		// Don't leak ownership info - return generic not found
+		return nil, fmt.Errorf("measurement not found")
+	}
+
+	if req.Note != nil {
+		measurement.Note = *req.Note
+	}
+	if req.Timestamp != nil {
+		measurement.Timestamp = *req.Timestamp
+
+		// A moved timestamp must pass the same range checks as a new measurement
+		if err := s.checkTimestampRange(measurement); err != nil {
+			return nil, err
+		}
+		earliest, err := s.earliestAllowedTimestamp(ctx, measurement.BabyID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check ownership: %w", err)
+			return nil, err
 		}
-		if !owned {
-			// Don't leak ownership info - return generic not found
+		if err := checkNotBefore(measurement, earliest); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the critical-reading note requirement from being bypassed by clearing the note
+	if s.config.RequireNoteOnRed && measurement.SafetyStatus == domain.SafetyStatusRed && strings.TrimSpace(measurement.Note) == "" {
+		return nil, fmt.Errorf("note is required for red status measurements: please add context for this critical reading")
+	}
+
+	// Update measurement - the repository scopes the UPDATE to userID
+	if err := s.measurementRepo.UpdateMeasurement(ctx, measurement); err != nil {
+		if strings.Contains(err.Error(), "measurement not found") {
 			return nil, fmt.Errorf("measurement not found")
 		}
+		return nil, fmt.Errorf("failed to update measurement: %w", err)
+	}
+
+	// Only the names of the changed fields are recorded; the note itself stays in the measurement
+	changed := []string{}
+	if req.Note != nil {
+		changed = append(changed, "note")
 	}
+	if req.Timestamp != nil {
+		changed = append(changed, "timestamp")
+	}
+	s.recordAudit(ctx, domain.AuditActionMeasurementUpdated, userID, measurement, map[string]interface{}{
+		"fields": changed,
+	})
 
 	return measurement, nil
 }
 
-// DeleteMeasurement deletes a measurement by ID
+// DeleteMeasurement deletes a measurement by ID and returns the deleted measurement
 // Enforces ownership: Only the parent who created the measurement can delete it
-// ADMIN cannot delete measurements (read-only access)
+// ADMIN and NURSE cannot delete measurements (read-only access)
 func (s *MeasurementService) DeleteMeasurement(
 	ctx context.Context,
 	measurementID uuid.UUID,
 	userID uuid.UUID,
-	isAdmin bool,
-) error {
-	// RBAC enforcement: ADMIN cannot delete measurements
-	if isAdmin {
-		return fmt.Errorf("forbidden: only PARENT can delete measurements")
+	role domain.Role,
+) (*domain.Measurement, error) {
+	// RBAC enforcement: ADMIN and NURSE cannot delete measurements
+	if role != domain.RoleParent {
+		return nil, fmt.Errorf("forbidden: only PARENT can delete measurements")
 	}
 
 	// Get measurement first to validate ownership
@@ -407,7 +1725,7 @@ func (s *MeasurementService) DeleteMeasurement(
 	if err != nil {
 		// Check if the underlying error is sql.ErrNoRows or "measurement not found"
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("measurement not found")
+			return nil, fmt.Errorf("measurement not found")
 		}
 		errStr := strings.ToLower(err.Error())
 		// Check for "measurement not found" or "no rows" in error message (case-insensitive)
@@ -415,22 +1733,107 @@ func (s *MeasurementService) DeleteMeasurement(
 		if strings.Contains(errStr, "measurement not found") || 
 			strings.Contains(errStr, "no rows") ||
 			strings.Contains(errStr, "sql: no rows") {
-			return fmt.Errorf("measurement not found")
+			return nil, fmt.Errorf("measurement not found")
 		}
-		return fmt.Errorf("failed to get measurement: %w", err)
+		return nil, fmt.Errorf("failed to get measurement: %w", err)
 	}
 
 	// RBAC enforcement: Only the parent who created the measurement can delete
 	if measurement.ParentID != userID {
 		// Don't leak ownership info - return generic not found
-		return fmt.Errorf("measurement not found")
+		return nil, fmt.Errorf("measurement not found")
 	}
 
 	// Delete measurement - pass userID to validate ownership
-	err = s.measurementRepo.DeleteMeasurement(ctx, measurementID, userID)
+	// The repository returns the row as it was deleted; a concurrent delete that won the race leaves nothing to return
+	deleted, err := s.measurementRepo.DeleteMeasurement(ctx, measurementID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to delete measurement: %w", err)
+		if err.Error() == "measurement not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to delete measurement: %w", err)
 	}
 
-	return nil
+	s.recordAudit(ctx, domain.AuditActionMeasurementDeleted, userID, measurement, map[string]interface{}{
+		"type":          measurement.Type,
+		"value":         measurement.Value,
+		"safety_status": measurement.SafetyStatus,
+	})
+
+	return deleted, nil
+}
+
+// recordAudit appends an audit entry for a mutation that has already succeeded
+// The write outlives a cancelled request; failures are logged and counted instead of undoing the mutation
+func (s *MeasurementService) recordAudit(ctx context.Context, action domain.AuditAction, userID uuid.UUID, m *domain.Measurement, details map[string]interface{}) {
+	if s.config.Audit == nil {
+		return
+	}
+
+	if err := s.config.Audit.Record(context.WithoutCancel(ctx), action, userID, m.BabyID, m.ID, details); err != nil {
+		s.logger.Error("failed to record audit entry",
+			slog.String("action", string(action)),
+			slog.String("measurement_id", m.ID.String()),
+			slog.String("baby_id", m.BabyID.String()),
+			slog.String("user_id", userID.String()),
+			slog.Any("error", err))
+		if s.config.Metrics != nil {
+			s.config.Metrics.AuditRecordFailed(action)
+		}
+	}
+}
+
+// BackfillSafetyStatus recomputes the safety status of rows whose stored status is NULL or not canonical
+// Data-repair tool for imported rows (ADMIN only); works in batches of batchSize (0 for DefaultBackfillBatchSize) until none are left
+// The baby's age at the measurement timestamp selects the temperature bands, like on creation
+func (s *MeasurementService) BackfillSafetyStatus(ctx context.Context, role domain.Role, batchSize int) (*domain.SafetyStatusBackfillResult, error) {
+	if role != domain.RoleAdmin {
+		return nil, fmt.Errorf("forbidden: only ADMIN can backfill safety status")
+	}
+	if batchSize == 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+	if batchSize < 1 || batchSize > MaxBackfillBatchSize {
+		return nil, fmt.Errorf("batch_size must be between 1 and %d", MaxBackfillBatchSize)
+	}
+
+	result := &domain.SafetyStatusBackfillResult{}
+	for {
+		rows, err := s.measurementRepo.ListInvalidSafetyStatusMeasurements(ctx, batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list measurements with invalid safety status: %w", err)
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+		result.Batches++
+
+		for _, row := range rows {
+			var ageDays *int
+			if row.DateOfBirth != nil {
+				days := domain.AgeInDays(*row.DateOfBirth, row.Timestamp)
+				ageDays = &days
+			}
+
+			status := domain.CalculateSafetyStatus(row.Type, row.Value, ageDays)
+			if err := s.measurementRepo.UpdateMeasurementSafetyStatus(ctx, row.ID, status); err != nil {
+				return nil, fmt.Errorf("failed to update safety status of measurement %s: %w", row.ID, err)
+			}
+
+			result.Updated++
+			switch status {
+			case domain.SafetyStatusGreen:
+				result.Green++
+			case domain.SafetyStatusYellow:
+				result.Yellow++
+			case domain.SafetyStatusRed:
+				result.Red++
+			}
+		}
+
+		// A short batch means every remaining row was repaired
+		if len(rows) < batchSize {
+			return result, nil
+		}
+	}
 }
@@ -0,0 +1,98 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAssignmentService is a mock implementation of AssignmentService
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) CreateAssignment(ctx context.Context, nurseUserID uuid.UUID, babyID *uuid.UUID, roomPrefix string, role domain.Role) (*domain.NurseAssignment, error) {
+	args := m.Called(ctx, nurseUserID, babyID, roomPrefix, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NurseAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) ListAssignments(ctx context.Context, nurseUserID uuid.UUID, role domain.Role) ([]*domain.NurseAssignment, error) {
+	args := m.Called(ctx, nurseUserID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NurseAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID, role domain.Role) error {
+	args := m.Called(ctx, assignmentID, nurseUserID, role)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) ListAssignedBabies(ctx context.Context, userID uuid.UUID, role domain.Role) ([]*domain.Baby, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Baby), args.Error(1)
+}
+
+func TestAssignmentHandler_ListMyAssignedBabies(t *testing.T) {
+	mockService := new(MockAssignmentService)
+	assignmentHandler := handler.NewAssignmentHandler(mockService)
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+	mockService.On("ListAssignedBabies", mock.Anything, nurseID, domain.RoleNurse).
+		Return([]*domain.Baby{{ID: babyID, LastName: "Smith", RoomNumber: "3B-01"}}, nil)
+
+	req := httptest.NewRequest("GET", "/me/assignments", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, nurseID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	assignmentHandler.ListMyAssignedBabies(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var babies []domain.Baby
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &babies))
+	require.Len(t, babies, 1)
+	assert.Equal(t, babyID, babies[0].ID)
+}
+
+func TestAssignmentHandler_CreateAssignment_ValidationError(t *testing.T) {
+	mockService := new(MockAssignmentService)
+	assignmentHandler := handler.NewAssignmentHandler(mockService)
+
+	nurseID := uuid.New()
+	mockService.On("CreateAssignment", mock.Anything, nurseID, (*uuid.UUID)(nil), "", domain.RoleAdmin).
+		Return(nil, errors.New("exactly one of baby_id or room_prefix is required"))
+
+	req := httptest.NewRequest("POST", "/nurses/"+nurseID.String()+"/assignments", bytes.NewBufferString(`{}`))
+	req.SetPathValue("nurse_id", nurseID.String())
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	assignmentHandler.CreateAssignment(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "exactly one of baby_id or room_prefix is required")
+}
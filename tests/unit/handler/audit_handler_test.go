@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditService is a mock implementation of AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *ports.AuditCursor, limit int, userID uuid.UUID, role domain.Role) ([]*domain.AuditEntry, *ports.AuditCursor, error) {
+	args := m.Called(ctx, babyID, before, limit, userID, role)
+	var entries []*domain.AuditEntry
+	if args.Get(0) != nil {
+		entries = args.Get(0).([]*domain.AuditEntry)
+	}
+	var next *ports.AuditCursor
+	if args.Get(1) != nil {
+		next = args.Get(1).(*ports.AuditCursor)
+	}
+	return entries, next, args.Error(2)
+}
+
+func newAuditRequest(babyID uuid.UUID, query string, userID uuid.UUID, role string) *http.Request {
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/audit"+query, nil)
+	req.SetPathValue("baby_id", babyID.String())
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, role)
+	return req.WithContext(ctx)
+}
+
+func TestAuditHandler_ListAuditEntries_CursorRoundTrip(t *testing.T) {
+	mockService := new(MockAuditService)
+	auditHandler := handler.NewAuditHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	entry := &domain.AuditEntry{
+		ID:            uuid.New(),
+		Action:        domain.AuditActionMeasurementCreated,
+		UserID:        uuid.New(),
+		BabyID:        babyID,
+		MeasurementID: uuid.New(),
+		CreatedAt:     time.Date(2026, 3, 1, 8, 30, 0, 123, time.UTC),
+	}
+	next := &ports.AuditCursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+
+	mockService.On("ListAuditEntries", mock.Anything, babyID, (*ports.AuditCursor)(nil), 1, userID, domain.RoleNurse).
+		Return([]*domain.AuditEntry{entry}, next, nil)
+	mockService.On("ListAuditEntries", mock.Anything, babyID, next, 50, userID, domain.RoleNurse).
+		Return([]*domain.AuditEntry{}, nil, nil)
+
+	// First page
+	w := httptest.NewRecorder()
+	auditHandler.ListAuditEntries(w, newAuditRequest(babyID, "?limit=1", userID, "NURSE"))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Entries    []domain.AuditEntry `json:"entries"`
+		NextCursor string              `json:"next_cursor"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, entry.UserID, page.Entries[0].UserID)
+	require.NotEmpty(t, page.NextCursor)
+
+	// The cursor leads to the next page
+	w = httptest.NewRecorder()
+	auditHandler.ListAuditEntries(w, newAuditRequest(babyID, "?cursor="+page.NextCursor, userID, "NURSE"))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"entries":[]}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_ListAuditEntries_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "parent", serviceErr: errors.New("forbidden: only ADMIN and NURSE can read the audit log"), wantStatus: http.StatusForbidden},
+		{name: "missing baby", serviceErr: errors.New("baby not found"), wantStatus: http.StatusNotFound},
+		{name: "limit out of range", query: "?limit=500", serviceErr: errors.New("limit must be between 1 and 200"), wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: "?limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "invalid cursor", query: "?cursor=not-a-cursor", wantStatus: http.StatusBadRequest},
+		{name: "repository failure", serviceErr: errors.New("failed to list audit entries: connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			auditHandler := handler.NewAuditHandler(mockService)
+			if tt.serviceErr != nil {
+				mockService.On("ListAuditEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, nil, tt.serviceErr)
+			}
+
+			w := httptest.NewRecorder()
+			auditHandler.ListAuditEntries(w, newAuditRequest(uuid.New(), tt.query, uuid.New(), "ADMIN"))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.serviceErr == nil {
+				mockService.AssertNotCalled(t, "ListAuditEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
 	"github.com/IANDYI/care-service/internal/adapters/middleware"
 	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,30 +27,67 @@ type MockBabyService struct {
 	mock.Mock
 }
 
-func (m *MockBabyService) CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, createdByUserID uuid.UUID, isAdmin bool) (*domain.Baby, error) {
-	args := m.Called(ctx, lastName, roomNumber, parentUserID, createdByUserID, isAdmin)
+func (m *MockBabyService) CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, lastName, roomNumber, parentUserID, dateOfBirth, createdByUserID, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Baby), args.Error(1)
 }
 
-func (m *MockBabyService) GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, isAdmin bool) (*domain.Baby, error) {
-	args := m.Called(ctx, babyID, userID, isAdmin)
+func (m *MockBabyService) CreateBabies(ctx context.Context, requests []ports.CreateBabyRequest, createdByUserID uuid.UUID, role domain.Role, atomic bool) (*domain.BabyBatchResult, error) {
+	args := m.Called(ctx, requests, createdByUserID, role, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BabyBatchResult), args.Error(1)
+}
+
+func (m *MockBabyService) EnsureBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, bool, error) {
+	args := m.Called(ctx, lastName, roomNumber, parentUserID, dateOfBirth, createdByUserID, role)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*domain.Baby), args.Bool(1), args.Error(2)
+}
+
+func (m *MockBabyService) GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, babyID, userID, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Baby), args.Error(1)
 }
 
-func (m *MockBabyService) ListBabies(ctx context.Context, userID uuid.UUID, isAdmin bool) ([]*domain.Baby, error) {
-	args := m.Called(ctx, userID, isAdmin)
+func (m *MockBabyService) ListBabies(ctx context.Context, userID uuid.UUID, role domain.Role, includeDeleted bool) ([]*domain.Baby, error) {
+	args := m.Called(ctx, userID, role, includeDeleted)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Baby), args.Error(1)
 }
 
+func (m *MockBabyService) GetParentSummary(ctx context.Context, userID uuid.UUID, role domain.Role) (*domain.ParentSummary, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ParentSummary), args.Error(1)
+}
+
+func (m *MockBabyService) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth string, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, babyID, lastName, roomNumber, dateOfBirth, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyService) DeleteBaby(ctx context.Context, babyID uuid.UUID, role domain.Role) error {
+	args := m.Called(ctx, babyID, role)
+	return args.Error(0)
+}
+
 func TestNewBabyHandler(t *testing.T) {
 	mockService := new(MockBabyService)
 	babyHandler := handler.NewBabyHandler(mockService)
@@ -69,7 +110,7 @@ func TestBabyHandler_CreateBaby_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockService.On("CreateBaby", mock.Anything, "Doe", "101", parentUserID, userID, true).Return(expectedBaby, nil)
+	mockService.On("CreateBaby", mock.Anything, "Doe", "101", parentUserID, "", userID, domain.RoleAdmin).Return(expectedBaby, nil)
 
 	reqBody := handler.CreateBabyRequest{
 		LastName:     "Doe",
@@ -121,7 +162,7 @@ func TestBabyHandler_CreateBaby_Forbidden(t *testing.T) {
 	userID := uuid.New()
 	parentUserID := uuid.New()
 
-	mockService.On("CreateBaby", mock.Anything, "Doe", "101", parentUserID, userID, false).
+	mockService.On("CreateBaby", mock.Anything, "Doe", "101", parentUserID, "", userID, domain.RoleParent).
 		Return(nil, assert.AnError)
 
 	reqBody := handler.CreateBabyRequest{
@@ -159,7 +200,7 @@ func TestBabyHandler_GetBaby_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockService.On("GetBaby", mock.Anything, babyID, userID, true).Return(expectedBaby, nil)
+	mockService.On("GetBaby", mock.Anything, babyID, userID, domain.RoleAdmin).Return(expectedBaby, nil)
 
 	// Use a router to properly set path values
 	mux := http.NewServeMux()
@@ -189,7 +230,7 @@ func TestBabyHandler_GetBaby_NotFound(t *testing.T) {
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockService.On("GetBaby", mock.Anything, babyID, userID, true).
+	mockService.On("GetBaby", mock.Anything, babyID, userID, domain.RoleAdmin).
 		Return(nil, assert.AnError)
 
 	// Use a router to properly set path values
@@ -208,6 +249,161 @@ func TestBabyHandler_GetBaby_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBabyHandler_GetBaby_LogsRequest(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+	var logs bytes.Buffer
+	babyHandler.SetLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetBaby", mock.Anything, babyID, userID, domain.RoleAdmin).Return(&domain.Baby{ID: babyID}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}", babyHandler.GetBaby)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String(), nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), "expected exactly one JSON log line, got %q", logs.String())
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "request completed", entry["msg"])
+	assert.NotEmpty(t, entry["request_id"])
+	assert.Equal(t, userID.String(), entry["user_id"])
+	assert.Equal(t, "ADMIN", entry["role"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/babies/"+babyID.String(), entry["endpoint"])
+	assert.Equal(t, float64(http.StatusOK), entry["status_code"])
+	assert.Contains(t, entry, "duration_ms")
+}
+
+func TestBabyHandler_UpdateBaby_Success(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("UpdateBaby", mock.Anything, babyID, "", "204", "", domain.RoleAdmin).
+		Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "204"}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /babies/{baby_id}", babyHandler.UpdateBaby)
+
+	body, _ := json.Marshal(handler.UpdateBabyRequest{RoomNumber: "204"})
+	req := httptest.NewRequest("PUT", "/babies/"+babyID.String(), bytes.NewBuffer(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var baby domain.Baby
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &baby))
+	assert.Equal(t, "204", baby.RoomNumber)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_DateOfBirth(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	userID := uuid.New()
+	parentUserID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("CreateBaby", mock.Anything, "Doe", "101", parentUserID, "2024-03-01", userID, domain.RoleAdmin).
+		Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "101", ParentUserID: parentUserID}, nil)
+	mockService.On("UpdateBaby", mock.Anything, babyID, "", "", "2024-03-02", domain.RoleAdmin).
+		Return(nil, fmt.Errorf(`invalid date_of_birth "2024-03-02": cannot be in the future`))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies", babyHandler.CreateBaby)
+	mux.HandleFunc("PUT /babies/{baby_id}", babyHandler.UpdateBaby)
+
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{method: "POST", path: "/babies", body: `{"last_name":"Doe","room_number":"101","parent_user_id":"` + parentUserID.String() + `","date_of_birth":"2024-03-01"}`, wantStatus: http.StatusCreated},
+		{method: "PUT", path: "/babies/" + babyID.String(), body: `{"date_of_birth":"2024-03-02"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.method)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_UpdateBaby_NotFound(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	babyID := uuid.New()
+	mockService.On("UpdateBaby", mock.Anything, babyID, "Smith", "", "", domain.RoleAdmin).
+		Return(nil, fmt.Errorf("baby not found"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /babies/{baby_id}", babyHandler.UpdateBaby)
+
+	body, _ := json.Marshal(handler.UpdateBabyRequest{LastName: "Smith"})
+	req := httptest.NewRequest("PUT", "/babies/"+babyID.String(), bytes.NewBuffer(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_UpdateBaby_Forbidden(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	babyID := uuid.New()
+	mockService.On("UpdateBaby", mock.Anything, babyID, "Smith", "", "", domain.RoleParent).
+		Return(nil, fmt.Errorf("forbidden: only ADMIN can update babies"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /babies/{baby_id}", babyHandler.UpdateBaby)
+
+	body, _ := json.Marshal(handler.UpdateBabyRequest{LastName: "Smith"})
+	req := httptest.NewRequest("PUT", "/babies/"+babyID.String(), bytes.NewBuffer(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestBabyHandler_ListBabies_Success(t *testing.T) {
 	mockService := new(MockBabyService)
 	babyHandler := handler.NewBabyHandler(mockService)
@@ -225,7 +421,7 @@ func TestBabyHandler_ListBabies_Success(t *testing.T) {
 		},
 	}
 
-	mockService.On("ListBabies", mock.Anything, userID, true).Return(expectedBabies, nil)
+	mockService.On("ListBabies", mock.Anything, userID, domain.RoleAdmin, false).Return(expectedBabies, nil)
 
 	req := httptest.NewRequest("GET", "/babies", nil)
 	
@@ -244,3 +440,288 @@ func TestBabyHandler_ListBabies_Success(t *testing.T) {
 	assert.Len(t, babies, 1)
 	mockService.AssertExpectations(t)
 }
+
+func TestBabyHandler_DeleteBaby_Success(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	babyID := uuid.New()
+	mockService.On("DeleteBaby", mock.Anything, babyID, domain.RoleAdmin).Return(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /babies/{baby_id}", babyHandler.DeleteBaby)
+
+	req := httptest.NewRequest("DELETE", "/babies/"+babyID.String(), nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_DeleteBaby_NotFound(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	babyID := uuid.New()
+	mockService.On("DeleteBaby", mock.Anything, babyID, domain.RoleAdmin).Return(fmt.Errorf("baby not found"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /babies/{baby_id}", babyHandler.DeleteBaby)
+
+	req := httptest.NewRequest("DELETE", "/babies/"+babyID.String(), nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_ListBabies_IncludeDeleted(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	userID := uuid.New()
+	deletedAt := time.Now()
+	mockService.On("ListBabies", mock.Anything, userID, domain.RoleAdmin, true).
+		Return([]*domain.Baby{{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", DeletedAt: &deletedAt}}, nil)
+
+	req := httptest.NewRequest("GET", "/babies?include_deleted=true", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.ListBabies(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"deleted_at"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_ListBabies_IncludeDeletedForbidden(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	userID := uuid.New()
+	mockService.On("ListBabies", mock.Anything, userID, domain.RoleParent, true).
+		Return(nil, fmt.Errorf("forbidden: only ADMIN can list deleted babies"))
+
+	req := httptest.NewRequest("GET", "/babies?include_deleted=true", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.ListBabies(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_ListBabies_InvalidIncludeDeleted(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	req := httptest.NewRequest("GET", "/babies?include_deleted=maybe", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.ListBabies(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListBabies")
+}
+
+func TestBabyHandler_GetParentSummary_Success(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	parentID := uuid.New()
+	mockService.On("GetParentSummary", mock.Anything, parentID, domain.RoleParent).
+		Return(&domain.ParentSummary{ParentUserID: parentID, BabyCount: 2, RedMeasurementsRecent: 1, Since: time.Now().Add(-24 * time.Hour)}, nil)
+
+	req := httptest.NewRequest("GET", "/parents/me/summary", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, parentID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.GetParentSummary(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var summary domain.ParentSummary
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&summary))
+	assert.Equal(t, 2, summary.BabyCount)
+	assert.Equal(t, 1, summary.RedMeasurementsRecent)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_GetParentSummary_AdminRejected(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	adminID := uuid.New()
+	mockService.On("GetParentSummary", mock.Anything, adminID, domain.RoleAdmin).
+		Return(nil, fmt.Errorf("parent summary is only available to PARENT accounts"))
+
+	req := httptest.NewRequest("GET", "/parents/me/summary", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, adminID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.GetParentSummary(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "only available to PARENT accounts")
+}
+
+func TestBabyHandler_UpdateBaby_InvalidRoomNumber(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	babyID := uuid.New()
+	mockService.On("UpdateBaby", mock.Anything, babyID, "", "B/12", "", domain.RoleAdmin).
+		Return(nil, fmt.Errorf(`invalid room_number "B/12": must match ^[A-Z0-9]+(-[A-Z0-9]+)?$`))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /babies/{baby_id}", babyHandler.UpdateBaby)
+
+	body, _ := json.Marshal(handler.UpdateBabyRequest{RoomNumber: "B/12"})
+	req := httptest.NewRequest("PUT", "/babies/"+babyID.String(), bytes.NewBuffer(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid room_number")
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyHandler_CreateBabies(t *testing.T) {
+	userID := uuid.New()
+	parentUserID := uuid.New()
+	reqBody := []handler.CreateBabyRequest{
+		{LastName: "Doe", RoomNumber: "101", ParentUserID: parentUserID},
+		{LastName: "", RoomNumber: "102", ParentUserID: parentUserID},
+	}
+	expectedRequests := []ports.CreateBabyRequest{
+		{LastName: "Doe", RoomNumber: "101", ParentUserID: parentUserID},
+		{LastName: "", RoomNumber: "102", ParentUserID: parentUserID},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		atomic     bool
+		result     *domain.BabyBatchResult
+		wantStatus int
+	}{
+		{
+			name:   "atomic by default",
+			atomic: true,
+			result: &domain.BabyBatchResult{Atomic: true, Failed: 1, Results: []domain.BabyBatchItemResult{
+				{Index: 0}, {Index: 1, Error: "baby last_name cannot be empty"},
+			}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "partial",
+			query:  "?atomic=false",
+			atomic: false,
+			result: &domain.BabyBatchResult{Created: 1, Failed: 1, Results: []domain.BabyBatchItemResult{
+				{Index: 0, Baby: &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101"}},
+				{Index: 1, Error: "baby last_name cannot be empty"},
+			}},
+			wantStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBabyService)
+			babyHandler := handler.NewBabyHandler(mockService)
+
+			mockService.On("CreateBabies", mock.Anything, expectedRequests, userID, domain.RoleAdmin, tt.atomic).Return(tt.result, nil)
+
+			body, _ := json.Marshal(reqBody)
+			req := httptest.NewRequest("POST", "/babies/batch"+tt.query, bytes.NewBuffer(body))
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			babyHandler.CreateBabies(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var got domain.BabyBatchResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.result.Created, got.Created)
+			assert.Equal(t, "baby last_name cannot be empty", got.Results[1].Error)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBabyHandler_CreateBabies_InvalidBody(t *testing.T) {
+	mockService := new(MockBabyService)
+	babyHandler := handler.NewBabyHandler(mockService)
+
+	req := httptest.NewRequest("POST", "/babies/batch", bytes.NewBufferString(`{"last_name":"Doe"}`))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	babyHandler.CreateBabies(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateBabies", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBabyHandler_CreateBaby_EmptyOrMalformedBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantMessage string
+	}{
+		{name: "empty body", body: "", wantMessage: "request body is required"},
+		{name: "whitespace only", body: " \n", wantMessage: "request body is required"},
+		{name: "malformed JSON", body: `{"last_name":`, wantMessage: "invalid request body"},
+		{name: "wrong type", body: `{"last_name":42}`, wantMessage: "invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBabyService)
+			babyHandler := handler.NewBabyHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/babies", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			babyHandler.CreateBaby(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.wantMessage, strings.TrimSpace(w.Body.String()))
+			mockService.AssertNotCalled(t, "CreateBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
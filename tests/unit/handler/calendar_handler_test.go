@@ -0,0 +1,193 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarHandler_GetMeasurementsFeed_ICSStructure(t *testing.T) {
+	mockMeasurementService := new(MockMeasurementService)
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	calendarHandler := handler.NewCalendarHandler(mockMeasurementService, new(MockBabyService), signer)
+
+	babyID := uuid.New()
+	userID := uuid.New()
+	token, _ := signer.Issue(babyID, userID, time.Now())
+
+	volume := 120
+	diaper := domain.DiaperStatusWet
+	sleepStart := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+	sleepEnd := time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC)
+	feedingID := uuid.New()
+	measurements := []*domain.Measurement{
+		{
+			ID: feedingID, BabyID: babyID, Type: domain.MeasurementTypeFeeding, FeedingType: domain.FeedingTypeBottle,
+			VolumeML: &volume, Value: 120, SafetyStatus: domain.SafetyStatusGreen, Note: "spit up a little, then slept",
+			Timestamp: time.Date(2026, 3, 1, 8, 15, 0, 0, time.UTC), CreatedAt: time.Date(2026, 3, 1, 8, 16, 0, 0, time.UTC),
+		},
+		{
+			ID: uuid.New(), BabyID: babyID, Type: domain.MeasurementTypeDiaper, DiaperStatus: &diaper,
+			SafetyStatus: domain.SafetyStatusGreen, Timestamp: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			ID: uuid.New(), BabyID: babyID, Type: domain.MeasurementTypeSleep, Value: 12600, SafetyStatus: domain.SafetyStatusGreen,
+			SleepStart: &sleepStart, SleepEnd: &sleepEnd, Timestamp: sleepEnd,
+		},
+	}
+	mockMeasurementService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+		return f.From != nil && f.Type == nil
+	})).Return(measurements, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/babies/"+babyID.String()+"/measurements.ics?token="+token, nil)
+	req.SetPathValue("baby_id", babyID.String())
+	w := httptest.NewRecorder()
+
+	calendarHandler.GetMeasurementsFeed(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	assert.Equal(t, 3, strings.Count(body, "END:VEVENT\r\n"))
+
+	assert.Contains(t, body, "UID:"+feedingID.String()+"@care-service\r\n")
+	assert.Contains(t, body, "DTSTART:20260301T081500Z\r\n")
+	assert.Contains(t, body, "DTSTAMP:20260301T081600Z\r\n")
+	assert.Contains(t, body, "SUMMARY:Feeding (bottle\\, 120 ml)\r\n")
+	assert.Contains(t, body, "DESCRIPTION:Safety status: green\\nspit up a little\\, then slept\r\n")
+	assert.Contains(t, body, "SUMMARY:Diaper (wet)\r\n")
+	assert.Contains(t, body, "SUMMARY:Sleep\r\nDESCRIPTION")
+	assert.Contains(t, body, "DTSTART:20260301T220000Z\r\nDTEND:20260302T013000Z\r\n")
+
+	// Content lines are folded at 75 octets
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	mockMeasurementService.AssertExpectations(t)
+}
+
+func TestCalendarHandler_GetMeasurementsFeed_InvalidToken(t *testing.T) {
+	mockMeasurementService := new(MockMeasurementService)
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	calendarHandler := handler.NewCalendarHandler(mockMeasurementService, new(MockBabyService), signer)
+
+	babyID := uuid.New()
+	forged, _ := middleware.NewFeedTokenSigner("other-secret", time.Hour).Issue(babyID, uuid.New(), time.Now())
+
+	for _, token := range []string{"", "garbage", forged} {
+		req := httptest.NewRequest(http.MethodGet, "/babies/"+babyID.String()+"/measurements.ics?token="+token, nil)
+		req.SetPathValue("baby_id", babyID.String())
+		w := httptest.NewRecorder()
+
+		calendarHandler.GetMeasurementsFeed(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	mockMeasurementService.AssertNotCalled(t, "GetMeasurements")
+}
+
+func TestCalendarHandler_GetMeasurementsFeed_TokenForOtherBaby(t *testing.T) {
+	mockMeasurementService := new(MockMeasurementService)
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	calendarHandler := handler.NewCalendarHandler(mockMeasurementService, new(MockBabyService), signer)
+
+	babyID := uuid.New()
+	token, _ := signer.Issue(uuid.New(), uuid.New(), time.Now())
+
+	req := httptest.NewRequest(http.MethodGet, "/babies/"+babyID.String()+"/measurements.ics?token="+token, nil)
+	req.SetPathValue("baby_id", babyID.String())
+	w := httptest.NewRecorder()
+
+	calendarHandler.GetMeasurementsFeed(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockMeasurementService.AssertNotCalled(t, "GetMeasurements")
+}
+
+func TestCalendarHandler_GetMeasurementsFeed_OwnershipRevoked(t *testing.T) {
+	mockMeasurementService := new(MockMeasurementService)
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	calendarHandler := handler.NewCalendarHandler(mockMeasurementService, new(MockBabyService), signer)
+
+	babyID := uuid.New()
+	userID := uuid.New()
+	token, _ := signer.Issue(babyID, userID, time.Now())
+	mockMeasurementService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.Anything).
+		Return(nil, errors.New("baby not found")).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/babies/"+babyID.String()+"/measurements.ics?token="+token, nil)
+	req.SetPathValue("baby_id", babyID.String())
+	w := httptest.NewRecorder()
+
+	calendarHandler.GetMeasurementsFeed(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockMeasurementService.AssertExpectations(t)
+}
+
+func TestCalendarHandler_IssueFeedToken_Success(t *testing.T) {
+	mockBabyService := new(MockBabyService)
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	calendarHandler := handler.NewCalendarHandler(new(MockMeasurementService), mockBabyService, signer)
+
+	babyID := uuid.New()
+	userID := uuid.New()
+	mockBabyService.On("GetBaby", mock.Anything, babyID, userID, domain.RoleParent).
+		Return(&domain.Baby{ID: babyID, ParentUserID: userID}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/babies/"+babyID.String()+"/calendar-token", nil)
+	req.SetPathValue("baby_id", babyID.String())
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	calendarHandler.IssueFeedToken(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var response handler.CalendarTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "/babies/"+babyID.String()+"/measurements.ics?token="+response.Token, response.FeedURL)
+
+	gotBabyID, gotUserID, err := signer.Verify(response.Token, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, babyID, gotBabyID)
+	assert.Equal(t, userID, gotUserID)
+	mockBabyService.AssertExpectations(t)
+}
+
+func TestCalendarHandler_IssueFeedToken_ForbiddenForNurse(t *testing.T) {
+	mockBabyService := new(MockBabyService)
+	calendarHandler := handler.NewCalendarHandler(new(MockMeasurementService), mockBabyService, middleware.NewFeedTokenSigner("test-secret", time.Hour))
+
+	babyID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/babies/"+babyID.String()+"/calendar-token", nil)
+	req.SetPathValue("baby_id", babyID.String())
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	calendarHandler.IssueFeedToken(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockBabyService.AssertNotCalled(t, "GetBaby")
+}
@@ -0,0 +1,52 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getEnums(t *testing.T, enumsHandler *handler.EnumsHandler) handler.EnumsResponse {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/measurements/enums", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	enumsHandler.GetEnums(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handler.EnumsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	return response
+}
+
+func TestEnumsHandler_GetEnums_IncludesNoteTemplates(t *testing.T) {
+	enumsHandler := handler.NewEnumsHandler(domain.NoteTemplates{"temperature": "Measured at: axillary/rectal"})
+
+	response := getEnums(t, enumsHandler)
+
+	assert.Equal(t, domain.ValidMeasurementTypes(), response.MeasurementTypes)
+	assert.Equal(t, domain.ValidSafetyStatuses(), response.SafetyStatuses)
+	assert.Equal(t, domain.ValidDiaperStatuses(), response.DiaperStatuses)
+	assert.Equal(t, map[string]string{"temperature": "Measured at: axillary/rectal"}, response.NoteTemplates)
+}
+
+func TestEnumsHandler_GetEnums_NoTemplates(t *testing.T) {
+	response := getEnums(t, handler.NewEnumsHandler(nil))
+
+	// Clients can rely on an object, never null
+	assert.NotNil(t, response.NoteTemplates)
+	assert.Empty(t, response.NoteTemplates)
+}
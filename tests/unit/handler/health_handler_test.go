@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/core/ports"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -97,6 +99,80 @@ func TestHealthHandler_Ready(t *testing.T) {
 	assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusServiceUnavailable)
 }
 
+// fakeBroker reports a fixed connection state
+type fakeBroker struct {
+	connected bool
+}
+
+func (f fakeBroker) IsConnected() bool {
+	return f.connected
+}
+
+func TestHealthHandler_Ready_AllComponentsUp(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+
+	healthHandler := handler.NewHealthHandlerWithBrokers(db, map[string]ports.ConnectionChecker{
+		"rabbitmq_publisher": fakeBroker{connected: true},
+	})
+
+	w := httptest.NewRecorder()
+	healthHandler.Ready(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response handler.HealthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "ready", response.Status)
+	assert.Equal(t, map[string]string{"database": "up", "rabbitmq_publisher": "up"}, response.Components)
+}
+
+func TestHealthHandler_Ready_RabbitMQDisconnected(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+
+	healthHandler := handler.NewHealthHandlerWithBrokers(db, map[string]ports.ConnectionChecker{
+		"rabbitmq_publisher": fakeBroker{connected: false},
+		"baby_consumer":      fakeBroker{connected: true},
+	})
+
+	w := httptest.NewRecorder()
+	healthHandler.Ready(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var response handler.HealthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "not ready", response.Status)
+	assert.Equal(t, map[string]string{
+		"database":           "up",
+		"rabbitmq_publisher": "down",
+		"baby_consumer":      "up",
+	}, response.Components)
+}
+
+func TestHealthHandler_Ready_DatabaseDown(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing().WillReturnError(sql.ErrConnDone)
+
+	healthHandler := handler.NewHealthHandlerWithBrokers(db, map[string]ports.ConnectionChecker{
+		"rabbitmq_publisher": fakeBroker{connected: true},
+	})
+
+	w := httptest.NewRecorder()
+	healthHandler.Ready(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response handler.HealthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, map[string]string{"database": "down", "rabbitmq_publisher": "up"}, response.Components)
+}
+
 func TestMetrics(t *testing.T) {
 	req := httptest.NewRequest("GET", "/metrics", nil)
 	w := httptest.NewRecorder()
@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func exportRequest(userID uuid.UUID, role string, path string) (*http.ServeMux, *http.Request) {
+	req := httptest.NewRequest("GET", path, nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, role)
+	return http.NewServeMux(), req.WithContext(ctx)
+}
+
+func TestMeasurementHandler_ExportMeasurements_CSV(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	volume := 120
+	spitUp := true
+	severity := domain.SpitUpSeverityModerate
+	timestamp := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{
+			{
+				ID:             uuid.New(),
+				BabyID:         babyID,
+				Type:           domain.MeasurementTypeFeeding,
+				Value:          120,
+				SafetyStatus:   domain.SafetyStatusGreen,
+				Note:           "after bath, sleepy",
+				FeedingType:    domain.FeedingTypeBottle,
+				VolumeML:       &volume,
+				SpitUp:         &spitUp,
+				SpitUpSeverity: &severity,
+				Timestamp:      timestamp,
+			},
+		}, nil)
+
+	mux, req := exportRequest(userID, "PARENT", "/babies/"+babyID.String()+"/measurements/export?format=csv")
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/export", measurementHandler.ExportMeasurements)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="measurements-`+babyID.String()+`.csv"`, w.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{
+		"timestamp", "type", "value", "safety_status", "note",
+		"feeding_type", "volume_ml", "position", "side", "left_duration", "right_duration", "duration",
+		"diaper_status", "sleep_start", "sleep_end", "spit_up", "spit_up_severity",
+	}, records[0])
+	assert.Equal(t, []string{
+		"2024-03-01T08:30:00Z", "feeding", "120", "green", "after bath, sleepy",
+		"bottle", "120", "", "", "", "", "",
+		"", "", "", "true", "moderate",
+	}, records[1])
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_ExportMeasurements_DateRange(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{From: &from, To: &to}).
+		Return([]*domain.Measurement{}, nil)
+
+	mux, req := exportRequest(userID, "PARENT", "/babies/"+babyID.String()+"/measurements/export?from=2024-03-01&to=2024-03-07")
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/export", measurementHandler.ExportMeasurements)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 1, "only the header row")
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_ExportMeasurements_StatementTimeout(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandlerWithConfig(mockService, handler.MeasurementHandlerConfig{ExportStatementTimeout: 5 * time.Minute})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	// The unbounded export query carries its own timeout instead of DB_STATEMENT_TIMEOUT
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleNurse, ports.MeasurementFilter{StatementTimeout: 5 * time.Minute}).
+		Return([]*domain.Measurement{}, nil)
+
+	mux, req := exportRequest(userID, "NURSE", "/babies/"+babyID.String()+"/measurements/export")
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/export", measurementHandler.ExportMeasurements)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_ExportMeasurements_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "unsupported format", query: "?format=xlsx", wantStatus: http.StatusBadRequest},
+		{name: "baby not owned", serviceErr: fmt.Errorf("baby not found"), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeasurementService)
+			measurementHandler := handler.NewMeasurementHandler(mockService)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			if tt.serviceErr != nil {
+				mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.Anything).
+					Return(nil, tt.serviceErr)
+			}
+
+			mux, req := exportRequest(userID, "PARENT", "/babies/"+babyID.String()+"/measurements/export"+tt.query)
+			mux.HandleFunc("GET /babies/{baby_id}/measurements/export", measurementHandler.ExportMeasurements)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.NotEqual(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		})
+	}
+}
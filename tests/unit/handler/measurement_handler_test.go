@@ -3,9 +3,13 @@ package handler_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,41 +28,148 @@ type MockMeasurementService struct {
 	mock.Mock
 }
 
-func (m *MockMeasurementService) CreateMeasurement(ctx context.Context, babyID uuid.UUID, measurementType string, value float64, note string, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error) {
-	args := m.Called(ctx, babyID, measurementType, value, note, userID, isAdmin)
+func (m *MockMeasurementService) CreateMeasurement(ctx context.Context, babyID uuid.UUID, measurementType string, value float64, note string, userID uuid.UUID, role domain.Role) (*domain.Measurement, error) {
+	args := m.Called(ctx, babyID, measurementType, value, note, userID, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Measurement), args.Error(1)
 }
 
-func (m *MockMeasurementService) CreateMeasurementWithDetails(ctx context.Context, babyID uuid.UUID, req ports.CreateMeasurementRequest, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error) {
-	args := m.Called(ctx, babyID, req, userID, isAdmin)
+func (m *MockMeasurementService) CreateMeasurementWithDetails(ctx context.Context, babyID uuid.UUID, req ports.CreateMeasurementRequest, userID uuid.UUID, role domain.Role) (*domain.Measurement, error) {
+	args := m.Called(ctx, babyID, req, userID, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Measurement), args.Error(1)
 }
 
-func (m *MockMeasurementService) GetMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, isAdmin bool, measurementType *string, limit *int) ([]*domain.Measurement, error) {
-	args := m.Called(ctx, babyID, userID, isAdmin, measurementType, limit)
+func (m *MockMeasurementService) CreateWithIdempotency(ctx context.Context, babyID uuid.UUID, req ports.CreateMeasurementRequest, userID uuid.UUID, role domain.Role, idempotencyKey string) (*domain.Measurement, bool, error) {
+	args := m.Called(ctx, babyID, req, userID, role, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*domain.Measurement), args.Bool(1), args.Error(2)
+}
+
+func (m *MockMeasurementService) ValidateMeasurement(ctx context.Context, babyID uuid.UUID, req ports.CreateMeasurementRequest, userID uuid.UUID, role domain.Role) (*ports.MeasurementValidationResult, error) {
+	args := m.Called(ctx, babyID, req, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ports.MeasurementValidationResult), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, filter ports.MeasurementFilter) ([]*domain.Measurement, error) {
+	args := m.Called(ctx, babyID, userID, role, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Measurement), args.Error(1)
 }
 
-func (m *MockMeasurementService) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, isAdmin bool) (*domain.Measurement, error) {
-	args := m.Called(ctx, measurementID, userID, isAdmin)
+func (m *MockMeasurementService) GetFeedingBalance(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to time.Time) (*domain.FeedingBalance, error) {
+	args := m.Called(ctx, babyID, userID, role, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedingBalance), args.Error(1)
+}
+
+func (m *MockMeasurementService) CompareFeedingDays(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, day1, day2 time.Time) (*domain.FeedingComparison, error) {
+	args := m.Called(ctx, babyID, userID, role, day1, day2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedingComparison), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetHourlyFeedingDistribution(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to time.Time, loc *time.Location) (*domain.HourlyFeedingDistribution, error) {
+	args := m.Called(ctx, babyID, userID, role, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HourlyFeedingDistribution), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetMeasurementStats(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, from, to *time.Time) ([]domain.MeasurementStats, error) {
+	args := m.Called(ctx, babyID, userID, role, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MeasurementStats), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetMeasurementTrend(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, measurementType string, bucket domain.TrendBucket, from, to time.Time, loc *time.Location) (*domain.MeasurementTrend, error) {
+	args := m.Called(ctx, babyID, userID, role, measurementType, bucket, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MeasurementTrend), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetLatestMeasurements(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (map[string]*domain.Measurement, error) {
+	args := m.Called(ctx, babyID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*domain.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetWardOverview(ctx context.Context, roomPrefix string, userID uuid.UUID, role domain.Role) (*domain.WardOverview, error) {
+	args := m.Called(ctx, roomPrefix, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WardOverview), args.Error(1)
+}
+
+func (m *MockMeasurementService) BackfillSafetyStatus(ctx context.Context, role domain.Role, batchSize int) (*domain.SafetyStatusBackfillResult, error) {
+	args := m.Called(ctx, role, batchSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SafetyStatusBackfillResult), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetSafetyStatusDistribution(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, filter ports.MeasurementFilter) (*domain.SafetyStatusDistribution, error) {
+	args := m.Called(ctx, babyID, userID, role, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SafetyStatusDistribution), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetDailyReport(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role, day time.Time) (*domain.DailyReport, error) {
+	args := m.Called(ctx, babyID, userID, role, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DailyReport), args.Error(1)
+}
+
+func (m *MockMeasurementService) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Measurement, error) {
+	args := m.Called(ctx, measurementID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementService) UpdateMeasurement(ctx context.Context, measurementID uuid.UUID, req ports.UpdateMeasurementRequest, userID uuid.UUID, role domain.Role) (*domain.Measurement, error) {
+	args := m.Called(ctx, measurementID, req, userID, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Measurement), args.Error(1)
 }
 
-func (m *MockMeasurementService) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, isAdmin bool) error {
-	args := m.Called(ctx, measurementID, userID, isAdmin)
-	return args.Error(0)
+func (m *MockMeasurementService) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Measurement, error) {
+	args := m.Called(ctx, measurementID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Measurement), args.Error(1)
 }
 
 func TestNewMeasurementHandler(t *testing.T) {
@@ -93,7 +204,7 @@ func TestMeasurementHandler_CreateMeasurement_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, false).
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
 		Return(expectedMeasurement, nil)
 
 	// Use a router to properly set path values
@@ -126,7 +237,7 @@ func TestMeasurementHandler_CreateMeasurement_Forbidden(t *testing.T) {
 		Value: 37.0,
 	}
 
-	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, true).
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleAdmin).
 		Return(nil, assert.AnError)
 
 	// Use a router to properly set path values
@@ -147,6 +258,159 @@ func TestMeasurementHandler_CreateMeasurement_Forbidden(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestMeasurementHandler_CreateMeasurement_Conflict(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	reqBody := handler.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 37.0,
+	}
+
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("failed to create measurement: %w", domain.ErrConflict))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_CreateMeasurement_TooFrequent(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	reqBody := handler.CreateMeasurementRequest{
+		Type:  "weight",
+		Value: 3420,
+	}
+
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("%w: weight already logged within the minimum interval", domain.ErrTooFrequent))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "minimum interval")
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_CreateMeasurement_IdempotentReplay(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	original := &domain.Measurement{ID: uuid.New(), ParentID: userID, BabyID: babyID, Type: "weight", Value: 3420}
+
+	mockService.On("CreateWithIdempotency", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent, "retry-key-1").
+		Return(original, true, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	body, _ := json.Marshal(handler.CreateMeasurementRequest{Type: "weight", Value: 3420})
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "retry-key-1")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	var got domain.Measurement
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, original.ID, got.ID)
+	mockService.AssertNotCalled(t, "CreateMeasurementWithDetails", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_CreateMeasurement_InvalidIdempotencyKey(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	for _, key := range []string{"", "not a token", strings.Repeat("k", domain.MaxIdempotencyKeyLength+1)} {
+		body, _ := json.Marshal(handler.CreateMeasurementRequest{Type: "weight", Value: 3420})
+		req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, "key %q", key)
+	}
+	mockService.AssertNotCalled(t, "CreateWithIdempotency", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_CreateMeasurement_IdempotencyKeyReused(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("CreateWithIdempotency", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent, "retry-key-1").
+		Return(nil, false, domain.ErrIdempotencyKeyReused)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	body, _ := json.Marshal(handler.CreateMeasurementRequest{Type: "weight", Value: 3420})
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Idempotency-Key", "retry-key-1")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestMeasurementHandler_GetMeasurements_Success(t *testing.T) {
 	mockService := new(MockMeasurementService)
 	measurementHandler := handler.NewMeasurementHandler(mockService)
@@ -167,7 +431,7 @@ func TestMeasurementHandler_GetMeasurements_Success(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetMeasurements", mock.Anything, babyID, userID, true, (*string)(nil), (*int)(nil)).
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleAdmin, ports.MeasurementFilter{}).
 		Return(expectedMeasurements, nil)
 
 	// Use a router to properly set path values
@@ -191,6 +455,52 @@ func TestMeasurementHandler_GetMeasurements_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestMeasurementHandler_GetMeasurements_FilterByDeviceID(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	deviceID := "thermo-01"
+
+	expectedMeasurements := []*domain.Measurement{
+		{
+			ID:           uuid.New(),
+			ParentID:     userID,
+			BabyID:       babyID,
+			Type:         "temperature",
+			Value:        37.0,
+			SafetyStatus: domain.SafetyStatusGreen,
+			DeviceID:     deviceID,
+			Timestamp:    time.Now(),
+			CreatedAt:    time.Now(),
+		},
+	}
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{DeviceID: &deviceID}).
+		Return(expectedMeasurements, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements?device_id="+deviceID, nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var measurements []*domain.Measurement
+	err := json.NewDecoder(w.Body).Decode(&measurements)
+	require.NoError(t, err)
+	require.Len(t, measurements, 1)
+	assert.Equal(t, deviceID, measurements[0].DeviceID)
+	mockService.AssertExpectations(t)
+}
+
 func TestMeasurementHandler_GetMeasurementByID_Success(t *testing.T) {
 	mockService := new(MockMeasurementService)
 	measurementHandler := handler.NewMeasurementHandler(mockService)
@@ -209,7 +519,7 @@ func TestMeasurementHandler_GetMeasurementByID_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockService.On("GetMeasurementByID", mock.Anything, measurementID, userID, true).
+	mockService.On("GetMeasurementByID", mock.Anything, measurementID, userID, domain.RoleAdmin).
 		Return(expectedMeasurement, nil)
 
 	// Use a router to properly set path values
@@ -240,8 +550,8 @@ func TestMeasurementHandler_DeleteMeasurement_Success(t *testing.T) {
 	userID := uuid.New()
 	measurementID := uuid.New()
 
-	mockService.On("DeleteMeasurement", mock.Anything, measurementID, userID, false).
-		Return(nil)
+	mockService.On("DeleteMeasurement", mock.Anything, measurementID, userID, domain.RoleParent).
+		Return(&domain.Measurement{ID: measurementID, ParentID: userID}, nil)
 
 	// Use a router to properly set path values
 	mux := http.NewServeMux()
@@ -256,5 +566,1552 @@ func TestMeasurementHandler_DeleteMeasurement_Success(t *testing.T) {
 	mux.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_DeleteMeasurement_ReturnRepresentation(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("DeleteMeasurement", mock.Anything, measurementID, userID, domain.RoleParent).
+		Return(&domain.Measurement{ID: measurementID, ParentID: userID, BabyID: babyID, Type: "temperature", Value: 37.2, SafetyStatus: domain.SafetyStatusGreen}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /measurements/{measurement_id}", measurementHandler.DeleteMeasurement)
+
+	req := httptest.NewRequest("DELETE", "/measurements/"+measurementID.String()+"?return=representation", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var deleted domain.Measurement
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&deleted))
+	assert.Equal(t, measurementID, deleted.ID)
+	assert.Equal(t, babyID, deleted.BabyID)
+	assert.Equal(t, "temperature", deleted.Type)
+	assert.Equal(t, 37.2, deleted.Value)
 	mockService.AssertExpectations(t)
 }
+
+func TestMeasurementHandler_DeleteMeasurement_InvalidReturn(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /measurements/{measurement_id}", measurementHandler.DeleteMeasurement)
+
+	req := httptest.NewRequest("DELETE", "/measurements/"+uuid.New().String()+"?return=full", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "DeleteMeasurement", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_UpdateMeasurement_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	note := "after bath"
+
+	mockService.On("UpdateMeasurement", mock.Anything, measurementID, ports.UpdateMeasurementRequest{Note: &note}, userID, domain.RoleParent).
+		Return(&domain.Measurement{ID: measurementID, ParentID: userID, Type: "temperature", Value: 37.0, Note: note}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", measurementHandler.UpdateMeasurement)
+
+	req := httptest.NewRequest("PATCH", "/measurements/"+measurementID.String(), bytes.NewBufferString(`{"note":"after bath"}`))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var measurement domain.Measurement
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&measurement))
+	assert.Equal(t, note, measurement.Note)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_UpdateMeasurement_RejectsImmutableFields(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", measurementHandler.UpdateMeasurement)
+
+	req := httptest.NewRequest("PATCH", "/measurements/"+uuid.New().String(), bytes.NewBufferString(`{"value":39.5}`))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_EmptyOrMalformedBody(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", measurementHandler.UpdateMeasurement)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		wantMessage string
+	}{
+		{name: "create empty", method: "POST", path: "/babies/" + uuid.New().String() + "/measurements", body: "", wantMessage: "request body is required"},
+		{name: "create malformed", method: "POST", path: "/babies/" + uuid.New().String() + "/measurements", body: `{"type":"weight",`, wantMessage: "invalid request body"},
+		{name: "update empty", method: "PATCH", path: "/measurements/" + uuid.New().String(), body: "", wantMessage: "request body is required"},
+		{name: "update malformed", method: "PATCH", path: "/measurements/" + uuid.New().String(), body: `{"note":`, wantMessage: "invalid request body: only note and timestamp can be updated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.wantMessage, strings.TrimSpace(w.Body.String()))
+		})
+	}
+	mockService.AssertNotCalled(t, "CreateMeasurementWithDetails", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_UpdateMeasurement_NotFound(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	mockService.On("UpdateMeasurement", mock.Anything, measurementID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("measurement not found"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", measurementHandler.UpdateMeasurement)
+
+	req := httptest.NewRequest("PATCH", "/measurements/"+measurementID.String(), bytes.NewBufferString(`{"note":"edited"}`))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_UpdateMeasurement_FutureTimestamp(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	mockService.On("UpdateMeasurement", mock.Anything, measurementID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("timestamp cannot be in the future"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /measurements/{measurement_id}", measurementHandler.UpdateMeasurement)
+
+	body := `{"timestamp":"` + time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339) + `"}`
+	req := httptest.NewRequest("PATCH", "/measurements/"+measurementID.String(), bytes.NewBufferString(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "timestamp cannot be in the future", strings.TrimSpace(w.Body.String()))
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_ValidateMeasurement_Valid(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	reqBody := handler.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 38.5,
+	}
+
+	mockService.On("ValidateMeasurement", mock.Anything, babyID, mock.MatchedBy(func(req ports.CreateMeasurementRequest) bool {
+		return req.Type == "temperature" && req.Value == 38.5
+	}), userID, domain.RoleParent).Return(&ports.MeasurementValidationResult{
+		Valid:        true,
+		SafetyStatus: domain.SafetyStatusRed,
+	}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements/validate", measurementHandler.ValidateMeasurement)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements/validate", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result ports.MeasurementValidationResult
+	err := json.NewDecoder(w.Body).Decode(&result)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_ValidateMeasurement_Invalid(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	reqBody := handler.CreateMeasurementRequest{
+		Type:  "weight",
+		Value: -5,
+	}
+
+	mockService.On("ValidateMeasurement", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
+		Return(&ports.MeasurementValidationResult{
+			Valid:  false,
+			Errors: []string{"weight must be greater than 0 grams"},
+		}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements/validate", measurementHandler.ValidateMeasurement)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements/validate", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result ports.MeasurementValidationResult
+	err := json.NewDecoder(w.Body).Decode(&result)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Empty(t, result.SafetyStatus)
+	assert.Equal(t, []string{"weight must be greater than 0 grams"}, result.Errors)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetFeedingBalance_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	// Date-only bounds are interpreted in the requested timezone, `to` covers the whole day
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2024, 1, 8, 0, 0, 0, 0, loc)
+
+	expected := domain.CalculateFeedingBalance([]domain.FeedingTotals{
+		{FeedingType: domain.FeedingTypeBottle, Count: 2, TotalVolumeML: 240},
+		{FeedingType: domain.FeedingTypeBreast, Count: 2, TotalDurationSeconds: 1200},
+	}, from, to)
+
+	mockService.On("GetFeedingBalance", mock.Anything, babyID, userID, domain.RoleParent,
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(from) }),
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(to) }),
+	).Return(expected, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/balance", measurementHandler.GetFeedingBalance)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/feeding/balance?from=2024-01-01&to=2024-01-07&tz=Europe/Amsterdam", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var balance domain.FeedingBalance
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&balance))
+	assert.Equal(t, 4, balance.TotalFeedings)
+	assert.InDelta(t, 0.5, balance.BottleRatio, 1e-9)
+	assert.InDelta(t, 0.5, balance.BreastRatio, 1e-9)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetFeedingBalance_InvalidWindow(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/balance", measurementHandler.GetFeedingBalance)
+
+	for _, query := range []string{"?tz=Mars/Olympus", "?from=yesterday", "?from=2024-01-08&to=2024-01-01"} {
+		req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/feeding/balance"+query, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetFeedingBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_GetMeasurementStats_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	expected := []domain.MeasurementStats{
+		{Type: "weight", Count: 2, MinValue: 3400, MaxValue: 3550, AvgValue: 3475},
+	}
+
+	// A date-only to includes that whole day
+	mockService.On("GetMeasurementStats", mock.Anything, babyID, userID, domain.RoleParent,
+		mock.MatchedBy(func(from *time.Time) bool { return from != nil && from.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) }),
+		mock.MatchedBy(func(to *time.Time) bool { return to != nil && to.Equal(time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)) }),
+	).Return(expected, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/stats", measurementHandler.GetMeasurementStats)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/stats?from=2024-03-01&to=2024-03-07", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stats []domain.MeasurementStats
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+	assert.Equal(t, expected, stats)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetSafetyStatusDistribution_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	expected := &domain.SafetyStatusDistribution{Type: "temperature", Total: 200, Green: 180, Yellow: 15, Red: 5}
+
+	mockService.On("GetSafetyStatusDistribution", mock.Anything, babyID, userID, domain.RoleNurse,
+		mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+			return f.Type != nil && *f.Type == "temperature" &&
+				f.From != nil && f.From.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) && f.To == nil
+		}),
+	).Return(expected, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/status-distribution", measurementHandler.GetSafetyStatusDistribution)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/status-distribution?type=temperature&from=2024-03-01", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var distribution domain.SafetyStatusDistribution
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&distribution))
+	assert.Equal(t, *expected, distribution)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurementStats_Empty(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetMeasurementStats", mock.Anything, babyID, userID, domain.RoleParent, (*time.Time)(nil), (*time.Time)(nil)).
+		Return([]domain.MeasurementStats{}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/stats", measurementHandler.GetMeasurementStats)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/stats", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetLatestMeasurements_KeyedByType(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	feedingID := uuid.New()
+	temperatureID := uuid.New()
+
+	mockService.On("GetLatestMeasurements", mock.Anything, babyID, userID, domain.RoleParent).
+		Return(map[string]*domain.Measurement{
+			"feeding":     {ID: feedingID, BabyID: babyID, Type: "feeding", Value: 120, SafetyStatus: domain.SafetyStatusGreen},
+			"temperature": {ID: temperatureID, BabyID: babyID, Type: "temperature", Value: 38.4, SafetyStatus: domain.SafetyStatusRed},
+		}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/latest", measurementHandler.GetLatestMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/latest", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var latest map[string]*domain.Measurement
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&latest))
+	require.Len(t, latest, 2)
+	assert.Equal(t, feedingID, latest["feeding"].ID)
+	assert.Equal(t, temperatureID, latest["temperature"].ID)
+	assert.Equal(t, domain.SafetyStatusRed, latest["temperature"].SafetyStatus)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetLatestMeasurements_Empty(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetLatestMeasurements", mock.Anything, babyID, userID, domain.RoleParent).
+		Return(map[string]*domain.Measurement{}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/latest", measurementHandler.GetLatestMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/latest", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "{}", w.Body.String())
+}
+
+func TestMeasurementHandler_GetLatestMeasurements_NotOwned(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetLatestMeasurements", mock.Anything, babyID, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("baby not found"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/latest", measurementHandler.GetLatestMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/latest", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMeasurementHandler_GetHourlyFeeding_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	expected := domain.BuildHourlyFeedingDistribution([]domain.FeedingHourCount{{Hour: 3, Count: 5}, {Hour: 19, Count: 2}},
+		time.Now().AddDate(0, 0, -7), time.Now(), loc)
+
+	mockService.On("GetHourlyFeedingDistribution", mock.Anything, babyID, userID, domain.RoleParent,
+		mock.Anything, mock.Anything,
+		mock.MatchedBy(func(l *time.Location) bool { return l.String() == "America/New_York" }),
+	).Return(expected, nil).Run(func(args mock.Arguments) {
+		from := args.Get(4).(time.Time)
+		to := args.Get(5).(time.Time)
+		assert.True(t, from.Equal(to.AddDate(0, 0, -7)))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/hourly", measurementHandler.GetHourlyFeeding)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/feeding/hourly?days=7&tz=America/New_York", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var distribution domain.HourlyFeedingDistribution
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&distribution))
+	assert.Equal(t, "America/New_York", distribution.Timezone)
+	assert.Equal(t, 7, distribution.TotalFeedings)
+	require.Len(t, distribution.Hours, 24)
+	assert.Equal(t, 5, distribution.Hours[3].Count)
+	assert.Equal(t, 2, distribution.Hours[19].Count)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetHourlyFeeding_InvalidParams(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/hourly", measurementHandler.GetHourlyFeeding)
+
+	for _, query := range []string{"?days=0", "?days=91", "?days=two", "?tz=Mars/Olympus"} {
+		req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/feeding/hourly"+query, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetHourlyFeedingDistribution", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_GetDailyReport_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+
+	report := domain.BuildDailyReport(babyID.String(), "2024-03-10", loc, day, day.AddDate(0, 0, 1), nil)
+	mockService.On("GetDailyReport", mock.Anything, babyID, userID, domain.RoleParent,
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(day) && t.Location().String() == "America/New_York" }),
+	).Return(report, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/daily-report", measurementHandler.GetDailyReport)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/daily-report?date=2024-03-10&tz=America/New_York", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var got domain.DailyReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, "2024-03-10", got.Date)
+	assert.Equal(t, "America/New_York", got.Timezone)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetDailyReport_InvalidDate(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/daily-report", measurementHandler.GetDailyReport)
+
+	req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/daily-report?date=10-03-2024", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetDailyReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_GetMeasurements_FieldProjection(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{
+			{
+				ID:           uuid.New(),
+				ParentID:     userID,
+				BabyID:       babyID,
+				Type:         "temperature",
+				Value:        37.0,
+				SafetyStatus: domain.SafetyStatusGreen,
+				Note:         "after bath",
+				Timestamp:    time.Now(),
+				CreatedAt:    time.Now(),
+			},
+		}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements?fields=timestamp,value,safety_status", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var items []map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&items))
+	require.Len(t, items, 1)
+	assert.Len(t, items[0], 3)
+	assert.Contains(t, items[0], "timestamp")
+	assert.Equal(t, 37.0, items[0]["value"])
+	assert.Equal(t, "green", items[0]["safety_status"])
+	assert.NotContains(t, items[0], "note")
+	assert.NotContains(t, items[0], "id")
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurements_CursorPagination(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	base := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newest := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3600, Timestamp: base}
+	middle := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3550, Timestamp: base.Add(-time.Hour)}
+	oldest := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3500, Timestamp: base.Add(-2 * time.Hour)}
+
+	// First page: one extra row is fetched to detect the next page
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+		return f.Before == nil && f.Limit != nil && *f.Limit == 3
+	})).Return([]*domain.Measurement{newest, middle, oldest}, nil).Once()
+
+	// Second page continues strictly after the last row of the first page
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+		return f.Before != nil && f.Before.ID == middle.ID && f.Before.Timestamp.Equal(middle.Timestamp)
+	})).Return([]*domain.Measurement{oldest}, nil).Once()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	get := func(query string) map[string]json.RawMessage {
+		req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements"+query, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var page map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+		return page
+	}
+
+	page := get("?cursor=&limit=2")
+	var measurements []*domain.Measurement
+	require.NoError(t, json.Unmarshal(page["measurements"], &measurements))
+	require.Len(t, measurements, 2)
+	assert.Equal(t, middle.ID, measurements[1].ID)
+	var nextCursor string
+	require.NoError(t, json.Unmarshal(page["next_cursor"], &nextCursor))
+	require.NotEmpty(t, nextCursor)
+
+	page = get("?cursor=" + nextCursor + "&limit=2")
+	measurements = nil
+	require.NoError(t, json.Unmarshal(page["measurements"], &measurements))
+	require.Len(t, measurements, 1)
+	assert.Equal(t, oldest.ID, measurements[0].ID)
+	_, hasNext := page["next_cursor"]
+	assert.False(t, hasNext)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurements_MalformedCursor(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	cursors := []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("no-separator")),
+		base64.RawURLEncoding.EncodeToString([]byte("yesterday," + uuid.New().String())),
+		base64.RawURLEncoding.EncodeToString([]byte("2024-01-10T12:00:00Z,not-a-uuid")),
+	}
+	for _, cursor := range cursors {
+		req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/measurements?cursor="+url.QueryEscape(cursor), nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, cursor)
+	}
+	mockService.AssertNotCalled(t, "GetMeasurements", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_GetMeasurements_UnknownField(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/measurements?fields=timestamp,password", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown field: password")
+	mockService.AssertNotCalled(t, "GetMeasurements", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_GetMeasurements_WeightInPounds(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	grams := 3400.0
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{
+			{ID: uuid.New(), BabyID: babyID, Type: domain.MeasurementTypeWeight, Value: grams, ValueGrams: &grams, SafetyStatus: domain.SafetyStatusGreen},
+			{ID: uuid.New(), BabyID: babyID, Type: domain.MeasurementTypeTemperature, Value: 37.0, SafetyStatus: domain.SafetyStatusGreen},
+		}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements?unit=lb", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var measurements []*domain.Measurement
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&measurements))
+	require.Len(t, measurements, 2)
+
+	weight := measurements[0]
+	require.NotNil(t, weight.DisplayValue)
+	assert.InDelta(t, 7.5, *weight.DisplayValue, 1e-9)
+	assert.Equal(t, "lb", weight.DisplayUnit)
+	assert.Equal(t, "7 lb 8 oz", weight.DisplayText)
+	// The canonical grams are untouched
+	assert.Equal(t, 3400.0, weight.Value)
+	require.NotNil(t, weight.ValueGrams)
+	assert.Equal(t, 3400.0, *weight.ValueGrams)
+
+	// Other types get no display fields
+	assert.Nil(t, measurements[1].DisplayValue)
+	assert.Empty(t, measurements[1].DisplayUnit)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurementByID_WeightInOunces(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	grams := 3400.0
+
+	mockService.On("GetMeasurementByID", mock.Anything, measurementID, userID, domain.RoleParent).
+		Return(&domain.Measurement{ID: measurementID, Type: domain.MeasurementTypeWeight, Value: grams, ValueGrams: &grams}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /measurements/{measurement_id}", measurementHandler.GetMeasurementByID)
+
+	req := httptest.NewRequest("GET", "/measurements/"+measurementID.String()+"?unit=oz", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var measurement domain.Measurement
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&measurement))
+	require.NotNil(t, measurement.DisplayValue)
+	assert.InDelta(t, 119.9, *measurement.DisplayValue, 1e-9)
+	assert.Equal(t, "oz", measurement.DisplayUnit)
+	assert.Empty(t, measurement.DisplayText)
+	assert.Equal(t, 3400.0, measurement.Value)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurements_DefaultsToGrams(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{{ID: uuid.New(), BabyID: babyID, Type: domain.MeasurementTypeWeight, Value: 3400}}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "display_value")
+	assert.NotContains(t, w.Body.String(), "display_unit")
+}
+
+func TestMeasurementHandler_GetMeasurements_InvalidUnit(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/measurements?unit=stone", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid unit parameter")
+	mockService.AssertNotCalled(t, "GetMeasurements", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// envelopeResponse mirrors the ?envelope=true response shape
+type envelopeResponse struct {
+	Data json.RawMessage `json:"data"`
+	Meta struct {
+		RequestID  string `json:"request_id"`
+		Pagination *struct {
+			Count      int    `json:"count"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+func TestMeasurementHandler_Envelope_Object(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	mockService.On("GetMeasurementByID", mock.Anything, measurementID, userID, domain.RoleParent).
+		Return(&domain.Measurement{ID: measurementID, Type: "temperature", Value: 37.0, SafetyStatus: domain.SafetyStatusGreen}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /measurements/{measurement_id}", measurementHandler.GetMeasurementByID)
+
+	req := httptest.NewRequest("GET", "/measurements/"+measurementID.String()+"?envelope=true", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var envelope envelopeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&envelope))
+	assert.NotEmpty(t, envelope.Meta.RequestID)
+	assert.Nil(t, envelope.Meta.Pagination)
+
+	var measurement domain.Measurement
+	require.NoError(t, json.Unmarshal(envelope.Data, &measurement))
+	assert.Equal(t, measurementID, measurement.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_Envelope_List(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	base := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, mock.Anything).Return([]*domain.Measurement{
+		{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3600, Timestamp: base},
+		{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3550, Timestamp: base.Add(-time.Hour)},
+		{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3500, Timestamp: base.Add(-2 * time.Hour)},
+	}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	// With a cursor the next page is reported in the meta rather than a page wrapper
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements?envelope=true&cursor=&limit=2", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var envelope envelopeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&envelope))
+	assert.NotEmpty(t, envelope.Meta.RequestID)
+	require.NotNil(t, envelope.Meta.Pagination)
+	assert.Equal(t, 2, envelope.Meta.Pagination.Count)
+	assert.NotEmpty(t, envelope.Meta.Pagination.NextCursor)
+
+	var measurements []*domain.Measurement
+	require.NoError(t, json.Unmarshal(envelope.Data, &measurements))
+	assert.Len(t, measurements, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_Envelope_EmptyList(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleParent, ports.MeasurementFilter{}).
+		Return(nil, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements?envelope=true", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var envelope envelopeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&envelope))
+	assert.JSONEq(t, `[]`, string(envelope.Data))
+	require.NotNil(t, envelope.Meta.Pagination)
+	assert.Equal(t, 0, envelope.Meta.Pagination.Count)
+	assert.Empty(t, envelope.Meta.Pagination.NextCursor)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_Envelope_Delete(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	mockService.On("DeleteMeasurement", mock.Anything, measurementID, userID, domain.RoleParent).Return(&domain.Measurement{ID: measurementID}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /measurements/{measurement_id}", measurementHandler.DeleteMeasurement)
+
+	req := httptest.NewRequest("DELETE", "/measurements/"+measurementID.String()+"?envelope=true", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	// 204 cannot carry a body, so the envelope is returned with 200
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var envelope envelopeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&envelope))
+	assert.Equal(t, "null", string(envelope.Data))
+	assert.NotEmpty(t, envelope.Meta.RequestID)
+	mockService.AssertExpectations(t)
+}
+
+// timesEqual reports whether two optional instants are both nil or the same instant
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func TestMeasurementHandler_GetMeasurements_TimeWindow(t *testing.T) {
+	from := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		query  string
+		filter ports.MeasurementFilter
+	}{
+		{name: "from only", query: "?from=2024-01-10T08:00:00Z", filter: ports.MeasurementFilter{From: &from}},
+		{name: "to only", query: "?to=2024-01-10T20:00:00Z", filter: ports.MeasurementFilter{To: &to}},
+		{name: "both", query: "?from=2024-01-10T08:00:00Z&to=2024-01-10T20:00:00Z", filter: ports.MeasurementFilter{From: &from, To: &to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeasurementService)
+			measurementHandler := handler.NewMeasurementHandler(mockService)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockService.On("GetMeasurements", mock.Anything, babyID, userID, domain.RoleNurse, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+				return timesEqual(f.From, tt.filter.From) && timesEqual(f.To, tt.filter.To)
+			})).Return([]*domain.Measurement{}, nil)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+			req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeasurementHandler_GetMeasurements_InvalidTimeWindow(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements", measurementHandler.GetMeasurements)
+
+	queries := []string{
+		"?from=2024-01-10T20:00:00Z&to=2024-01-10T08:00:00Z", // from after to
+		"?from=2024-01-10T08:00:00Z&to=2024-01-10T08:00:00Z", // empty window
+		"?from=yesterday",
+		"?to=2024-13-45T08:00:00Z",
+	}
+	for _, query := range queries {
+		req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/measurements"+query, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "GetMeasurements", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_BackfillSafetyStatus_ReportsCounts(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mockService.On("BackfillSafetyStatus", mock.Anything, domain.RoleAdmin, 200).
+		Return(&domain.SafetyStatusBackfillResult{Updated: 3, Batches: 1, Green: 2, Red: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/admin/measurements/backfill-status?batch_size=200", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "ADMIN")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	measurementHandler.BackfillSafetyStatus(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"updated":3,"batches":1,"green":2,"yellow":0,"red":1}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_BackfillSafetyStatus_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		role       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "not admin", role: "PARENT", serviceErr: fmt.Errorf("forbidden: only ADMIN can backfill safety status"), wantStatus: http.StatusForbidden},
+		{name: "batch out of range", query: "?batch_size=5000", role: "ADMIN", serviceErr: fmt.Errorf("batch_size must be between 1 and 1000"), wantStatus: http.StatusBadRequest},
+		{name: "batch not a number", query: "?batch_size=lots", role: "ADMIN", wantStatus: http.StatusBadRequest},
+		{name: "database down", role: "ADMIN", serviceErr: fmt.Errorf("failed to list measurements with invalid safety status: connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeasurementService)
+			measurementHandler := handler.NewMeasurementHandler(mockService)
+			mockService.On("BackfillSafetyStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req := httptest.NewRequest("POST", "/admin/measurements/backfill-status"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, tt.role)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			measurementHandler.BackfillSafetyStatus(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+
+func TestMeasurementHandler_GetWardOverview(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	overview := &domain.WardOverview{
+		RoomPrefix: "3B",
+		Babies: []*domain.WardBabyOverview{{
+			BabyID:            babyID,
+			RoomNumber:        "3B-01",
+			Latest:            map[string]*domain.Measurement{"temperature": {Type: "temperature", Value: 38.6, SafetyStatus: domain.SafetyStatusRed}},
+			WorstRecentStatus: domain.SafetyStatusRed,
+		}},
+	}
+	mockService.On("GetWardOverview", mock.Anything, "3B", userID, domain.RoleNurse).Return(overview, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wards/{room_prefix}/overview", measurementHandler.GetWardOverview)
+
+	req := httptest.NewRequest("GET", "/wards/3B/overview", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response domain.WardOverview
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Babies, 1)
+	assert.Equal(t, babyID, response.Babies[0].BabyID)
+	assert.Equal(t, domain.SafetyStatusRed, response.Babies[0].WorstRecentStatus)
+	assert.Equal(t, 38.6, response.Babies[0].Latest["temperature"].Value)
+}
+
+func TestMeasurementHandler_GetWardOverview_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "parent", serviceErr: fmt.Errorf("forbidden: only ADMIN and NURSE can view ward overviews"), wantStatus: http.StatusForbidden},
+		{name: "invalid prefix", serviceErr: fmt.Errorf("room_prefix must be 1-32 characters"), wantStatus: http.StatusBadRequest},
+		{name: "database down", serviceErr: fmt.Errorf("failed to list babies: connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeasurementService)
+			measurementHandler := handler.NewMeasurementHandler(mockService)
+			mockService.On("GetWardOverview", mock.Anything, "3B", mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /wards/{room_prefix}/overview", measurementHandler.GetWardOverview)
+
+			req := httptest.NewRequest("GET", "/wards/3B/overview", nil)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMeasurementHandler_GetMeasurementTrend_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	expected := &domain.MeasurementTrend{
+		Type: domain.MeasurementTypeTemperature, Bucket: domain.TrendBucketDay, From: from, To: to, Timezone: "UTC",
+		Points: []domain.TrendPoint{
+			{BucketStart: from, Avg: 37.0, Min: 36.8, Max: 37.2, Count: 2},
+			{BucketStart: from.AddDate(0, 0, 2), Avg: 37.9, Min: 37.4, Max: 38.4, Count: 3},
+		},
+	}
+
+	mockService.On("GetMeasurementTrend", mock.Anything, babyID, userID, domain.RoleParent,
+		domain.MeasurementTypeTemperature, domain.TrendBucketDay, from, to, time.UTC).Return(expected, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/{type}/trend", measurementHandler.GetMeasurementTrend)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/temperature/trend?bucket=day&from=2024-03-01&to=2024-03-03", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var trend domain.MeasurementTrend
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&trend))
+	require.Len(t, trend.Points, 2)
+	assert.Equal(t, 37.9, trend.Points[1].Avg)
+	assert.Equal(t, 3, trend.Points[1].Count)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurementTrend_DefaultsToLast30Days(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("GetMeasurementTrend", mock.Anything, babyID, userID, domain.RoleNurse,
+		domain.MeasurementTypeWeight, domain.TrendBucketDay, mock.Anything, mock.Anything, mock.Anything,
+	).Return(&domain.MeasurementTrend{Points: []domain.TrendPoint{}}, nil).Run(func(args mock.Arguments) {
+		from := args.Get(6).(time.Time)
+		to := args.Get(7).(time.Time)
+		assert.True(t, from.Equal(to.AddDate(0, 0, -30)))
+		assert.WithinDuration(t, time.Now(), to, time.Minute)
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/measurements/{type}/trend", measurementHandler.GetMeasurementTrend)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements/weight/trend", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "NURSE")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_GetMeasurementTrend_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "unsupported type", path: "/feeding/trend",
+			serviceErr: fmt.Errorf("invalid trend type: must be 'temperature', 'weight', 'height', or 'head_circumference'"), wantStatus: http.StatusBadRequest},
+		{name: "unknown bucket", path: "/weight/trend?bucket=month",
+			serviceErr: fmt.Errorf("invalid bucket: must be 'hour', 'day', or 'week'"), wantStatus: http.StatusBadRequest},
+		{name: "baby not owned", path: "/weight/trend", serviceErr: fmt.Errorf("baby not found"), wantStatus: http.StatusNotFound},
+		{name: "hidden type", path: "/temperature/trend",
+			serviceErr: fmt.Errorf("forbidden: temperature measurements are not visible to this role"), wantStatus: http.StatusForbidden},
+		{name: "invalid from", path: "/weight/trend?from=yesterday", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeasurementService)
+			measurementHandler := handler.NewMeasurementHandler(mockService)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			if tt.serviceErr != nil {
+				mockService.On("GetMeasurementTrend", mock.Anything, babyID, userID, domain.RoleParent,
+					mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /babies/{baby_id}/measurements/{type}/trend", measurementHandler.GetMeasurementTrend)
+
+			req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/measurements"+tt.path, nil)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+			ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMeasurementHandler_CompareFeeding_Success(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	day1 := time.Date(2024, 3, 9, 0, 0, 0, 0, loc)
+	day2 := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+
+	expected := domain.CompareFeedingDays(day1, day2,
+		[]domain.FeedingTotals{{FeedingType: domain.FeedingTypeBottle, Count: 4, TotalVolumeML: 400}},
+		[]domain.FeedingTotals{{FeedingType: domain.FeedingTypeBottle, Count: 5, TotalVolumeML: 500}})
+
+	mockService.On("CompareFeedingDays", mock.Anything, babyID, userID, domain.RoleParent, day1, day2).Return(expected, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/compare", measurementHandler.CompareFeeding)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/feeding/compare?day1=2024-03-09&day2=2024-03-10&tz=America/New_York", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "America/New_York", body["timezone"])
+	assert.Equal(t, float64(100), body["bottle_ml_delta"])
+	assert.Equal(t, 25.0, body["bottle_ml_percent_change"])
+	assert.Nil(t, body["breast_duration_percent_change"])
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_CompareFeeding_DefaultsToYesterdayAndToday(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("CompareFeedingDays", mock.Anything, babyID, userID, domain.RoleParent, mock.Anything, mock.Anything).
+		Return(&domain.FeedingComparison{}, nil).Run(func(args mock.Arguments) {
+		day1 := args.Get(4).(time.Time)
+		day2 := args.Get(5).(time.Time)
+		assert.Equal(t, time.Now().UTC().Format("2006-01-02"), day2.Format("2006-01-02"))
+		assert.True(t, day1.Equal(day2.AddDate(0, 0, -1)))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/compare", measurementHandler.CompareFeeding)
+
+	req := httptest.NewRequest("GET", "/babies/"+babyID.String()+"/feeding/compare", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMeasurementHandler_CompareFeeding_InvalidParams(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /babies/{baby_id}/feeding/compare", measurementHandler.CompareFeeding)
+
+	for _, query := range []string{"?day1=yesterday", "?day2=2024-13-01", "?tz=Mars/Olympus"} {
+		req := httptest.NewRequest("GET", "/babies/"+uuid.New().String()+"/feeding/compare"+query, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+		ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "CompareFeedingDays", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementHandler_CreateMeasurement_SlowServiceTimesOut(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	// The slow service blocks until the request deadline cancels its context, as a database call would
+	observedDeadline := make(chan error, 1)
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, context.DeadlineExceeded).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		<-ctx.Done()
+		observedDeadline <- ctx.Err()
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", middleware.TimeoutMiddleware(50*time.Millisecond)(measurementHandler.CreateMeasurement))
+
+	body, _ := json.Marshal(handler.CreateMeasurementRequest{Type: "temperature", Value: 37.0})
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "request timed out")
+	select {
+	case err := <-observedDeadline:
+		assert.Equal(t, context.DeadlineExceeded, err)
+	case <-time.After(time.Second):
+		t.Fatal("service context never reached its deadline")
+	}
+}
+
+func TestMeasurementHandler_CreateMeasurement_AlertUnavailable(t *testing.T) {
+	mockService := new(MockMeasurementService)
+	measurementHandler := handler.NewMeasurementHandler(mockService)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockService.On("CreateMeasurementWithDetails", mock.Anything, babyID, mock.Anything, userID, domain.RoleParent).
+		Return(nil, fmt.Errorf("%w: broker unreachable", domain.ErrAlertUnavailable))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies/{baby_id}/measurements", measurementHandler.CreateMeasurement)
+
+	body, _ := json.Marshal(handler.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"})
+	req := httptest.NewRequest("POST", "/babies/"+babyID.String()+"/measurements", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID.String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "measurement not saved")
+	assert.NotContains(t, w.Body.String(), "broker unreachable")
+}
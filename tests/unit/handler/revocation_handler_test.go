@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func revokeRequest(role string, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/admin/revoke", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, role)
+	return req.WithContext(ctx)
+}
+
+func TestRevocationHandler_RevokeToken_DeniesToken(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	authMiddleware := middleware.NewAuthMiddleware(&privateKey.PublicKey)
+	defer authMiddleware.Stop()
+	revocationHandler := handler.NewRevocationHandler(authMiddleware)
+
+	exp := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":  uuid.New().String(),
+		"role": "PARENT",
+		"exp":  exp.Unix(),
+		"jti":  "deactivated-user-jti",
+	}).SignedString(privateKey)
+	require.NoError(t, err)
+
+	_, _, err = authMiddleware.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	revocationHandler.RevokeToken(w, revokeRequest("ADMIN", `{"jti": "deactivated-user-jti", "until": "`+exp.Format(time.RFC3339)+`"}`))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handler.RevokeTokenResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "deactivated-user-jti", response.JTI)
+	assert.True(t, exp.Equal(response.Until))
+
+	_, _, err = authMiddleware.GetClaimsFromCacheOrParse(tokenString)
+	assert.ErrorIs(t, err, middleware.ErrTokenRevoked)
+}
+
+func TestRevocationHandler_RevokeToken_DefaultsUntil(t *testing.T) {
+	authMiddleware := middleware.NewAuthMiddleware(nil)
+	defer authMiddleware.Stop()
+	revocationHandler := handler.NewRevocationHandler(authMiddleware)
+
+	w := httptest.NewRecorder()
+	revocationHandler.RevokeToken(w, revokeRequest("ADMIN", `{"jti": "some-jti"}`))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handler.RevokeTokenResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.WithinDuration(t, time.Now().Add(handler.DefaultRevocationTTL), response.Until, time.Minute)
+}
+
+func TestRevocationHandler_RevokeToken_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		body       string
+		wantStatus int
+	}{
+		{name: "parent", role: "PARENT", body: `{"jti": "some-jti"}`, wantStatus: http.StatusForbidden},
+		{name: "nurse", role: "NURSE", body: `{"jti": "some-jti"}`, wantStatus: http.StatusForbidden},
+		{name: "missing jti", role: "ADMIN", body: `{"jti": "  "}`, wantStatus: http.StatusBadRequest},
+		{name: "until in the past", role: "ADMIN", body: `{"jti": "some-jti", "until": "2020-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed body", role: "ADMIN", body: `{"jti":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authMiddleware := middleware.NewAuthMiddleware(nil)
+			defer authMiddleware.Stop()
+			revocationHandler := handler.NewRevocationHandler(authMiddleware)
+
+			w := httptest.NewRecorder()
+			revocationHandler.RevokeToken(w, revokeRequest(tt.role, tt.body))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
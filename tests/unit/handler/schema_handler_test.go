@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/handler" //nolint:staticcheck // handler package contains non-deprecated code
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonSchema is the subset of a JSON Schema object the tests inspect
+type jsonSchema struct {
+	Type       string                     `json:"type"`
+	Format     string                     `json:"format"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+	Enum       []string                   `json:"enum"`
+}
+
+func getSchemaDefs(t *testing.T) map[string]jsonSchema {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/schema", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.RoleKey, "PARENT")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	handler.NewSchemaHandler().GetSchema(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var document struct {
+		Schema string                `json:"$schema"`
+		Defs   map[string]jsonSchema `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", document.Schema)
+	return document.Defs
+}
+
+func property(t *testing.T, schema jsonSchema, name string) jsonSchema {
+	t.Helper()
+	raw, ok := schema.Properties[name]
+	require.True(t, ok, "missing property %s", name)
+	var p jsonSchema
+	require.NoError(t, json.Unmarshal(raw, &p))
+	return p
+}
+
+func TestSchemaHandler_GetSchema_CreateMeasurementRequest(t *testing.T) {
+	schema := getSchemaDefs(t)["CreateMeasurementRequest"]
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"type"}, schema.Required)
+
+	measurementType := property(t, schema, "type")
+	assert.Equal(t, "string", measurementType.Type)
+	assert.Equal(t, domain.ValidMeasurementTypes(), measurementType.Enum)
+
+	assert.Equal(t, []string{"bottle", "breast"}, property(t, schema, "feeding_type").Enum)
+	assert.Equal(t, "integer", property(t, schema, "volume_ml").Type)
+	assert.Equal(t, "number", property(t, schema, "value").Type)
+	timestamp := property(t, schema, "timestamp")
+	assert.Equal(t, "string", timestamp.Type)
+	assert.Equal(t, "date-time", timestamp.Format)
+}
+
+func TestSchemaHandler_GetSchema_CreateBabyRequest(t *testing.T) {
+	schema := getSchemaDefs(t)["CreateBabyRequest"]
+
+	assert.ElementsMatch(t, []string{"last_name", "room_number", "parent_user_id"}, schema.Required)
+	assert.Equal(t, "uuid", property(t, schema, "parent_user_id").Format)
+}
+
+func TestSchemaHandler_GetSchema_CoversEveryField(t *testing.T) {
+	defs := getSchemaDefs(t)
+
+	// Every JSON field the handlers decode or return appears in its schema
+	for name, v := range map[string]interface{}{
+		"CreateBabyRequest":        handler.CreateBabyRequest{},
+		"CreateMeasurementRequest": handler.CreateMeasurementRequest{},
+		"Baby":                     domain.Baby{},
+		"Measurement":              domain.Measurement{},
+	} {
+		typ := reflect.TypeOf(v)
+		for i := 0; i < typ.NumField(); i++ {
+			jsonName, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			assert.Contains(t, defs[name].Properties, jsonName, "%s.%s", name, typ.Field(i).Name)
+		}
+	}
+
+	assert.Equal(t, []string{"green", "yellow", "red"}, property(t, defs["Measurement"], "safety_status").Enum)
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rsa"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -83,6 +84,122 @@ func TestAuthMiddleware_GetClaimsFromCacheOrParse_CacheHit(t *testing.T) {
 	assert.Equal(t, claims1["role"], claims2["role"])
 }
 
+func TestAuthMiddleware_CacheTTL_RevalidatesLongLivedToken(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddlewareWithCacheTTL(publicKey, time.Second)
+	defer mw.Stop()
+
+	// Token valid for a day, far longer than the cache TTL
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  time.Now().Add(24 * time.Hour).Unix(),
+		"jti":  "long-lived-jti",
+	})
+
+	claims, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	// Mark the cached claims so a cache hit can be told apart from a fresh validation
+	claims["cached"] = true
+
+	cached, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, true, cached["cached"], "within the TTL the cached claims are reused")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	revalidated, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	assert.NotContains(t, revalidated, "cached", "after the TTL the token is validated again")
+	assert.Equal(t, "user123", revalidated["sub"])
+}
+
+func TestAuthMiddleware_CacheTTL_DisabledCachesUntilExpiry(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddlewareWithCacheTTL(publicKey, 0)
+	defer mw.Stop()
+
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"jti":  "uncapped-jti",
+	})
+
+	claims, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	claims["cached"] = true
+
+	time.Sleep(1100 * time.Millisecond)
+
+	cached, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, true, cached["cached"])
+}
+
+func TestAuthMiddleware_RevokeJTI_RejectsCachedToken(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	exp := time.Now().Add(time.Hour)
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  exp.Unix(),
+		"jti":  "revoked-jti",
+	})
+	otherToken := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user456",
+		"role": "PARENT",
+		"exp":  exp.Unix(),
+		"jti":  "other-jti",
+	})
+
+	// Validated and cached before the revocation
+	_, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+
+	mw.RevokeJTI("revoked-jti", exp)
+
+	claims, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	assert.Nil(t, claims)
+	assert.ErrorIs(t, err, middleware.ErrTokenRevoked)
+	assert.EqualError(t, err, "token revoked")
+
+	// Other tokens are unaffected
+	_, _, err = mw.GetClaimsFromCacheOrParse(otherToken)
+	assert.NoError(t, err)
+
+	// RequireAuth rejects the revoked token
+	handler := mw.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/babies", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAuthMiddleware_RevokeJTI_LapsedRevocationIsIgnored(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"jti":  "lapsed-jti",
+	})
+
+	mw.RevokeJTI("lapsed-jti", time.Now().Add(-time.Minute))
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	assert.NoError(t, err)
+}
+
 func TestAuthMiddleware_GetClaimsFromCacheOrParse_ExpiredToken(t *testing.T) {
 	privateKey, publicKey := generateTestKeyPair(t)
 	mw := middleware.NewAuthMiddleware(publicKey)
@@ -101,6 +218,86 @@ func TestAuthMiddleware_GetClaimsFromCacheOrParse_ExpiredToken(t *testing.T) {
 	assert.Contains(t, err.Error(), "expired")
 }
 
+func TestAuthMiddleware_GetClaimsFromCacheOrParse_StringExp(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+		"iat":  strconv.FormatInt(now.Unix(), 10),
+		"nbf":  strconv.FormatInt(now.Add(-time.Minute).Unix(), 10),
+		"jti":  "test-jti-string-exp",
+	}
+	tokenString := createTestToken(t, privateKey, claims)
+
+	resultClaims, jti, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "test-jti-string-exp", jti)
+	assert.Equal(t, "user123", resultClaims["sub"])
+	// Time claims are normalized to numbers
+	assert.Equal(t, float64(now.Add(time.Hour).Unix()), resultClaims["exp"])
+	assert.Equal(t, float64(now.Unix()), resultClaims["iat"])
+}
+
+func TestAuthMiddleware_GetClaimsFromCacheOrParse_StringExpExpired(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10),
+		"jti":  "test-jti-string-exp-expired",
+	}
+	tokenString := createTestToken(t, privateKey, claims)
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+}
+
+func TestAuthMiddleware_GetClaimsFromCacheOrParse_InvalidStringExp(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  "tomorrow",
+		"jti":  "test-jti-bad-exp",
+	}
+	tokenString := createTestToken(t, privateKey, claims)
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid expiration claim")
+}
+
+func TestAuthMiddleware_GetClaimsFromCacheOrParse_StringNbfInFuture(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+		"nbf":  strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10),
+		"jti":  "test-jti-future-nbf",
+	}
+	tokenString := createTestToken(t, privateKey, claims)
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid yet")
+}
+
 func TestAuthMiddleware_GetClaimsFromCacheOrParse_InvalidToken(t *testing.T) {
 	_, publicKey := generateTestKeyPair(t)
 	mw := middleware.NewAuthMiddleware(publicKey)
@@ -235,6 +432,33 @@ func TestAuthMiddleware_RequireRole_WrongRole(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 }
 
+func TestAuthMiddleware_RequireRole_NurseCannotCreateBabies(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := jwt.MapClaims{
+		"sub":  "nurse123",
+		"role": "NURSE",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"jti":  "test-jti-nurse",
+	}
+	tokenString := createTestToken(t, privateKey, claims)
+
+	// Same wiring as POST /babies in cmd/api
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /babies", mw.RequireRole("ADMIN", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called")
+	}))
+
+	req := httptest.NewRequest("POST", "/babies", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 func TestAuthMiddleware_RequireAnyRole(t *testing.T) {
 	privateKey, publicKey := generateTestKeyPair(t)
 	mw := middleware.NewAuthMiddleware(publicKey)
@@ -292,3 +516,224 @@ func TestIsAdmin(t *testing.T) {
 	ctx3 := context.Background()
 	assert.False(t, middleware.IsAdmin(ctx3))
 }
+
+func TestCanReadAllBabies(t *testing.T) {
+	for role, expected := range map[string]bool{"ADMIN": true, "NURSE": true, "PARENT": false} {
+		ctx := context.WithValue(context.Background(), middleware.RoleKey, role)
+		assert.Equal(t, expected, middleware.CanReadAllBabies(ctx), role)
+	}
+
+	assert.False(t, middleware.CanReadAllBabies(context.Background()))
+}
+
+func TestAuthMiddleware_RequireAuth_SchemeIsCaseInsensitive(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+
+	handler := mw.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, scheme := range []string{"Bearer", "bearer", "BEARER"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", scheme+" "+tokenString)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, scheme)
+	}
+}
+
+func TestAuthMiddleware_RequireAuth_InvalidHeaderFormat(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	tokenString := createTestToken(t, privateKey, jwt.MapClaims{
+		"sub":  "user123",
+		"role": "PARENT",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+
+	handler := mw.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called")
+	})
+
+	for _, header := range []string{tokenString, "Basic " + tokenString, "Bearer ", "Bearer " + tokenString + " extra"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, header)
+	}
+}
+
+func createTestTokenWithKID(t *testing.T, privateKey *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+	return tokenString
+}
+
+func testClaims(jti string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  "user123",
+		"role": "NURSE",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"jti":  jti,
+	}
+}
+
+func TestAuthMiddleware_KeyRotation_OldKeyStillValid(t *testing.T) {
+	oldPrivateKey, oldPublicKey := generateTestKeyPair(t)
+	newPrivateKey, newPublicKey := generateTestKeyPair(t)
+
+	// Tokens issued before the rotation carry the old kid
+	oldToken := createTestTokenWithKID(t, oldPrivateKey, "2025-01", testClaims("jti-old"))
+
+	// The new key is added next to the old one
+	mw := middleware.NewAuthMiddlewareWithKeys(map[string]*rsa.PublicKey{
+		"2025-01": oldPublicKey,
+		"2025-07": newPublicKey,
+	}, 0, middleware.DefaultClockSkewLeeway)
+	defer mw.Stop()
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(oldToken)
+	require.NoError(t, err)
+
+	newToken := createTestTokenWithKID(t, newPrivateKey, "2025-07", testClaims("jti-new"))
+	_, _, err = mw.GetClaimsFromCacheOrParse(newToken)
+	require.NoError(t, err)
+}
+
+func TestAuthMiddleware_KeyRotation_WithoutKIDTriesAllKeys(t *testing.T) {
+	oldPrivateKey, oldPublicKey := generateTestKeyPair(t)
+	_, newPublicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddlewareWithKeys(map[string]*rsa.PublicKey{
+		"2025-01": oldPublicKey,
+		"2025-07": newPublicKey,
+	}, 0, middleware.DefaultClockSkewLeeway)
+	defer mw.Stop()
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestToken(t, oldPrivateKey, testClaims("jti-no-kid")))
+
+	require.NoError(t, err)
+}
+
+func TestAuthMiddleware_KeyRotation_KIDSelectsKey(t *testing.T) {
+	oldPrivateKey, oldPublicKey := generateTestKeyPair(t)
+	_, newPublicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddlewareWithKeys(map[string]*rsa.PublicKey{
+		"2025-01": oldPublicKey,
+		"2025-07": newPublicKey,
+	}, 0, middleware.DefaultClockSkewLeeway)
+	defer mw.Stop()
+
+	// Signed with the old key but claiming the new kid: only the new key is tried
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestTokenWithKID(t, oldPrivateKey, "2025-07", testClaims("jti-wrong-kid")))
+	require.Error(t, err)
+
+	// A kid nobody has published is rejected
+	_, _, err = mw.GetClaimsFromCacheOrParse(createTestTokenWithKID(t, oldPrivateKey, "2026-01", testClaims("jti-unknown-kid")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown signing key: 2026-01")
+}
+
+func TestAuthMiddleware_SingleKey_AcceptsAnyKID(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	// A key loaded without an ID keeps verifying once the Identity Service starts sending kid
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestTokenWithKID(t, privateKey, "2025-01", testClaims("jti-single")))
+
+	require.NoError(t, err)
+}
+
+func TestAuthMiddleware_Leeway_ExpiredWithinLeewayAccepted(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	// The Identity Service clock runs ahead; the token expired moments ago by our clock
+	claims := testClaims("jti-within-leeway")
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestToken(t, privateKey, claims))
+
+	require.NoError(t, err)
+}
+
+func TestAuthMiddleware_Leeway_ExpiredWithinLeewayIsCached(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := testClaims("jti-within-leeway-cached")
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	tokenString := createTestToken(t, privateKey, claims)
+
+	validated, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	// Mark the cached claims so a cache hit can be told apart from a fresh validation
+	validated["cached"] = true
+
+	// Still inside the leeway window, so the second call must not re-run the RSA validation
+	cached, _, err := mw.GetClaimsFromCacheOrParse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, true, cached["cached"])
+}
+
+func TestAuthMiddleware_Leeway_ExpiredPastLeewayRejected(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := testClaims("jti-past-leeway")
+	claims["exp"] = time.Now().Add(-middleware.DefaultClockSkewLeeway - time.Minute).Unix()
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestToken(t, privateKey, claims))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+}
+
+func TestAuthMiddleware_Leeway_NotBeforeWithinLeewayAccepted(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddleware(publicKey)
+	defer mw.Stop()
+
+	claims := testClaims("jti-nbf-within-leeway")
+	claims["nbf"] = time.Now().Add(10 * time.Second).Unix()
+
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestToken(t, privateKey, claims))
+
+	require.NoError(t, err)
+}
+
+func TestAuthMiddleware_Leeway_Configurable(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	mw := middleware.NewAuthMiddlewareWithKeys(map[string]*rsa.PublicKey{"": publicKey}, 0, 0)
+	defer mw.Stop()
+
+	expired := testClaims("jti-no-leeway-exp")
+	expired["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	_, _, err := mw.GetClaimsFromCacheOrParse(createTestToken(t, privateKey, expired))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+
+	notYetValid := testClaims("jti-no-leeway-nbf")
+	notYetValid["nbf"] = time.Now().Add(10 * time.Second).Unix()
+	_, _, err = mw.GetClaimsFromCacheOrParse(createTestToken(t, privateKey, notYetValid))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not valid yet")
+}
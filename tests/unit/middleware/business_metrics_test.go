@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStatsRepository returns canned business stats and records the requested window
+type fakeStatsRepository struct {
+	stats *domain.BusinessStats
+	err   error
+	since time.Time
+}
+
+func (f *fakeStatsRepository) GetBusinessStats(ctx context.Context, since time.Time) (*domain.BusinessStats, error) {
+	f.since = since
+	return f.stats, f.err
+}
+
+func TestBusinessMetricsCollector_RefreshPopulatesGauges(t *testing.T) {
+	repo := &fakeStatsRepository{stats: &domain.BusinessStats{ActiveBabies: 12, MeasurementsLastHour: 34, ActiveRedAlerts: 2}}
+	registry := prometheus.NewRegistry()
+	collector := middleware.NewBusinessMetricsCollector(repo, time.Minute, registry)
+
+	require.NoError(t, collector.Refresh(context.Background()))
+
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), repo.since, 5*time.Second)
+	assert.Equal(t, 3, testutil.CollectAndCount(registry))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = family.GetMetric()[0].GetGauge().GetValue()
+	}
+	assert.Equal(t, map[string]float64{
+		"care_active_babies":          12,
+		"care_measurements_last_hour": 34,
+		"care_active_red_alerts":      2,
+	}, values)
+}
+
+func TestBusinessMetricsCollector_RefreshErrorKeepsPreviousValues(t *testing.T) {
+	repo := &fakeStatsRepository{stats: &domain.BusinessStats{ActiveBabies: 5}}
+	registry := prometheus.NewRegistry()
+	collector := middleware.NewBusinessMetricsCollector(repo, time.Minute, registry)
+	require.NoError(t, collector.Refresh(context.Background()))
+
+	repo.stats, repo.err = nil, errors.New("database unavailable")
+	assert.Error(t, collector.Refresh(context.Background()))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "care_active_babies" {
+			assert.Equal(t, 5.0, family.GetMetric()[0].GetGauge().GetValue())
+		}
+	}
+}
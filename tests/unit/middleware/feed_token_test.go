@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedTokenSigner_RoundTrip(t *testing.T) {
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	babyID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	token, expiresAt := signer.Issue(babyID, userID, now)
+	assert.WithinDuration(t, now.Add(time.Hour), expiresAt, time.Second)
+
+	gotBabyID, gotUserID, err := signer.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, babyID, gotBabyID)
+	assert.Equal(t, userID, gotUserID)
+}
+
+func TestFeedTokenSigner_TamperedToken(t *testing.T) {
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	now := time.Now()
+	token, _ := signer.Issue(uuid.New(), uuid.New(), now)
+
+	// Swap in the payload of a token for another baby, keeping the original signature
+	other, _ := signer.Issue(uuid.New(), uuid.New(), now)
+	otherPayload, _, _ := strings.Cut(other, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	tampered := otherPayload + "." + signature
+
+	_, _, err := signer.Verify(tampered, now)
+	assert.ErrorIs(t, err, middleware.ErrInvalidFeedToken)
+
+	// A token signed with another secret is rejected too
+	foreign, _ := middleware.NewFeedTokenSigner("other-secret", time.Hour).Issue(uuid.New(), uuid.New(), now)
+	_, _, err = signer.Verify(foreign, now)
+	assert.ErrorIs(t, err, middleware.ErrInvalidFeedToken)
+
+	_, _, err = signer.Verify("not-a-token", now)
+	assert.ErrorIs(t, err, middleware.ErrInvalidFeedToken)
+}
+
+func TestFeedTokenSigner_ExpiredToken(t *testing.T) {
+	signer := middleware.NewFeedTokenSigner("test-secret", time.Hour)
+	now := time.Now()
+	token, _ := signer.Issue(uuid.New(), uuid.New(), now)
+
+	_, _, err := signer.Verify(token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, middleware.ErrInvalidFeedToken)
+}
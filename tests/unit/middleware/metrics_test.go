@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware_StoresRequestStart(t *testing.T) {
+	before := time.Now()
+	var first, second time.Time
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first = domain.RequestStart(r.Context())
+		time.Sleep(5 * time.Millisecond)
+		second = domain.RequestStart(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	middleware.MetricsMiddleware(next).ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+	// Every reader of the context sees the same base time, taken when the request entered
+	assert.Equal(t, first, second)
+	assert.False(t, first.Before(before))
+	assert.True(t, first.Before(before.Add(5*time.Millisecond)))
+}
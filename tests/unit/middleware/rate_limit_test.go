@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func rateLimitedRequest(userID string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/babies/123/measurements", nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestRateLimitByUser_RejectsOverBurst(t *testing.T) {
+	const burst = 5
+	limiter := middleware.NewUserRateLimiter(rate.Limit(1.0/60), burst)
+	defer limiter.Stop()
+
+	calls := 0
+	handler := limiter.RateLimitByUser(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	var rr *httptest.ResponseRecorder
+	for i := 0; i < burst+1; i++ {
+		rr = httptest.NewRecorder()
+		handler(rr, rateLimitedRequest("user-1"))
+		if i < burst {
+			assert.Equal(t, http.StatusCreated, rr.Code, "request %d is within the burst", i+1)
+		}
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, burst, calls)
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.Greater(t, retryAfter, 0)
+	assert.LessOrEqual(t, retryAfter, 60)
+}
+
+func TestRateLimitByUser_BucketsArePerUser(t *testing.T) {
+	limiter := middleware.NewUserRateLimiter(rate.Limit(1.0/60), 1)
+	defer limiter.Stop()
+
+	handler := limiter.RateLimitByUser(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, rateLimitedRequest("user-1"))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler(rr, rateLimitedRequest("user-1"))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// Another user's bucket is unaffected
+	rr = httptest.NewRecorder()
+	handler(rr, rateLimitedRequest("user-2"))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestRateLimitByUser_RequiresUserID(t *testing.T) {
+	limiter := middleware.NewUserRateLimiter(rate.Inf, 1)
+	defer limiter.Stop()
+
+	handler := limiter.RateLimitByUser(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/babies/123/measurements", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServedByMiddleware_SetsConfiguredNodeName(t *testing.T) {
+	handler := middleware.ServedByMiddleware("care-service-7d9f-abcde", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "baby not found", http.StatusNotFound)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/babies/123", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "care-service-7d9f-abcde", rr.Header().Get(middleware.ServedByHeader))
+}
+
+func TestServedByMiddleware_DisabledWithoutNodeName(t *testing.T) {
+	handler := middleware.ServedByMiddleware("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	_, present := rr.Header()[middleware.ServedByHeader]
+	assert.False(t, present)
+}
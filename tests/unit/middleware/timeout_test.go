@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware_FastHandlerPassesThrough(t *testing.T) {
+	handler := middleware.TimeoutMiddleware(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline := r.Context().Deadline()
+		assert.True(t, hasDeadline, "handler context carries the deadline")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/babies/123/measurements", nil))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"1"}`, rr.Body.String())
+}
+
+func TestTimeoutMiddleware_SlowHandlerGets503(t *testing.T) {
+	cancelled := make(chan struct{})
+	handler := middleware.TimeoutMiddleware(50 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request) {
+		// A well-behaved slow handler: waits on its context like a database call would
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/babies/123/measurements", nil))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "request timed out", body["error"])
+	assert.Equal(t, "50ms", body["timeout"])
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestTimeoutMiddleware_ClientGoneWritesNothing(t *testing.T) {
+	handler := middleware.TimeoutMiddleware(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodDelete, "/measurements/1", nil).WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestTimeoutMiddleware_PanicPropagates(t *testing.T) {
+	handler := middleware.TimeoutMiddleware(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/babies/123/measurements", nil))
+	})
+}
@@ -0,0 +1,299 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBabyService is a mock implementation of ports.BabyService
+type MockBabyService struct {
+	mock.Mock
+}
+
+func (m *MockBabyService) CreateBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, lastName, roomNumber, parentUserID, dateOfBirth, createdByUserID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyService) CreateBabies(ctx context.Context, requests []ports.CreateBabyRequest, createdByUserID uuid.UUID, role domain.Role, atomic bool) (*domain.BabyBatchResult, error) {
+	args := m.Called(ctx, requests, createdByUserID, role, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BabyBatchResult), args.Error(1)
+}
+
+func (m *MockBabyService) EnsureBaby(ctx context.Context, lastName string, roomNumber string, parentUserID uuid.UUID, dateOfBirth string, createdByUserID uuid.UUID, role domain.Role) (*domain.Baby, bool, error) {
+	args := m.Called(ctx, lastName, roomNumber, parentUserID, dateOfBirth, createdByUserID, role)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*domain.Baby), args.Bool(1), args.Error(2)
+}
+
+func (m *MockBabyService) GetBaby(ctx context.Context, babyID uuid.UUID, userID uuid.UUID, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, babyID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyService) ListBabies(ctx context.Context, userID uuid.UUID, role domain.Role, includeDeleted bool) ([]*domain.Baby, error) {
+	args := m.Called(ctx, userID, role, includeDeleted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyService) GetParentSummary(ctx context.Context, userID uuid.UUID, role domain.Role) (*domain.ParentSummary, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ParentSummary), args.Error(1)
+}
+
+func (m *MockBabyService) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth string, role domain.Role) (*domain.Baby, error) {
+	args := m.Called(ctx, babyID, lastName, roomNumber, dateOfBirth, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyService) DeleteBaby(ctx context.Context, babyID uuid.UUID, role domain.Role) error {
+	args := m.Called(ctx, babyID, role)
+	return args.Error(0)
+}
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func newDelivery(t *testing.T, ack *fakeAcknowledger, body interface{}) amqp091.Delivery {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	return amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Body: payload}
+}
+
+func TestBabyMessageProcessor_Process_CreatesBaby(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, false)
+
+	parentID := uuid.New()
+	mockService.On("EnsureBaby", mock.Anything, "Smith", "101", parentID, "", uuid.Nil, domain.RoleAdmin).
+		Return(&domain.Baby{ID: uuid.New(), LastName: "Smith", RoomNumber: "101", ParentUserID: parentID}, true, nil)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:     parentID.String(),
+		LastName:   "Smith",
+		RoomNumber: "101",
+	}))
+
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+	mockService.AssertExpectations(t)
+}
+
+func TestBabyMessageProcessor_Process_DryRun(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, true)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:     uuid.New().String(),
+		LastName:   "Smith",
+		RoomNumber: "101",
+	}))
+
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+	mockService.AssertNotCalled(t, "EnsureBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBabyMessageProcessor_Process_DryRunInvalidMessage(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, true)
+
+	// Invalid messages are still rejected in dry-run so format problems are visible
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:   "not-a-uuid",
+		LastName: "Smith",
+	}))
+
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.False(t, ack.requeue)
+	mockService.AssertNotCalled(t, "EnsureBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// fakePublisher records the messages published per queue
+type fakePublisher struct {
+	published map[string][]amqp091.Publishing
+	err       error
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, queue string, msg amqp091.Publishing) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.published == nil {
+		p.published = map[string][]amqp091.Publishing{}
+	}
+	p.published[queue] = append(p.published[queue], msg)
+	return nil
+}
+
+func TestBabyMessageProcessor_Process_DeadLettersAfterMaxRetries(t *testing.T) {
+	const maxRetries = 3
+	mockService := new(MockBabyService)
+	publisher := &fakePublisher{}
+	processor := repository.NewBabyMessageProcessorWithRetry(mockService, false, publisher, "babies", maxRetries)
+
+	// Structurally valid but always failing (e.g. a duplicate primary key)
+	mockService.On("EnsureBaby", mock.Anything, "Smith", "101", mock.Anything, "", uuid.Nil, domain.RoleAdmin).
+		Return(nil, false, errors.New("duplicate key value violates unique constraint"))
+
+	msg := newDelivery(t, &fakeAcknowledger{}, repository.BabyCreationRequest{
+		UserID:     uuid.New().String(),
+		LastName:   "Smith",
+		RoomNumber: "101",
+	})
+
+	// Each retry is redelivered from the main queue until the message lands in the DLQ
+	for attempt := 1; attempt <= maxRetries+1; attempt++ {
+		ack := &fakeAcknowledger{}
+		msg.Acknowledger = ack
+		processor.Process(context.Background(), msg)
+
+		assert.True(t, ack.acked, "attempt %d: original must be acked once republished", attempt)
+		assert.False(t, ack.nacked)
+
+		if attempt <= maxRetries {
+			require.Len(t, publisher.published["babies"], attempt)
+			retry := publisher.published["babies"][attempt-1]
+			assert.Equal(t, int32(attempt), retry.Headers[repository.RetryCountHeader])
+			msg.Headers = retry.Headers
+			msg.Body = retry.Body
+		}
+	}
+
+	require.Len(t, publisher.published[repository.BabyCreationDLQ], 1)
+	dead := publisher.published[repository.BabyCreationDLQ][0]
+	assert.Equal(t, msg.Body, dead.Body)
+	assert.Equal(t, "duplicate key value violates unique constraint", dead.Headers["x-last-error"])
+	assert.Len(t, publisher.published["babies"], maxRetries)
+	mockService.AssertNumberOfCalls(t, "EnsureBaby", maxRetries+1)
+}
+
+func TestBabyMessageProcessor_Process_RequeuesWhenRepublishFails(t *testing.T) {
+	mockService := new(MockBabyService)
+	publisher := &fakePublisher{err: errors.New("channel closed")}
+	processor := repository.NewBabyMessageProcessorWithRetry(mockService, false, publisher, "babies", 3)
+
+	mockService.On("EnsureBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", mock.Anything, mock.Anything).
+		Return(nil, false, errors.New("connection refused"))
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:     uuid.New().String(),
+		LastName:   "Smith",
+		RoomNumber: "101",
+	}))
+
+	// The message must not be lost when it can't be republished
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.True(t, ack.requeue)
+}
+
+func TestBabyMessageProcessor_Process_WhitespaceOnlyLastNameRejected(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, false)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:     uuid.New().String(),
+		LastName:   "   ",
+		RoomNumber: "101",
+	}))
+
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.False(t, ack.requeue)
+	mockService.AssertNotCalled(t, "EnsureBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBabyMessageProcessor_Process_InvalidDateOfBirthRejected(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, false)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:      uuid.New().String(),
+		LastName:    "Smith",
+		RoomNumber:  "101",
+		DateOfBirth: "01.03.2024",
+	}))
+
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.False(t, ack.requeue)
+	mockService.AssertNotCalled(t, "EnsureBaby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBabyMessageProcessor_Process_TrimsPadding(t *testing.T) {
+	mockService := new(MockBabyService)
+	processor := repository.NewBabyMessageProcessor(mockService, false)
+
+	parentID := uuid.New()
+	mockService.On("EnsureBaby", mock.Anything, "Smith", "101", parentID, "", uuid.Nil, domain.RoleAdmin).
+		Return(&domain.Baby{ID: uuid.New(), LastName: "Smith", RoomNumber: "101", ParentUserID: parentID}, true, nil)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.BabyCreationRequest{
+		UserID:     parentID.String(),
+		LastName:   " Smith ",
+		RoomNumber: "101 ",
+	}))
+
+	assert.True(t, ack.acked)
+	mockService.AssertExpectations(t)
+}
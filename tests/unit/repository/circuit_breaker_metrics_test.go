@@ -0,0 +1,61 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateChangeCount reads circuit_breaker_state_changes_total for one breaker transition
+func stateChangeCount(t *testing.T, registry *prometheus.Registry, name, from, to string) float64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "circuit_breaker_state_changes_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["name"] == name && labels["from"] == from && labels["to"] == to {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestInstrumentCircuitBreaker_CountsTripToOpen(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, repository.RegisterRepositoryMetrics(registry))
+
+	var callbackStates []gobreaker.State
+	settings := repository.InstrumentCircuitBreaker(gobreaker.Settings{
+		Name: "test_trip_to_open",
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 2
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			callbackStates = append(callbackStates, to)
+		},
+	})
+	cb := gobreaker.NewCircuitBreaker(settings)
+	failing := func() (interface{}, error) { return nil, errors.New("connection refused") }
+
+	_, _ = cb.Execute(failing)
+	assert.Equal(t, 0.0, stateChangeCount(t, registry, "test_trip_to_open", "closed", "open"))
+
+	_, _ = cb.Execute(failing)
+	assert.Equal(t, gobreaker.StateOpen, cb.State())
+	assert.Equal(t, 1.0, stateChangeCount(t, registry, "test_trip_to_open", "closed", "open"))
+
+	// An existing callback still runs
+	assert.Equal(t, []gobreaker.State{gobreaker.StateOpen}, callbackStates)
+}
@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParentEventProcessor_Process_ProjectsUserCreated(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	processor := repository.NewParentEventProcessor(repo)
+
+	userID := uuid.New()
+	occurredAt := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO parents \\(id, name, email, updated_at\\)").
+		WithArgs(userID, "Jane Smith", "jane@example.com", occurredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.UserEvent{
+		EventType:  repository.UserEventCreated,
+		UserID:     userID.String(),
+		Role:       "PARENT",
+		Name:       "Jane Smith",
+		Email:      "jane@example.com",
+		OccurredAt: &occurredAt,
+	}))
+
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParentEventProcessor_Process_SkipsNonParentUsers(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	processor := repository.NewParentEventProcessor(repo)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.UserEvent{
+		EventType: repository.UserEventCreated,
+		UserID:    uuid.New().String(),
+		Role:      "NURSE",
+		Name:      "Nina Nurse",
+		Email:     "nina@example.com",
+	}))
+
+	assert.True(t, ack.acked)
+	assert.False(t, ack.nacked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParentEventProcessor_Process_SkipsOtherEventTypes(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	processor := repository.NewParentEventProcessor(repo)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.UserEvent{
+		EventType: "user.deleted",
+		UserID:    uuid.New().String(),
+	}))
+
+	assert.True(t, ack.acked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParentEventProcessor_Process_InvalidUserID(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	processor := repository.NewParentEventProcessor(repo)
+
+	ack := &fakeAcknowledger{}
+	processor.Process(context.Background(), newDelivery(t, ack, repository.UserEvent{
+		EventType: repository.UserEventUpdated,
+		UserID:    "not-a-uuid",
+		Name:      "Jane Smith",
+	}))
+
+	assert.False(t, ack.acked)
+	assert.True(t, ack.nacked)
+	assert.False(t, ack.requeue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetParentByID(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	parentID := uuid.New()
+	updatedAt := time.Now().UTC()
+	mock.ExpectQuery("SELECT id, name, email, updated_at FROM parents WHERE id = \\$1").
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "updated_at"}).
+			AddRow(parentID, "Jane Smith", "jane@example.com", updatedAt))
+
+	parent, err := repo.GetParentByID(context.Background(), parentID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Smith", parent.Name)
+	assert.Equal(t, "jane@example.com", parent.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAlertEvent_Severity(t *testing.T) {
+	babyID := uuid.New()
+
+	tests := []struct {
+		name        string
+		measurement *domain.Measurement
+		severity    string
+		alertType   string
+	}{
+		{
+			name:        "red temperature is critical",
+			measurement: &domain.Measurement{Type: domain.MeasurementTypeTemperature, Value: 39.0, SafetyStatus: domain.SafetyStatusRed},
+			severity:    "critical",
+			alertType:   "high_temperature_critical",
+		},
+		{
+			name:        "yellow high temperature is a warning",
+			measurement: &domain.Measurement{Type: domain.MeasurementTypeTemperature, Value: 37.8, SafetyStatus: domain.SafetyStatusYellow},
+			severity:    "warning",
+			alertType:   "high_temperature_warning",
+		},
+		{
+			name:        "yellow low temperature is a warning",
+			measurement: &domain.Measurement{Type: domain.MeasurementTypeTemperature, Value: 36.2, SafetyStatus: domain.SafetyStatusYellow},
+			severity:    "warning",
+			alertType:   "low_temperature_warning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := repository.NewAlertEvent(babyID, tt.measurement)
+
+			assert.Equal(t, babyID, event.BabyID)
+			assert.Equal(t, tt.severity, event.Severity)
+			assert.Equal(t, tt.alertType, event.AlertType)
+			assert.Equal(t, string(tt.measurement.SafetyStatus), event.SafetyStatus)
+		})
+	}
+}
@@ -0,0 +1,1287 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// measurementColumns matches the SELECT column order used by the repository
+var measurementColumns = []string{
+	"id", "parent_id", "baby_id", "type", "value", "safety_status", "note", "timestamp", "created_at",
+	"feeding_type", "volume_ml", "position", "side", "left_duration", "right_duration", "duration",
+	"value_celsius", "diaper_status", "device_id", "value_grams", "sleep_start", "sleep_end",
+	"spit_up", "spit_up_severity", "metadata",
+}
+
+func newMockRepository(t *testing.T) (*repository.SQLRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repository.NewSQLRepository(db, gobreaker.Settings{}), mock
+}
+
+func TestSQLRepository_Weight_RoundTripsValueGrams(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	grams := 3520.0
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeWeight,
+		Value:        grams,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    now,
+		CreatedAt:    now,
+		ValueGrams:   &grams,
+	}
+
+	// value_grams is the 20th insert argument
+	args := make([]driver.Value, 25)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[19] = grams
+	mock.ExpectExec("INSERT INTO measurements").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateMeasurement(context.Background(), measurement))
+
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1").
+		WithArgs(measurement.ID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			measurement.ID, measurement.ParentID, measurement.BabyID, "weight", grams, "green", "", now, now,
+			nil, nil, nil, nil, nil, nil, nil,
+			nil, nil, nil, grams, nil, nil,
+			nil, nil, nil,
+		))
+
+	result, err := repo.GetMeasurementByID(context.Background(), measurement.ID)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.ValueGrams)
+	assert.Equal(t, grams, *result.ValueGrams)
+	assert.Equal(t, grams, result.Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_NonWeight_HasNoValueGrams(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	id := uuid.New()
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			id, uuid.New(), uuid.New(), "temperature", 37.0, "green", "", now, now,
+			nil, nil, nil, nil, nil, nil, nil,
+			37.0, nil, nil, nil, nil, nil,
+			nil, nil, nil,
+		))
+
+	result, err := repo.GetMeasurementByID(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.ValueGrams)
+	require.NotNil(t, result.ValueCelsius)
+	assert.Equal(t, 37.0, *result.ValueCelsius)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_DeleteMeasurement_ReturnsDeletedRow(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	id := uuid.New()
+	parentID := uuid.New()
+	babyID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1 AND parent_id = \\$2 FOR UPDATE").
+		WithArgs(id, parentID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			id, parentID, babyID, "temperature", 37.2, "green", "", now, now,
+			nil, nil, nil, nil, nil, nil, nil,
+			37.2, nil, nil, nil, nil, nil,
+			nil, nil, nil,
+		))
+	mock.ExpectExec("DELETE FROM measurements WHERE id = \\$1").
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteMeasurement(context.Background(), id, parentID)
+
+	require.NoError(t, err)
+	assert.Equal(t, id, deleted.ID)
+	assert.Equal(t, babyID, deleted.BabyID)
+	assert.Equal(t, 37.2, deleted.Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_DeleteMeasurement_AlreadyDeleted(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	id := uuid.New()
+	parentID := uuid.New()
+
+	// A concurrent delete committed first: the locked read finds nothing and nothing is deleted
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1 AND parent_id = \\$2 FOR UPDATE").
+		WithArgs(id, parentID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+	mock.ExpectRollback()
+
+	deleted, err := repo.DeleteMeasurement(context.Background(), id, parentID)
+
+	assert.Nil(t, deleted)
+	require.Error(t, err)
+	assert.Equal(t, "measurement not found", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CreateMeasurement_DuplicateKeyReturnsConflict(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeWeight,
+		Value:        3500,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    now,
+		CreatedAt:    now,
+	}
+
+	// A single attempt: unique violations are not retried
+	mock.ExpectExec("INSERT INTO measurements").
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint \"measurements_pkey\""})
+
+	err := repo.CreateMeasurement(context.Background(), measurement)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CreateMeasurement_CheckViolationReturnsFriendlyError(t *testing.T) {
+	tests := []struct {
+		constraint  string
+		wantMessage string
+	}{
+		{"chk_feeding_fields", "invalid measurement: feeding measurements require a feeding_type, and only feedings may have feeding fields (chk_feeding_fields)"},
+		{"chk_temperature_fields", "invalid measurement: temperature measurements require value_celsius, and only temperatures may have it (chk_temperature_fields)"},
+		{"chk_diaper_fields", "invalid measurement: diaper measurements require a diaper_status, and only diapers may have it (chk_diaper_fields)"},
+		{"chk_breastfeeding_durations", "invalid measurement: breastfeeding both-sides requires left and right durations (chk_breastfeeding_durations)"},
+		{"chk_sleep_fields", "invalid measurement: sleep measurements require sleep_start before sleep_end, and only sleeps may have them (chk_sleep_fields)"},
+		{"chk_growth_values", "invalid measurement: height must be 20-120 cm and head circumference 25-60 cm (chk_growth_values)"},
+		{"chk_spit_up_fields", "invalid measurement: only feedings may record a spit-up (chk_spit_up_fields)"},
+		{"chk_added_later", "invalid measurement: violates a data integrity check (chk_added_later)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			repo, mock := newMockRepository(t)
+
+			now := time.Now()
+			side := domain.SideBoth
+			measurement := &domain.Measurement{
+				ID:           uuid.New(),
+				ParentID:     uuid.New(),
+				BabyID:       uuid.New(),
+				Type:         domain.MeasurementTypeFeeding,
+				SafetyStatus: domain.SafetyStatusGreen,
+				FeedingType:  domain.FeedingTypeBreast,
+				Side:         &side,
+				Timestamp:    now,
+				CreatedAt:    now,
+			}
+
+			// A single attempt: constraint violations are not retried
+			mock.ExpectExec("INSERT INTO measurements").
+				WillReturnError(&pq.Error{
+					Code:       "23514",
+					Message:    "new row for relation \"measurements\" violates check constraint \"" + tt.constraint + "\"",
+					Constraint: tt.constraint,
+				})
+
+			err := repo.CreateMeasurement(context.Background(), measurement)
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+			assert.Equal(t, tt.wantMessage, err.Error())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLRepository_CreateMeasurementWithIdempotencyKey_ClaimsKeyAndInserts(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	notBefore := now.Add(-24 * time.Hour)
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeWeight,
+		Value:        3500,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    now,
+		CreatedAt:    now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE user_id = \\$1 AND created_at < \\$2").
+		WithArgs(measurement.ParentID, notBefore.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WithArgs(measurement.ParentID, "retry-key-1", measurement.ID, now.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO measurements").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	existingID, err := repo.CreateMeasurementWithIdempotencyKey(context.Background(), measurement, "retry-key-1", notBefore)
+
+	require.NoError(t, err)
+	assert.Nil(t, existingID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CreateMeasurementWithIdempotencyKey_ExistingKeySkipsInsert(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	originalID := uuid.New()
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeWeight,
+		Value:        3500,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    now,
+		CreatedAt:    now,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM idempotency_keys").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO idempotency_keys").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT measurement_id FROM idempotency_keys").
+		WithArgs(measurement.ParentID, "retry-key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"measurement_id"}).AddRow(originalID))
+	mock.ExpectRollback()
+
+	existingID, err := repo.CreateMeasurementWithIdempotencyKey(context.Background(), measurement, "retry-key-1", now.Add(-24*time.Hour))
+
+	require.NoError(t, err)
+	require.NotNil(t, existingID)
+	assert.Equal(t, originalID, *existingID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateBaby_RoomOnly(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	mock.ExpectExec("UPDATE babies SET room_number = \\$2 WHERE id = \\$1").
+		WithArgs(babyID, "204").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpdateBaby(context.Background(), babyID, "", "204", nil)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateBaby_AllFields(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	mock.ExpectExec("UPDATE babies SET last_name = \\$2, room_number = \\$3 WHERE id = \\$1").
+		WithArgs(babyID, "Smith", "204").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpdateBaby(context.Background(), babyID, "Smith", "204", nil)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateBaby_NotFound(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	mock.ExpectExec("UPDATE babies SET").
+		WithArgs(babyID, "Smith").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateBaby(context.Background(), babyID, "Smith", "", nil)
+
+	assert.EqualError(t, err, "baby not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_DeleteBaby_SetsDeletedAt(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	// Soft delete is an UPDATE; the row is never physically removed
+	babyID := uuid.New()
+	mock.ExpectExec("UPDATE babies SET deleted_at = now\\(\\) WHERE id = \\$1 AND deleted_at IS NULL").
+		WithArgs(babyID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.DeleteBaby(context.Background(), babyID)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_DeleteBaby_NotFound(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	mock.ExpectExec("UPDATE babies SET deleted_at").
+		WithArgs(babyID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteBaby(context.Background(), babyID)
+
+	assert.EqualError(t, err, "baby not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CountBabiesForParent(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	parentID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies WHERE parent_user_id = \\$1 AND deleted_at IS NULL").
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountBabiesForParent(context.Background(), parentID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CountRedMeasurementsForParent_JoinsBabies(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	parentID := uuid.New()
+	since := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM measurements m\\s+JOIN babies b ON b.id = m.baby_id\\s+WHERE b.parent_user_id = \\$1 AND b.deleted_at IS NULL\\s+AND m.safety_status = \\$2 AND m.timestamp >= \\$3").
+		WithArgs(parentID, domain.SafetyStatusRed, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := repo.CountRedMeasurementsForParent(context.Background(), parentID, since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListBabies_ExcludesDeleted(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	parentID := uuid.New()
+	mock.ExpectQuery("FROM babies WHERE parent_user_id = \\$1 AND deleted_at IS NULL ORDER BY").
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_name", "room_number", "parent_user_id", "date_of_birth", "created_at", "deleted_at"}).
+			AddRow(uuid.New(), "Doe", "101", parentID, nil, time.Now(), nil))
+
+	babies, err := repo.ListBabies(context.Background(), parentID, false, false)
+
+	require.NoError(t, err)
+	require.Len(t, babies, 1)
+	assert.Nil(t, babies[0].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListBabies_IncludeDeleted(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	deletedAt := time.Now()
+	mock.ExpectQuery("FROM babies ORDER BY created_at DESC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_name", "room_number", "parent_user_id", "date_of_birth", "created_at", "deleted_at"}).
+			AddRow(uuid.New(), "Doe", "101", uuid.New(), nil, time.Now(), deletedAt))
+
+	babies, err := repo.ListBabies(context.Background(), uuid.Nil, true, true)
+
+	require.NoError(t, err)
+	require.Len(t, babies, 1)
+	require.NotNil(t, babies[0].DeletedAt)
+	assert.True(t, deletedAt.Equal(*babies[0].DeletedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateMeasurement_ScopedToParent(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:        uuid.New(),
+		ParentID:  uuid.New(),
+		Note:      "after bath",
+		Timestamp: now,
+	}
+
+	mock.ExpectExec("UPDATE measurements SET note = \\$1, timestamp = \\$2 WHERE id = \\$3 AND parent_id = \\$4").
+		WithArgs(measurement.Note, measurement.Timestamp, measurement.ID, measurement.ParentID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdateMeasurement(context.Background(), measurement))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateMeasurement_OtherParentNotFound(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	measurement := &domain.Measurement{ID: uuid.New(), ParentID: uuid.New(), Timestamp: time.Now()}
+
+	// A single attempt: no matching row is not retried
+	mock.ExpectExec("UPDATE measurements").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateMeasurement(context.Background(), measurement)
+
+	require.Error(t, err)
+	assert.Equal(t, "measurement not found", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetFeedingCountsByHour_GroupsInRequestedTimezone(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	to := time.Date(2024, 3, 15, 0, 0, 0, 0, loc)
+	from := to.AddDate(0, 0, -14)
+
+	// Stored timestamps are UTC, bucketed by the hour in the requested zone
+	mock.ExpectQuery("EXTRACT\\(HOUR FROM timezone\\(\\$5, timestamp AT TIME ZONE 'UTC'\\)\\)(.+)GROUP BY hour").
+		WithArgs(babyID, domain.MeasurementTypeFeeding, from.UTC(), to.UTC(), "America/Los_Angeles").
+		WillReturnRows(sqlmock.NewRows([]string{"hour", "count"}).
+			AddRow(2, 3).
+			AddRow(18, 6))
+
+	counts, err := repo.GetFeedingCountsByHour(context.Background(), babyID, from, to, loc)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FeedingHourCount{{Hour: 2, Count: 3}, {Hour: 18, Count: 6}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementStats_GroupsByType(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastWeight := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	lastTemperature := time.Date(2024, 3, 12, 20, 30, 0, 0, time.UTC)
+
+	// Only the lower bound is set, so to adds no placeholder
+	mock.ExpectQuery("WHERE baby_id = \\$1 AND timestamp >= \\$2 GROUP BY type ORDER BY type").
+		WithArgs(babyID, from).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count", "min", "max", "avg", "max"}).
+			AddRow("temperature", 3, 36.6, 38.2, 37.3, lastTemperature).
+			AddRow("weight", 2, 3400.0, 3550.0, 3475.0, lastWeight))
+
+	stats, err := repo.GetMeasurementStats(context.Background(), babyID, &from, nil)
+
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, domain.MeasurementStats{Type: "temperature", Count: 3, MinValue: 36.6, MaxValue: 38.2, AvgValue: 37.3, LastTimestamp: &lastTemperature}, stats[0])
+	assert.Equal(t, "weight", stats[1].Type)
+	assert.Equal(t, 3475.0, stats[1].AvgValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementTrend_MultiDay(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2024, 3, 4, 0, 0, 0, 0, loc)
+	day1 := time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)
+	day3 := time.Date(2024, 3, 2, 23, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("date_trunc\\(\\$5, timezone\\(\\$6, timestamp AT TIME ZONE 'UTC'\\)\\)(.+)GROUP BY bucket_start\\s+ORDER BY bucket_start").
+		WithArgs(babyID, domain.MeasurementTypeTemperature, from.UTC(), to.UTC(), "day", "Europe/Amsterdam").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket_start", "avg", "min", "max", "count"}).
+			AddRow(day1, 37.0, 36.8, 37.2, 2).
+			AddRow(day3, 37.9, 37.4, 38.4, 3))
+
+	points, err := repo.GetMeasurementTrend(context.Background(), babyID, domain.MeasurementTypeTemperature, domain.TrendBucketDay, from, to, loc)
+
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.True(t, points[0].BucketStart.Equal(from), "bucket starts at local midnight")
+	assert.Equal(t, loc, points[0].BucketStart.Location())
+	assert.Equal(t, domain.TrendPoint{BucketStart: day3.In(loc), Avg: 37.9, Min: 37.4, Max: 38.4, Count: 3}, points[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetLatestMeasurements_OnePerType(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	fed := time.Date(2024, 3, 12, 21, 0, 0, 0, time.UTC)
+	measured := time.Date(2024, 3, 12, 20, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(type\\) (.+) FROM measurements WHERE baby_id = \\$1 ORDER BY type, timestamp DESC, id DESC").
+		WithArgs(babyID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).
+			AddRow(uuid.New(), uuid.New(), babyID, "feeding", 120.0, "green", "", fed, fed,
+				"bottle", 120, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).
+			AddRow(uuid.New(), uuid.New(), babyID, "temperature", 37.2, "green", "", measured, measured,
+				nil, nil, nil, nil, nil, nil, nil, 37.2, nil, nil, nil, nil, nil, nil, nil, nil))
+
+	result, err := repo.GetLatestMeasurements(context.Background(), babyID)
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "feeding", result[0].Type)
+	assert.True(t, result[0].Timestamp.Equal(fed))
+	assert.Equal(t, "temperature", result[1].Type)
+	assert.Equal(t, 37.2, result[1].Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetLatestMeasurementsForBabies(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	first, second := uuid.New(), uuid.New()
+	measured := time.Date(2024, 3, 12, 20, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(baby_id, type\\) (.+) FROM measurements WHERE baby_id = ANY\\(\\$1\\) ORDER BY baby_id, type, timestamp DESC, id DESC").
+		WithArgs(pq.Array([]uuid.UUID{first, second})).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).
+			AddRow(uuid.New(), uuid.New(), first, "temperature", 37.2, "green", "", measured, measured,
+				nil, nil, nil, nil, nil, nil, nil, 37.2, nil, nil, nil, nil, nil, nil, nil, nil).
+			AddRow(uuid.New(), uuid.New(), second, "temperature", 38.6, "red", "", measured, measured,
+				nil, nil, nil, nil, nil, nil, nil, 38.6, nil, nil, nil, nil, nil, nil, nil, nil))
+
+	result, err := repo.GetLatestMeasurementsForBabies(context.Background(), []uuid.UUID{first, second})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, first, result[0].BabyID)
+	assert.Equal(t, second, result[1].BabyID)
+	assert.Equal(t, domain.SafetyStatusRed, result[1].SafetyStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetSafetyStatusesSince(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	first, second := uuid.New(), uuid.New()
+	since := time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT DISTINCT baby_id, safety_status FROM measurements WHERE baby_id = ANY\\(\\$1\\) AND timestamp >= \\$2 AND type = ANY\\(\\$3\\)").
+		WithArgs(pq.Array([]uuid.UUID{first, second}), since, pq.Array([]string{"temperature"})).
+		WillReturnRows(sqlmock.NewRows([]string{"baby_id", "safety_status"}).
+			AddRow(first, "green").
+			AddRow(first, "red").
+			AddRow(second, "yellow"))
+
+	statuses, err := repo.GetSafetyStatusesSince(context.Background(), []uuid.UUID{first, second}, since, []string{"temperature"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]domain.SafetyStatus{
+		first:  {domain.SafetyStatusGreen, domain.SafetyStatusRed},
+		second: {domain.SafetyStatusYellow},
+	}, statuses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListInvalidSafetyStatusMeasurements(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	id := uuid.New()
+	takenAt := time.Date(2024, 3, 12, 20, 30, 0, 0, time.UTC)
+	dateOfBirth := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("WHERE m.safety_status IS NULL OR NOT \\(m.safety_status = ANY\\(\\$1\\)\\) ORDER BY m.id LIMIT \\$2").
+		WithArgs(pq.Array([]string{"green", "yellow", "red"}), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "value", "timestamp", "date_of_birth"}).
+			AddRow(id, "temperature", 38.4, takenAt, dateOfBirth).
+			AddRow(uuid.New(), "weight", 3400.0, takenAt, nil))
+
+	rows, err := repo.ListInvalidSafetyStatusMeasurements(context.Background(), 100)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, ports.SafetyStatusBackfillRow{ID: id, Type: "temperature", Value: 38.4, Timestamp: takenAt, DateOfBirth: &dateOfBirth}, rows[0])
+	assert.Nil(t, rows[1].DateOfBirth)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListInvalidSafetyStatusMeasurements_CancelledMidScan(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	rows := sqlmock.NewRows([]string{"id", "type", "value", "timestamp", "date_of_birth"})
+	takenAt := time.Now().UTC()
+	for i := 0; i < 10000; i++ {
+		rows.AddRow(uuid.New(), "temperature", 38.4, takenAt, nil)
+	}
+	mock.ExpectQuery("FROM measurements m").WillReturnRows(rows).RowsWillBeClosed()
+
+	// The backfill is cancelled after a few rows have been scanned
+	ctx := &cancelAfterChecksContext{Context: context.Background(), checks: 5}
+	result, err := repo.ListInvalidSafetyStatusMeasurements(ctx, 10000)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+	assert.Greater(t, ctx.checks, -10, "scan should stop at the next row, not read the whole result")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_UpdateMeasurementSafetyStatus(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	id := uuid.New()
+	mock.ExpectExec("UPDATE measurements SET safety_status = \\$1 WHERE id = \\$2").
+		WithArgs("red", id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdateMeasurementSafetyStatus(context.Background(), id, domain.SafetyStatusRed))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetSafetyStatusCounts_GroupsByStatus(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	temperature := "temperature"
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT safety_status, COUNT\\(\\*\\) FROM measurements WHERE baby_id = \\$1 AND type = \\$2 AND timestamp < \\$3 GROUP BY safety_status").
+		WithArgs(babyID, temperature, to).
+		WillReturnRows(sqlmock.NewRows([]string{"safety_status", "count"}).
+			AddRow("green", 180).
+			AddRow("yellow", 15).
+			AddRow("red", 5))
+
+	counts, err := repo.GetSafetyStatusCounts(context.Background(), babyID, ports.MeasurementFilter{Type: &temperature, To: &to})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.SafetyStatus]int{domain.SafetyStatusGreen: 180, domain.SafetyStatusYellow: 15, domain.SafetyStatusRed: 5}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_BeforeCursor(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	cursorTime := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	cursor := &ports.MeasurementCursor{Timestamp: cursorTime, ID: uuid.New()}
+	limit := 2
+
+	older := cursorTime.Add(-time.Hour)
+	mock.ExpectQuery("AND \\(timestamp, id\\) < \\(\\$2, \\$3\\) ORDER BY timestamp DESC, id DESC LIMIT \\$4").
+		WithArgs(babyID, cursorTime, cursor.ID, limit).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			uuid.New(), uuid.New(), babyID, "weight", 3500.0, "green", "", older, older,
+			nil, nil, nil, nil, nil, nil, nil,
+			nil, nil, nil, 3500.0, nil, nil,
+			nil, nil, nil,
+		))
+
+	result, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{Before: cursor, Limit: &limit})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.True(t, result[0].Timestamp.Equal(older))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// cancelAfterChecksContext reports itself cancelled from its checks-th Err call onwards
+// Its Done channel is nil, so database/sql never closes the rows on its own and the
+// test sees only the repository's own cancellation checks
+type cancelAfterChecksContext struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterChecksContext) Err() error {
+	c.checks--
+	if c.checks <= 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_CancelledMidScan(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	rows := sqlmock.NewRows(measurementColumns)
+	timestamp := time.Now().UTC()
+	for i := 0; i < 10000; i++ {
+		rows.AddRow(
+			uuid.New(), uuid.New(), babyID, "temperature", 37.0, "green", "", timestamp, timestamp,
+			nil, nil, nil, nil, nil, nil, nil,
+			37.0, nil, nil, nil, nil, nil,
+			nil, nil, nil,
+		)
+	}
+	mock.ExpectQuery("FROM measurements").WithArgs(babyID).WillReturnRows(rows).RowsWillBeClosed()
+
+	// The client disconnects after a few rows have been scanned
+	ctx := &cancelAfterChecksContext{Context: context.Background(), checks: 5}
+	result, err := repo.GetMeasurementsByBabyID(ctx, babyID, ports.MeasurementFilter{})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+	assert.LessOrEqual(t, ctx.checks, 0)
+	assert.Greater(t, ctx.checks, -10, "scan should stop at the next row, not read the whole result")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_TimeWindowWithTypeAndLimit(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	measurementType := "temperature"
+	shiftTZ := time.FixedZone("CET", 3600)
+	from := time.Date(2024, 1, 10, 8, 0, 0, 0, shiftTZ)
+	to := time.Date(2024, 1, 10, 20, 0, 0, 0, shiftTZ)
+	limit := 10
+
+	// Bounds are bound in UTC after the type filter and before the limit
+	mock.ExpectQuery("AND type = \\$2 AND timestamp >= \\$3 AND timestamp < \\$4 ORDER BY timestamp DESC, id DESC LIMIT \\$5").
+		WithArgs(babyID, measurementType, from.UTC(), to.UTC(), limit).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+
+	_, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{Type: &measurementType, From: &from, To: &to, Limit: &limit})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_ValueFilter(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	measurementType := "weight"
+	value := 3400.0
+	limit := 1
+
+	mock.ExpectQuery("AND type = \\$2 AND value = \\$3 ORDER BY timestamp DESC, id DESC LIMIT \\$4").
+		WithArgs(babyID, measurementType, value, limit).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+
+	_, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{Type: &measurementType, Value: &value, Limit: &limit})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_FromOnly(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	from := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("WHERE baby_id = \\$1 AND timestamp >= \\$2 ORDER BY").
+		WithArgs(babyID, from).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+
+	_, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{From: &from})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_StatementTimeoutScopedToTransaction(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 300000").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM measurements WHERE baby_id = \\$1 ORDER BY").
+		WithArgs(babyID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+	mock.ExpectRollback()
+
+	_, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{StatementTimeout: 5 * time.Minute})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetMeasurementsByBabyID_AfterCursorReturnsNewestFirst(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	cursorTime := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	cursor := &ports.MeasurementCursor{Timestamp: cursorTime, ID: uuid.New()}
+	first, second := cursorTime.Add(time.Hour), cursorTime.Add(2*time.Hour)
+
+	// Rows closest to the cursor are selected in ascending order, then returned newest first
+	mock.ExpectQuery("AND \\(timestamp, id\\) > \\(\\$2, \\$3\\) ORDER BY timestamp ASC, id ASC").
+		WithArgs(babyID, cursorTime, cursor.ID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).
+			AddRow(uuid.New(), uuid.New(), babyID, "weight", 3500.0, "green", "", first, first,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 3500.0, nil, nil, nil, nil, nil).
+			AddRow(uuid.New(), uuid.New(), babyID, "weight", 3510.0, "green", "", second, second,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 3510.0, nil, nil, nil, nil, nil))
+
+	result, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{After: cursor})
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.True(t, result[0].Timestamp.Equal(second))
+	assert.True(t, result[1].Timestamp.Equal(first))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Sleep_RoundTripsStartAndEnd(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	start := time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeSleep,
+		Value:        5400,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    start,
+		CreatedAt:    end,
+		SleepStart:   &start,
+		SleepEnd:     &end,
+	}
+
+	// sleep_start and sleep_end are the 21st and 22nd insert arguments
+	args := make([]driver.Value, 25)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[20] = start
+	args[21] = end
+	mock.ExpectExec("INSERT INTO measurements").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateMeasurement(context.Background(), measurement))
+
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1").
+		WithArgs(measurement.ID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			measurement.ID, measurement.ParentID, measurement.BabyID, "sleep", 5400.0, "green", "", start, end,
+			nil, nil, nil, nil, nil, nil, nil,
+			nil, nil, nil, nil, start, end,
+			nil, nil, nil,
+		))
+
+	result, err := repo.GetMeasurementByID(context.Background(), measurement.ID)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.SleepStart)
+	require.NotNil(t, result.SleepEnd)
+	assert.True(t, result.SleepStart.Equal(start))
+	assert.True(t, result.SleepEnd.Equal(end))
+	assert.Equal(t, 5400.0, result.Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_SpitUp_RoundTrips(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	volume := 90
+	spitUp := true
+	severity := domain.SpitUpSeverityModerate
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:             uuid.New(),
+		ParentID:       uuid.New(),
+		BabyID:         uuid.New(),
+		Type:           domain.MeasurementTypeFeeding,
+		Value:          90,
+		SafetyStatus:   domain.SafetyStatusGreen,
+		Timestamp:      now,
+		CreatedAt:      now,
+		FeedingType:    domain.FeedingTypeBottle,
+		VolumeML:       &volume,
+		SpitUp:         &spitUp,
+		SpitUpSeverity: &severity,
+	}
+
+	// spit_up and spit_up_severity are the 23rd and 24th insert arguments
+	args := make([]driver.Value, 25)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[22] = true
+	args[23] = "moderate"
+	mock.ExpectExec("INSERT INTO measurements").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateMeasurement(context.Background(), measurement))
+
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1").
+		WithArgs(measurement.ID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			measurement.ID, measurement.ParentID, measurement.BabyID, "feeding", 90.0, "green", "", now, now,
+			"bottle", 90, nil, nil, nil, nil, nil,
+			nil, nil, nil, nil, nil, nil,
+			true, "moderate", nil,
+		))
+
+	result, err := repo.GetMeasurementByID(context.Background(), measurement.ID)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.SpitUp)
+	assert.True(t, *result.SpitUp)
+	require.NotNil(t, result.SpitUpSeverity)
+	assert.Equal(t, domain.SpitUpSeverityModerate, *result.SpitUpSeverity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Metadata_RoundTripsAsIs(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	metadata := `{"study_id":"NEO-42","cohort":3}`
+	now := time.Now()
+	measurement := &domain.Measurement{
+		ID:           uuid.New(),
+		ParentID:     uuid.New(),
+		BabyID:       uuid.New(),
+		Type:         domain.MeasurementTypeHeight,
+		Value:        51,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    now,
+		CreatedAt:    now,
+		Metadata:     json.RawMessage(metadata),
+	}
+
+	// metadata is the last insert argument
+	args := make([]driver.Value, 24)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	mock.ExpectExec("INSERT INTO measurements").
+		WithArgs(append(args, metadata)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.CreateMeasurement(context.Background(), measurement))
+
+	mock.ExpectQuery("SELECT (.+) FROM measurements WHERE id = \\$1").
+		WithArgs(measurement.ID).
+		WillReturnRows(sqlmock.NewRows(measurementColumns).AddRow(
+			measurement.ID, measurement.ParentID, measurement.BabyID, "height", 51.0, "green", "", now, now,
+			nil, nil, nil, nil, nil, nil, nil,
+			nil, nil, nil, nil, nil, nil,
+			nil, nil, []byte(metadata),
+		))
+
+	result, err := repo.GetMeasurementByID(context.Background(), measurement.ID)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, metadata, string(result.Metadata))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetBusinessStats(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	since := time.Now().Add(-time.Hour)
+	// Same-timestamp readings are broken by id, like the other latest-measurement queries
+	mock.ExpectQuery("DISTINCT ON \\(m.baby_id\\) (.+) ORDER BY m.baby_id, m.timestamp DESC, m.id DESC").
+		WithArgs(since.UTC(), "red").
+		WillReturnRows(sqlmock.NewRows([]string{"active_babies", "measurements_last_hour", "active_red_alerts"}).
+			AddRow(12, 34, 2))
+
+	stats, err := repo.GetBusinessStats(context.Background(), since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, stats.ActiveBabies)
+	assert.Equal(t, 34, stats.MeasurementsLastHour)
+	assert.Equal(t, 2, stats.ActiveRedAlerts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListAssignedBabies(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+	createdAt := time.Now().UTC()
+	mock.ExpectQuery("FROM babies b\\s+WHERE b.deleted_at IS NULL\\s+AND EXISTS \\(SELECT 1 FROM nurse_assignments a WHERE a.nurse_user_id = \\$1").
+		WithArgs(nurseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_name", "room_number", "parent_user_id", "date_of_birth", "created_at"}).
+			AddRow(babyID, "Smith", "3B-01", uuid.New(), nil, createdAt))
+
+	babies, err := repo.ListAssignedBabies(context.Background(), nurseID)
+
+	require.NoError(t, err)
+	require.Len(t, babies, 1)
+	assert.Equal(t, babyID, babies[0].ID)
+	assert.Equal(t, "3B-01", babies[0].RoomNumber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_DeleteAssignment_NotFound(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	assignmentID, nurseID := uuid.New(), uuid.New()
+	mock.ExpectExec("DELETE FROM nurse_assignments WHERE id = \\$1 AND nurse_user_id = \\$2").
+		WithArgs(assignmentID, nurseID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteAssignment(context.Background(), assignmentID, nurseID)
+
+	assert.EqualError(t, err, "assignment not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetActiveAlertMute_ExpiredIsNil(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	at := time.Now()
+	mock.ExpectQuery("FROM baby_alert_mutes\\s+WHERE baby_id = \\$1 AND muted_until > \\$2").
+		WithArgs(babyID, at.UTC()).
+		WillReturnRows(sqlmock.NewRows([]string{"baby_id", "muted_until", "muted_by", "reason", "created_at"}))
+
+	mute, err := repo.GetActiveAlertMute(context.Background(), babyID, at)
+
+	require.NoError(t, err)
+	assert.Nil(t, mute)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_GetActiveAlertMute(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID, mutedBy := uuid.New(), uuid.New()
+	mutedUntil := time.Now().Add(time.Hour).UTC()
+	mock.ExpectQuery("FROM baby_alert_mutes").
+		WillReturnRows(sqlmock.NewRows([]string{"baby_id", "muted_until", "muted_by", "reason", "created_at"}).
+			AddRow(babyID, mutedUntil, mutedBy, "chronic low-grade fever", time.Now().UTC()))
+
+	mute, err := repo.GetActiveAlertMute(context.Background(), babyID, time.Now())
+
+	require.NoError(t, err)
+	require.NotNil(t, mute)
+	assert.Equal(t, mutedUntil, mute.MutedUntil)
+	assert.Equal(t, mutedBy, mute.MutedBy)
+	assert.Equal(t, "chronic low-grade fever", mute.Reason)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_RecordAudit(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	userID, babyID, measurementID := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectExec("INSERT INTO audit_log \\(id, action, user_id, baby_id, measurement_id, details, created_at\\)").
+		WithArgs(sqlmock.AnyArg(), "measurement_created", userID, babyID, measurementID, `{"type":"temperature"}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Record(context.Background(), domain.AuditActionMeasurementCreated, userID, babyID, measurementID,
+		map[string]interface{}{"type": "temperature"})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ListAuditEntries(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	babyID := uuid.New()
+	before := &ports.AuditCursor{CreatedAt: time.Now(), ID: uuid.New()}
+	entryID, userID, measurementID := uuid.New(), uuid.New(), uuid.New()
+	createdAt := time.Now().Add(-time.Hour).UTC()
+	mock.ExpectQuery("FROM audit_log\\s+WHERE baby_id = \\$1 AND \\(created_at, id\\) < \\(\\$2, \\$3\\) ORDER BY created_at DESC, id DESC LIMIT \\$4").
+		WithArgs(babyID, before.CreatedAt.UTC(), before.ID, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action", "user_id", "baby_id", "measurement_id", "details", "created_at"}).
+			AddRow(entryID, "measurement_deleted", userID, babyID, measurementID, []byte(`{"type":"weight"}`), createdAt).
+			AddRow(uuid.New(), "measurement_created", userID, babyID, measurementID, nil, createdAt.Add(-time.Hour)))
+
+	entries, err := repo.ListAuditEntries(context.Background(), babyID, before, 10)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entryID, entries[0].ID)
+	assert.Equal(t, domain.AuditActionMeasurementDeleted, entries[0].Action)
+	assert.Equal(t, userID, entries[0].UserID)
+	assert.Equal(t, measurementID, entries[0].MeasurementID)
+	assert.JSONEq(t, `{"type":"weight"}`, string(entries[0].Details))
+	assert.Nil(t, entries[1].Details)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_FindBabyByParentAndRoom_NoneIsNil(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	parentID := uuid.New()
+	mock.ExpectQuery("FROM babies\\s+WHERE parent_user_id = \\$1 AND last_name = \\$2 AND room_number = \\$3 AND deleted_at IS NULL").
+		WithArgs(parentID, "Smith", "101").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_name", "room_number", "parent_user_id", "date_of_birth", "created_at"}))
+
+	baby, err := repo.FindBabyByParentAndRoom(context.Background(), parentID, "Smith", "101")
+
+	require.NoError(t, err)
+	assert.Nil(t, baby)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CreateBabies_CommitsAllInOneTransaction(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	babies := []*domain.Baby{
+		{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), LastName: "Roe", RoomNumber: "102", ParentUserID: uuid.New(), CreatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	for _, baby := range babies {
+		mock.ExpectExec("INSERT INTO babies").
+			WithArgs(baby.ID, baby.LastName, baby.RoomNumber, baby.ParentUserID, nil, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	err := repo.CreateBabies(context.Background(), babies)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_CreateBabies_RollsBackOnFailedInsert(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	now := time.Now()
+	babies := []*domain.Baby{
+		{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), LastName: "Roe", RoomNumber: "102", ParentUserID: uuid.New(), CreatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO babies").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO babies").WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+	mock.ExpectRollback()
+
+	err := repo.CreateBabies(context.Background(), babies)
+
+	require.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newMockRepositoryWithReadReplica(t *testing.T) (*repository.SQLRepository, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	t.Helper()
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { primary.Close() })
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { replica.Close() })
+	return repository.NewSQLRepositoryWithReadReplica(primary, replica, gobreaker.Settings{}, repository.RetrySettings{}), primaryMock, replicaMock
+}
+
+func TestSQLRepository_ReadReplica_ReadQueriesUseReadPool(t *testing.T) {
+	repo, primaryMock, replicaMock := newMockRepositoryWithReadReplica(t)
+
+	parentID := uuid.New()
+	babyID := uuid.New()
+	now := time.Now()
+
+	replicaMock.ExpectQuery("SELECT id, last_name, room_number, parent_user_id, date_of_birth, created_at, deleted_at FROM babies").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_name", "room_number", "parent_user_id", "date_of_birth", "created_at", "deleted_at"}).
+			AddRow(babyID, "Doe", "101", parentID, nil, now, nil))
+	replicaMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies WHERE parent_user_id = \\$1").
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	replicaMock.ExpectQuery("SELECT").
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+
+	babies, err := repo.ListBabies(context.Background(), parentID, true, false)
+	require.NoError(t, err)
+	assert.Len(t, babies, 1)
+
+	count, err := repo.CountBabiesForParent(context.Background(), parentID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	measurements, err := repo.GetLatestMeasurements(context.Background(), babyID)
+	require.NoError(t, err)
+	assert.Empty(t, measurements)
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	// Unexpected calls fail on the mock, so nothing reached the primary
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ReadReplica_WritesAndAccessChecksUsePrimary(t *testing.T) {
+	repo, primaryMock, replicaMock := newMockRepositoryWithReadReplica(t)
+
+	parentID := uuid.New()
+	baby := &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: parentID, CreatedAt: time.Now()}
+
+	primaryMock.ExpectExec("INSERT INTO babies").WillReturnResult(sqlmock.NewResult(0, 1))
+	// A baby created a moment ago must be visible to the access check of its first measurement
+	primaryMock.ExpectQuery("SELECT parent_user_id = \\$2 FROM babies WHERE id = \\$1").
+		WithArgs(baby.ID, parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"owned"}).AddRow(true))
+
+	require.NoError(t, repo.CreateBaby(context.Background(), baby))
+	exists, owned, err := repo.GetBabyAccess(context.Background(), baby.ID, parentID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.True(t, owned)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_ReadReplica_PrimaryMeasurementFilterUsesPrimary(t *testing.T) {
+	repo, primaryMock, replicaMock := newMockRepositoryWithReadReplica(t)
+
+	babyID := uuid.New()
+	measurementType := "weight"
+	limit := 1
+
+	// The weight interval and near-duplicate checks must see a weight logged a moment ago
+	primaryMock.ExpectQuery("FROM measurements WHERE baby_id = \\$1 AND type = \\$2").
+		WithArgs(babyID, measurementType, limit).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+	replicaMock.ExpectQuery("FROM measurements WHERE baby_id = \\$1 AND type = \\$2").
+		WithArgs(babyID, measurementType, limit).
+		WillReturnRows(sqlmock.NewRows(measurementColumns))
+
+	_, err := repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{Type: &measurementType, Limit: &limit, Primary: true})
+	require.NoError(t, err)
+	_, err = repo.GetMeasurementsByBabyID(context.Background(), babyID, ports.MeasurementFilter{Type: &measurementType, Limit: &limit})
+	require.NoError(t, err)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
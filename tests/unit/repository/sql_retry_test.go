@@ -0,0 +1,259 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/IANDYI/care-service/internal/adapters/repository"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryingMockRepository(t *testing.T, retry repository.RetrySettings) (*repository.SQLRepository, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return repository.NewSQLRepositoryWithRetry(db, gobreaker.Settings{}, retry), mock
+}
+
+func TestRetrySettings_BackoffDoublesUpToCap(t *testing.T) {
+	settings := repository.RetrySettings{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, settings.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, settings.Backoff(2))
+	assert.Equal(t, 400*time.Millisecond, settings.Backoff(3))
+	assert.Equal(t, 800*time.Millisecond, settings.Backoff(4))
+	assert.Equal(t, time.Second, settings.Backoff(5))
+	assert.Equal(t, time.Second, settings.Backoff(30))
+}
+
+func TestRetrySettings_BackoffDefaults(t *testing.T) {
+	settings := repository.RetrySettings{}
+
+	assert.Equal(t, repository.DefaultRetryBaseDelay, settings.Backoff(1))
+	assert.Equal(t, repository.DefaultRetryMaxDelay, settings.Backoff(10))
+}
+
+func TestSQLRepository_Retry_FailingThenSucceeding(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 3,
+		BaseDelay:   20 * time.Millisecond,
+		MaxDelay:    time.Second,
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	start := time.Now()
+	exists, err := repo.BabyExists(context.Background(), babyID)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.True(t, exists)
+	// Waits are jittered down to half their ceiling: at least 10ms, then at least 20ms
+	assert.GreaterOrEqual(t, elapsed, 30*time.Millisecond)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.BabyExists(context.Background(), babyID)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Contains(t, err.Error(), "operation failed after 2 retries")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_CancelledContextStopsRetries(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    10 * time.Second,
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := repo.BabyExists(ctx, babyID)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Less(t, time.Since(start), time.Second)
+	// Only the first attempt reached the database
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_CancelledBeforeFirstAttempt(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.BabyExists(ctx, uuid.New())
+
+	assert.Equal(t, context.Canceled, err)
+	// No query was sent
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_SkippedWhenPastDeadline(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    10 * time.Second,
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repo.BabyExists(ctx, babyID)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Contains(t, err.Error(), "no time left to retry")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_CancellationDoesNotTripBreaker(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	repo := repository.NewSQLRepositoryWithRetry(db, gobreaker.Settings{
+		ReadyToTrip: func(counts gobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 },
+	}, repository.RetrySettings{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.BabyExists(ctx, uuid.New())
+		assert.Equal(t, context.Canceled, err, "call %d", i+1)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection failure", err: &pq.Error{Code: "08006"}, want: true},
+		{name: "connection refused", err: &pq.Error{Code: "08001"}, want: true},
+		{name: "deadlock", err: &pq.Error{Code: "40P01"}, want: true},
+		{name: "serialization failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "server restarting", err: &pq.Error{Code: "57P03"}, want: true},
+		{name: "bad connection", err: driver.ErrBadConn, want: true},
+		{name: "connection closed", err: sql.ErrConnDone, want: true},
+		{name: "unexpected EOF", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "check violation", err: &pq.Error{Code: "23514"}, want: false},
+		{name: "invalid input", err: &pq.Error{Code: "22P02"}, want: false},
+		{name: "syntax error", err: &pq.Error{Code: "42601"}, want: false},
+		{name: "no rows", err: fmt.Errorf("lookup: %w", sql.ErrNoRows), want: false},
+		{name: "conflict", err: domain.ErrConflict, want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "unknown error", err: errors.New("sql: Scan error on column index 0"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repository.IsRetryableError(tt.err))
+		})
+	}
+}
+
+func TestSQLRepository_Retry_ConstraintViolationNotRetried(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Second,
+	})
+
+	babyID := uuid.New()
+	violation := &pq.Error{Code: "23503", Message: "insert or update violates foreign key constraint"}
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(violation)
+
+	start := time.Now()
+	_, err := repo.BabyExists(context.Background(), babyID)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, violation)
+	assert.NotContains(t, err.Error(), "operation failed after")
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "failed without waiting for a retry")
+	// Only one attempt reached the database
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_ConnectionErrorRetried(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).
+		WillReturnError(&pq.Error{Code: "08006", Message: "connection failure"})
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).
+		WillReturnError(&pq.Error{Code: "40P01", Message: "deadlock detected"})
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	exists, err := repo.BabyExists(context.Background(), babyID)
+
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLRepository_Retry_CustomClassification(t *testing.T) {
+	repo, mock := newRetryingMockRepository(t, repository.RetrySettings{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Retryable:   func(err error) bool { return false },
+	})
+
+	babyID := uuid.New()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM babies").WithArgs(babyID).WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.BabyExists(context.Background(), babyID)
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
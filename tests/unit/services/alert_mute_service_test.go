@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAlertMuteRepository is a mock implementation of AlertMuteRepository
+type MockAlertMuteRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertMuteRepository) UpsertAlertMute(ctx context.Context, mute *domain.AlertMute) error {
+	args := m.Called(ctx, mute)
+	return args.Error(0)
+}
+
+func (m *MockAlertMuteRepository) GetActiveAlertMute(ctx context.Context, babyID uuid.UUID, at time.Time) (*domain.AlertMute, error) {
+	args := m.Called(ctx, babyID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AlertMute), args.Error(1)
+}
+
+func TestAlertMuteService_MuteAlerts_Owner(t *testing.T) {
+	mockMutes := new(MockAlertMuteRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	muteService := services.NewAlertMuteService(mockMutes, mockBabyRepo)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMutes.On("UpsertAlertMute", mock.Anything, mock.AnythingOfType("*domain.AlertMute")).Return(nil)
+
+	before := time.Now()
+	mute, err := muteService.MuteAlerts(context.Background(), babyID, 2*time.Hour, " chronic low-grade fever ", userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, babyID, mute.BabyID)
+	assert.Equal(t, userID, mute.MutedBy)
+	assert.Equal(t, "chronic low-grade fever", mute.Reason)
+	assert.WithinDuration(t, before.Add(2*time.Hour), mute.MutedUntil, time.Second)
+	mockMutes.AssertExpectations(t)
+}
+
+func TestAlertMuteService_MuteAlerts_AdminAnyBaby(t *testing.T) {
+	mockMutes := new(MockAlertMuteRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	muteService := services.NewAlertMuteService(mockMutes, mockBabyRepo)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+	mockMutes.On("UpsertAlertMute", mock.Anything, mock.AnythingOfType("*domain.AlertMute")).Return(nil)
+
+	_, err := muteService.MuteAlerts(context.Background(), babyID, time.Hour, "", userID, domain.RoleAdmin)
+
+	require.NoError(t, err)
+	mockMutes.AssertExpectations(t)
+}
+
+func TestAlertMuteService_MuteAlerts_Forbidden(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    domain.Role
+		owned   bool
+		wantErr string
+	}{
+		{name: "nurse", role: domain.RoleNurse, owned: false, wantErr: "forbidden: only the baby's parent or ADMIN can mute alerts"},
+		{name: "other parent", role: domain.RoleParent, owned: false, wantErr: "baby not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMutes := new(MockAlertMuteRepository)
+			mockBabyRepo := new(MockBabyRepository)
+			muteService := services.NewAlertMuteService(mockMutes, mockBabyRepo)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, tt.owned, nil)
+
+			_, err := muteService.MuteAlerts(context.Background(), babyID, time.Hour, "", userID, tt.role)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockMutes.AssertNotCalled(t, "UpsertAlertMute", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAlertMuteService_MuteAlerts_InvalidDuration(t *testing.T) {
+	mockMutes := new(MockAlertMuteRepository)
+	muteService := services.NewAlertMuteService(mockMutes, new(MockBabyRepository))
+
+	for _, duration := range []time.Duration{0, -time.Hour, services.MaxAlertMuteDuration + time.Minute} {
+		_, err := muteService.MuteAlerts(context.Background(), uuid.New(), duration, "", uuid.New(), domain.RoleAdmin)
+
+		require.Error(t, err)
+		assert.Equal(t, "duration_minutes must be between 1 and 10080", err.Error())
+	}
+	mockMutes.AssertNotCalled(t, "UpsertAlertMute", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_MutedBabyNotBroadcast(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	mockMutes := new(MockAlertMuteRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{AlertMutes: mockMutes})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	checked := make(chan struct{}, 1)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockMutes.On("GetActiveAlertMute", mock.Anything, babyID, mock.Anything).
+		Return(&domain.AlertMute{BabyID: babyID, MutedUntil: time.Now().Add(time.Hour)}, nil).
+		Run(func(args mock.Arguments) { checked <- struct{}{} })
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0}, userID, domain.RoleParent)
+
+	// The measurement is still stored and classified
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	mockMeasurementRepo.AssertExpectations(t)
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("alert mute was not checked")
+	}
+	time.Sleep(50 * time.Millisecond)
+	mockAlertPublisher.AssertNotCalled(t, "PublishAlert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_MuteLookupFailurePublishes(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	mockMutes := new(MockAlertMuteRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{AlertMutes: mockMutes})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	published := make(chan struct{}, 1)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockMutes.On("GetActiveAlertMute", mock.Anything, babyID, mock.Anything).Return(nil, errors.New("connection refused"))
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) { published <- struct{}{} })
+
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("alert not published after a failed mute lookup")
+	}
+}
@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedAlertPublisher holds every publish until its gate is closed and records how many ran at once
+type gatedAlertPublisher struct {
+	gate      chan struct{}
+	started   chan struct{}
+	inFlight  atomic.Int32
+	maxFlight atomic.Int32
+	published atomic.Int32
+}
+
+func newGatedAlertPublisher() *gatedAlertPublisher {
+	return &gatedAlertPublisher{gate: make(chan struct{}), started: make(chan struct{}, 100)}
+}
+
+func (p *gatedAlertPublisher) PublishAlert(ctx context.Context, babyID uuid.UUID, measurement *domain.Measurement) error {
+	n := p.inFlight.Add(1)
+	for {
+		peak := p.maxFlight.Load()
+		if n <= peak || p.maxFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	p.started <- struct{}{}
+	<-p.gate
+	p.inFlight.Add(-1)
+	p.published.Add(1)
+	return nil
+}
+
+func TestMeasurementService_CreateMeasurement_AlertStormUsesBoundedWorkers(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	publisher := newGatedAlertPublisher()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, publisher,
+		services.MeasurementServiceConfig{AlertWorkers: 3, AlertQueueSize: 50})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	// A monitor glitch: many red readings at once
+	const storm = 30
+	var wg sync.WaitGroup
+	for i := 0; i < storm; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: 39.5, Note: "Monitor glitch"}, userID, domain.RoleParent)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Creates return while their alerts wait; only the workers publish
+	for i := 0; i < 3; i++ {
+		<-publisher.started
+	}
+	assert.Equal(t, int32(3), publisher.inFlight.Load())
+
+	close(publisher.gate)
+	measurementService.Close()
+
+	assert.Equal(t, int32(storm), publisher.published.Load())
+	assert.Equal(t, int32(3), publisher.maxFlight.Load())
+}
+
+func TestMeasurementService_CreateMeasurement_FullAlertQueueDropsAlerts(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	publisher := newGatedAlertPublisher()
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, publisher,
+		services.MeasurementServiceConfig{
+			AlertWorkers:   1,
+			AlertQueueSize: 2,
+			Metrics:        middleware.NewMeasurementMetricsCollector(registry),
+		})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	create := func() {
+		_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+			ports.CreateMeasurementRequest{Type: "temperature", Value: 39.5, Note: "Fever"}, userID, domain.RoleParent)
+		require.NoError(t, err)
+	}
+
+	// The only worker is busy with the first alert, two more fill the queue, the last two are dropped
+	create()
+	<-publisher.started
+	for i := 0; i < 4; i++ {
+		create()
+	}
+
+	expected := `
+# HELP alerts_dropped_total Total number of alerts not published because the background alert queue was full
+# TYPE alerts_dropped_total counter
+alerts_dropped_total{type="temperature"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "alerts_dropped_total"))
+
+	// Dropping never fails the create, and everything queued is still published on Close
+	close(publisher.gate)
+	measurementService.Close()
+	assert.Equal(t, int32(3), publisher.published.Load())
+	mockMeasurementRepo.AssertNumberOfCalls(t, "CreateMeasurement", 5)
+}
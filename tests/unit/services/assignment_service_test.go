@@ -0,0 +1,208 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAssignmentRepository is a mock implementation of AssignmentRepository
+type MockAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentRepository) CreateAssignment(ctx context.Context, assignment *domain.NurseAssignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) ListAssignments(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.NurseAssignment, error) {
+	args := m.Called(ctx, nurseUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NurseAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) DeleteAssignment(ctx context.Context, assignmentID uuid.UUID, nurseUserID uuid.UUID) error {
+	args := m.Called(ctx, assignmentID, nurseUserID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) ListAssignedBabies(ctx context.Context, nurseUserID uuid.UUID) ([]*domain.Baby, error) {
+	args := m.Called(ctx, nurseUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Baby), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) IsBabyAssigned(ctx context.Context, babyID uuid.UUID, nurseUserID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, babyID, nurseUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAssignmentService_CreateAssignment_Baby(t *testing.T) {
+	mockAssignments := new(MockAssignmentRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	assignmentService := services.NewAssignmentService(mockAssignments, mockBabyRepo)
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
+	mockAssignments.On("CreateAssignment", mock.Anything, mock.MatchedBy(func(a *domain.NurseAssignment) bool {
+		return a.NurseUserID == nurseID && a.BabyID != nil && *a.BabyID == babyID && a.RoomPrefix == nil
+	})).Return(nil)
+
+	assignment, err := assignmentService.CreateAssignment(context.Background(), nurseID, &babyID, "", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, assignment.ID)
+	mockAssignments.AssertExpectations(t)
+}
+
+func TestAssignmentService_CreateAssignment_RoomPrefixTrimmed(t *testing.T) {
+	mockAssignments := new(MockAssignmentRepository)
+	assignmentService := services.NewAssignmentService(mockAssignments, new(MockBabyRepository))
+
+	mockAssignments.On("CreateAssignment", mock.Anything, mock.MatchedBy(func(a *domain.NurseAssignment) bool {
+		return a.BabyID == nil && a.RoomPrefix != nil && *a.RoomPrefix == "3B"
+	})).Return(nil)
+
+	_, err := assignmentService.CreateAssignment(context.Background(), uuid.New(), nil, " 3B ", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	mockAssignments.AssertExpectations(t)
+}
+
+func TestAssignmentService_CreateAssignment_Validation(t *testing.T) {
+	assignmentService := services.NewAssignmentService(new(MockAssignmentRepository), new(MockBabyRepository))
+	babyID := uuid.New()
+
+	// Neither target
+	_, err := assignmentService.CreateAssignment(context.Background(), uuid.New(), nil, "  ", domain.RoleAdmin)
+	assert.EqualError(t, err, "exactly one of baby_id or room_prefix is required")
+
+	// Both targets
+	_, err = assignmentService.CreateAssignment(context.Background(), uuid.New(), &babyID, "3B", domain.RoleAdmin)
+	assert.EqualError(t, err, "exactly one of baby_id or room_prefix is required")
+
+	// Only ADMIN manages assignments
+	_, err = assignmentService.CreateAssignment(context.Background(), uuid.New(), nil, "3B", domain.RoleNurse)
+	assert.EqualError(t, err, "forbidden: only ADMIN can manage assignments")
+}
+
+func TestAssignmentService_CreateAssignment_BabyNotFound(t *testing.T) {
+	mockAssignments := new(MockAssignmentRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	assignmentService := services.NewAssignmentService(mockAssignments, mockBabyRepo)
+
+	babyID := uuid.New()
+	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(false, nil)
+
+	_, err := assignmentService.CreateAssignment(context.Background(), uuid.New(), &babyID, "", domain.RoleAdmin)
+
+	assert.EqualError(t, err, "baby not found")
+	mockAssignments.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_ListAssignedBabies_NurseOnly(t *testing.T) {
+	mockAssignments := new(MockAssignmentRepository)
+	assignmentService := services.NewAssignmentService(mockAssignments, new(MockBabyRepository))
+
+	nurseID := uuid.New()
+	assigned := []*domain.Baby{{ID: uuid.New(), RoomNumber: "3B-01"}}
+	mockAssignments.On("ListAssignedBabies", mock.Anything, nurseID).Return(assigned, nil)
+
+	babies, err := assignmentService.ListAssignedBabies(context.Background(), nurseID, domain.RoleNurse)
+	require.NoError(t, err)
+	assert.Equal(t, assigned, babies)
+
+	_, err = assignmentService.ListAssignedBabies(context.Background(), uuid.New(), domain.RoleAdmin)
+	assert.EqualError(t, err, "forbidden: only NURSE has assignments")
+}
+
+func TestAssignmentService_DeleteAssignment_NotFound(t *testing.T) {
+	mockAssignments := new(MockAssignmentRepository)
+	assignmentService := services.NewAssignmentService(mockAssignments, new(MockBabyRepository))
+
+	assignmentID, nurseID := uuid.New(), uuid.New()
+	mockAssignments.On("DeleteAssignment", mock.Anything, assignmentID, nurseID).Return(errors.New("assignment not found"))
+
+	err := assignmentService.DeleteAssignment(context.Background(), assignmentID, nurseID, domain.RoleAdmin)
+	assert.EqualError(t, err, "assignment not found")
+}
+
+func TestBabyService_EnforcedAssignments_NurseSeesOnlyAssignedBabies(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	mockAssignments := new(MockAssignmentRepository)
+	babyService := services.NewBabyServiceWithAssignments(mockRepo, mockAssignments)
+
+	nurseID := uuid.New()
+	assignedBaby := &domain.Baby{ID: uuid.New(), RoomNumber: "3B-01", ParentUserID: uuid.New()}
+	otherBaby := &domain.Baby{ID: uuid.New(), RoomNumber: "4A-02", ParentUserID: uuid.New()}
+
+	mockAssignments.On("ListAssignedBabies", mock.Anything, nurseID).Return([]*domain.Baby{assignedBaby}, nil)
+	mockAssignments.On("IsBabyAssigned", mock.Anything, assignedBaby.ID, nurseID).Return(true, nil)
+	mockAssignments.On("IsBabyAssigned", mock.Anything, otherBaby.ID, nurseID).Return(false, nil)
+	mockRepo.On("GetBabyByID", mock.Anything, assignedBaby.ID).Return(assignedBaby, nil)
+	mockRepo.On("GetBabyByID", mock.Anything, otherBaby.ID).Return(otherBaby, nil)
+
+	babies, err := babyService.ListBabies(context.Background(), nurseID, domain.RoleNurse, false)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Baby{assignedBaby}, babies)
+	mockRepo.AssertNotCalled(t, "ListBabies", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	baby, err := babyService.GetBaby(context.Background(), assignedBaby.ID, nurseID, domain.RoleNurse)
+	require.NoError(t, err)
+	assert.Equal(t, assignedBaby.ID, baby.ID)
+
+	// Unassigned babies look the same as missing ones
+	_, err = babyService.GetBaby(context.Background(), otherBaby.ID, nurseID, domain.RoleNurse)
+	assert.EqualError(t, err, "baby not found")
+}
+
+func TestBabyService_EnforcedAssignments_AdminUnaffected(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	mockAssignments := new(MockAssignmentRepository)
+	babyService := services.NewBabyServiceWithAssignments(mockRepo, mockAssignments)
+
+	baby := &domain.Baby{ID: uuid.New(), RoomNumber: "4A-02"}
+	mockRepo.On("GetBabyByID", mock.Anything, baby.ID).Return(baby, nil)
+
+	_, err := babyService.GetBaby(context.Background(), baby.ID, uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	mockAssignments.AssertNotCalled(t, "IsBabyAssigned", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_EnforcedAssignments_NurseReadsOnlyAssignedBabies(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAssignments := new(MockAssignmentRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{NurseAssignments: mockAssignments})
+
+	nurseID := uuid.New()
+	assignedBabyID, otherBabyID := uuid.New(), uuid.New()
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, mock.Anything, nurseID).Return(true, false, nil)
+	mockAssignments.On("IsBabyAssigned", mock.Anything, assignedBabyID, nurseID).Return(true, nil)
+	mockAssignments.On("IsBabyAssigned", mock.Anything, otherBabyID, nurseID).Return(false, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, assignedBabyID, mock.Anything).Return([]*domain.Measurement{}, nil)
+
+	_, err := measurementService.GetMeasurements(context.Background(), assignedBabyID, nurseID, domain.RoleNurse, ports.MeasurementFilter{})
+	require.NoError(t, err)
+
+	_, err = measurementService.GetMeasurements(context.Background(), otherBabyID, nurseID, domain.RoleNurse, ports.MeasurementFilter{})
+	assert.EqualError(t, err, "baby not found")
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementsByBabyID", mock.Anything, otherBabyID, mock.Anything)
+}
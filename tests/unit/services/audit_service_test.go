@@ -0,0 +1,253 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Record(ctx context.Context, action domain.AuditAction, userID uuid.UUID, babyID uuid.UUID, measurementID uuid.UUID, details map[string]interface{}) error {
+	args := m.Called(ctx, action, userID, babyID, measurementID, details)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) ListAuditEntries(ctx context.Context, babyID uuid.UUID, before *ports.AuditCursor, limit int) ([]*domain.AuditEntry, error) {
+	args := m.Called(ctx, babyID, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AuditEntry), args.Error(1)
+}
+
+func TestMeasurementService_CreateMeasurement_RecordsOneAuditEntry(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAudit := new(MockAuditRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Audit: mockAudit})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAudit.On("Record", mock.Anything, domain.AuditActionMeasurementCreated, userID, babyID, mock.Anything, mock.Anything).Return(nil)
+
+	measurement, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	mockAudit.AssertNumberOfCalls(t, "Record", 1)
+	mockAudit.AssertCalled(t, "Record", mock.Anything, domain.AuditActionMeasurementCreated, userID, babyID, measurement.ID, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_RejectedIsNotAudited(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAudit := new(MockAuditRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Audit: mockAudit})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(errors.New("connection refused"))
+
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
+
+	require.Error(t, err)
+	mockAudit.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_AuditFailureDoesNotFailMutation(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAudit := new(MockAuditRepository)
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Audit: mockAudit, Metrics: middleware.NewMeasurementMetricsCollector(registry)})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	measurementID := uuid.New()
+	existing := &domain.Measurement{
+		ID:           measurementID,
+		ParentID:     userID,
+		BabyID:       babyID,
+		Type:         "temperature",
+		Value:        37.0,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    time.Now(),
+		CreatedAt:    time.Now(),
+	}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(existing, nil)
+	mockMeasurementRepo.On("UpdateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, measurementID, userID).Return(existing, nil)
+	mockAudit.On("Record", mock.Anything, mock.Anything, userID, babyID, mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	note := "after bath"
+	_, err = measurementService.UpdateMeasurement(context.Background(), measurementID, ports.UpdateMeasurementRequest{Note: &note}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	_, err = measurementService.DeleteMeasurement(context.Background(), measurementID, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	expected := `
+# HELP audit_write_failures_total Total number of measurement audit entries that could not be written, by action
+# TYPE audit_write_failures_total counter
+audit_write_failures_total{action="measurement_created"} 1
+audit_write_failures_total{action="measurement_deleted"} 1
+audit_write_failures_total{action="measurement_updated"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "audit_write_failures_total"))
+}
+
+func TestMeasurementService_UpdateMeasurement_AuditsChangedFields(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAudit := new(MockAuditRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Audit: mockAudit})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	measurementID := uuid.New()
+	existing := &domain.Measurement{
+		ID:           measurementID,
+		ParentID:     userID,
+		BabyID:       babyID,
+		Type:         "temperature",
+		Value:        37.0,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Note:         "private context",
+		Timestamp:    time.Now(),
+		CreatedAt:    time.Now(),
+	}
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(existing, nil)
+	mockMeasurementRepo.On("UpdateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAudit.On("Record", mock.Anything, domain.AuditActionMeasurementUpdated, userID, babyID, measurementID,
+		map[string]interface{}{"fields": []string{"note"}}).Return(nil)
+
+	note := "after bath"
+	_, err := measurementService.UpdateMeasurement(context.Background(), measurementID, ports.UpdateMeasurementRequest{Note: &note}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditEntries_NextCursor(t *testing.T) {
+	mockAudit := new(MockAuditRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	auditService := services.NewAuditService(mockAudit, mockBabyRepo, nil)
+
+	babyID := uuid.New()
+	now := time.Now()
+	entries := []*domain.AuditEntry{
+		{ID: uuid.New(), BabyID: babyID, CreatedAt: now},
+		{ID: uuid.New(), BabyID: babyID, CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), BabyID: babyID, CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	mockBabyRepo.On("BabyRecordExists", mock.Anything, babyID).Return(true, nil)
+	// One extra entry is fetched to detect the next page
+	mockAudit.On("ListAuditEntries", mock.Anything, babyID, (*ports.AuditCursor)(nil), 3).Return(entries, nil)
+
+	page, next, err := auditService.ListAuditEntries(context.Background(), babyID, nil, 2, uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, entries[:2], page)
+	require.NotNil(t, next)
+	assert.Equal(t, entries[1].ID, next.ID)
+	assert.True(t, entries[1].CreatedAt.Equal(next.CreatedAt))
+}
+
+func TestAuditService_ListAuditEntries_LastPage(t *testing.T) {
+	mockAudit := new(MockAuditRepository)
+	mockBabyRepo := new(MockBabyRepository)
+	auditService := services.NewAuditService(mockAudit, mockBabyRepo, nil)
+
+	babyID := uuid.New()
+	before := &ports.AuditCursor{CreatedAt: time.Now(), ID: uuid.New()}
+
+	mockBabyRepo.On("BabyRecordExists", mock.Anything, babyID).Return(true, nil)
+	mockAudit.On("ListAuditEntries", mock.Anything, babyID, before, 51).Return(nil, nil)
+
+	page, next, err := auditService.ListAuditEntries(context.Background(), babyID, before, 50, uuid.New(), domain.RoleNurse)
+
+	require.NoError(t, err)
+	assert.NotNil(t, page)
+	assert.Empty(t, page)
+	assert.Nil(t, next)
+}
+
+func TestAuditService_ListAuditEntries_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     domain.Role
+		limit    int
+		exists   bool
+		assigned bool
+		wantErr  string
+	}{
+		{name: "parent", role: domain.RoleParent, limit: 50, exists: true, assigned: true, wantErr: "forbidden: only ADMIN and NURSE can read the audit log"},
+		{name: "zero limit", role: domain.RoleAdmin, limit: 0, exists: true, assigned: true, wantErr: "limit must be between 1 and 200"},
+		{name: "limit too large", role: domain.RoleAdmin, limit: services.MaxAuditPageSize + 1, exists: true, assigned: true, wantErr: "limit must be between 1 and 200"},
+		{name: "missing baby", role: domain.RoleAdmin, limit: 50, exists: false, assigned: true, wantErr: "baby not found"},
+		{name: "unassigned nurse", role: domain.RoleNurse, limit: 50, exists: true, assigned: false, wantErr: "baby not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAudit := new(MockAuditRepository)
+			mockBabyRepo := new(MockBabyRepository)
+			mockAssignments := new(MockAssignmentRepository)
+			auditService := services.NewAuditService(mockAudit, mockBabyRepo, mockAssignments)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("BabyRecordExists", mock.Anything, babyID).Return(tt.exists, nil)
+			mockAssignments.On("IsBabyAssigned", mock.Anything, babyID, userID).Return(tt.assigned, nil)
+
+			_, _, err := auditService.ListAuditEntries(context.Background(), babyID, nil, tt.limit, userID, tt.role)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockAudit.AssertNotCalled(t, "ListAuditEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
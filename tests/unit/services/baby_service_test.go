@@ -2,12 +2,17 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/adapters/repository"
 	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/IANDYI/care-service/internal/core/services"
 	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -23,6 +28,11 @@ func (m *MockBabyRepository) CreateBaby(ctx context.Context, baby *domain.Baby)
 	return args.Error(0)
 }
 
+func (m *MockBabyRepository) CreateBabies(ctx context.Context, babies []*domain.Baby) error {
+	args := m.Called(ctx, babies)
+	return args.Error(0)
+}
+
 func (m *MockBabyRepository) GetBabyByID(ctx context.Context, babyID uuid.UUID) (*domain.Baby, error) {
 	args := m.Called(ctx, babyID)
 	if args.Get(0) == nil {
@@ -31,24 +41,70 @@ func (m *MockBabyRepository) GetBabyByID(ctx context.Context, babyID uuid.UUID)
 	return args.Get(0).(*domain.Baby), args.Error(1)
 }
 
-func (m *MockBabyRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool) ([]*domain.Baby, error) {
-	args := m.Called(ctx, parentUserID, isAdmin)
+func (m *MockBabyRepository) FindBabyByParentAndRoom(ctx context.Context, parentUserID uuid.UUID, lastName string, roomNumber string) (*domain.Baby, error) {
+	args := m.Called(ctx, parentUserID, lastName, roomNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyRepository) ListBabiesByRoomPrefix(ctx context.Context, roomPrefix string) ([]*domain.Baby, error) {
+	args := m.Called(ctx, roomPrefix)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Baby), args.Error(1)
 }
 
+func (m *MockBabyRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool, includeDeleted bool) ([]*domain.Baby, error) {
+	args := m.Called(ctx, parentUserID, isAdmin, includeDeleted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyRepository) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth *time.Time) error {
+	args := m.Called(ctx, babyID, lastName, roomNumber, dateOfBirth)
+	return args.Error(0)
+}
+
+func (m *MockBabyRepository) DeleteBaby(ctx context.Context, babyID uuid.UUID) error {
+	args := m.Called(ctx, babyID)
+	return args.Error(0)
+}
+
 func (m *MockBabyRepository) BabyExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
 	args := m.Called(ctx, babyID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBabyRepository) BabyRecordExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, babyID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBabyRepository) CountBabiesForParent(ctx context.Context, parentUserID uuid.UUID) (int, error) {
+	args := m.Called(ctx, parentUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBabyRepository) CountRedMeasurementsForParent(ctx context.Context, parentUserID uuid.UUID, since time.Time) (int, error) {
+	args := m.Called(ctx, parentUserID, since)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockBabyRepository) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error) {
 	args := m.Called(ctx, babyID, parentUserID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBabyRepository) GetBabyAccess(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, bool, error) {
+	args := m.Called(ctx, babyID, parentUserID)
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
 func TestNewBabyService(t *testing.T) {
 	mockRepo := new(MockBabyRepository)
 	babyService := services.NewBabyService(mockRepo)
@@ -66,7 +122,7 @@ func TestBabyService_CreateBaby_Success(t *testing.T) {
 		return b.LastName == "Doe" && b.RoomNumber == "101" && b.ParentUserID == parentUserID
 	})).Return(nil)
 
-	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", parentUserID, createdByUserID, true)
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", parentUserID, "", createdByUserID, domain.RoleAdmin)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -76,6 +132,39 @@ func TestBabyService_CreateBaby_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBabyService_CreateBaby_WithDateOfBirth(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	dateOfBirth := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("CreateBaby", mock.Anything, mock.MatchedBy(func(b *domain.Baby) bool {
+		return b.DateOfBirth != nil && b.DateOfBirth.Equal(dateOfBirth)
+	})).Return(nil)
+
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", uuid.New(), "2024-03-01", uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.DateOfBirth)
+	assert.True(t, result.DateOfBirth.Equal(dateOfBirth))
+	require.NotNil(t, result.AgeDays)
+	assert.Equal(t, domain.AgeInDays(dateOfBirth, time.Now()), *result.AgeDays)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_CreateBaby_InvalidDateOfBirth(t *testing.T) {
+	for _, dateOfBirth := range []string{"01.03.2024", "2024-02-30", time.Now().AddDate(0, 0, 2).Format("2006-01-02")} {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		result, err := babyService.CreateBaby(context.Background(), "Doe", "101", uuid.New(), dateOfBirth, uuid.New(), domain.RoleAdmin)
+
+		require.Error(t, err, dateOfBirth)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "invalid date_of_birth")
+		mockRepo.AssertNotCalled(t, "CreateBaby")
+	}
+}
+
 func TestBabyService_CreateBaby_Forbidden(t *testing.T) {
 	mockRepo := new(MockBabyRepository)
 	babyService := services.NewBabyService(mockRepo)
@@ -83,7 +172,7 @@ func TestBabyService_CreateBaby_Forbidden(t *testing.T) {
 	parentUserID := uuid.New()
 	createdByUserID := uuid.New()
 
-	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", parentUserID, createdByUserID, false)
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", parentUserID, "", createdByUserID, domain.RoleParent)
 	
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -91,6 +180,18 @@ func TestBabyService_CreateBaby_Forbidden(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "CreateBaby")
 }
 
+func TestBabyService_CreateBaby_Forbidden_Nurse(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "101", uuid.New(), "", uuid.New(), domain.RoleNurse)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "forbidden: only ADMIN can create babies", err.Error())
+	mockRepo.AssertNotCalled(t, "CreateBaby")
+}
+
 func TestBabyService_CreateBaby_EmptyLastName(t *testing.T) {
 	mockRepo := new(MockBabyRepository)
 	babyService := services.NewBabyService(mockRepo)
@@ -98,7 +199,7 @@ func TestBabyService_CreateBaby_EmptyLastName(t *testing.T) {
 	parentUserID := uuid.New()
 	createdByUserID := uuid.New()
 
-	result, err := babyService.CreateBaby(context.Background(), "", "101", parentUserID, createdByUserID, true)
+	result, err := babyService.CreateBaby(context.Background(), "", "101", parentUserID, "", createdByUserID, domain.RoleAdmin)
 	
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -113,7 +214,7 @@ func TestBabyService_CreateBaby_EmptyRoomNumber(t *testing.T) {
 	parentUserID := uuid.New()
 	createdByUserID := uuid.New()
 
-	result, err := babyService.CreateBaby(context.Background(), "Doe", "", parentUserID, createdByUserID, true)
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "", parentUserID, "", createdByUserID, domain.RoleAdmin)
 	
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -137,10 +238,9 @@ func TestBabyService_GetBaby_Success_Admin(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
 	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(expectedBaby, nil)
 
-	result, err := babyService.GetBaby(context.Background(), babyID, userID, true)
+	result, err := babyService.GetBaby(context.Background(), babyID, userID, domain.RoleAdmin)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -163,11 +263,9 @@ func TestBabyService_GetBaby_Success_Parent(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	mockRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(true, nil)
 	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(expectedBaby, nil)
 
-	result, err := babyService.GetBaby(context.Background(), babyID, userID, false)
+	result, err := babyService.GetBaby(context.Background(), babyID, userID, domain.RoleParent)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -182,14 +280,13 @@ func TestBabyService_GetBaby_NotFound(t *testing.T) {
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockRepo.On("BabyExists", mock.Anything, babyID).Return(false, nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(nil, fmt.Errorf("baby not found"))
 
-	result, err := babyService.GetBaby(context.Background(), babyID, userID, true)
+	result, err := babyService.GetBaby(context.Background(), babyID, userID, domain.RoleAdmin)
 	
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "baby not found")
-	mockRepo.AssertNotCalled(t, "GetBabyByID")
+	assert.Equal(t, "baby not found", err.Error())
 }
 
 func TestBabyService_GetBaby_NotOwned(t *testing.T) {
@@ -199,15 +296,40 @@ func TestBabyService_GetBaby_NotOwned(t *testing.T) {
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(false, nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{
+		ID:           babyID,
+		LastName:     "Doe",
+		RoomNumber:   "101",
+		ParentUserID: uuid.New(),
+		CreatedAt:    time.Now(),
+	}, nil)
 
-	result, err := babyService.GetBaby(context.Background(), babyID, userID, false)
+	result, err := babyService.GetBaby(context.Background(), babyID, userID, domain.RoleParent)
 	
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "baby not found")
-	mockRepo.AssertNotCalled(t, "GetBabyByID")
+	assert.Equal(t, "baby not found", err.Error())
+}
+
+func TestBabyService_GetBaby_Nurse_ReadsAnyBaby(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{
+		ID:           babyID,
+		LastName:     "Doe",
+		RoomNumber:   "101",
+		ParentUserID: uuid.New(),
+		CreatedAt:    time.Now(),
+	}, nil)
+
+	result, err := babyService.GetBaby(context.Background(), babyID, nurseID, domain.RoleNurse)
+
+	require.NoError(t, err)
+	assert.Equal(t, babyID, result.ID)
 }
 
 func TestBabyService_ListBabies_Success_Admin(t *testing.T) {
@@ -233,9 +355,9 @@ func TestBabyService_ListBabies_Success_Admin(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("ListBabies", mock.Anything, uuid.Nil, true).Return(expectedBabies, nil)
+	mockRepo.On("ListBabies", mock.Anything, uuid.Nil, true, false).Return(expectedBabies, nil)
 
-	result, err := babyService.ListBabies(context.Background(), userID, true)
+	result, err := babyService.ListBabies(context.Background(), userID, domain.RoleAdmin, false)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -259,12 +381,573 @@ func TestBabyService_ListBabies_Success_Parent(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("ListBabies", mock.Anything, userID, false).Return(expectedBabies, nil)
+	mockRepo.On("ListBabies", mock.Anything, userID, false, false).Return(expectedBabies, nil)
 
-	result, err := babyService.ListBabies(context.Background(), userID, false)
+	result, err := babyService.ListBabies(context.Background(), userID, domain.RoleParent, false)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Len(t, result, 1)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestBabyService_ListBabies_Nurse_ListsAll(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	expectedBabies := []*domain.Baby{
+		{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: uuid.New(), CreatedAt: time.Now()},
+	}
+
+	mockRepo.On("ListBabies", mock.Anything, uuid.Nil, true, false).Return(expectedBabies, nil)
+
+	result, err := babyService.ListBabies(context.Background(), uuid.New(), domain.RoleNurse, false)
+
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_RoomOnly(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "", "204", (*time.Time)(nil)).Return(nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).
+		Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "204"}, nil)
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "", "204", "", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Doe", result.LastName)
+	assert.Equal(t, "204", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_DateOfBirthOnly(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	dateOfBirth := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "", "", &dateOfBirth).Return(nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).
+		Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "204", DateOfBirth: &dateOfBirth}, nil)
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "", "", "2024-03-01", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.DateOfBirth)
+	assert.True(t, result.DateOfBirth.Equal(dateOfBirth))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_AllFields(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "Smith", "204", (*time.Time)(nil)).Return(nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).
+		Return(&domain.Baby{ID: babyID, LastName: "Smith", RoomNumber: "204"}, nil)
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "Smith", "204", "", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Smith", result.LastName)
+	assert.Equal(t, "204", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_Forbidden(t *testing.T) {
+	for _, role := range []domain.Role{domain.RoleParent, domain.RoleNurse} {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		result, err := babyService.UpdateBaby(context.Background(), uuid.New(), "Smith", "204", "", role)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "forbidden: only ADMIN can update babies", string(role))
+		mockRepo.AssertNotCalled(t, "UpdateBaby")
+	}
+}
+
+func TestBabyService_UpdateBaby_NoFields(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	result, err := babyService.UpdateBaby(context.Background(), uuid.New(), "", "", "", domain.RoleAdmin)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "at least one of last_name, room_number or date_of_birth is required")
+	mockRepo.AssertNotCalled(t, "UpdateBaby")
+}
+
+func TestBabyService_UpdateBaby_NotFound(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "", "204", (*time.Time)(nil)).Return(fmt.Errorf("baby not found"))
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "", "204", "", domain.RoleAdmin)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "baby not found")
+	mockRepo.AssertNotCalled(t, "GetBabyByID")
+}
+
+func TestBabyService_DeleteBaby_Forbidden(t *testing.T) {
+	for _, role := range []domain.Role{domain.RoleParent, domain.RoleNurse} {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		err := babyService.DeleteBaby(context.Background(), uuid.New(), role)
+
+		assert.EqualError(t, err, "forbidden: only ADMIN can delete babies", string(role))
+		mockRepo.AssertNotCalled(t, "DeleteBaby")
+	}
+}
+
+func TestBabyService_DeleteBaby_NotFound(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("DeleteBaby", mock.Anything, babyID).Return(fmt.Errorf("baby not found"))
+
+	err := babyService.DeleteBaby(context.Background(), babyID, domain.RoleAdmin)
+
+	assert.EqualError(t, err, "baby not found")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_ListBabies_IncludeDeletedForbidden(t *testing.T) {
+	for _, role := range []domain.Role{domain.RoleParent, domain.RoleNurse} {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		result, err := babyService.ListBabies(context.Background(), uuid.New(), role, true)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "forbidden: only ADMIN can list deleted babies", string(role))
+		mockRepo.AssertNotCalled(t, "ListBabies")
+	}
+}
+
+func TestBabyService_EnsureBaby_ExistingBabyNotCreated(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	parentID := uuid.New()
+	existing := &domain.Baby{ID: uuid.New(), LastName: "Smith", RoomNumber: "101", ParentUserID: parentID}
+	mockRepo.On("FindBabyByParentAndRoom", mock.Anything, parentID, "Smith", "101").Return(existing, nil)
+
+	baby, created, err := babyService.EnsureBaby(context.Background(), "Smith", "101", parentID, "", uuid.Nil, domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, existing.ID, baby.ID)
+	mockRepo.AssertNotCalled(t, "CreateBaby", mock.Anything, mock.Anything)
+}
+
+func TestBabyService_EnsureBaby_CreatesWhenMissing(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	parentID := uuid.New()
+	mockRepo.On("FindBabyByParentAndRoom", mock.Anything, parentID, "Smith", "101").Return(nil, nil)
+	mockRepo.On("CreateBaby", mock.Anything, mock.AnythingOfType("*domain.Baby")).Return(nil)
+
+	baby, created, err := babyService.EnsureBaby(context.Background(), "Smith", "101", parentID, "", uuid.Nil, domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, parentID, baby.ParentUserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_EnsureBaby_Forbidden(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	_, _, err := babyService.EnsureBaby(context.Background(), "Smith", "101", uuid.New(), "", uuid.New(), domain.RoleNurse)
+
+	require.Error(t, err)
+	assert.Equal(t, "forbidden: only ADMIN can create babies", err.Error())
+	mockRepo.AssertNotCalled(t, "FindBabyByParentAndRoom", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// countingAcknowledger counts how a delivery was settled
+type countingAcknowledger struct {
+	acks, nacks int
+}
+
+func (a *countingAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acks++
+	return nil
+}
+
+func (a *countingAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacks++
+	return nil
+}
+
+func (a *countingAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func TestBabyMessageProcessor_DuplicateDeliveryCreatesOneBaby(t *testing.T) {
+	repo := newInMemoryBabyRepository()
+	processor := repository.NewBabyMessageProcessor(services.NewBabyService(repo), false)
+
+	parentID := uuid.New()
+	body, err := json.Marshal(repository.BabyCreationRequest{UserID: parentID.String(), LastName: "Smith", RoomNumber: "101"})
+	require.NoError(t, err)
+
+	// At-least-once delivery: the same request arrives twice
+	ack := &countingAcknowledger{}
+	for i := 0; i < 2; i++ {
+		processor.Process(context.Background(), amqp091.Delivery{Acknowledger: ack, DeliveryTag: uint64(i + 1), Body: body})
+	}
+
+	babies, err := repo.ListBabies(context.Background(), parentID, false, false)
+	require.NoError(t, err)
+	assert.Len(t, babies, 1)
+	// Both deliveries are acked so the queue drains
+	assert.Equal(t, 2, ack.acks)
+	assert.Equal(t, 0, ack.nacks)
+}
+
+func TestBabyService_CreateBaby_WhitespaceOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastName   string
+		roomNumber string
+		wantErr    string
+	}{
+		{name: "last name", lastName: "   ", roomNumber: "101", wantErr: "baby last_name cannot be empty"},
+		{name: "room number", lastName: "Doe", roomNumber: "\t ", wantErr: "baby room_number cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBabyRepository)
+			babyService := services.NewBabyService(mockRepo)
+
+			result, err := babyService.CreateBaby(context.Background(), tt.lastName, tt.roomNumber, uuid.New(), "", uuid.New(), domain.RoleAdmin)
+
+			assert.Nil(t, result)
+			assert.EqualError(t, err, tt.wantErr)
+			mockRepo.AssertNotCalled(t, "CreateBaby")
+		})
+	}
+}
+
+func TestBabyService_CreateBaby_TrimsPadding(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	mockRepo.On("CreateBaby", mock.Anything, mock.MatchedBy(func(b *domain.Baby) bool {
+		return b.LastName == "Doe" && b.RoomNumber == "3B-01"
+	})).Return(nil)
+
+	result, err := babyService.CreateBaby(context.Background(), "  Doe ", " 3B-01\n", uuid.New(), "", uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Doe", result.LastName)
+	assert.Equal(t, "3B-01", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_WhitespaceOnly(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	result, err := babyService.UpdateBaby(context.Background(), uuid.New(), "  ", "204", "", domain.RoleAdmin)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "baby last_name cannot be empty")
+	mockRepo.AssertNotCalled(t, "UpdateBaby")
+}
+
+func TestBabyService_UpdateBaby_TrimsPadding(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "", "204", (*time.Time)(nil)).Return(nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "204"}, nil)
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "", " 204 ", "", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "204", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_GetParentSummary(t *testing.T) {
+	tests := []struct {
+		name      string
+		babyCount int
+		redCount  int
+	}{
+		{name: "no babies", babyCount: 0},
+		{name: "one baby", babyCount: 1, redCount: 2},
+		{name: "several babies", babyCount: 3, redCount: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBabyRepository)
+			babyService := services.NewBabyService(mockRepo)
+
+			parentID := uuid.New()
+			mockRepo.On("CountBabiesForParent", mock.Anything, parentID).Return(tt.babyCount, nil)
+			if tt.babyCount > 0 {
+				mockRepo.On("CountRedMeasurementsForParent", mock.Anything, parentID, mock.AnythingOfType("time.Time")).
+					Return(tt.redCount, nil)
+			}
+
+			summary, err := babyService.GetParentSummary(context.Background(), parentID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, parentID, summary.ParentUserID)
+			assert.Equal(t, tt.babyCount, summary.BabyCount)
+			assert.Equal(t, tt.redCount, summary.RedMeasurementsRecent)
+			assert.WithinDuration(t, time.Now().Add(-services.ParentSummaryRecentWindow), summary.Since, time.Minute)
+			mockRepo.AssertExpectations(t)
+			if tt.babyCount == 0 {
+				// Nothing to join when the parent has no babies
+				mockRepo.AssertNotCalled(t, "CountRedMeasurementsForParent", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBabyService_GetParentSummary_NotParent(t *testing.T) {
+	for _, role := range []domain.Role{domain.RoleAdmin, domain.RoleNurse} {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		summary, err := babyService.GetParentSummary(context.Background(), uuid.New(), role)
+
+		assert.Nil(t, summary)
+		assert.EqualError(t, err, "parent summary is only available to PARENT accounts", string(role))
+		mockRepo.AssertNotCalled(t, "CountBabiesForParent", mock.Anything, mock.Anything)
+	}
+}
+
+func TestNormalizeRoomNumber(t *testing.T) {
+	tests := []struct {
+		name       string
+		roomNumber string
+		want       string
+		wantErr    bool
+	}{
+		{name: "plain", roomNumber: "101", want: "101"},
+		{name: "trims whitespace", roomNumber: " 101 ", want: "101"},
+		{name: "uppercases", roomNumber: "101a", want: "101A"},
+		{name: "dash", roomNumber: "b-12", want: "B-12"},
+		{name: "trims and uppercases", roomNumber: "\t3b-01\n", want: "3B-01"},
+		{name: "empty", roomNumber: "   ", wantErr: true},
+		{name: "inner space", roomNumber: "10 1", wantErr: true},
+		{name: "leading dash", roomNumber: "-12", wantErr: true},
+		{name: "two dashes", roomNumber: "B-1-2", wantErr: true},
+		{name: "slash", roomNumber: "B/12", wantErr: true},
+		{name: "non-ascii letter", roomNumber: "101ä", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.NormalizeRoomNumber(tt.roomNumber, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRoomNumberPattern(t *testing.T) {
+	pattern, err := domain.ParseRoomNumberPattern("")
+	require.NoError(t, err)
+	assert.Same(t, domain.DefaultRoomNumberPattern, pattern)
+
+	// Custom patterns must match the whole room number
+	pattern, err = domain.ParseRoomNumberPattern(`[A-Z]{2}\.[0-9]{3}`)
+	require.NoError(t, err)
+	got, err := domain.NormalizeRoomNumber(" nb.204 ", pattern)
+	require.NoError(t, err)
+	assert.Equal(t, "NB.204", got)
+	_, err = domain.NormalizeRoomNumber("NB.2045", pattern)
+	assert.Error(t, err)
+
+	_, err = domain.ParseRoomNumberPattern("[A-Z")
+	assert.Error(t, err)
+}
+
+func TestBabyService_CreateBaby_NormalizesRoomNumber(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	mockRepo.On("CreateBaby", mock.Anything, mock.MatchedBy(func(b *domain.Baby) bool {
+		return b.RoomNumber == "101A"
+	})).Return(nil)
+
+	result, err := babyService.CreateBaby(context.Background(), "Doe", " 101a ", uuid.New(), "", uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "101A", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_CreateBaby_InvalidRoomNumber(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "B/12", uuid.New(), "", uuid.New(), domain.RoleAdmin)
+
+	assert.Nil(t, result)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid room_number "B/12"`)
+	mockRepo.AssertNotCalled(t, "CreateBaby")
+}
+
+func TestBabyService_CreateBaby_CustomRoomNumberPattern(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	pattern, err := domain.ParseRoomNumberPattern(`[A-Z]{2}\.[0-9]{3}`)
+	require.NoError(t, err)
+	babyService := services.NewBabyServiceWithConfig(mockRepo, services.BabyServiceConfig{RoomNumberPattern: pattern})
+
+	mockRepo.On("CreateBaby", mock.Anything, mock.AnythingOfType("*domain.Baby")).Return(nil)
+
+	result, err := babyService.CreateBaby(context.Background(), "Doe", "nb.204", uuid.New(), "", uuid.New(), domain.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, "NB.204", result.RoomNumber)
+
+	// The default format no longer applies
+	_, err = babyService.CreateBaby(context.Background(), "Doe", "101", uuid.New(), "", uuid.New(), domain.RoleAdmin)
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "CreateBaby", 1)
+}
+
+func TestBabyService_UpdateBaby_NormalizesRoomNumber(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	babyID := uuid.New()
+	mockRepo.On("UpdateBaby", mock.Anything, babyID, "", "B-12", (*time.Time)(nil)).Return(nil)
+	mockRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, LastName: "Doe", RoomNumber: "B-12"}, nil)
+
+	result, err := babyService.UpdateBaby(context.Background(), babyID, "", "b-12", "", domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.Equal(t, "B-12", result.RoomNumber)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_UpdateBaby_InvalidRoomNumber(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	result, err := babyService.UpdateBaby(context.Background(), uuid.New(), "", "Room 12", "", domain.RoleAdmin)
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateBaby")
+}
+
+func TestBabyService_CreateBabies_ValidBatch(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	parentID := uuid.New()
+	mockRepo.On("CreateBabies", mock.Anything, mock.MatchedBy(func(babies []*domain.Baby) bool {
+		return len(babies) == 2 && babies[0].RoomNumber == "101A" && babies[1].LastName == "Roe"
+	})).Return(nil)
+
+	result, err := babyService.CreateBabies(context.Background(), []ports.CreateBabyRequest{
+		{LastName: "Doe", RoomNumber: " 101a ", ParentUserID: parentID},
+		{LastName: "Roe", RoomNumber: "102", ParentUserID: parentID},
+	}, uuid.New(), domain.RoleAdmin, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 0, result.Failed)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, 1, result.Results[1].Index)
+	require.NotNil(t, result.Results[0].Baby)
+	assert.Equal(t, "101A", result.Results[0].Baby.RoomNumber)
+	assert.Empty(t, result.Results[0].Error)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBabyService_CreateBabies_InvalidItem(t *testing.T) {
+	requests := []ports.CreateBabyRequest{
+		{LastName: "Doe", RoomNumber: "101", ParentUserID: uuid.New()},
+		{LastName: "  ", RoomNumber: "102", ParentUserID: uuid.New()},
+		{LastName: "Poe", RoomNumber: "B/12", ParentUserID: uuid.New()},
+	}
+
+	t.Run("atomic", func(t *testing.T) {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		result, err := babyService.CreateBabies(context.Background(), requests, uuid.New(), domain.RoleAdmin, true)
+
+		require.NoError(t, err)
+		assert.True(t, result.Atomic)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		// The valid entry is rolled back with the rest of the batch
+		assert.Nil(t, result.Results[0].Baby)
+		assert.Empty(t, result.Results[0].Error)
+		assert.Equal(t, "baby last_name cannot be empty", result.Results[1].Error)
+		assert.Contains(t, result.Results[2].Error, "invalid room_number")
+		mockRepo.AssertNotCalled(t, "CreateBabies", mock.Anything, mock.Anything)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		mockRepo := new(MockBabyRepository)
+		babyService := services.NewBabyService(mockRepo)
+
+		mockRepo.On("CreateBabies", mock.Anything, mock.MatchedBy(func(babies []*domain.Baby) bool {
+			return len(babies) == 1 && babies[0].LastName == "Doe"
+		})).Return(nil)
+
+		result, err := babyService.CreateBabies(context.Background(), requests, uuid.New(), domain.RoleAdmin, false)
+
+		require.NoError(t, err)
+		assert.False(t, result.Atomic)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		require.NotNil(t, result.Results[0].Baby)
+		assert.Nil(t, result.Results[1].Baby)
+		assert.Equal(t, "baby last_name cannot be empty", result.Results[1].Error)
+		assert.Contains(t, result.Results[2].Error, "invalid room_number")
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBabyService_CreateBabies_Rejected(t *testing.T) {
+	mockRepo := new(MockBabyRepository)
+	babyService := services.NewBabyService(mockRepo)
+
+	one := []ports.CreateBabyRequest{{LastName: "Doe", RoomNumber: "101", ParentUserID: uuid.New()}}
+	tooMany := make([]ports.CreateBabyRequest, domain.MaxBabyBatchSize+1)
+
+	_, err := babyService.CreateBabies(context.Background(), one, uuid.New(), domain.RoleNurse, true)
+	assert.EqualError(t, err, "forbidden: only ADMIN can create babies")
+
+	_, err = babyService.CreateBabies(context.Background(), nil, uuid.New(), domain.RoleAdmin, true)
+	assert.EqualError(t, err, "batch must contain between 1 and 100 babies")
+
+	_, err = babyService.CreateBabies(context.Background(), tooMany, uuid.New(), domain.RoleAdmin, false)
+	assert.EqualError(t, err, "batch must contain between 1 and 100 babies")
+
+	mockRepo.AssertNotCalled(t, "CreateBabies", mock.Anything, mock.Anything)
+}
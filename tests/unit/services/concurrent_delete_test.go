@@ -0,0 +1,322 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// inMemoryBabyRepository is a thread-safe BabyRepository used to exercise races
+// Each method reads the map under the lock, like a single SQL statement would
+type inMemoryBabyRepository struct {
+	mu     sync.RWMutex
+	babies map[uuid.UUID]*domain.Baby
+}
+
+func newInMemoryBabyRepository(babies ...*domain.Baby) *inMemoryBabyRepository {
+	repo := &inMemoryBabyRepository{babies: make(map[uuid.UUID]*domain.Baby)}
+	for _, b := range babies {
+		repo.babies[b.ID] = b
+	}
+	return repo
+}
+
+func (r *inMemoryBabyRepository) CreateBaby(ctx context.Context, baby *domain.Baby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.babies[baby.ID] = baby
+	return nil
+}
+
+func (r *inMemoryBabyRepository) CreateBabies(ctx context.Context, babies []*domain.Baby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, baby := range babies {
+		r.babies[baby.ID] = baby
+	}
+	return nil
+}
+
+// live returns the baby unless it is missing or soft-deleted; callers hold the lock
+func (r *inMemoryBabyRepository) live(babyID uuid.UUID) (*domain.Baby, bool) {
+	baby, ok := r.babies[babyID]
+	if !ok || baby.DeletedAt != nil {
+		return nil, false
+	}
+	return baby, true
+}
+
+func (r *inMemoryBabyRepository) GetBabyByID(ctx context.Context, babyID uuid.UUID) (*domain.Baby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	baby, ok := r.live(babyID)
+	if !ok {
+		return nil, fmt.Errorf("baby not found")
+	}
+	copied := *baby
+	return &copied, nil
+}
+
+func (r *inMemoryBabyRepository) FindBabyByParentAndRoom(ctx context.Context, parentUserID uuid.UUID, lastName string, roomNumber string) (*domain.Baby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var found *domain.Baby
+	for _, b := range r.babies {
+		if b.DeletedAt != nil || b.ParentUserID != parentUserID || b.LastName != lastName || b.RoomNumber != roomNumber {
+			continue
+		}
+		if found == nil || b.CreatedAt.Before(found.CreatedAt) {
+			found = b
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	copied := *found
+	return &copied, nil
+}
+
+func (r *inMemoryBabyRepository) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool, includeDeleted bool) ([]*domain.Baby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var babies []*domain.Baby
+	for _, b := range r.babies {
+		if b.DeletedAt != nil && !includeDeleted {
+			continue
+		}
+		if isAdmin || b.ParentUserID == parentUserID {
+			copied := *b
+			babies = append(babies, &copied)
+		}
+	}
+	return babies, nil
+}
+
+func (r *inMemoryBabyRepository) ListBabiesByRoomPrefix(ctx context.Context, roomPrefix string) ([]*domain.Baby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var babies []*domain.Baby
+	for _, b := range r.babies {
+		if b.DeletedAt == nil && strings.HasPrefix(b.RoomNumber, roomPrefix) {
+			copied := *b
+			babies = append(babies, &copied)
+		}
+	}
+	return babies, nil
+}
+
+func (r *inMemoryBabyRepository) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	baby, ok := r.live(babyID)
+	if !ok {
+		return fmt.Errorf("baby not found")
+	}
+	if lastName != "" {
+		baby.LastName = lastName
+	}
+	if roomNumber != "" {
+		baby.RoomNumber = roomNumber
+	}
+	if dateOfBirth != nil {
+		baby.DateOfBirth = dateOfBirth
+	}
+	return nil
+}
+
+func (r *inMemoryBabyRepository) DeleteBaby(ctx context.Context, babyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	baby, ok := r.live(babyID)
+	if !ok {
+		return fmt.Errorf("baby not found")
+	}
+	now := time.Now()
+	baby.DeletedAt = &now
+	return nil
+}
+
+func (r *inMemoryBabyRepository) BabyExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.live(babyID)
+	return ok, nil
+}
+
+func (r *inMemoryBabyRepository) BabyRecordExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.babies[babyID]
+	return ok, nil
+}
+
+func (r *inMemoryBabyRepository) CountBabiesForParent(ctx context.Context, parentUserID uuid.UUID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, b := range r.babies {
+		if b.DeletedAt == nil && b.ParentUserID == parentUserID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *inMemoryBabyRepository) CountRedMeasurementsForParent(ctx context.Context, parentUserID uuid.UUID, since time.Time) (int, error) {
+	return 0, nil // Measurements are not stored
+}
+
+func (r *inMemoryBabyRepository) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	baby, ok := r.live(babyID)
+	return ok && baby.ParentUserID == parentUserID, nil
+}
+
+func (r *inMemoryBabyRepository) GetBabyAccess(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	baby, ok := r.live(babyID)
+	if !ok {
+		return false, false, nil
+	}
+	return true, baby.ParentUserID == parentUserID, nil
+}
+
+// rowCount returns the number of stored rows, soft-deleted ones included
+func (r *inMemoryBabyRepository) rowCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.babies)
+}
+
+// runDuringDelete calls read concurrently from several goroutines while the baby is deleted
+// Returns every error observed
+func runDuringDelete(t *testing.T, repo *inMemoryBabyRepository, babyID uuid.UUID, read func() error) []error {
+	t.Helper()
+
+	const readers = 8
+	const iterations = 200
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		errs  []error
+		start = make(chan struct{})
+	)
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for j := 0; j < iterations; j++ {
+				if err := read(); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	close(start)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, repo.DeleteBaby(context.Background(), babyID))
+	wg.Wait()
+
+	return errs
+}
+
+func TestBabyService_GetBaby_ConcurrentDelete(t *testing.T) {
+	parentID := uuid.New()
+	baby := &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: parentID, CreatedAt: time.Now()}
+	repo := newInMemoryBabyRepository(baby)
+	babyService := services.NewBabyService(repo)
+
+	errs := runDuringDelete(t, repo, baby.ID, func() error {
+		_, err := babyService.GetBaby(context.Background(), baby.ID, parentID, domain.RoleParent)
+		return err
+	})
+
+	// Every failure must be the same not-found error, never a lookup failure
+	for _, err := range errs {
+		assert.Equal(t, "baby not found", err.Error())
+	}
+
+	_, err := babyService.GetBaby(context.Background(), baby.ID, parentID, domain.RoleParent)
+	require.Error(t, err)
+	assert.Equal(t, "baby not found", err.Error())
+}
+
+func TestMeasurementService_GetMeasurements_ConcurrentDelete(t *testing.T) {
+	parentID := uuid.New()
+	baby := &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: parentID, CreatedAt: time.Now()}
+	repo := newInMemoryBabyRepository(baby)
+
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, baby.ID, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{}, nil).Maybe()
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, repo, new(MockAlertPublisher))
+
+	errs := runDuringDelete(t, repo, baby.ID, func() error {
+		_, err := measurementService.GetMeasurements(context.Background(), baby.ID, parentID, domain.RoleParent, ports.MeasurementFilter{})
+		return err
+	})
+
+	for _, err := range errs {
+		assert.Equal(t, "baby not found", err.Error())
+	}
+
+	_, err := measurementService.GetMeasurements(context.Background(), baby.ID, parentID, domain.RoleParent, ports.MeasurementFilter{})
+	require.Error(t, err)
+	assert.Equal(t, "baby not found", err.Error())
+}
+
+func TestBabyService_DeleteBaby_SoftDeletes(t *testing.T) {
+	parentID := uuid.New()
+	adminID := uuid.New()
+	baby := &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: parentID, CreatedAt: time.Now()}
+	other := &domain.Baby{ID: uuid.New(), LastName: "Roe", RoomNumber: "102", ParentUserID: parentID, CreatedAt: time.Now()}
+	repo := newInMemoryBabyRepository(baby, other)
+	babyService := services.NewBabyService(repo)
+
+	require.NoError(t, babyService.DeleteBaby(context.Background(), baby.ID, domain.RoleAdmin))
+
+	// Gone from every read path
+	babies, err := babyService.ListBabies(context.Background(), adminID, domain.RoleAdmin, false)
+	require.NoError(t, err)
+	require.Len(t, babies, 1)
+	assert.Equal(t, other.ID, babies[0].ID)
+
+	babies, err = babyService.ListBabies(context.Background(), parentID, domain.RoleParent, false)
+	require.NoError(t, err)
+	assert.Len(t, babies, 1)
+
+	_, err = babyService.GetBaby(context.Background(), baby.ID, adminID, domain.RoleAdmin)
+	assert.EqualError(t, err, "baby not found")
+
+	// The row is retained and visible to ADMIN on request
+	assert.Equal(t, 2, repo.rowCount())
+	babies, err = babyService.ListBabies(context.Background(), adminID, domain.RoleAdmin, true)
+	require.NoError(t, err)
+	require.Len(t, babies, 2)
+	for _, b := range babies {
+		assert.Equal(t, b.ID == baby.ID, b.DeletedAt != nil)
+	}
+
+	// Deleting again reports not found
+	assert.EqualError(t, babyService.DeleteBaby(context.Background(), baby.ID, domain.RoleAdmin), "baby not found")
+}
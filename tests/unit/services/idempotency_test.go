@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/ports"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idempotencyKeyEntry is a claimed key in inMemoryIdempotentMeasurementRepository
+type idempotencyKeyEntry struct {
+	measurementID uuid.UUID
+	createdAt     time.Time
+}
+
+// inMemoryIdempotentMeasurementRepository stores measurements and idempotency keys under one lock,
+// like the single transaction of the SQL repository; methods not needed here fall through to the mock
+type inMemoryIdempotentMeasurementRepository struct {
+	*MockMeasurementRepository
+	mu           sync.Mutex
+	measurements map[uuid.UUID]*domain.Measurement
+	keys         map[string]idempotencyKeyEntry
+	inserts      int
+}
+
+func newInMemoryIdempotentMeasurementRepository() *inMemoryIdempotentMeasurementRepository {
+	return &inMemoryIdempotentMeasurementRepository{
+		MockMeasurementRepository: new(MockMeasurementRepository),
+		measurements:              make(map[uuid.UUID]*domain.Measurement),
+		keys:                      make(map[string]idempotencyKeyEntry),
+	}
+}
+
+func idempotencyMapKey(userID uuid.UUID, key string) string {
+	return userID.String() + "|" + key
+}
+
+func (r *inMemoryIdempotentMeasurementRepository) CreateMeasurementWithIdempotencyKey(ctx context.Context, measurement *domain.Measurement, key string, notBefore time.Time) (*uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mapKey := idempotencyMapKey(measurement.ParentID, key)
+	if entry, ok := r.keys[mapKey]; ok && !entry.createdAt.Before(notBefore) {
+		id := entry.measurementID
+		return &id, nil
+	}
+	r.keys[mapKey] = idempotencyKeyEntry{measurementID: measurement.ID, createdAt: measurement.CreatedAt}
+	copied := *measurement
+	r.measurements[measurement.ID] = &copied
+	r.inserts++
+	return nil, nil
+}
+
+func (r *inMemoryIdempotentMeasurementRepository) GetIdempotentMeasurementID(ctx context.Context, userID uuid.UUID, key string, notBefore time.Time) (*uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.keys[idempotencyMapKey(userID, key)]
+	if !ok || entry.createdAt.Before(notBefore) {
+		return nil, nil
+	}
+	id := entry.measurementID
+	return &id, nil
+}
+
+func (r *inMemoryIdempotentMeasurementRepository) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID) (*domain.Measurement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	measurement, ok := r.measurements[measurementID]
+	if !ok {
+		return nil, fmt.Errorf("measurement not found")
+	}
+	copied := *measurement
+	return &copied, nil
+}
+
+func (r *inMemoryIdempotentMeasurementRepository) insertCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.inserts
+}
+
+func newIdempotencyTestService(parentID uuid.UUID) (*services.MeasurementService, *inMemoryIdempotentMeasurementRepository, *domain.Baby) {
+	baby := &domain.Baby{ID: uuid.New(), LastName: "Doe", RoomNumber: "101", ParentUserID: parentID, CreatedAt: time.Now()}
+	measurementRepo := newInMemoryIdempotentMeasurementRepository()
+	measurementService := services.NewMeasurementService(measurementRepo, newInMemoryBabyRepository(baby), new(MockAlertPublisher))
+	return measurementService, measurementRepo, baby
+}
+
+func bottleFeedingRequest() ports.CreateMeasurementRequest {
+	volume := 120
+	return ports.CreateMeasurementRequest{
+		Type:        domain.MeasurementTypeFeeding,
+		FeedingType: "bottle",
+		VolumeML:    &volume,
+		Timestamp:   time.Now(),
+	}
+}
+
+func TestMeasurementService_CreateWithIdempotency_ConcurrentRetriesInsertOnce(t *testing.T) {
+	parentID := uuid.New()
+	measurementService, measurementRepo, baby := newIdempotencyTestService(parentID)
+	req := bottleFeedingRequest()
+
+	const requests = 2
+	var (
+		wg       sync.WaitGroup
+		start    = make(chan struct{})
+		results  [requests]*domain.Measurement
+		replayed [requests]bool
+		errs     [requests]error
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i], replayed[i], errs[i] = measurementService.CreateWithIdempotency(context.Background(), baby.ID, req, parentID, domain.RoleParent, "retry-key-1")
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i := 0; i < requests; i++ {
+		require.NoError(t, errs[i])
+		require.NotNil(t, results[i])
+	}
+	assert.Equal(t, 1, measurementRepo.insertCount())
+	assert.Equal(t, results[0].ID, results[1].ID)
+	assert.True(t, replayed[0] != replayed[1], "exactly one request should be a replay")
+}
+
+func TestMeasurementService_CreateWithIdempotency_SequentialRetryReplays(t *testing.T) {
+	parentID := uuid.New()
+	measurementService, measurementRepo, baby := newIdempotencyTestService(parentID)
+	req := bottleFeedingRequest()
+
+	first, replayed, err := measurementService.CreateWithIdempotency(context.Background(), baby.ID, req, parentID, domain.RoleParent, "retry-key-1")
+	require.NoError(t, err)
+	assert.False(t, replayed)
+
+	second, replayed, err := measurementService.CreateWithIdempotency(context.Background(), baby.ID, req, parentID, domain.RoleParent, "retry-key-1")
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, first.ID, second.ID)
+
+	// A different key is a different request
+	third, replayed, err := measurementService.CreateWithIdempotency(context.Background(), baby.ID, req, parentID, domain.RoleParent, "retry-key-2")
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, first.ID, third.ID)
+	assert.Equal(t, 2, measurementRepo.insertCount())
+}
+
+func TestMeasurementService_CreateWithIdempotency_KeyReusedForOtherType(t *testing.T) {
+	parentID := uuid.New()
+	measurementService, _, baby := newIdempotencyTestService(parentID)
+
+	_, _, err := measurementService.CreateWithIdempotency(context.Background(), baby.ID, bottleFeedingRequest(), parentID, domain.RoleParent, "retry-key-1")
+	require.NoError(t, err)
+
+	diaperReq := ports.CreateMeasurementRequest{Type: domain.MeasurementTypeDiaper, DiaperStatus: "wet", Timestamp: time.Now()}
+	_, _, err = measurementService.CreateWithIdempotency(context.Background(), baby.ID, diaperReq, parentID, domain.RoleParent, "retry-key-1")
+
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+}
+
+func TestMeasurementService_CreateWithIdempotency_InvalidKey(t *testing.T) {
+	parentID := uuid.New()
+	measurementService, measurementRepo, baby := newIdempotencyTestService(parentID)
+
+	for _, key := range []string{"", "has space", string(make([]byte, domain.MaxIdempotencyKeyLength+1))} {
+		_, _, err := measurementService.CreateWithIdempotency(context.Background(), baby.ID, bottleFeedingRequest(), parentID, domain.RoleParent, key)
+		assert.EqualError(t, err, "invalid idempotency key")
+	}
+	assert.Equal(t, 0, measurementRepo.insertCount())
+}
@@ -1,14 +1,23 @@
 package services_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/IANDYI/care-service/internal/adapters/middleware"
 	"github.com/IANDYI/care-service/internal/core/domain"
 	"github.com/IANDYI/care-service/internal/core/ports"
 	"github.com/IANDYI/care-service/internal/core/services"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -24,14 +33,107 @@ func (m *MockMeasurementRepository) CreateMeasurement(ctx context.Context, measu
 	return args.Error(0)
 }
 
-func (m *MockMeasurementRepository) GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, measurementType *string, limit *int) ([]*domain.Measurement, error) {
-	args := m.Called(ctx, babyID, measurementType, limit)
+func (m *MockMeasurementRepository) CreateMeasurementWithIdempotencyKey(ctx context.Context, measurement *domain.Measurement, key string, notBefore time.Time) (*uuid.UUID, error) {
+	args := m.Called(ctx, measurement, key, notBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*uuid.UUID), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetIdempotentMeasurementID(ctx context.Context, userID uuid.UUID, key string, notBefore time.Time) (*uuid.UUID, error) {
+	args := m.Called(ctx, userID, key, notBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*uuid.UUID), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetMeasurementsByBabyID(ctx context.Context, babyID uuid.UUID, filter ports.MeasurementFilter) ([]*domain.Measurement, error) {
+	args := m.Called(ctx, babyID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetFeedingTotals(ctx context.Context, babyID uuid.UUID, from, to time.Time) ([]domain.FeedingTotals, error) {
+	args := m.Called(ctx, babyID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FeedingTotals), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetFeedingCountsByHour(ctx context.Context, babyID uuid.UUID, from, to time.Time, loc *time.Location) ([]domain.FeedingHourCount, error) {
+	args := m.Called(ctx, babyID, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FeedingHourCount), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetMeasurementStats(ctx context.Context, babyID uuid.UUID, from, to *time.Time) ([]domain.MeasurementStats, error) {
+	args := m.Called(ctx, babyID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MeasurementStats), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetMeasurementTrend(ctx context.Context, babyID uuid.UUID, measurementType string, bucket domain.TrendBucket, from, to time.Time, loc *time.Location) ([]domain.TrendPoint, error) {
+	args := m.Called(ctx, babyID, measurementType, bucket, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TrendPoint), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetLatestMeasurements(ctx context.Context, babyID uuid.UUID) ([]*domain.Measurement, error) {
+	args := m.Called(ctx, babyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Measurement), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) GetLatestMeasurementsForBabies(ctx context.Context, babyIDs []uuid.UUID) ([]*domain.Measurement, error) {
+	args := m.Called(ctx, babyIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Measurement), args.Error(1)
 }
 
+func (m *MockMeasurementRepository) GetSafetyStatusesSince(ctx context.Context, babyIDs []uuid.UUID, since time.Time, types []string) (map[uuid.UUID][]domain.SafetyStatus, error) {
+	args := m.Called(ctx, babyIDs, since, types)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID][]domain.SafetyStatus), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) ListInvalidSafetyStatusMeasurements(ctx context.Context, limit int) ([]ports.SafetyStatusBackfillRow, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ports.SafetyStatusBackfillRow), args.Error(1)
+}
+
+func (m *MockMeasurementRepository) UpdateMeasurementSafetyStatus(ctx context.Context, measurementID uuid.UUID, status domain.SafetyStatus) error {
+	args := m.Called(ctx, measurementID, status)
+	return args.Error(0)
+}
+
+func (m *MockMeasurementRepository) GetSafetyStatusCounts(ctx context.Context, babyID uuid.UUID, filter ports.MeasurementFilter) (map[domain.SafetyStatus]int, error) {
+	args := m.Called(ctx, babyID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[domain.SafetyStatus]int), args.Error(1)
+}
+
 func (m *MockMeasurementRepository) GetMeasurementByID(ctx context.Context, measurementID uuid.UUID) (*domain.Measurement, error) {
 	args := m.Called(ctx, measurementID)
 	if args.Get(0) == nil {
@@ -40,11 +142,19 @@ func (m *MockMeasurementRepository) GetMeasurementByID(ctx context.Context, meas
 	return args.Get(0).(*domain.Measurement), args.Error(1)
 }
 
-func (m *MockMeasurementRepository) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) error {
-	args := m.Called(ctx, measurementID, parentID)
+func (m *MockMeasurementRepository) UpdateMeasurement(ctx context.Context, measurement *domain.Measurement) error {
+	args := m.Called(ctx, measurement)
 	return args.Error(0)
 }
 
+func (m *MockMeasurementRepository) DeleteMeasurement(ctx context.Context, measurementID uuid.UUID, parentID uuid.UUID) (*domain.Measurement, error) {
+	args := m.Called(ctx, measurementID, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Measurement), args.Error(1)
+}
+
 // MockBabyRepository for measurement service tests
 type MockBabyRepositoryForMeasurement struct {
 	mock.Mock
@@ -55,6 +165,11 @@ func (m *MockBabyRepositoryForMeasurement) CreateBaby(ctx context.Context, baby
 	return args.Error(0)
 }
 
+func (m *MockBabyRepositoryForMeasurement) CreateBabies(ctx context.Context, babies []*domain.Baby) error {
+	args := m.Called(ctx, babies)
+	return args.Error(0)
+}
+
 func (m *MockBabyRepositoryForMeasurement) GetBabyByID(ctx context.Context, babyID uuid.UUID) (*domain.Baby, error) {
 	args := m.Called(ctx, babyID)
 	if args.Get(0) == nil {
@@ -63,24 +178,70 @@ func (m *MockBabyRepositoryForMeasurement) GetBabyByID(ctx context.Context, baby
 	return args.Get(0).(*domain.Baby), args.Error(1)
 }
 
-func (m *MockBabyRepositoryForMeasurement) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool) ([]*domain.Baby, error) {
-	args := m.Called(ctx, parentUserID, isAdmin)
+func (m *MockBabyRepositoryForMeasurement) FindBabyByParentAndRoom(ctx context.Context, parentUserID uuid.UUID, lastName string, roomNumber string) (*domain.Baby, error) {
+	args := m.Called(ctx, parentUserID, lastName, roomNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyRepositoryForMeasurement) ListBabiesByRoomPrefix(ctx context.Context, roomPrefix string) ([]*domain.Baby, error) {
+	args := m.Called(ctx, roomPrefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Baby), args.Error(1)
+}
+
+func (m *MockBabyRepositoryForMeasurement) ListBabies(ctx context.Context, parentUserID uuid.UUID, isAdmin bool, includeDeleted bool) ([]*domain.Baby, error) {
+	args := m.Called(ctx, parentUserID, isAdmin, includeDeleted)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Baby), args.Error(1)
 }
 
+func (m *MockBabyRepositoryForMeasurement) UpdateBaby(ctx context.Context, babyID uuid.UUID, lastName string, roomNumber string, dateOfBirth *time.Time) error {
+	args := m.Called(ctx, babyID, lastName, roomNumber, dateOfBirth)
+	return args.Error(0)
+}
+
+func (m *MockBabyRepositoryForMeasurement) DeleteBaby(ctx context.Context, babyID uuid.UUID) error {
+	args := m.Called(ctx, babyID)
+	return args.Error(0)
+}
+
 func (m *MockBabyRepositoryForMeasurement) BabyExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
 	args := m.Called(ctx, babyID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBabyRepositoryForMeasurement) BabyRecordExists(ctx context.Context, babyID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, babyID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBabyRepositoryForMeasurement) CountBabiesForParent(ctx context.Context, parentUserID uuid.UUID) (int, error) {
+	args := m.Called(ctx, parentUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBabyRepositoryForMeasurement) CountRedMeasurementsForParent(ctx context.Context, parentUserID uuid.UUID, since time.Time) (int, error) {
+	args := m.Called(ctx, parentUserID, since)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockBabyRepositoryForMeasurement) CheckBabyOwnership(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, error) {
 	args := m.Called(ctx, babyID, parentUserID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBabyRepositoryForMeasurement) GetBabyAccess(ctx context.Context, babyID uuid.UUID, parentUserID uuid.UUID) (bool, bool, error) {
+	args := m.Called(ctx, babyID, parentUserID)
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
 // MockAlertPublisher is a mock implementation of ports.AlertPublisher
 type MockAlertPublisher struct {
 	mock.Mock
@@ -110,8 +271,8 @@ func TestMeasurementService_CreateMeasurement_Success(t *testing.T) {
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockBabyRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(true, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
 	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
 
 	req := ports.CreateMeasurementRequest{
@@ -120,7 +281,7 @@ func TestMeasurementService_CreateMeasurement_Success(t *testing.T) {
 		Note:  "Normal temperature",
 	}
 
-	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, false)
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
 	
 	require.NoError(t, err)
 	assert.NotNil(t, result)
@@ -131,228 +292,261 @@ func TestMeasurementService_CreateMeasurement_Success(t *testing.T) {
 	mockMeasurementRepo.AssertExpectations(t)
 }
 
-func TestMeasurementService_CreateMeasurement_Forbidden_Admin(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_LogsCreatedEvent(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
-	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	var logs bytes.Buffer
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
 
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
 
-	req := ports.CreateMeasurementRequest{
-		Type:  "temperature",
-		Value: 37.0,
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Note: "Normal temperature"}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), "expected exactly one JSON log line, got %q", logs.String())
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "measurement created", entry["msg"])
+	assert.Equal(t, "created", entry["event"])
+	assert.Equal(t, result.ID.String(), entry["measurement_id"])
+	assert.Equal(t, babyID.String(), entry["baby_id"])
+	assert.Equal(t, "temperature", entry["type"])
+	assert.Equal(t, 37.0, entry["value"])
+	assert.Equal(t, "green", entry["safety_status"])
+	assert.Equal(t, "Normal temperature", entry["note"])
+}
+
+func TestMeasurementService_CreateMeasurement_TimestampRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		wantErr   string
+	}{
+		{name: "now", timestamp: time.Now()},
+		{name: "one minute ahead is within the skew", timestamp: time.Now().Add(time.Minute)},
+		{name: "one hour ahead", timestamp: time.Now().Add(time.Hour), wantErr: "timestamp cannot be in the future"},
+		{name: "ancient", timestamp: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), wantErr: "timestamp cannot be before 2000-01-01"},
 	}
 
-	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, true)
-	
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "forbidden")
-	mockBabyRepo.AssertExpectations(t)
-	mockBabyRepo.AssertNotCalled(t, "CheckBabyOwnership")
-	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			mockAlertPublisher := new(MockAlertPublisher)
+
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Timestamp: tt.timestamp}, userID, domain.RoleParent)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.timestamp.Equal(result.Timestamp))
+		})
+	}
 }
 
-func TestMeasurementService_CreateMeasurement_InvalidType(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_ConfiguredFutureSkew(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{MaxFutureSkew: 2 * time.Hour})
 
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	req := ports.CreateMeasurementRequest{
-		Type:  "invalid_type",
-		Value: 37.0,
-	}
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
 
-	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, false)
-	
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "invalid measurement type")
-	mockBabyRepo.AssertNotCalled(t, "BabyExists")
-	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Timestamp: time.Now().Add(time.Hour)}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
 }
 
-func TestMeasurementService_CreateMeasurement_BabyNotFound(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_ConstraintViolationNotWrapped(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
-	
+
 	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
 
 	userID := uuid.New()
 	babyID := uuid.New()
+	violation := fmt.Errorf("%w: breastfeeding both-sides requires left and right durations (chk_breastfeeding_durations)", domain.ErrConstraintViolation)
 
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(false, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(violation)
 
-	req := ports.CreateMeasurementRequest{
-		Type:  "temperature",
-		Value: 37.0,
-	}
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
 
-	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, false)
-	
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "baby not found")
-	mockBabyRepo.AssertExpectations(t)
-	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+	assert.Equal(t, violation.Error(), err.Error())
 }
 
-func TestMeasurementService_CreateMeasurement_RedStatus(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_CountsCreated(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{Metrics: middleware.NewMeasurementMetricsCollector(registry)})
 
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockBabyRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(true, nil)
-	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
-		return m.SafetyStatus == domain.SafetyStatusRed
-	})).Return(nil)
-	// Alert publisher is called asynchronously in a goroutine for Red status measurements
-	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.MatchedBy(func(m *domain.Measurement) bool {
-		return m.SafetyStatus == domain.SafetyStatusRed
-	})).Return(nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
 
-	req := ports.CreateMeasurementRequest{
-		Type:  "temperature",
-		Value: 39.0, // Red status (>38.0)
-		Note:  "High temperature",
-	}
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
 
-	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, false)
-	
 	require.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
-	
-	// Wait a bit for the async goroutine to complete
-	time.Sleep(100 * time.Millisecond)
-	
-	mockBabyRepo.AssertExpectations(t)
-	mockMeasurementRepo.AssertExpectations(t)
-	mockAlertPublisher.AssertExpectations(t)
+	expected := `
+# HELP measurements_created_total Total number of measurements created, by type and safety status
+# TYPE measurements_created_total counter
+measurements_created_total{safety_status="green",type="temperature"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "measurements_created_total"))
 }
 
-func TestMeasurementService_GetMeasurements_Success(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_ObservesDurationByType(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
-	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Metrics: middleware.NewMeasurementMetricsCollector(registry)})
 
 	userID := uuid.New()
 	babyID := uuid.New()
 
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockBabyRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(true, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
 
-	expectedMeasurements := []*domain.Measurement{
-		{
-			ID:           uuid.New(),
-			ParentID:     userID,
-			BabyID:       babyID,
-			Type:         "temperature",
-			Value:        37.0,
-			SafetyStatus: domain.SafetyStatusGreen,
-			Timestamp:    time.Now(),
-			CreatedAt:    time.Now(),
-		},
+	for _, req := range []ports.CreateMeasurementRequest{
+		{Type: "temperature", Value: 37.0},
+		{Type: "temperature", Value: 37.1},
+		{Type: "heart_rate", Value: 130},
+	} {
+		_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+		require.NoError(t, err)
 	}
+	// A rejected create is not timed
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "heart_rate", Value: 500}, userID, domain.RoleParent)
+	require.Error(t, err)
 
-	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, (*string)(nil), (*int)(nil)).
-		Return(expectedMeasurements, nil)
-
-	result, err := measurementService.GetMeasurements(context.Background(), babyID, userID, false, nil, nil)
-	
+	families, err := registry.Gather()
 	require.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Len(t, result, 1)
-	mockBabyRepo.AssertExpectations(t)
-	mockMeasurementRepo.AssertExpectations(t)
+	counts := map[string]uint64{}
+	for _, family := range families {
+		if family.GetName() != "measurement_create_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			require.Len(t, metric.GetLabel(), 1)
+			assert.Equal(t, "type", metric.GetLabel()[0].GetName())
+			counts[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+	assert.Equal(t, map[string]uint64{"temperature": 2, "heart_rate": 1}, counts)
 }
 
-func TestMeasurementService_GetMeasurementByID_Success(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_CountsByTypeAndStatus(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+	registry := prometheus.NewRegistry()
 
-	userID := uuid.New()
-	measurementID := uuid.New()
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{Metrics: middleware.NewMeasurementMetricsCollector(registry)})
+
+	parentID := uuid.New()
 	babyID := uuid.New()
 
-	expectedMeasurement := &domain.Measurement{
-		ID:           measurementID,
-		ParentID:     userID,
-		BabyID:       babyID,
-		Type:         "temperature",
-		Value:        37.0,
-		SafetyStatus: domain.SafetyStatusGreen,
-		Timestamp:    time.Now(),
-		CreatedAt:    time.Now(),
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, parentID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+	create := func(value float64, userID uuid.UUID, role domain.Role) error {
+		_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+			ports.CreateMeasurementRequest{Type: "temperature", Value: value}, userID, role)
+		return err
 	}
 
-	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(expectedMeasurement, nil)
-	mockBabyRepo.On("BabyExists", mock.Anything, babyID).Return(true, nil)
-	mockBabyRepo.On("CheckBabyOwnership", mock.Anything, babyID, userID).Return(true, nil)
+	require.NoError(t, create(37.0, parentID, domain.RoleParent))
+	require.NoError(t, create(39.0, parentID, domain.RoleParent))
 
-	result, err := measurementService.GetMeasurementByID(context.Background(), measurementID, userID, false)
-	
-	require.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, measurementID, result.ID)
-	mockBabyRepo.AssertExpectations(t)
-	mockMeasurementRepo.AssertExpectations(t)
+	// Rejections are not counted: RBAC, not-owned baby and validation
+	require.Error(t, create(39.0, uuid.New(), domain.RoleNurse))
+	require.Error(t, create(39.0, uuid.New(), domain.RoleParent))
+	require.Error(t, create(99.0, parentID, domain.RoleParent))
+
+	expected := `
+# HELP measurements_created_total Total number of measurements created, by type and safety status
+# TYPE measurements_created_total counter
+measurements_created_total{safety_status="green",type="temperature"} 1
+measurements_created_total{safety_status="red",type="temperature"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "measurements_created_total"))
 }
 
-func TestMeasurementService_DeleteMeasurement_Success(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_FailedInsertNotCounted(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
-	
-	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{Metrics: middleware.NewMeasurementMetricsCollector(registry)})
 
 	userID := uuid.New()
-	measurementID := uuid.New()
 	babyID := uuid.New()
 
-	expectedMeasurement := &domain.Measurement{
-		ID:           measurementID,
-		ParentID:     userID,
-		BabyID:       babyID,
-		Type:         "temperature",
-		Value:        37.0,
-		SafetyStatus: domain.SafetyStatusGreen,
-		Timestamp:    time.Now(),
-		CreatedAt:    time.Now(),
-	}
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(fmt.Errorf("connection refused"))
 
-	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(expectedMeasurement, nil)
-	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, measurementID, userID).Return(nil)
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
 
-	err := measurementService.DeleteMeasurement(context.Background(), measurementID, userID, false)
-	
-	require.NoError(t, err)
-	mockMeasurementRepo.AssertExpectations(t)
+	require.Error(t, err)
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "measurements_created_total"))
 }
 
-func TestMeasurementService_DeleteMeasurement_Forbidden_Admin(t *testing.T) {
+func TestMeasurementService_CreateMeasurement_Forbidden_Admin(t *testing.T) {
 	mockMeasurementRepo := new(MockMeasurementRepository)
 	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
 	mockAlertPublisher := new(MockAlertPublisher)
@@ -360,12 +554,2879 @@ func TestMeasurementService_DeleteMeasurement_Forbidden_Admin(t *testing.T) {
 	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
 
 	userID := uuid.New()
-	measurementID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 37.0,
+	}
 
-	err := measurementService.DeleteMeasurement(context.Background(), measurementID, userID, true)
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleAdmin)
 	
 	assert.Error(t, err)
+	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "forbidden")
-	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementByID")
-	mockMeasurementRepo.AssertNotCalled(t, "DeleteMeasurement")
+	mockBabyRepo.AssertExpectations(t)
+	mockBabyRepo.AssertNotCalled(t, "CheckBabyOwnership")
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+}
+
+func TestMeasurementService_CreateMeasurement_Forbidden_Nurse(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, uuid.New(), domain.RoleNurse)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "forbidden: only PARENT can create measurements", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetMeasurements_Nurse_ReadsAnyBaby(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, ports.MeasurementFilter{}).
+		Return([]*domain.Measurement{{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3500}}, nil)
+
+	result, err := measurementService.GetMeasurements(context.Background(), babyID, nurseID, domain.RoleNurse, ports.MeasurementFilter{})
+
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_VisibilityPolicy_RestrictedNurse(t *testing.T) {
+	policy, err := domain.ParseMeasurementVisibility("NURSE=temperature,weight")
+	require.NoError(t, err)
+
+	nurseID := uuid.New()
+	parentID := uuid.New()
+	babyID := uuid.New()
+
+	t.Run("list excludes hidden types in the query", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+		mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, ports.MeasurementFilter{Types: []string{"temperature", "weight"}}).
+			Return([]*domain.Measurement{
+				{ID: uuid.New(), BabyID: babyID, Type: "temperature", Value: 37.0},
+				{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3500},
+			}, nil)
+
+		result, err := measurementService.GetMeasurements(context.Background(), babyID, nurseID, domain.RoleNurse, ports.MeasurementFilter{})
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		for _, m := range result {
+			assert.Contains(t, []string{"temperature", "weight"}, m.Type)
+		}
+		mockMeasurementRepo.AssertExpectations(t)
+	})
+
+	t.Run("filtering on a hidden type returns nothing", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+		feeding := "feeding"
+
+		result, err := measurementService.GetMeasurements(context.Background(), babyID, nurseID, domain.RoleNurse, ports.MeasurementFilter{Type: &feeding})
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementsByBabyID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("parent still sees every type", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, parentID).Return(true, true, nil)
+		mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, ports.MeasurementFilter{}).
+			Return([]*domain.Measurement{{ID: uuid.New(), BabyID: babyID, Type: "diaper"}}, nil)
+
+		result, err := measurementService.GetMeasurements(context.Background(), babyID, parentID, domain.RoleParent, ports.MeasurementFilter{})
+
+		require.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockMeasurementRepo.AssertExpectations(t)
+	})
+
+	t.Run("hidden measurement by ID is not found", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		measurementID := uuid.New()
+		mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).
+			Return(&domain.Measurement{ID: measurementID, BabyID: babyID, Type: "diaper"}, nil)
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+
+		result, err := measurementService.GetMeasurementByID(context.Background(), measurementID, nurseID, domain.RoleNurse)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "measurement not found")
+	})
+
+	t.Run("stats drop hidden types", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+		mockMeasurementRepo.On("GetMeasurementStats", mock.Anything, babyID, (*time.Time)(nil), (*time.Time)(nil)).
+			Return([]domain.MeasurementStats{{Type: "diaper", Count: 4}, {Type: "feeding", Count: 6}, {Type: "temperature", Count: 2}}, nil)
+
+		stats, err := measurementService.GetMeasurementStats(context.Background(), babyID, nurseID, domain.RoleNurse, nil, nil)
+
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "temperature", stats[0].Type)
+	})
+
+	t.Run("feeding balance is forbidden", func(t *testing.T) {
+		mockMeasurementRepo := new(MockMeasurementRepository)
+		mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+		measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+			services.MeasurementServiceConfig{Visibility: policy})
+
+		mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+		to := time.Now()
+
+		balance, err := measurementService.GetFeedingBalance(context.Background(), babyID, nurseID, domain.RoleNurse, to.Add(-24*time.Hour), to)
+
+		assert.Nil(t, balance)
+		assert.EqualError(t, err, "forbidden: feeding measurements are not visible to this role")
+	})
+}
+
+func TestParseMeasurementVisibility(t *testing.T) {
+	policy, err := domain.ParseMeasurementVisibility("")
+	require.NoError(t, err)
+	assert.Nil(t, policy.VisibleTypes(domain.RoleNurse))
+	assert.True(t, policy.CanSee(domain.RoleNurse, "feeding"))
+
+	policy, err = domain.ParseMeasurementVisibility(" nurse = Temperature, weight ; ADMIN=")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"temperature", "weight"}, policy.VisibleTypes(domain.RoleNurse))
+	assert.Equal(t, []string{}, policy.VisibleTypes(domain.RoleAdmin))
+	assert.False(t, policy.CanSee(domain.RoleAdmin, "temperature"))
+	assert.True(t, policy.CanSee(domain.RoleParent, "diaper"))
+
+	for _, spec := range []string{"NURSE", "DOCTOR=temperature", "NURSE=blood", "NURSE=weight;NURSE=temperature"} {
+		_, err := domain.ParseMeasurementVisibility(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidType(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "invalid_type",
+		Value: 37.0,
+	}
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+	
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid measurement type")
+	mockBabyRepo.AssertNotCalled(t, "GetBabyAccess")
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+}
+
+func TestMeasurementService_CreateMeasurement_BabyNotFound(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(false, false, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 37.0,
+	}
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+	
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "baby not found")
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+}
+
+func TestMeasurementService_CreateMeasurement_RedStatus(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.SafetyStatus == domain.SafetyStatusRed
+	})).Return(nil)
+	// Alert publisher is called asynchronously in a goroutine for Red status measurements
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.SafetyStatus == domain.SafetyStatusRed
+	})).Return(nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 39.0, // Red status (>38.0)
+		Note:  "High temperature",
+	}
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+	
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	
+	// Wait a bit for the async goroutine to complete
+	time.Sleep(100 * time.Millisecond)
+	
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertExpectations(t)
+	mockAlertPublisher.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurements_Success(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+
+	expectedMeasurements := []*domain.Measurement{
+		{
+			ID:           uuid.New(),
+			ParentID:     userID,
+			BabyID:       babyID,
+			Type:         "temperature",
+			Value:        37.0,
+			SafetyStatus: domain.SafetyStatusGreen,
+			Timestamp:    time.Now(),
+			CreatedAt:    time.Now(),
+		},
+	}
+
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, ports.MeasurementFilter{}).
+		Return(expectedMeasurements, nil)
+
+	result, err := measurementService.GetMeasurements(context.Background(), babyID, userID, domain.RoleParent, ports.MeasurementFilter{})
+	
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Len(t, result, 1)
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurementByID_Success(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	babyID := uuid.New()
+
+	expectedMeasurement := &domain.Measurement{
+		ID:           measurementID,
+		ParentID:     userID,
+		BabyID:       babyID,
+		Type:         "temperature",
+		Value:        37.0,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    time.Now(),
+		CreatedAt:    time.Now(),
+	}
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(expectedMeasurement, nil)
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+
+	result, err := measurementService.GetMeasurementByID(context.Background(), measurementID, userID, domain.RoleParent)
+	
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, measurementID, result.ID)
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurementByID_OrphanedMeasurement(t *testing.T) {
+	tests := []struct {
+		name         string
+		recordExists bool
+		wantOrphaned string
+		wantWarning  bool
+	}{
+		{name: "baby row missing", recordExists: false, wantOrphaned: "1", wantWarning: true},
+		{name: "baby soft-deleted", recordExists: true, wantOrphaned: "0", wantWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			registry := prometheus.NewRegistry()
+
+			measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+				services.MeasurementServiceConfig{
+					Metrics: middleware.NewMeasurementMetricsCollector(registry),
+					Logger:  slog.New(slog.NewJSONHandler(&logs, nil)),
+				})
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			measurement := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "temperature", Value: 37.0}
+
+			mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurement.ID).Return(measurement, nil)
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(false, false, nil)
+			mockBabyRepo.On("BabyRecordExists", mock.Anything, babyID).Return(tt.recordExists, nil)
+
+			result, err := measurementService.GetMeasurementByID(context.Background(), measurement.ID, userID, domain.RoleAdmin)
+
+			// Clients see a plain not-found either way
+			assert.Nil(t, result)
+			require.Error(t, err)
+			assert.Equal(t, "measurement not found", err.Error())
+
+			expected := `
+# HELP measurements_orphaned_total Total number of measurements read whose baby row no longer exists (data integrity violation)
+# TYPE measurements_orphaned_total counter
+measurements_orphaned_total ` + tt.wantOrphaned + `
+`
+			assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "measurements_orphaned_total"))
+			assert.Equal(t, tt.wantWarning, strings.Contains(logs.String(), `"level":"WARN","msg":"data integrity: measurement has no baby row"`))
+			mockBabyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeasurementService_DeleteMeasurement_Success(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	babyID := uuid.New()
+
+	expectedMeasurement := &domain.Measurement{
+		ID:           measurementID,
+		ParentID:     userID,
+		BabyID:       babyID,
+		Type:         "temperature",
+		Value:        37.0,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Timestamp:    time.Now(),
+		CreatedAt:    time.Now(),
+	}
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(expectedMeasurement, nil)
+	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, measurementID, userID).Return(expectedMeasurement, nil)
+
+	deleted, err := measurementService.DeleteMeasurement(context.Background(), measurementID, userID, domain.RoleParent)
+	
+	require.NoError(t, err)
+	assert.Equal(t, expectedMeasurement, deleted)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_DeleteMeasurement_ConcurrentDeleteNotFound(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	// The measurement was still there when ownership was checked, but another request deleted it first
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).
+		Return(&domain.Measurement{ID: measurementID, ParentID: userID, BabyID: uuid.New()}, nil)
+	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, measurementID, userID).Return(nil, fmt.Errorf("measurement not found"))
+
+	deleted, err := measurementService.DeleteMeasurement(context.Background(), measurementID, userID, domain.RoleParent)
+
+	assert.Nil(t, deleted)
+	require.Error(t, err)
+	assert.Equal(t, "measurement not found", err.Error())
+}
+
+func TestMeasurementService_DeleteMeasurement_Forbidden_Admin(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+
+	_, err := measurementService.DeleteMeasurement(context.Background(), measurementID, userID, domain.RoleAdmin)
+	
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementByID")
+	mockMeasurementRepo.AssertNotCalled(t, "DeleteMeasurement")
+}
+
+func TestMeasurementService_UpdateMeasurement_Success(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	createdAt := time.Now().Add(-time.Hour)
+
+	existing := &domain.Measurement{
+		ID:           measurementID,
+		ParentID:     userID,
+		BabyID:       uuid.New(),
+		Type:         "temperature",
+		Value:        37.0,
+		SafetyStatus: domain.SafetyStatusGreen,
+		Note:         "typo",
+		Timestamp:    createdAt,
+		CreatedAt:    createdAt,
+	}
+
+	note := "after bath"
+	timestamp := createdAt.Add(-30 * time.Minute)
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(existing, nil)
+	mockMeasurementRepo.On("UpdateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.ID == measurementID && m.ParentID == userID && m.Note == note && m.Timestamp.Equal(timestamp)
+	})).Return(nil)
+
+	result, err := measurementService.UpdateMeasurement(context.Background(), measurementID,
+		ports.UpdateMeasurementRequest{Note: &note, Timestamp: &timestamp}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, note, result.Note)
+	assert.True(t, result.Timestamp.Equal(timestamp))
+	assert.Equal(t, 37.0, result.Value)
+	assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+	assert.True(t, result.CreatedAt.Equal(createdAt))
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_UpdateMeasurement_Forbidden_Admin(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	note := "edited"
+	result, err := measurementService.UpdateMeasurement(context.Background(), uuid.New(),
+		ports.UpdateMeasurementRequest{Note: &note}, uuid.New(), domain.RoleAdmin)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "forbidden: only PARENT can update measurements", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementByID", mock.Anything, mock.Anything)
+	mockMeasurementRepo.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_UpdateMeasurement_NotOwner(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	measurementID := uuid.New()
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(&domain.Measurement{
+		ID:       measurementID,
+		ParentID: uuid.New(),
+		Type:     "temperature",
+		Value:    37.0,
+	}, nil)
+
+	note := "edited"
+	result, err := measurementService.UpdateMeasurement(context.Background(), measurementID,
+		ports.UpdateMeasurementRequest{Note: &note}, uuid.New(), domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "measurement not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_UpdateMeasurement_EmptyRequest(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	result, err := measurementService.UpdateMeasurement(context.Background(), uuid.New(),
+		ports.UpdateMeasurementRequest{}, uuid.New(), domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "nothing to update")
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementByID", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_UpdateMeasurement_FutureTimestamp(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	userID := uuid.New()
+	measurementID := uuid.New()
+	existing := &domain.Measurement{ID: measurementID, ParentID: userID, BabyID: uuid.New(), Type: "temperature", Value: 37.0, Timestamp: time.Now().Add(-time.Hour)}
+	future := time.Now().Add(24 * time.Hour)
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(existing, nil)
+
+	result, err := measurementService.UpdateMeasurement(context.Background(), measurementID,
+		ports.UpdateMeasurementRequest{Timestamp: &future}, userID, domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "timestamp cannot be in the future", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_UpdateMeasurement_TimestampBeforeBabyCreated(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{RejectBeforeBabyCreated: true, BabyCreatedGrace: time.Hour})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	measurementID := uuid.New()
+	babyCreatedAt := time.Now().Add(-48 * time.Hour)
+	existing := &domain.Measurement{ID: measurementID, ParentID: userID, BabyID: babyID, Type: "temperature", Value: 37.0, Timestamp: time.Now().Add(-time.Hour)}
+	backdated := babyCreatedAt.Add(-2 * time.Hour)
+
+	mockMeasurementRepo.On("GetMeasurementByID", mock.Anything, measurementID).Return(existing, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, CreatedAt: babyCreatedAt}, nil)
+
+	_, err := measurementService.UpdateMeasurement(context.Background(), measurementID,
+		ports.UpdateMeasurementRequest{Timestamp: &backdated}, userID, domain.RoleParent)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "before the baby's record was created")
+	mockMeasurementRepo.AssertNotCalled(t, "UpdateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_ValidateMeasurement_Valid(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 37.8, // Yellow status (37.5-38.0)
+	}
+
+	result, err := measurementService.ValidateMeasurement(context.Background(), babyID, req, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.Valid)
+	assert.Equal(t, domain.SafetyStatusYellow, result.SafetyStatus)
+	assert.Empty(t, result.Errors)
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+	mockAlertPublisher.AssertNotCalled(t, "PublishAlert")
+}
+
+func TestMeasurementService_ValidateMeasurement_Invalid(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:        "feeding",
+		FeedingType: "bottle", // Missing volume_ml
+	}
+
+	result, err := measurementService.ValidateMeasurement(context.Background(), babyID, req, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.Valid)
+	assert.Empty(t, result.SafetyStatus)
+	assert.Equal(t, []string{"bottle feeding requires volume_ml > 0"}, result.Errors)
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement")
+}
+
+func TestMeasurementService_ValidateMeasurement_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "weight",
+		Value: 3500,
+	}
+
+	result, err := measurementService.ValidateMeasurement(context.Background(), babyID, req, userID, domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "baby not found", err.Error())
+}
+
+func TestMeasurementService_ValidateMeasurement_WeightWithinMinIntervalRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{WeightMinInterval: 6 * time.Hour, RejectWeightWithinMinInterval: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	now := time.Now().UTC()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil).Maybe()
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+		return f.Type != nil && *f.Type == "weight"
+	})).Return([]*domain.Measurement{
+		{Type: "weight", Value: 3400, Timestamp: now.Add(-2 * time.Hour)},
+	}, nil)
+
+	// The dry-run must reject what a create would reject
+	result, err := measurementService.ValidateMeasurement(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3420, Timestamp: now}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.Valid)
+	assert.Empty(t, result.SafetyStatus)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], domain.ErrTooFrequent.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_WithDeviceID(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.DeviceID == "thermo-01:A7"
+	})).Return(nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:     "temperature",
+		Value:    37.0,
+		DeviceID: "thermo-01:A7",
+	}
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, "thermo-01:A7", result.DeviceID)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidDeviceID(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	for _, deviceID := range []string{"has space", "bad/slash", strings.Repeat("a", domain.MaxDeviceIDLength+1)} {
+		req := ports.CreateMeasurementRequest{
+			Type:     "temperature",
+			Value:    37.0,
+			DeviceID: deviceID,
+		}
+
+		result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(), req, uuid.New(), domain.RoleParent)
+
+		assert.Error(t, err, deviceID)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "device_id")
+	}
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetMeasurements_FilterByDeviceID(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	deviceID := "scale-42"
+	filter := ports.MeasurementFilter{DeviceID: &deviceID}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, filter).
+		Return([]*domain.Measurement{{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3500, DeviceID: deviceID}}, nil)
+
+	result, err := measurementService.GetMeasurements(context.Background(), babyID, userID, domain.RoleParent, filter)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, deviceID, result[0].DeviceID)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurements_BeforeAndAfterCursorRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	cursor := &ports.MeasurementCursor{Timestamp: time.Now(), ID: uuid.New()}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+
+	result, err := measurementService.GetMeasurements(context.Background(), babyID, userID, domain.RoleParent,
+		ports.MeasurementFilter{Before: cursor, After: cursor})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "cannot be combined")
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementsByBabyID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetMeasurements_FromAfterToRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	from := time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC)
+	to := from.Add(-12 * time.Hour)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+
+	result, err := measurementService.GetMeasurements(context.Background(), babyID, userID, domain.RoleParent,
+		ports.MeasurementFilter{From: &from, To: &to})
+
+	assert.EqualError(t, err, "from must be before to")
+	assert.Nil(t, result)
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementsByBabyID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetFeedingBalance_MixedFeedings(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	to := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, from, to).Return([]domain.FeedingTotals{
+		{FeedingType: domain.FeedingTypeBottle, Count: 3, TotalVolumeML: 360},
+		{FeedingType: domain.FeedingTypeBreast, Count: 1, TotalDurationSeconds: 900},
+	}, nil)
+
+	balance, err := measurementService.GetFeedingBalance(context.Background(), babyID, userID, domain.RoleParent, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, balance.TotalFeedings)
+	assert.Equal(t, 3, balance.BottleCount)
+	assert.Equal(t, 1, balance.BreastCount)
+	assert.InDelta(t, 0.75, balance.BottleRatio, 1e-9)
+	assert.InDelta(t, 0.25, balance.BreastRatio, 1e-9)
+	assert.Equal(t, 360, balance.TotalBottleML)
+	assert.Equal(t, 900, balance.TotalBreastDurationSeconds)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurementStats_Success(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	expected := []domain.MeasurementStats{
+		{Type: "feeding", Count: 8, MinValue: 60, MaxValue: 120, AvgValue: 90},
+	}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementStats", mock.Anything, babyID, (*time.Time)(nil), (*time.Time)(nil)).Return(expected, nil)
+
+	stats, err := measurementService.GetMeasurementStats(context.Background(), babyID, userID, domain.RoleParent, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, stats)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurementStats_NoMeasurements(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+	mockMeasurementRepo.On("GetMeasurementStats", mock.Anything, babyID, mock.Anything, mock.Anything).
+		Return([]domain.MeasurementStats(nil), nil)
+
+	// ADMIN can read any baby
+	stats, err := measurementService.GetMeasurementStats(context.Background(), babyID, userID, domain.RoleAdmin, nil, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.Empty(t, stats)
+}
+
+func TestMeasurementService_GetMeasurementStats_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	stats, err := measurementService.GetMeasurementStats(context.Background(), babyID, userID, domain.RoleParent, nil, nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetLatestMeasurements_KeyedByType(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	feeding := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "feeding", Value: 120}
+	temperature := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "temperature", Value: 37.1}
+	weight := &domain.Measurement{ID: uuid.New(), BabyID: babyID, Type: "weight", Value: 3450}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetLatestMeasurements", mock.Anything, babyID).
+		Return([]*domain.Measurement{feeding, temperature, weight}, nil)
+
+	latest, err := measurementService.GetLatestMeasurements(context.Background(), babyID, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*domain.Measurement{"feeding": feeding, "temperature": temperature, "weight": weight}, latest)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetLatestMeasurements_NoMeasurements(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+	mockMeasurementRepo.On("GetLatestMeasurements", mock.Anything, babyID).Return([]*domain.Measurement(nil), nil)
+
+	latest, err := measurementService.GetLatestMeasurements(context.Background(), babyID, userID, domain.RoleNurse)
+
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Empty(t, latest)
+}
+
+func TestMeasurementService_GetLatestMeasurements_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	latest, err := measurementService.GetLatestMeasurements(context.Background(), babyID, userID, domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, latest)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetLatestMeasurements", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetLatestMeasurements_HidesInvisibleTypes(t *testing.T) {
+	policy, err := domain.ParseMeasurementVisibility("NURSE=temperature,weight")
+	require.NoError(t, err)
+
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{Visibility: policy})
+
+	nurseID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, nurseID).Return(true, false, nil)
+	mockMeasurementRepo.On("GetLatestMeasurements", mock.Anything, babyID).
+		Return([]*domain.Measurement{
+			{ID: uuid.New(), BabyID: babyID, Type: "feeding", Value: 90},
+			{ID: uuid.New(), BabyID: babyID, Type: "temperature", Value: 37.0},
+		}, nil)
+
+	latest, err := measurementService.GetLatestMeasurements(context.Background(), babyID, nurseID, domain.RoleNurse)
+
+	require.NoError(t, err)
+	assert.Len(t, latest, 1)
+	assert.Contains(t, latest, "temperature")
+}
+
+func TestMeasurementService_GetSafetyStatusDistribution_CountsPerStatus(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	temperature := "temperature"
+	filter := ports.MeasurementFilter{Type: &temperature}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	// No red readings: the missing status counts as zero
+	mockMeasurementRepo.On("GetSafetyStatusCounts", mock.Anything, babyID, filter).
+		Return(map[domain.SafetyStatus]int{domain.SafetyStatusGreen: 180, domain.SafetyStatusYellow: 20}, nil)
+
+	distribution, err := measurementService.GetSafetyStatusDistribution(context.Background(), babyID, userID, domain.RoleParent, filter)
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.SafetyStatusDistribution{Type: "temperature", Total: 200, Green: 180, Yellow: 20, Red: 0}, distribution)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetSafetyStatusDistribution_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	distribution, err := measurementService.GetSafetyStatusDistribution(context.Background(), babyID, userID, domain.RoleParent, ports.MeasurementFilter{})
+
+	assert.Nil(t, distribution)
+	assert.EqualError(t, err, "baby not found")
+	mockMeasurementRepo.AssertNotCalled(t, "GetSafetyStatusCounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetSafetyStatusDistribution_InvalidType(t *testing.T) {
+	measurementService := services.NewMeasurementService(new(MockMeasurementRepository), new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	blood := "blood_pressure"
+	distribution, err := measurementService.GetSafetyStatusDistribution(context.Background(), uuid.New(), uuid.New(), domain.RoleAdmin, ports.MeasurementFilter{Type: &blood})
+
+	assert.Nil(t, distribution)
+	assert.EqualError(t, err, "invalid measurement type filter: blood_pressure")
+}
+
+func TestMeasurementService_GetHourlyFeedingDistribution_FillsAllHours(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	to := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+	from := to.AddDate(0, 0, -14)
+
+	// Sparse grouped rows as returned by the repository, hours already in loc
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetFeedingCountsByHour", mock.Anything, babyID, from, to, loc).Return([]domain.FeedingHourCount{
+		{Hour: 0, Count: 4},
+		{Hour: 7, Count: 10},
+		{Hour: 23, Count: 1},
+	}, nil)
+
+	distribution, err := measurementService.GetHourlyFeedingDistribution(context.Background(), babyID, userID, domain.RoleParent, from, to, loc)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", distribution.Timezone)
+	assert.Equal(t, 15, distribution.TotalFeedings)
+	require.Len(t, distribution.Hours, 24)
+	for hour, bucket := range distribution.Hours {
+		assert.Equal(t, hour, bucket.Hour)
+	}
+	assert.Equal(t, 4, distribution.Hours[0].Count)
+	assert.Equal(t, 10, distribution.Hours[7].Count)
+	assert.Equal(t, 1, distribution.Hours[23].Count)
+	assert.Equal(t, 0, distribution.Hours[12].Count)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetHourlyFeedingDistribution_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	to := time.Now()
+	from := to.AddDate(0, 0, -14)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	distribution, err := measurementService.GetHourlyFeedingDistribution(context.Background(), babyID, userID, domain.RoleParent, from, to, time.UTC)
+
+	assert.Error(t, err)
+	assert.Nil(t, distribution)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetFeedingCountsByHour", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetFeedingBalance_NoFeedings(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	babyID := uuid.New()
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, from, to).Return([]domain.FeedingTotals{}, nil)
+
+	balance, err := measurementService.GetFeedingBalance(context.Background(), babyID, uuid.New(), domain.RoleAdmin, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, balance.TotalFeedings)
+	assert.Equal(t, 0.0, balance.BottleRatio)
+	assert.Equal(t, 0.0, balance.BreastRatio)
+}
+
+func TestMeasurementService_GetFeedingBalance_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	balance, err := measurementService.GetFeedingBalance(context.Background(), babyID, userID, domain.RoleParent, time.Now().Add(-time.Hour), time.Now())
+
+	assert.Error(t, err)
+	assert.Nil(t, balance)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetFeedingTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_WeightSetsValueGrams(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	grams := 3400.0
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	// Legacy payload: grams in value
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: grams}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.ValueGrams)
+	assert.Equal(t, grams, *result.ValueGrams)
+	assert.Equal(t, grams, result.Value)
+
+	// Typed payload: grams in value_grams only
+	result, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", ValueGrams: &grams}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.ValueGrams)
+	assert.Equal(t, grams, *result.ValueGrams)
+	assert.Equal(t, grams, result.Value)
+	assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+}
+
+func TestMeasurementService_CreateMeasurement_WeightWithinMinInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		reject bool
+	}{
+		{name: "warn", reject: false},
+		{name: "reject", reject: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			mockAlertPublisher := new(MockAlertPublisher)
+
+			measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+				services.MeasurementServiceConfig{WeightMinInterval: 6 * time.Hour, RejectWeightWithinMinInterval: tt.reject})
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			now := time.Now().UTC()
+
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+				return f.Type != nil && *f.Type == "weight" && f.Primary &&
+					f.From.Equal(now.Add(-6*time.Hour)) && f.To.Equal(now.Add(6*time.Hour))
+			})).Return([]*domain.Measurement{
+				{Type: "weight", Value: 3400, Timestamp: now.Add(-2 * time.Hour)},
+			}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "weight", Value: 3420, Timestamp: now}, userID, domain.RoleParent)
+
+			if tt.reject {
+				assert.ErrorIs(t, err, domain.ErrTooFrequent)
+				assert.Nil(t, result)
+				mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Warnings, 1)
+			assert.Contains(t, result.Warnings[0], "within the minimum interval of 6h0m0s")
+			mockMeasurementRepo.AssertCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_WeightBeyondMinInterval(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{WeightMinInterval: 6 * time.Hour, RejectWeightWithinMinInterval: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, mock.AnythingOfType("ports.MeasurementFilter")).
+		Return([]*domain.Measurement{}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3420}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidValueGrams(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	grams := 12000.0
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(),
+		ports.CreateMeasurementRequest{Type: "weight", ValueGrams: &grams}, uuid.New(), domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "10000g")
+}
+
+func TestMeasurementService_GetDailyReport_MatchesSeededData(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	from, to := day, day.AddDate(0, 0, 1)
+
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	diaper := func(s domain.DiaperStatus) *domain.DiaperStatus { return &s }
+	spitUp, noSpitUp := true, false
+
+	// Repository returns newest first
+	seeded := []*domain.Measurement{
+		{Type: "weight", Value: 3550, ValueGrams: floatPtr(3550), Timestamp: at(20)},
+		{Type: "temperature", Value: 38.4, ValueCelsius: floatPtr(38.4), SafetyStatus: domain.SafetyStatusRed, Timestamp: at(18)},
+		{Type: "diaper", DiaperStatus: diaper(domain.DiaperStatusBoth), Timestamp: at(15)},
+		{Type: "feeding", FeedingType: domain.FeedingTypeBreast, LeftDuration: intPtr(600), RightDuration: intPtr(300), Timestamp: at(12)},
+		{Type: "diaper", DiaperStatus: diaper(domain.DiaperStatusWet), Timestamp: at(9)},
+		{Type: "temperature", Value: 37.0, ValueCelsius: floatPtr(37.0), SafetyStatus: domain.SafetyStatusGreen, Timestamp: at(8)},
+		{Type: "feeding", FeedingType: domain.FeedingTypeBottle, VolumeML: intPtr(90), SpitUp: &spitUp, Timestamp: at(6)},
+		{Type: "weight", Value: 3500, ValueGrams: floatPtr(3500), Timestamp: at(5)},
+		{Type: "feeding", FeedingType: domain.FeedingTypeBottle, VolumeML: intPtr(120), SpitUp: &noSpitUp, Timestamp: at(2)},
+	}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, ports.MeasurementFilter{From: &from, To: &to}).
+		Return(seeded, nil)
+
+	report, err := measurementService.GetDailyReport(context.Background(), babyID, userID, domain.RoleParent, day)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-15", report.Date)
+	assert.Equal(t, "UTC", report.Timezone)
+
+	assert.Equal(t, 3, report.Feeding.Count)
+	assert.Equal(t, 2, report.Feeding.BottleCount)
+	assert.Equal(t, 1, report.Feeding.BreastCount)
+	assert.Equal(t, 210, report.Feeding.TotalBottleML)
+	assert.Equal(t, 900, report.Feeding.TotalBreastDurationSeconds)
+	assert.Equal(t, 1, report.Feeding.SpitUpCount)
+
+	assert.Equal(t, 2, report.Diapers.Total)
+	assert.Equal(t, 1, report.Diapers.Wet)
+	assert.Equal(t, 1, report.Diapers.Both)
+	assert.Equal(t, 0, report.Diapers.Dirty)
+
+	require.Len(t, report.Temperatures, 2)
+	assert.Equal(t, 37.0, report.Temperatures[0].ValueCelsius)
+	assert.Equal(t, domain.SafetyStatusGreen, report.Temperatures[0].SafetyStatus)
+	assert.Equal(t, 38.4, report.Temperatures[1].ValueCelsius)
+	assert.Equal(t, domain.SafetyStatusRed, report.Temperatures[1].SafetyStatus)
+
+	// Latest weight of the day
+	require.NotNil(t, report.Weight)
+	assert.Equal(t, 3550.0, report.Weight.ValueGrams)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetDailyReport_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, false, nil)
+
+	report, err := measurementService.GetDailyReport(context.Background(), babyID, userID, domain.RoleParent, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementsByBabyID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_RequireNoteOnRed_Rejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{RequireNoteOnRed: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+
+	req := ports.CreateMeasurementRequest{
+		Type:  "temperature",
+		Value: 39.0, // Red status (>38.0)
+		Note:  "   ",
+	}
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "note is required")
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+	mockAlertPublisher.AssertNotCalled(t, "PublishAlert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_RequireNoteOnRed_Accepted(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{RequireNoteOnRed: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+	// Red with a note is accepted
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever after vaccination"}, userID, domain.RoleParent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+
+	// Non-red readings don't need a note
+	result, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+
+	// Give the async alert goroutine time to finish before the mocks go out of scope
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestMeasurementService_CreateMeasurement_RequireBreastfeedingPosition(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{RequireBreastfeedingPosition: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+	intPtr := func(v int) *int { return &v }
+	breastFeeding := func(position string) ports.CreateMeasurementRequest {
+		return ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "breast", Side: "left", Duration: intPtr(600), Position: position}
+	}
+
+	// Missing position is rejected
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, breastFeeding(""), userID, domain.RoleParent)
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "breast feeding requires position (cross_cradle, cradle, football, side_lying, or laid_back)", err.Error())
+
+	// Unknown positions are still rejected
+	_, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID, breastFeeding("upside_down"), userID, domain.RoleParent)
+	require.Error(t, err)
+	assert.Equal(t, "invalid breastfeeding position: upside_down", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+
+	// A valid position is accepted
+	result, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID, breastFeeding("football"), userID, domain.RoleParent)
+	require.NoError(t, err)
+	require.NotNil(t, result.Position)
+	assert.Equal(t, domain.PositionFootball, *result.Position)
+
+	// Bottle feedings have no position to require
+	_, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "bottle", VolumeML: intPtr(90)}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	// Give the async alert goroutine time to finish before the mocks go out of scope
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestMeasurementService_CreateMeasurement_BreastfeedingPositionOptionalByDefault(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+	intPtr := func(v int) *int { return &v }
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "breast", Side: "right", Duration: intPtr(300)}, userID, domain.RoleParent)
+	require.NoError(t, err)
+	assert.Nil(t, result.Position)
+
+	// Give the async alert goroutine time to finish before the mocks go out of scope
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestMeasurementService_CreateMeasurement_NoteTemplate(t *testing.T) {
+	templates := domain.NoteTemplates{"temperature": "Measured at: axillary/rectal"}
+
+	tests := []struct {
+		name     string
+		req      ports.CreateMeasurementRequest
+		wantNote string
+	}{
+		{name: "empty note gets the template", req: ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, wantNote: "Measured at: axillary/rectal"},
+		{name: "blank note gets the template", req: ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Note: "  "}, wantNote: "Measured at: axillary/rectal"},
+		{name: "client note is kept", req: ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Note: "Rectal"}, wantNote: "Rectal"},
+		{name: "type without template", req: ports.CreateMeasurementRequest{Type: "heart_rate", Value: 130}, wantNote: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+			measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+				services.MeasurementServiceConfig{NoteTemplates: templates})
+
+			userID := uuid.New()
+			babyID := uuid.New()
+
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+				return m.Note == tt.wantNote
+			})).Return(nil)
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, tt.req, userID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNote, result.Note)
+			mockMeasurementRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_NoteTemplateDoesNotSatisfyRequireNoteOnRed(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{
+			RequireNoteOnRed: true,
+			NoteTemplates:    domain.NoteTemplates{"temperature": "Measured at: axillary/rectal"},
+		})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0}, userID, domain.RoleParent)
+
+	assert.Nil(t, result)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "note is required")
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestParseNoteTemplates(t *testing.T) {
+	templates, err := domain.ParseNoteTemplates(`{"temperature": "Measured at: axillary/rectal", "weight": "Scale: clinic/home"}`)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NoteTemplates{"temperature": "Measured at: axillary/rectal", "weight": "Scale: clinic/home"}, templates)
+
+	templates, err = domain.ParseNoteTemplates("")
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	for _, spec := range []string{
+		`temperature=Measured at`,
+		`{"temprature": "Measured at"}`,
+		`{"temperature": "  "}`,
+		`["temperature"]`,
+	} {
+		_, err := domain.ParseNoteTemplates(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_ExtremeTemperatureStoredAsRed(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.Value == 42.5 && m.SafetyStatus == domain.SafetyStatusRed
+	})).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 42.5}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	mockMeasurementRepo.AssertExpectations(t)
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestMeasurementService_CreateMeasurement_AgeAdjustedTemperature(t *testing.T) {
+	timePtr := func(v time.Time) *time.Time { return &v }
+
+	// 37.9°C is above the newborn band (37.8°C) but inside the default yellow band (38.0°C)
+	tests := []struct {
+		name           string
+		dateOfBirth    *time.Time
+		timestamp      time.Time
+		expectedStatus domain.SafetyStatus
+	}{
+		{name: "10-day-old newborn", dateOfBirth: timePtr(time.Now().AddDate(0, 0, -10)), expectedStatus: domain.SafetyStatusRed},
+		{name: "6-month-old", dateOfBirth: timePtr(time.Now().AddDate(0, -6, 0)), expectedStatus: domain.SafetyStatusYellow},
+		{name: "unknown date of birth", dateOfBirth: nil, expectedStatus: domain.SafetyStatusYellow},
+		// Logged on day 40 but taken on day 5: the newborn band applies
+		{name: "backdated newborn reading", dateOfBirth: timePtr(time.Now().AddDate(0, 0, -40)), timestamp: time.Now().AddDate(0, 0, -35), expectedStatus: domain.SafetyStatusRed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			mockAlertPublisher := new(MockAlertPublisher)
+
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, DateOfBirth: tt.dateOfBirth}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+			mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: 37.9, Timestamp: tt.timestamp}, userID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, result.SafetyStatus)
+			mockBabyRepo.AssertExpectations(t)
+
+			time.Sleep(50 * time.Millisecond)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_ImpossibleTemperatureRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	for _, value := range []float64{19.5, 45.5} {
+		result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(),
+			ports.CreateMeasurementRequest{Type: "temperature", Value: value}, uuid.New(), domain.RoleParent)
+
+		assert.Error(t, err, value)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "temperature must be between 20.0 and 45.0")
+	}
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_ConfiguredTemperatureRange(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{TemperatureMinCelsius: 30, TemperatureMaxCelsius: 42})
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(),
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 42.5}, uuid.New(), domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "temperature must be between 30.0 and 42.0")
+}
+
+func TestMeasurementService_CreateMeasurement_Sleep(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	start := time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "sleep", SleepStart: &start, SleepEnd: &end}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.MeasurementTypeSleep, result.Type)
+	assert.Equal(t, 5400.0, result.Value)
+	assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+	require.NotNil(t, result.SleepStart)
+	require.NotNil(t, result.SleepEnd)
+	assert.True(t, result.SleepStart.Equal(start))
+	assert.True(t, result.SleepEnd.Equal(end))
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidSleep(t *testing.T) {
+	start := time.Date(2024, 1, 10, 13, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Minute)
+	tooLong := start.Add(25 * time.Hour)
+
+	tests := []struct {
+		name     string
+		start    *time.Time
+		end      *time.Time
+		contains string
+	}{
+		{"missing end", &start, nil, "sleep requires sleep_start and sleep_end"},
+		{"end before start", &start, &before, "sleep_end must be after sleep_start"},
+		{"end equals start", &start, &start, "sleep_end must be after sleep_start"},
+		{"longer than 24 hours", &start, &tooLong, "exceeds reasonable maximum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "sleep", SleepStart: tt.start, SleepEnd: tt.end}, userID, domain.RoleParent)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.contains)
+			mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_FeedingWithSpitUp(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	volume := 90
+	spitUp := true
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.SpitUp != nil && *m.SpitUp && m.SpitUpSeverity != nil && *m.SpitUpSeverity == domain.SpitUpSeverityMild
+	})).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "bottle", VolumeML: &volume, SpitUp: &spitUp, SpitUpSeverity: "mild"},
+		userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, 90.0, result.Value)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidSpitUp(t *testing.T) {
+	volume := 90
+	spitUp, noSpitUp := true, false
+
+	tests := []struct {
+		name     string
+		req      ports.CreateMeasurementRequest
+		contains string
+	}{
+		{"not a feeding", ports.CreateMeasurementRequest{Type: "diaper", DiaperStatus: "wet", SpitUp: &spitUp}, "spit_up is only valid for feeding measurements"},
+		{"severity on a non-feeding", ports.CreateMeasurementRequest{Type: "diaper", DiaperStatus: "wet", SpitUpSeverity: "mild"}, "spit_up is only valid for feeding measurements"},
+		{"severity without spit_up", ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "bottle", VolumeML: &volume, SpitUpSeverity: "mild"}, "spit_up_severity requires spit_up to be true"},
+		{"severity with spit_up false", ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "bottle", VolumeML: &volume, SpitUp: &noSpitUp, SpitUpSeverity: "mild"}, "spit_up_severity requires spit_up to be true"},
+		{"unknown severity", ports.CreateMeasurementRequest{Type: "feeding", FeedingType: "bottle", VolumeML: &volume, SpitUp: &spitUp, SpitUpSeverity: "projectile"}, "invalid spit_up_severity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, tt.req, userID, domain.RoleParent)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.contains)
+			mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// studyMetadataSchema is a research deployment's metadata schema used by the metadata tests
+const studyMetadataSchema = `{
+	"type": "object",
+	"properties": {
+		"study_id": {"type": "string", "maxLength": 16},
+		"cohort": {"type": "integer", "minimum": 1},
+		"arm": {"enum": ["control", "treatment"]}
+	},
+	"required": ["study_id"],
+	"additionalProperties": false
+}`
+
+func TestMeasurementService_CreateMeasurement_WithMetadata(t *testing.T) {
+	schema, err := domain.ParseMetadataSchema(studyMetadataSchema)
+	require.NoError(t, err)
+
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{MetadataSchema: schema})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	metadata := json.RawMessage(`{"study_id": "NEO-42", "cohort": 3, "arm": "control"}`)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return string(m.Metadata) == string(metadata)
+	})).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "height", Value: 51, Metadata: metadata}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	// Returned exactly as submitted
+	assert.Equal(t, metadata, result.Metadata)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_NullMetadataIgnored(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.MatchedBy(func(m *domain.Measurement) bool {
+		return m.Metadata == nil
+	})).Return(nil)
+
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "height", Value: 51, Metadata: json.RawMessage("null")}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CreateMeasurement_InvalidMetadata(t *testing.T) {
+	schema, err := domain.ParseMetadataSchema(studyMetadataSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		schema   *domain.MetadataSchema
+		maxBytes int
+		metadata string
+		wantErr  string
+	}{
+		{name: "over size", maxBytes: 32, metadata: `{"study_id": "NEO-42", "notes": "` + strings.Repeat("x", 32) + `"}`, wantErr: "metadata exceeds the maximum size of 32 bytes"},
+		{name: "not an object", metadata: `["NEO-42"]`, wantErr: "metadata must be a JSON object"},
+		{name: "malformed", metadata: `{"study_id": }`, wantErr: "metadata must be a JSON object"},
+		{name: "missing required", schema: schema, metadata: `{"cohort": 3}`, wantErr: "metadata does not match schema: metadata.study_id is required"},
+		{name: "wrong type", schema: schema, metadata: `{"study_id": 42}`, wantErr: "metadata does not match schema: metadata.study_id must be a string"},
+		{name: "not an integer", schema: schema, metadata: `{"study_id": "NEO-42", "cohort": 2.5}`, wantErr: "metadata does not match schema: metadata.cohort must be an integer"},
+		{name: "below minimum", schema: schema, metadata: `{"study_id": "NEO-42", "cohort": 0}`, wantErr: "metadata does not match schema: metadata.cohort must be at least 1"},
+		{name: "too long", schema: schema, metadata: `{"study_id": "NEO-42-0123456789"}`, wantErr: "metadata does not match schema: metadata.study_id must be at most 16 characters"},
+		{name: "not in enum", schema: schema, metadata: `{"study_id": "NEO-42", "arm": "placebo"}`, wantErr: "metadata does not match schema: metadata.arm is not one of the allowed values"},
+		{name: "additional property", schema: schema, metadata: `{"study_id": "NEO-42", "mood": "calm"}`, wantErr: "metadata does not match schema: metadata.mood is not an allowed property"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+			measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+				services.MeasurementServiceConfig{MetadataSchema: tt.schema, MetadataMaxBytes: tt.maxBytes})
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0, Metadata: json.RawMessage(tt.metadata)}, userID, domain.RoleParent)
+
+			assert.Nil(t, result)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestParseMetadataSchema_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "not an object schema", schema: `{"type": "array"}`, wantErr: "top-level type must be \"object\""},
+		{name: "unsupported keyword", schema: `{"type": "object", "patternProperties": {}}`, wantErr: "unknown field \"patternProperties\""},
+		{name: "unsupported type", schema: `{"type": "object", "properties": {"at": {"type": "date"}}}`, wantErr: "unsupported type \"date\" at $.at"},
+		{name: "malformed", schema: `{"type": `, wantErr: "invalid metadata schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := domain.ParseMetadataSchema(tt.schema)
+
+			assert.Nil(t, schema)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_BeforeBabyCreatedRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{RejectBeforeBabyCreated: true, BabyCreatedGrace: time.Hour})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	createdAt := time.Now().UTC().Add(-2 * time.Hour)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, CreatedAt: createdAt}, nil)
+
+	// Two hours before the record, beyond the one hour grace
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3400, Timestamp: createdAt.Add(-2 * time.Hour)}, userID, domain.RoleParent)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "before the baby's record was created")
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_BackdatedWithinGraceAccepted(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{RejectBeforeBabyCreated: true, BabyCreatedGrace: time.Hour})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	createdAt := time.Now().UTC().Add(-2 * time.Hour)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID, CreatedAt: createdAt}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	// Weighed at birth, entered once the record existed: 30 minutes before created_at is within the grace
+	timestamp := createdAt.Add(-30 * time.Minute)
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3400, Timestamp: timestamp}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.True(t, result.Timestamp.Equal(timestamp))
+	mockMeasurementRepo.AssertCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_YellowAlerts(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			mockAlertPublisher := new(MockAlertPublisher)
+
+			measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+				services.MeasurementServiceConfig{PublishYellowAlerts: tt.enabled})
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			published := make(chan *domain.Measurement, 1)
+
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+			mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).
+				Run(func(args mock.Arguments) { published <- args.Get(2).(*domain.Measurement) }).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: 37.8}, userID, domain.RoleParent)
+			require.NoError(t, err)
+			require.Equal(t, domain.SafetyStatusYellow, result.SafetyStatus)
+
+			select {
+			case m := <-published:
+				assert.True(t, tt.enabled, "yellow alert published while disabled")
+				assert.Equal(t, domain.SafetyStatusYellow, m.SafetyStatus)
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, tt.enabled, "yellow alert not published while enabled")
+			}
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_AlertKeepsRequestStart(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	requestStart := time.Now().Add(-500 * time.Millisecond)
+	publishStart := make(chan time.Time, 1)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) { publishStart <- domain.RequestStart(args.Get(0).(context.Context)) })
+
+	ctx := domain.WithRequestStart(context.Background(), requestStart)
+	_, err := measurementService.CreateMeasurementWithDetails(ctx, babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"}, userID, domain.RoleParent)
+	require.NoError(t, err)
+
+	// The async publish measures its latency from the same base time as the request
+	select {
+	case start := <-publishStart:
+		assert.True(t, start.Equal(requestStart))
+	case <-time.After(time.Second):
+		t.Fatal("alert was not published")
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_SLOMeasuredFromRequestStart(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	// A request that already spent 3s before reaching the service is over the 2s budget
+	ctx := domain.WithRequestStart(context.Background(), time.Now().Add(-3*time.Second))
+	_, err := measurementService.CreateMeasurementWithDetails(ctx, babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3400}, userID, domain.RoleParent)
+
+	assert.EqualError(t, err, "operation exceeded 2s timeout")
+}
+
+func TestMeasurementService_CreateMeasurement_GrowthBoundaries(t *testing.T) {
+	tests := []struct {
+		measurementType string
+		value           float64
+	}{
+		{measurementType: "height", value: 20},
+		{measurementType: "height", value: 120},
+		{measurementType: "head_circumference", value: 25},
+		{measurementType: "head_circumference", value: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.measurementType+"_"+strconv.FormatFloat(tt.value, 'f', -1, 64), func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: tt.measurementType, Value: tt.value}, userID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, result.Value)
+			assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_GrowthOutOfRangeRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	tests := []struct {
+		measurementType string
+		value           float64
+		contains        string
+	}{
+		{measurementType: "height", value: 0, contains: "height must be greater than 0 cm"},
+		{measurementType: "height", value: -50, contains: "height must be greater than 0 cm"},
+		{measurementType: "height", value: 19.9, contains: "height must be between 20 and 120 cm"},
+		{measurementType: "height", value: 120.1, contains: "height must be between 20 and 120 cm"},
+		{measurementType: "head_circumference", value: 0, contains: "head circumference must be greater than 0 cm"},
+		{measurementType: "head_circumference", value: 24.9, contains: "head circumference must be between 25 and 60 cm"},
+		{measurementType: "head_circumference", value: 60.1, contains: "head circumference must be between 25 and 60 cm"},
+	}
+
+	for _, tt := range tests {
+		result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(),
+			ports.CreateMeasurementRequest{Type: tt.measurementType, Value: tt.value}, uuid.New(), domain.RoleParent)
+
+		require.Error(t, err, "%s %v", tt.measurementType, tt.value)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), tt.contains)
+	}
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestCalculateSafetyStatus_GrowthImpossibleValuesAreRed(t *testing.T) {
+	assert.Equal(t, domain.SafetyStatusGreen, domain.CalculateSafetyStatus(domain.MeasurementTypeHeight, 52, nil))
+	assert.Equal(t, domain.SafetyStatusRed, domain.CalculateSafetyStatus(domain.MeasurementTypeHeight, 150, nil))
+	assert.Equal(t, domain.SafetyStatusRed, domain.CalculateSafetyStatus(domain.MeasurementTypeHeight, 0, nil))
+	assert.Equal(t, domain.SafetyStatusGreen, domain.CalculateSafetyStatus(domain.MeasurementTypeHeadCircumference, 35, nil))
+	assert.Equal(t, domain.SafetyStatusRed, domain.CalculateSafetyStatus(domain.MeasurementTypeHeadCircumference, 10, nil))
+	assert.True(t, domain.IsValidMeasurementType("height"))
+	assert.True(t, domain.IsValidMeasurementType("head_circumference"))
+}
+
+func TestMeasurementService_CreateMeasurement_NearDuplicateWarning(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{NearDuplicateWindow: 10 * time.Minute})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	now := time.Now().UTC()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, mock.MatchedBy(func(f ports.MeasurementFilter) bool {
+		return f.Type != nil && *f.Type == "weight" && f.Value != nil && *f.Value == 3400 && f.Primary &&
+			f.Limit != nil && *f.Limit == 1 &&
+			f.From.Equal(now.Add(-10*time.Minute)) && f.To.Equal(now.Add(10*time.Minute))
+	})).Return([]*domain.Measurement{
+		{Type: "weight", Value: 3400, Timestamp: now.Add(-2 * time.Minute)},
+	}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "weight", Value: 3400, Timestamp: now}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "possible duplicate: weight of 3400 already logged at")
+	mockMeasurementRepo.AssertCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_NoWarningsForCleanCreate(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{NearDuplicateWindow: 10 * time.Minute})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	// No nearby reading with the same value
+	mockMeasurementRepo.On("GetMeasurementsByBabyID", mock.Anything, babyID, mock.AnythingOfType("ports.MeasurementFilter")).
+		Return([]*domain.Measurement{}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 37.0}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "warnings")
+}
+
+func TestMeasurementService_CreateMeasurement_BorderlineAndBackdatedWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     float64
+		timestamp time.Time
+		contains  string
+	}{
+		{name: "lower edge", value: 36.6, contains: "at the lower edge of the normal range"},
+		{name: "upper edge", value: 37.5, contains: "at the upper edge of the normal range"},
+		{name: "backdated", value: 37.0, timestamp: time.Now().Add(-48 * time.Hour), contains: "more than 24h0m0s in the past"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: "temperature", Value: tt.value, Timestamp: tt.timestamp}, userID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.SafetyStatusGreen, result.SafetyStatus)
+			require.Len(t, result.Warnings, 1)
+			assert.Contains(t, result.Warnings[0], tt.contains)
+		})
+	}
+}
+
+// invalidStatusMeasurementRepository serves seeded rows with their stored safety status
+// and only lists the ones whose status is not canonical, like the SQL query
+type invalidStatusMeasurementRepository struct {
+	*MockMeasurementRepository
+	rows     []ports.SafetyStatusBackfillRow
+	statuses map[uuid.UUID]domain.SafetyStatus
+}
+
+func (r *invalidStatusMeasurementRepository) ListInvalidSafetyStatusMeasurements(ctx context.Context, limit int) ([]ports.SafetyStatusBackfillRow, error) {
+	var invalid []ports.SafetyStatusBackfillRow
+	for _, row := range r.rows {
+		status := r.statuses[row.ID]
+		if status != domain.SafetyStatusGreen && status != domain.SafetyStatusYellow && status != domain.SafetyStatusRed {
+			invalid = append(invalid, row)
+		}
+		if len(invalid) == limit {
+			break
+		}
+	}
+	return invalid, nil
+}
+
+func (r *invalidStatusMeasurementRepository) UpdateMeasurementSafetyStatus(ctx context.Context, measurementID uuid.UUID, status domain.SafetyStatus) error {
+	r.statuses[measurementID] = status
+	return nil
+}
+
+func TestMeasurementService_BackfillSafetyStatus_CorrectsInvalidRows(t *testing.T) {
+	now := time.Now()
+	newbornDOB := now.AddDate(0, 0, -10)
+	fever := ports.SafetyStatusBackfillRow{ID: uuid.New(), Type: "temperature", Value: 38.5, Timestamp: now}
+	// 37.9°C is yellow for older babies but red for a newborn
+	newbornFever := ports.SafetyStatusBackfillRow{ID: uuid.New(), Type: "temperature", Value: 37.9, Timestamp: now, DateOfBirth: &newbornDOB}
+	weight := ports.SafetyStatusBackfillRow{ID: uuid.New(), Type: "weight", Value: 3400, Timestamp: now}
+	valid := ports.SafetyStatusBackfillRow{ID: uuid.New(), Type: "temperature", Value: 36.0, Timestamp: now}
+
+	repo := &invalidStatusMeasurementRepository{
+		MockMeasurementRepository: new(MockMeasurementRepository),
+		rows:                      []ports.SafetyStatusBackfillRow{fever, newbornFever, weight, valid},
+		statuses: map[uuid.UUID]domain.SafetyStatus{
+			fever.ID:        "",
+			newbornFever.ID: "RED",
+			weight.ID:       "ok",
+			valid.ID:        domain.SafetyStatusYellow,
+		},
+	}
+	measurementService := services.NewMeasurementService(repo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	// A batch size of 2 takes one full batch and a short one
+	result, err := measurementService.BackfillSafetyStatus(context.Background(), domain.RoleAdmin, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.SafetyStatusBackfillResult{Updated: 3, Batches: 2, Green: 1, Red: 2}, result)
+	assert.Equal(t, domain.SafetyStatusRed, repo.statuses[fever.ID])
+	assert.Equal(t, domain.SafetyStatusRed, repo.statuses[newbornFever.ID])
+	assert.Equal(t, domain.SafetyStatusGreen, repo.statuses[weight.ID])
+	// Rows that already had a canonical status are left alone
+	assert.Equal(t, domain.SafetyStatusYellow, repo.statuses[valid.ID])
+}
+
+func TestMeasurementService_BackfillSafetyStatus_NothingToRepair(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+	mockMeasurementRepo.On("ListInvalidSafetyStatusMeasurements", mock.Anything, services.DefaultBackfillBatchSize).
+		Return([]ports.SafetyStatusBackfillRow(nil), nil)
+
+	result, err := measurementService.BackfillSafetyStatus(context.Background(), domain.RoleAdmin, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.SafetyStatusBackfillResult{}, result)
+	mockMeasurementRepo.AssertNotCalled(t, "UpdateMeasurementSafetyStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_BackfillSafetyStatus_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      domain.Role
+		batchSize int
+		wantErr   string
+	}{
+		{name: "parent", role: domain.RoleParent, batchSize: 100, wantErr: "forbidden: only ADMIN can backfill safety status"},
+		{name: "nurse", role: domain.RoleNurse, batchSize: 100, wantErr: "forbidden: only ADMIN can backfill safety status"},
+		{name: "batch too large", role: domain.RoleAdmin, batchSize: services.MaxBackfillBatchSize + 1, wantErr: "batch_size must be between 1 and 1000"},
+		{name: "negative batch", role: domain.RoleAdmin, batchSize: -1, wantErr: "batch_size must be between 1 and 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, new(MockBabyRepositoryForMeasurement), new(MockAlertPublisher))
+
+			result, err := measurementService.BackfillSafetyStatus(context.Background(), tt.role, tt.batchSize)
+
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockMeasurementRepo.AssertNotCalled(t, "ListInvalidSafetyStatusMeasurements", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+
+func TestMeasurementService_GetMeasurementTrend_MultiDay(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	points := []domain.TrendPoint{
+		{BucketStart: from, Avg: 3400, Min: 3380, Max: 3420, Count: 2},
+		{BucketStart: from.AddDate(0, 0, 3), Avg: 3475, Min: 3475, Max: 3475, Count: 1},
+		{BucketStart: from.AddDate(0, 0, 6), Avg: 3560, Min: 3540, Max: 3580, Count: 2},
+	}
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetMeasurementTrend", mock.Anything, babyID, domain.MeasurementTypeWeight, domain.TrendBucketDay, from, to, time.UTC).
+		Return(points, nil)
+
+	trend, err := measurementService.GetMeasurementTrend(context.Background(), babyID, userID, domain.RoleParent,
+		domain.MeasurementTypeWeight, domain.TrendBucketDay, from, to, time.UTC)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.MeasurementTypeWeight, trend.Type)
+	assert.Equal(t, domain.TrendBucketDay, trend.Bucket)
+	assert.Equal(t, "UTC", trend.Timezone)
+	assert.Equal(t, points, trend.Points)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetMeasurementTrend_NoMeasurements(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	babyID := uuid.New()
+	to := time.Now()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+	mockMeasurementRepo.On("GetMeasurementTrend", mock.Anything, babyID, domain.MeasurementTypeTemperature, domain.TrendBucketHour, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.TrendPoint(nil), nil)
+
+	trend, err := measurementService.GetMeasurementTrend(context.Background(), babyID, uuid.New(), domain.RoleNurse,
+		domain.MeasurementTypeTemperature, domain.TrendBucketHour, to.Add(-24*time.Hour), to, time.UTC)
+
+	require.NoError(t, err)
+	require.NotNil(t, trend.Points)
+	assert.Empty(t, trend.Points)
+}
+
+func TestMeasurementService_GetMeasurementTrend_Rejected(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		measurementType string
+		bucket          domain.TrendBucket
+		to              time.Time
+		wantErr         string
+	}{
+		{name: "feeding", measurementType: domain.MeasurementTypeFeeding, bucket: domain.TrendBucketDay, to: from.AddDate(0, 0, 7),
+			wantErr: "invalid trend type: must be 'temperature', 'weight', 'height', or 'head_circumference'"},
+		{name: "diaper", measurementType: domain.MeasurementTypeDiaper, bucket: domain.TrendBucketDay, to: from.AddDate(0, 0, 7),
+			wantErr: "invalid trend type: must be 'temperature', 'weight', 'height', or 'head_circumference'"},
+		{name: "unknown bucket", measurementType: domain.MeasurementTypeWeight, bucket: "month", to: from.AddDate(0, 0, 7),
+			wantErr: "invalid bucket: must be 'hour', 'day', or 'week'"},
+		{name: "empty window", measurementType: domain.MeasurementTypeWeight, bucket: domain.TrendBucketDay, to: from,
+			wantErr: "from must be before to"},
+		{name: "too many buckets", measurementType: domain.MeasurementTypeTemperature, bucket: domain.TrendBucketHour, to: from.AddDate(0, 0, 60),
+			wantErr: "time window too large: at most 1000 hour buckets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+			_, err := measurementService.GetMeasurementTrend(context.Background(), uuid.New(), uuid.New(), domain.RoleParent,
+				tt.measurementType, tt.bucket, from, tt.to, time.UTC)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockBabyRepo.AssertNotCalled(t, "GetBabyAccess", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMeasurementService_GetMeasurementTrend_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	babyID := uuid.New()
+	to := time.Now()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+
+	_, err := measurementService.GetMeasurementTrend(context.Background(), babyID, uuid.New(), domain.RoleParent,
+		domain.MeasurementTypeWeight, domain.TrendBucketWeek, to.AddDate(0, -3, 0), to, time.UTC)
+
+	require.Error(t, err)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetMeasurementTrend", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CompareFeedingDays_Delta(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+	yesterday := time.Date(2024, 3, 9, 0, 0, 0, 0, loc)
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, yesterday, today).Return([]domain.FeedingTotals{
+		{FeedingType: domain.FeedingTypeBottle, Count: 4, TotalVolumeML: 400},
+		{FeedingType: domain.FeedingTypeBreast, Count: 2, TotalDurationSeconds: 1200},
+	}, nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, today, today.AddDate(0, 0, 1)).Return([]domain.FeedingTotals{
+		{FeedingType: domain.FeedingTypeBottle, Count: 5, TotalVolumeML: 450},
+	}, nil)
+
+	comparison, err := measurementService.CompareFeedingDays(context.Background(), babyID, userID, domain.RoleParent, yesterday, today)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Amsterdam", comparison.Timezone)
+	assert.Equal(t, domain.FeedingDayTotals{Date: "2024-03-09", TotalFeedings: 6, TotalBottleML: 400, TotalBreastDurationSeconds: 1200}, comparison.Day1)
+	assert.Equal(t, domain.FeedingDayTotals{Date: "2024-03-10", TotalFeedings: 5, TotalBottleML: 450}, comparison.Day2)
+	assert.Equal(t, -1, comparison.FeedingsDelta)
+	require.NotNil(t, comparison.FeedingsPercentChange)
+	assert.Equal(t, -16.7, *comparison.FeedingsPercentChange)
+	assert.Equal(t, 50, comparison.BottleMLDelta)
+	require.NotNil(t, comparison.BottleMLPercentChange)
+	assert.Equal(t, 12.5, *comparison.BottleMLPercentChange)
+	assert.Equal(t, -1200, comparison.BreastDurationDeltaSeconds)
+	require.NotNil(t, comparison.BreastDurationPercentChange)
+	assert.Equal(t, -100.0, *comparison.BreastDurationPercentChange)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_CompareFeedingDays_EmptyFirstDay(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	babyID := uuid.New()
+	day1 := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, day1, day2).Return([]domain.FeedingTotals(nil), nil)
+	mockMeasurementRepo.On("GetFeedingTotals", mock.Anything, babyID, day2, day2.AddDate(0, 0, 1)).Return([]domain.FeedingTotals{
+		{FeedingType: domain.FeedingTypeBottle, Count: 2, TotalVolumeML: 180},
+	}, nil)
+
+	comparison, err := measurementService.CompareFeedingDays(context.Background(), babyID, uuid.New(), domain.RoleNurse, day1, day2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 180, comparison.BottleMLDelta)
+	assert.Nil(t, comparison.BottleMLPercentChange, "no percentage change from zero")
+	assert.Nil(t, comparison.FeedingsPercentChange)
+}
+
+func TestMeasurementService_CompareFeedingDays_NotOwned(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	babyID := uuid.New()
+	day := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, mock.Anything).Return(true, false, nil)
+
+	_, err := measurementService.CompareFeedingDays(context.Background(), babyID, uuid.New(), domain.RoleParent, day, day.AddDate(0, 0, 1))
+
+	require.Error(t, err)
+	assert.Equal(t, "baby not found", err.Error())
+	mockMeasurementRepo.AssertNotCalled(t, "GetFeedingTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_PublishFailureBestEffort(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	// Default mode: the alert is published in the background and its failure is only logged
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	attempted := make(chan struct{}, 1)
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(fmt.Errorf("broker unreachable")).
+		Run(func(args mock.Arguments) { attempted <- struct{}{} })
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	select {
+	case <-attempted:
+	case <-time.After(time.Second):
+		t.Fatal("alert was not published")
+	}
+	mockMeasurementRepo.AssertNotCalled(t, "DeleteMeasurement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_PublishFailureStrict(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{FailOnAlertPublishFailure: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	var created *domain.Measurement
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*domain.Measurement) })
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(fmt.Errorf("broker unreachable"))
+	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, mock.AnythingOfType("uuid.UUID"), userID).Return(nil, nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"}, userID, domain.RoleParent)
+
+	assert.Nil(t, result)
+	require.ErrorIs(t, err, domain.ErrAlertUnavailable)
+	assert.Contains(t, err.Error(), "broker unreachable")
+	// The inserted row is compensated by deleting it again
+	require.NotNil(t, created)
+	mockMeasurementRepo.AssertCalled(t, "DeleteMeasurement", mock.Anything, created.ID, userID)
+	mockAlertPublisher.AssertNumberOfCalls(t, "PublishAlert", 1)
+}
+
+func TestMeasurementService_CreateMeasurement_StrictPublishesBeforeReturning(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{FailOnAlertPublishFailure: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil)
+
+	result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+		ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"}, userID, domain.RoleParent)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	// No waiting needed: strict mode publishes exactly once, within the call
+	mockAlertPublisher.AssertNumberOfCalls(t, "PublishAlert", 1)
+	mockMeasurementRepo.AssertNotCalled(t, "DeleteMeasurement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCalculateSafetyStatus_HeartRateBands(t *testing.T) {
+	tests := []struct {
+		bpm  float64
+		want domain.SafetyStatus
+	}{
+		{bpm: 79.9, want: domain.SafetyStatusRed},
+		{bpm: 80, want: domain.SafetyStatusYellow},
+		{bpm: 99.9, want: domain.SafetyStatusYellow},
+		{bpm: 100, want: domain.SafetyStatusGreen},
+		{bpm: 140, want: domain.SafetyStatusGreen},
+		{bpm: 160, want: domain.SafetyStatusGreen},
+		{bpm: 160.1, want: domain.SafetyStatusYellow},
+		{bpm: 200, want: domain.SafetyStatusYellow},
+		{bpm: 200.1, want: domain.SafetyStatusRed},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, domain.CalculateSafetyStatus(domain.MeasurementTypeHeartRate, tt.bpm, nil), "%v bpm", tt.bpm)
+	}
+	assert.True(t, domain.IsValidMeasurementType("heart_rate"))
+}
+
+func TestCalculateSafetyStatus_SpO2Bands(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    domain.SafetyStatus
+	}{
+		{percent: 100, want: domain.SafetyStatusGreen},
+		{percent: 95, want: domain.SafetyStatusGreen},
+		{percent: 94.9, want: domain.SafetyStatusYellow},
+		{percent: 90, want: domain.SafetyStatusYellow},
+		{percent: 89.9, want: domain.SafetyStatusRed},
+		{percent: 0, want: domain.SafetyStatusRed},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, domain.CalculateSafetyStatus(domain.MeasurementTypeSpO2, tt.percent, nil), "%v%%", tt.percent)
+	}
+	assert.True(t, domain.IsValidMeasurementType("spo2"))
+}
+
+func TestMeasurementService_CreateMeasurement_VitalsBoundaries(t *testing.T) {
+	tests := []struct {
+		measurementType string
+		value           float64
+		want            domain.SafetyStatus
+	}{
+		{measurementType: "heart_rate", value: 20, want: domain.SafetyStatusRed},
+		{measurementType: "heart_rate", value: 130, want: domain.SafetyStatusGreen},
+		{measurementType: "heart_rate", value: 300, want: domain.SafetyStatusRed},
+		{measurementType: "spo2", value: 0, want: domain.SafetyStatusRed},
+		{measurementType: "spo2", value: 92, want: domain.SafetyStatusYellow},
+		{measurementType: "spo2", value: 100, want: domain.SafetyStatusGreen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.measurementType+"_"+strconv.FormatFloat(tt.value, 'f', -1, 64), func(t *testing.T) {
+			mockMeasurementRepo := new(MockMeasurementRepository)
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			mockAlertPublisher := new(MockAlertPublisher)
+			measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher)
+
+			userID := uuid.New()
+			babyID := uuid.New()
+			mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+			mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+			mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Maybe()
+
+			result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+				ports.CreateMeasurementRequest{Type: tt.measurementType, Value: tt.value, Note: "Pulse oximeter"}, userID, domain.RoleParent)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, result.Value)
+			assert.Equal(t, tt.want, result.SafetyStatus)
+		})
+	}
+}
+
+func TestMeasurementService_CreateMeasurement_VitalsOutOfRangeRejected(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	tests := []struct {
+		measurementType string
+		value           float64
+		contains        string
+	}{
+		{measurementType: "heart_rate", value: 19.9, contains: "heart rate must be between 20 and 300 bpm"},
+		{measurementType: "heart_rate", value: 300.1, contains: "heart rate must be between 20 and 300 bpm"},
+		{measurementType: "spo2", value: -1, contains: "spo2 must be between 0 and 100 percent"},
+		{measurementType: "spo2", value: 100.1, contains: "spo2 must be between 0 and 100 percent"},
+	}
+
+	for _, tt := range tests {
+		result, err := measurementService.CreateMeasurementWithDetails(context.Background(), uuid.New(),
+			ports.CreateMeasurementRequest{Type: tt.measurementType, Value: tt.value}, uuid.New(), domain.RoleParent)
+
+		require.Error(t, err, "%s %v", tt.measurementType, tt.value)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), tt.contains)
+	}
+	mockMeasurementRepo.AssertNotCalled(t, "CreateMeasurement", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_CreateMeasurement_AlertThrottle(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+	registry := prometheus.NewRegistry()
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{
+			AlertThrottleWindow: 5 * time.Minute,
+			Metrics:             middleware.NewMeasurementMetricsCollector(registry),
+		})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil)
+
+	// A flapping sensor: three red readings within the window
+	for _, value := range []float64{39.0, 39.2, 38.9} {
+		result, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID,
+			ports.CreateMeasurementRequest{Type: "temperature", Value: value, Note: "Fever"}, userID, domain.RoleParent)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SafetyStatusRed, result.SafetyStatus)
+	}
+
+	expected := `
+# HELP alerts_suppressed_total Total number of alerts not published because the baby had an alert of the same type within the throttle window
+# TYPE alerts_suppressed_total counter
+alerts_suppressed_total{type="temperature"} 2
+`
+	assert.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(registry, strings.NewReader(expected), "alerts_suppressed_total") == nil
+	}, time.Second, 10*time.Millisecond)
+	mockAlertPublisher.AssertNumberOfCalls(t, "PublishAlert", 1)
+	mockMeasurementRepo.AssertNumberOfCalls(t, "CreateMeasurement", 3)
+}
+
+func TestMeasurementService_CreateMeasurement_AlertThrottlePerBabyAndType(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	// Strict mode publishes within the request, so the calls can be counted right away
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{AlertThrottleWindow: 5 * time.Minute, FailOnAlertPublishFailure: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+	otherBabyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, mock.Anything, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, mock.Anything).Return(&domain.Baby{}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	create := func(babyID uuid.UUID, req ports.CreateMeasurementRequest) {
+		_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+		require.NoError(t, err)
+	}
+	create(babyID, ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"})
+	create(otherBabyID, ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"})
+	create(babyID, ports.CreateMeasurementRequest{Type: "spo2", Value: 85, Note: "Desaturation"})
+	create(babyID, ports.CreateMeasurementRequest{Type: "temperature", Value: 39.1, Note: "Still febrile"})
+
+	mockAlertPublisher.AssertNumberOfCalls(t, "PublishAlert", 3)
+}
+
+func TestMeasurementService_CreateMeasurement_FailedAlertDoesNotStartThrottle(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAlertPublisher := new(MockAlertPublisher)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, mockAlertPublisher,
+		services.MeasurementServiceConfig{AlertThrottleWindow: 5 * time.Minute, FailOnAlertPublishFailure: true})
+
+	userID := uuid.New()
+	babyID := uuid.New()
+
+	mockBabyRepo.On("GetBabyAccess", mock.Anything, babyID, userID).Return(true, true, nil)
+	mockBabyRepo.On("GetBabyByID", mock.Anything, babyID).Return(&domain.Baby{ID: babyID}, nil)
+	mockMeasurementRepo.On("CreateMeasurement", mock.Anything, mock.AnythingOfType("*domain.Measurement")).Return(nil)
+	mockMeasurementRepo.On("DeleteMeasurement", mock.Anything, mock.Anything, userID).Return(nil, nil)
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(fmt.Errorf("broker unreachable")).Once()
+	mockAlertPublisher.On("PublishAlert", mock.Anything, babyID, mock.Anything).Return(nil).Once()
+
+	req := ports.CreateMeasurementRequest{Type: "temperature", Value: 39.0, Note: "Fever"}
+	_, err := measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+	require.ErrorIs(t, err, domain.ErrAlertUnavailable)
+
+	// The retried reading is alerted rather than throttled behind the alert that never went out
+	_, err = measurementService.CreateMeasurementWithDetails(context.Background(), babyID, req, userID, domain.RoleParent)
+	require.NoError(t, err)
+	mockAlertPublisher.AssertNumberOfCalls(t, "PublishAlert", 2)
 }
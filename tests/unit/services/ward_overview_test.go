@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IANDYI/care-service/internal/core/domain"
+	"github.com/IANDYI/care-service/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasurementService_GetWardOverview_MultipleBabies(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	smith := &domain.Baby{ID: uuid.New(), LastName: "Smith", RoomNumber: "3B-01"}
+	jones := &domain.Baby{ID: uuid.New(), LastName: "Jones", RoomNumber: "3B-02"}
+	quiet := &domain.Baby{ID: uuid.New(), LastName: "Quiet", RoomNumber: "3B-03"}
+	babyIDs := []uuid.UUID{smith.ID, jones.ID, quiet.ID}
+
+	smithTemperature := &domain.Measurement{ID: uuid.New(), BabyID: smith.ID, Type: domain.MeasurementTypeTemperature, Value: 38.6, SafetyStatus: domain.SafetyStatusRed}
+	smithFeeding := &domain.Measurement{ID: uuid.New(), BabyID: smith.ID, Type: domain.MeasurementTypeFeeding, Value: 90, SafetyStatus: domain.SafetyStatusGreen}
+	jonesFeeding := &domain.Measurement{ID: uuid.New(), BabyID: jones.ID, Type: domain.MeasurementTypeFeeding, Value: 60, SafetyStatus: domain.SafetyStatusGreen}
+
+	mockBabyRepo.On("ListBabiesByRoomPrefix", mock.Anything, "3B").Return([]*domain.Baby{smith, jones, quiet}, nil)
+	mockMeasurementRepo.On("GetLatestMeasurementsForBabies", mock.Anything, babyIDs).
+		Return([]*domain.Measurement{smithTemperature, smithFeeding, jonesFeeding}, nil)
+	mockMeasurementRepo.On("GetSafetyStatusesSince", mock.Anything, babyIDs, mock.AnythingOfType("time.Time"), []string(nil)).
+		Return(map[uuid.UUID][]domain.SafetyStatus{
+			smith.ID: {domain.SafetyStatusGreen, domain.SafetyStatusRed, domain.SafetyStatusYellow},
+			jones.ID: {domain.SafetyStatusGreen, domain.SafetyStatusYellow},
+		}, nil)
+
+	overview, err := measurementService.GetWardOverview(context.Background(), " 3B ", uuid.New(), domain.RoleNurse)
+
+	require.NoError(t, err)
+	assert.Equal(t, "3B", overview.RoomPrefix)
+	assert.WithinDuration(t, time.Now().Add(-services.WardOverviewRecentWindow), overview.Since, time.Minute)
+	require.Len(t, overview.Babies, 3)
+
+	assert.Equal(t, smith.ID, overview.Babies[0].BabyID)
+	assert.Equal(t, "3B-01", overview.Babies[0].RoomNumber)
+	assert.Equal(t, map[string]*domain.Measurement{"temperature": smithTemperature, "feeding": smithFeeding}, overview.Babies[0].Latest)
+	assert.Equal(t, domain.SafetyStatusRed, overview.Babies[0].WorstRecentStatus)
+
+	assert.Equal(t, map[string]*domain.Measurement{"feeding": jonesFeeding}, overview.Babies[1].Latest)
+	assert.Equal(t, domain.SafetyStatusYellow, overview.Babies[1].WorstRecentStatus)
+
+	// A baby with nothing recorded still gets a row
+	assert.Empty(t, overview.Babies[2].Latest)
+	assert.Empty(t, overview.Babies[2].WorstRecentStatus)
+	mockBabyRepo.AssertExpectations(t)
+	mockMeasurementRepo.AssertExpectations(t)
+}
+
+func TestMeasurementService_GetWardOverview_HidesTypesAndUnassignedBabies(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+	mockAssignments := new(MockAssignmentRepository)
+
+	measurementService := services.NewMeasurementServiceWithConfig(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher),
+		services.MeasurementServiceConfig{
+			NurseAssignments: mockAssignments,
+			Visibility:       domain.MeasurementVisibility{domain.RoleNurse: {domain.MeasurementTypeTemperature}},
+		})
+
+	nurseID := uuid.New()
+	assigned := &domain.Baby{ID: uuid.New(), RoomNumber: "3B-01"}
+	other := &domain.Baby{ID: uuid.New(), RoomNumber: "3B-02"}
+	temperature := &domain.Measurement{BabyID: assigned.ID, Type: domain.MeasurementTypeTemperature, SafetyStatus: domain.SafetyStatusGreen}
+	weight := &domain.Measurement{BabyID: assigned.ID, Type: domain.MeasurementTypeWeight, SafetyStatus: domain.SafetyStatusRed}
+
+	mockBabyRepo.On("ListBabiesByRoomPrefix", mock.Anything, "3B").Return([]*domain.Baby{assigned, other}, nil)
+	mockAssignments.On("ListAssignedBabies", mock.Anything, nurseID).Return([]*domain.Baby{assigned}, nil)
+	mockMeasurementRepo.On("GetLatestMeasurementsForBabies", mock.Anything, []uuid.UUID{assigned.ID}).
+		Return([]*domain.Measurement{temperature, weight}, nil)
+	mockMeasurementRepo.On("GetSafetyStatusesSince", mock.Anything, []uuid.UUID{assigned.ID}, mock.AnythingOfType("time.Time"), []string{domain.MeasurementTypeTemperature}).
+		Return(map[uuid.UUID][]domain.SafetyStatus{assigned.ID: {domain.SafetyStatusGreen}}, nil)
+
+	overview, err := measurementService.GetWardOverview(context.Background(), "3B", nurseID, domain.RoleNurse)
+
+	require.NoError(t, err)
+	require.Len(t, overview.Babies, 1)
+	assert.Equal(t, assigned.ID, overview.Babies[0].BabyID)
+	assert.Equal(t, map[string]*domain.Measurement{"temperature": temperature}, overview.Babies[0].Latest)
+	assert.Equal(t, domain.SafetyStatusGreen, overview.Babies[0].WorstRecentStatus)
+}
+
+func TestMeasurementService_GetWardOverview_EmptyWard(t *testing.T) {
+	mockMeasurementRepo := new(MockMeasurementRepository)
+	mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+
+	measurementService := services.NewMeasurementService(mockMeasurementRepo, mockBabyRepo, new(MockAlertPublisher))
+
+	mockBabyRepo.On("ListBabiesByRoomPrefix", mock.Anything, "9Z").Return([]*domain.Baby{}, nil)
+
+	overview, err := measurementService.GetWardOverview(context.Background(), "9Z", uuid.New(), domain.RoleAdmin)
+
+	require.NoError(t, err)
+	assert.NotNil(t, overview.Babies)
+	assert.Empty(t, overview.Babies)
+	mockMeasurementRepo.AssertNotCalled(t, "GetLatestMeasurementsForBabies", mock.Anything, mock.Anything)
+}
+
+func TestMeasurementService_GetWardOverview_Rejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		roomPrefix string
+		role       domain.Role
+		wantErr    string
+	}{
+		{name: "parent", roomPrefix: "3B", role: domain.RoleParent, wantErr: "forbidden: only ADMIN and NURSE can view ward overviews"},
+		{name: "blank prefix", roomPrefix: "  ", role: domain.RoleNurse, wantErr: "room_prefix must be 1-32 characters"},
+		{name: "long prefix", roomPrefix: "3B-0123456789012345678901234567890", role: domain.RoleAdmin, wantErr: "room_prefix must be 1-32 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBabyRepo := new(MockBabyRepositoryForMeasurement)
+			measurementService := services.NewMeasurementService(new(MockMeasurementRepository), mockBabyRepo, new(MockAlertPublisher))
+
+			_, err := measurementService.GetWardOverview(context.Background(), tt.roomPrefix, uuid.New(), tt.role)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockBabyRepo.AssertNotCalled(t, "ListBabiesByRoomPrefix", mock.Anything, mock.Anything)
+		})
+	}
+}